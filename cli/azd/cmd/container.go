@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -32,6 +33,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/helm"
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
@@ -49,17 +51,22 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/golang"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/javac"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/kubectl"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/maven"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/notation"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/swa"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/syft"
 	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
 	"github.com/mattn/go-colorable"
 	"github.com/spf13/cobra"
@@ -201,6 +208,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			envManager environment.Manager,
 			lazyEnv *lazy.Lazy[*environment.Environment],
 			envFlags internal.EnvFlag,
+			secretResolver environment.SecretResolver,
 		) (*environment.Environment, error) {
 			if azdContext == nil {
 				return nil, azdcontext.ErrNoProject
@@ -214,6 +222,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 				return nil, fmt.Errorf("loading environment: %w", err)
 			}
 
+			// The values referencing key vault secrets are resolved when building the environment of processes
+			env.SetSecretResolver(secretResolver)
+
 			// Reset lazy env value after loading or creating environment
 			// This allows any previous lazy instances (such as hooks) to now point to the same instance
 			lazyEnv.SetValue(env)
@@ -376,13 +387,15 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		},
 	)
 
+	// The cloud configuration is selected without loading the metadata document of a custom cloud, which is loaded when
+	// the cloud is first resolved
 	container.MustRegisterSingleton(func(
 		ctx context.Context,
 		userConfigManager config.UserConfigManager,
 		lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
 		lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext],
 		lazyLocalEnvStore *lazy.Lazy[environment.LocalDataStore],
-	) (*cloud.Cloud, error) {
+	) (*cloud.Config, error) {
 
 		// Precedence for cloud configuration:
 		// 1. Local environment config (.azure/<environment>/config.json)
@@ -391,7 +404,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		// Default if no cloud configured: Azure Public Cloud
 
 		validClouds := fmt.Sprintf(
-			"Valid cloud names are '%s', '%s', '%s'.",
+			"Valid cloud names are '%s', '%s', '%s'. A custom cloud is defined by its 'metadata' document or its "+
+				"'resourceManagerEndpoint', 'authorityHost' and 'portalUrl' endpoints.",
 			cloud.AzurePublicName,
 			cloud.AzureChinaCloudName,
 			cloud.AzureUSGovernmentName,
@@ -405,9 +419,9 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 					if env, err := localEnvStore.Get(ctx, defaultEnvName); err == nil {
 						if cloudConfigurationNode, exists := env.Config.Get(cloud.ConfigPath); exists {
 							if value, err := cloud.ParseCloudConfig(cloudConfigurationNode); err == nil {
-								cloudConfig, err := cloud.NewCloud(value)
+								err := value.Validate()
 								if err == nil {
-									return cloudConfig, nil
+									return value, nil
 								}
 
 								return nil, &internal.ErrorWithSuggestion{
@@ -430,8 +444,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		if err == nil && projConfig != nil && projConfig.Cloud != nil {
 			if value, err := cloud.ParseCloudConfig(projConfig.Cloud); err == nil {
 				if cloudConfig, err := cloud.ParseCloudConfig(value); err == nil {
-					if cloud, err := cloud.NewCloud(cloudConfig); err == nil {
-						return cloud, nil
+					if err := cloudConfig.Validate(); err == nil {
+						return cloudConfig, nil
 					} else {
 						return nil, &internal.ErrorWithSuggestion{
 							Err: err,
@@ -447,8 +461,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		if azdConfig, err := userConfigManager.Load(); err == nil {
 			if cloudConfigNode, exists := azdConfig.Get(cloud.ConfigPath); exists {
 				if value, err := cloud.ParseCloudConfig(cloudConfigNode); err == nil {
-					if cloud, err := cloud.NewCloud(value); err == nil {
-						return cloud, nil
+					if err := value.Validate(); err == nil {
+						return value, nil
 					} else {
 						return nil, &internal.ErrorWithSuggestion{
 							Err:        err,
@@ -459,7 +473,27 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 			}
 		}
 
-		return cloud.NewCloud(&cloud.Config{Name: cloud.AzurePublicName})
+		return &cloud.Config{Name: cloud.AzurePublicName}, nil
+	})
+
+	container.MustRegisterSingleton(func(
+		ctx context.Context,
+		cloudConfig *cloud.Config,
+		httpClient httputil.HttpClient,
+	) (*cloud.Cloud, error) {
+		azureCloud, err := cloud.NewCloud(ctx, cloudConfig, httpClient)
+		if err != nil && cloudConfig.Metadata != "" {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: err,
+				Suggestion: fmt.Sprintf(
+					"Check the metadata document '%s' of the custom cloud is reachable, or set the endpoints of the cloud "+
+						"in the cloud configuration instead.",
+					cloudConfig.Metadata,
+				),
+			}
+		}
+
+		return azureCloud, err
 	})
 
 	container.MustRegisterSingleton(func(cloud *cloud.Cloud) cloud.PortalUrlBase {
@@ -506,12 +540,21 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(project.NewDotNetImporter)
 	container.MustRegisterScoped(project.NewImportManager)
 	container.MustRegisterScoped(project.NewServiceManager)
+	container.MustRegisterScoped(
+		func(azdContext *azdcontext.AzdContext, env *environment.Environment) *project.BuildCache {
+			return project.NewBuildCache(filepath.Join(azdContext.EnvironmentRoot(env.Name()), project.BuildCacheFileName))
+		},
+	)
+	container.MustRegisterScoped(
+		func(azdContext *azdcontext.AzdContext, env *environment.Environment) *project.DeploymentHistory {
+			return project.NewDeploymentHistory(
+				filepath.Join(azdContext.EnvironmentRoot(env.Name()), project.DeploymentHistoryFileName))
+		},
+	)
 
 	// Even though the service manager is scoped based on its use of environment we can still
 	// register its internal cache as a singleton to ensure operation caching is consistent across all instances
-	container.MustRegisterSingleton(func() project.ServiceOperationCache {
-		return project.ServiceOperationCache{}
-	})
+	container.MustRegisterSingleton(project.NewServiceOperationCache)
 
 	container.MustRegisterScoped(func(serviceLocator ioc.ServiceLocator) *lazy.Lazy[project.ServiceManager] {
 		return lazy.NewLazy(func() (project.ServiceManager, error) {
@@ -569,7 +612,11 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(azcli.NewContainerRegistryService)
 	container.MustRegisterSingleton(containerapps.NewContainerAppService)
 	container.MustRegisterSingleton(keyvault.NewKeyVaultService)
+	container.MustRegisterSingleton(func(keyVaultService keyvault.KeyVaultService) environment.SecretResolver {
+		return keyvault.NewSecretResolver(keyVaultService)
+	})
 	container.MustRegisterScoped(project.NewContainerHelper)
+	container.MustRegisterScoped(project.NewImageSupplyChain)
 	container.MustRegisterSingleton(azcli.NewSpringService)
 
 	container.MustRegisterSingleton(func(subManager *account.SubscriptionsManager) account.SubscriptionTenantResolver {
@@ -599,6 +646,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(dotnet.NewDotNetCli)
 	container.MustRegisterSingleton(git.NewGitCli)
 	container.MustRegisterSingleton(github.NewGitHubCli)
+	container.MustRegisterSingleton(golang.NewGoCli)
 	container.MustRegisterSingleton(javac.NewCli)
 	container.MustRegisterSingleton(kubectl.NewKubectl)
 	container.MustRegisterSingleton(maven.NewMavenCli)
@@ -607,7 +655,12 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 	container.MustRegisterSingleton(kustomize.NewCli)
 	container.MustRegisterSingleton(npm.NewNpmCli)
 	container.MustRegisterSingleton(python.NewPythonCli)
+	container.MustRegisterSingleton(python.NewPoetryCli)
+	container.MustRegisterSingleton(python.NewUvCli)
 	container.MustRegisterSingleton(swa.NewSwaCli)
+	container.MustRegisterSingleton(syft.NewSyftCli)
+	container.MustRegisterSingleton(oras.NewOrasCli)
+	container.MustRegisterSingleton(notation.NewNotationCli)
 	container.MustRegisterScoped(ai.NewPythonBridge)
 	container.MustRegisterScoped(project.NewAiHelper)
 
@@ -631,6 +684,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.SpringAppTarget:          project.NewSpringAppTarget,
 		project.DotNetContainerAppTarget: project.NewDotNetContainerAppTarget,
 		project.AiEndpointTarget:         project.NewAiEndpointTarget,
+		project.PluginTarget:             project.NewPluginTarget,
 	}
 
 	for target, constructor := range serviceTargetMap {
@@ -647,6 +701,7 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		project.ServiceLanguageJavaScript: project.NewNpmProject,
 		project.ServiceLanguageTypeScript: project.NewNpmProject,
 		project.ServiceLanguageJava:       project.NewMavenProject,
+		project.ServiceLanguageGo:         project.NewGoProject,
 		project.ServiceLanguageDocker:     project.NewDockerProject,
 	}
 
@@ -756,7 +811,24 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		return &workflowCmdAdapter{cmd: rootCmd}, nil
 
 	})
-	container.MustRegisterSingleton(workflow.NewRunner)
+	container.MustRegisterScoped(func(
+		lazyEnv *lazy.Lazy[*environment.Environment],
+		lazyEnvManager *lazy.Lazy[environment.Manager],
+		lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
+		commandRunner exec.CommandRunner,
+		console input.Console,
+		keyVaultService keyvault.KeyVaultService,
+	) workflow.ScriptRunner {
+		return &workflowScriptAdapter{
+			lazyEnv:           lazyEnv,
+			lazyEnvManager:    lazyEnvManager,
+			lazyProjectConfig: lazyProjectConfig,
+			commandRunner:     commandRunner,
+			console:           console,
+			keyVaultService:   keyVaultService,
+		}
+	})
+	container.MustRegisterScoped(workflow.NewRunner)
 
 	// Required for nested actions called from composite actions like 'up'
 	registerAction[*cmd.ProvisionAction](container, "azd-provision-action")
@@ -779,6 +851,80 @@ func (w *workflowCmdAdapter) ExecuteContext(ctx context.Context) error {
 	return w.cmd.ExecuteContext(childCtx)
 }
 
+// workflowScriptAdapter runs the scripts of workflow steps with the hooks machinery
+type workflowScriptAdapter struct {
+	lazyEnv           *lazy.Lazy[*environment.Environment]
+	lazyEnvManager    *lazy.Lazy[environment.Manager]
+	lazyProjectConfig *lazy.Lazy[*project.ProjectConfig]
+	commandRunner     exec.CommandRunner
+	console           input.Console
+	keyVaultService   keyvault.KeyVaultService
+}
+
+// Values implements workflow.ScriptRunner
+func (w *workflowScriptAdapter) Values(ctx context.Context) (map[string]string, error) {
+	env, err := w.lazyEnv.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	envManager, err := w.lazyEnvManager.GetValue()
+	if err != nil {
+		return nil, err
+	}
+
+	// Steps may have changed the environment since it was loaded
+	if err := envManager.Reload(ctx, env); err != nil {
+		return nil, fmt.Errorf("reloading environment: %w", err)
+	}
+
+	return env.Dotenv(), nil
+}
+
+// RunScript implements workflow.ScriptRunner
+func (w *workflowScriptAdapter) RunScript(ctx context.Context, step *workflow.Step, stdout io.Writer) error {
+	env, err := w.lazyEnv.GetValue()
+	if err != nil {
+		return err
+	}
+
+	envManager, err := w.lazyEnvManager.GetValue()
+	if err != nil {
+		return err
+	}
+
+	projectConfig, err := w.lazyProjectConfig.GetValue()
+	if err != nil {
+		return err
+	}
+
+	hooksRunner := ext.NewHooksRunner(
+		ext.NewHooksManager(projectConfig.Path),
+		w.commandRunner,
+		envManager,
+		w.console,
+		projectConfig.Path,
+		nil,
+		env,
+		w.keyVaultService,
+	)
+
+	options := &tools.ExecOptions{}
+	if stdout != nil {
+		interactive := false
+		options.Interactive = &interactive
+		options.StdOut = stdout
+	}
+
+	hookConfig := &ext.HookConfig{
+		Shell:       ext.ShellType(step.Shell),
+		Run:         step.Run,
+		Interactive: step.Interactive,
+	}
+
+	return hooksRunner.RunScript(ctx, "step", hookConfig, options)
+}
+
 // ArmClientInitializer is a function definition for all Azure SDK ARM Client
 type ArmClientInitializer[T comparable] func(
 	subscriptionId string,
@@ -59,6 +59,7 @@ type downAction struct {
 	env              *environment.Environment
 	console          input.Console
 	projectConfig    *project.ProjectConfig
+	buildCache       *project.BuildCache
 }
 
 func newDownAction(
@@ -69,6 +70,7 @@ func newDownAction(
 	console input.Console,
 	alphaFeatureManager *alpha.FeatureManager,
 	importManager *project.ImportManager,
+	buildCache *project.BuildCache,
 ) actions.Action {
 	return &downAction{
 		flags:            flags,
@@ -77,6 +79,7 @@ func newDownAction(
 		console:          console,
 		projectConfig:    projectConfig,
 		importManager:    importManager,
+		buildCache:       buildCache,
 	}
 }
 
@@ -104,6 +107,11 @@ func (a *downAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		return nil, fmt.Errorf("deleting infrastructure: %w", err)
 	}
 
+	// The services are deployed again once their resources are recreated
+	if err := a.buildCache.InvalidateAll(project.ServiceEventDeploy); err != nil {
+		return nil, err
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
 			Header: fmt.Sprintf("Your application was removed from Azure in %s.", ux.DurationAsText(since(startTime))),
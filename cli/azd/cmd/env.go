@@ -8,8 +8,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
@@ -17,12 +22,15 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
 )
 
 func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
@@ -45,6 +53,15 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newEnvSetAction,
 	})
 
+	group.Add("set-secret", &actions.ActionDescriptorOptions{
+		Command:        newEnvSetSecretCmd(),
+		FlagsResolver:  newEnvSetSecretFlags,
+		ActionResolver: newEnvSetSecretAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvSetSecretHelpDescription,
+		},
+	})
+
 	group.Add("select", &actions.ActionDescriptorOptions{
 		Command:        newEnvSelectCmd(),
 		ActionResolver: newEnvSelectAction,
@@ -79,6 +96,60 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		DefaultFormat:  output.EnvVarsFormat,
 	})
 
+	group.Add("diff", &actions.ActionDescriptorOptions{
+		Command:        newEnvDiffCmd(),
+		ActionResolver: newEnvDiffAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	group.Add("export", &actions.ActionDescriptorOptions{
+		Command:        newEnvExportCmd(),
+		FlagsResolver:  newEnvExportFlags,
+		ActionResolver: newEnvExportAction,
+	})
+
+	group.Add("import", &actions.ActionDescriptorOptions{
+		Command:        newEnvImportCmd(),
+		FlagsResolver:  newEnvImportFlags,
+		ActionResolver: newEnvImportAction,
+	})
+
+	group.Add("history", &actions.ActionDescriptorOptions{
+		Command:        newEnvHistoryCmd(),
+		FlagsResolver:  newEnvNameFlags,
+		ActionResolver: newEnvHistoryAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	group.Add("restore", &actions.ActionDescriptorOptions{
+		Command:        newEnvRestoreCmd(),
+		FlagsResolver:  newEnvRestoreFlags,
+		ActionResolver: newEnvRestoreAction,
+	}).UseMiddleware("lock", middleware.NewLockMiddleware)
+
+	lockGroup := group.Add("lock", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "lock",
+			Short: "Manage the lock of the remote state of an environment.",
+		},
+	})
+
+	lockGroup.Add("status", &actions.ActionDescriptorOptions{
+		Command:        newEnvLockStatusCmd(),
+		FlagsResolver:  newEnvNameFlags,
+		ActionResolver: newEnvLockStatusAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+	})
+
+	lockGroup.Add("break", &actions.ActionDescriptorOptions{
+		Command:        newEnvLockBreakCmd(),
+		FlagsResolver:  newEnvLockBreakFlags,
+		ActionResolver: newEnvLockBreakAction,
+	})
+
 	return group
 }
 
@@ -144,6 +215,204 @@ func (e *envSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	return nil, nil
 }
 
+func newEnvSetSecretFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envSetSecretFlags {
+	flags := &envSetSecretFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvSetSecretCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-secret <key>",
+		Short: "Set an environment value referencing a secret stored in Azure Key Vault.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type envSetSecretFlags struct {
+	internal.EnvFlag
+	vault  string
+	secret string
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envSetSecretFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	local.StringVar(&f.vault, "vault", "", "The name of the key vault storing the secret. Prompted when not specified.")
+	local.StringVar(
+		&f.secret,
+		"secret",
+		"",
+		"The name of the secret. The secret is created when it doesn't exist. Prompted when not specified.",
+	)
+	f.global = global
+}
+
+type envSetSecretAction struct {
+	console         input.Console
+	env             *environment.Environment
+	envManager      environment.Manager
+	keyVaultService keyvault.KeyVaultService
+	flags           *envSetSecretFlags
+	args            []string
+}
+
+func newEnvSetSecretAction(
+	env *environment.Environment,
+	envManager environment.Manager,
+	keyVaultService keyvault.KeyVaultService,
+	console input.Console,
+	flags *envSetSecretFlags,
+	args []string,
+) actions.Action {
+	return &envSetSecretAction{
+		console:         console,
+		env:             env,
+		envManager:      envManager,
+		keyVaultService: keyVaultService,
+		flags:           flags,
+		args:            args,
+	}
+}
+
+func (e *envSetSecretAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	key := e.args[0]
+
+	subscriptionId := e.env.GetSubscriptionId()
+	if subscriptionId == "" {
+		return nil, fmt.Errorf(
+			"the subscription of environment '%s' isn't set, set %s or run %s first",
+			e.env.Name(),
+			environment.SubscriptionIdEnvVarName,
+			output.WithHighLightFormat("azd provision"),
+		)
+	}
+
+	vaultName, err := e.selectVault(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	secretName, create, err := e.selectSecret(ctx, subscriptionId, vaultName, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if create {
+		value, err := e.console.Prompt(ctx, input.ConsoleOptions{
+			Message:    fmt.Sprintf("Enter the value of secret '%s':", secretName),
+			IsPassword: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("prompting for secret value: %w", err)
+		}
+
+		if err := e.keyVaultService.CreateKeyVaultSecret(ctx, subscriptionId, vaultName, secretName, value); err != nil {
+			return nil, err
+		}
+	}
+
+	reference := &keyvault.SecretReference{
+		VaultName:  vaultName,
+		SecretName: secretName,
+	}
+
+	e.env.DotenvSet(key, reference.String())
+	if err := e.envManager.Save(ctx, e.env); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("%s references secret '%s' of key vault '%s'", key, secretName, vaultName),
+		},
+	}, nil
+}
+
+// selectVault returns the key vault specified by --vault, or prompts for a key vault of the subscription
+func (e *envSetSecretAction) selectVault(ctx context.Context, subscriptionId string) (string, error) {
+	if e.flags.vault != "" {
+		return e.flags.vault, nil
+	}
+
+	vaults, err := e.keyVaultService.ListKeyVaults(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	if len(vaults) == 0 {
+		return "", fmt.Errorf("no key vault was found in subscription '%s'", subscriptionId)
+	}
+
+	vaultNames := make([]string, 0, len(vaults))
+	for _, vault := range vaults {
+		vaultNames = append(vaultNames, vault.Name)
+	}
+	slices.Sort(vaultNames)
+
+	selected, err := e.console.Select(ctx, input.ConsoleOptions{
+		Message: "Select the key vault storing the secret:",
+		Options: vaultNames,
+	})
+	if err != nil {
+		return "", fmt.Errorf("selecting key vault: %w", err)
+	}
+
+	return vaultNames[selected], nil
+}
+
+// selectSecret returns the secret specified by --secret, or prompts to select a secret of the key vault or to create a
+// new secret. create is true when the secret doesn't exist yet.
+func (e *envSetSecretAction) selectSecret(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	key string,
+) (secretName string, create bool, err error) {
+	secretNames, err := e.keyVaultService.ListKeyVaultSecrets(ctx, subscriptionId, vaultName)
+	if err != nil {
+		return "", false, err
+	}
+
+	if e.flags.secret != "" {
+		return e.flags.secret, !containsSecretName(secretNames, e.flags.secret), nil
+	}
+
+	slices.Sort(secretNames)
+
+	const createSecretOption = "Create a new secret"
+	selected, err := e.console.Select(ctx, input.ConsoleOptions{
+		Message: "Select the secret:",
+		Options: append([]string{createSecretOption}, secretNames...),
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("selecting secret: %w", err)
+	}
+
+	if selected > 0 {
+		return secretNames[selected-1], false, nil
+	}
+
+	secretName, err = e.console.Prompt(ctx, input.ConsoleOptions{
+		Message:      "Enter the name of the secret:",
+		DefaultValue: keyvault.SecretNameFromKey(key),
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("prompting for secret name: %w", err)
+	}
+
+	return secretName, !containsSecretName(secretNames, secretName), nil
+}
+
+// containsSecretName returns true when the names contain the name of the secret. The names of the Key Vault secrets are
+// case insensitive.
+func containsSecretName(secretNames []string, secretName string) bool {
+	return slices.ContainsFunc(secretNames, func(name string) bool {
+		return strings.EqualFold(name, secretName)
+	})
+}
+
 func newEnvSelectCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "select <environment>",
@@ -542,12 +811,13 @@ func (eg *envGetValuesFlags) Bind(local *pflag.FlagSet, global *internal.GlobalC
 }
 
 type envGetValuesAction struct {
-	azdCtx     *azdcontext.AzdContext
-	console    input.Console
-	envManager environment.Manager
-	formatter  output.Formatter
-	writer     io.Writer
-	flags      *envGetValuesFlags
+	azdCtx         *azdcontext.AzdContext
+	console        input.Console
+	envManager     environment.Manager
+	formatter      output.Formatter
+	writer         io.Writer
+	flags          *envGetValuesFlags
+	secretResolver environment.SecretResolver
 }
 
 func newEnvGetValuesAction(
@@ -557,14 +827,16 @@ func newEnvGetValuesAction(
 	formatter output.Formatter,
 	writer io.Writer,
 	flags *envGetValuesFlags,
+	secretResolver environment.SecretResolver,
 ) actions.Action {
 	return &envGetValuesAction{
-		azdCtx:     azdCtx,
-		console:    console,
-		envManager: envManager,
-		formatter:  formatter,
-		writer:     writer,
-		flags:      flags,
+		azdCtx:         azdCtx,
+		console:        console,
+		envManager:     envManager,
+		formatter:      formatter,
+		writer:         writer,
+		flags:          flags,
+		secretResolver: secretResolver,
 	}
 }
 
@@ -589,21 +861,820 @@ func (eg *envGetValuesAction) Run(ctx context.Context) (*actions.ActionResult, e
 		return nil, fmt.Errorf("ensuring environment exists: %w", err)
 	}
 
-	return nil, eg.formatter.Format(env.Dotenv(), eg.writer, nil)
+	// The values referencing secrets are output with the values of the secrets
+	env.SetSecretResolver(eg.secretResolver)
+	values, err := env.ResolvedDotenv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, eg.formatter.Format(values, eg.writer, nil)
 }
 
-func getCmdEnvHelpDescription(*cobra.Command) string {
-	return generateCmdHelpDescription(
-		"Manage your application environments. With this command group, you can create a new environment or get, set,"+
-			" and list your application environments.",
-		[]string{
-			formatHelpNote("An Application can have multiple environments (ex: dev, test, prod)."),
-			formatHelpNote("Each environment may have a different configuration (that is, connectivity information)" +
-				" for accessing Azure resources."),
-			formatHelpNote(fmt.Sprintf("You can find all environment configuration under the %s folder.",
-				output.WithLinkFormat(".azure/<environment-name>"))),
-			formatHelpNote(fmt.Sprintf("The environment name is stored as the %s environment variable in the %s file.",
-				output.WithHighLightFormat("AZURE_ENV_NAME"),
-				output.WithLinkFormat(".azure/<environment-name>/.env"))),
+type envNameFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envNameFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvNameFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envNameFlags {
+	flags := &envNameFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+// environmentName returns the name of the environment specified by the --environment flag, or the default environment
+func (f *envNameFlags) environmentName(azdCtx *azdcontext.AzdContext) (string, error) {
+	if f.EnvironmentName != "" {
+		return f.EnvironmentName, nil
+	}
+
+	name, err := azdCtx.GetDefaultEnvironmentName()
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		return "", environment.ErrNameNotSpecified
+	}
+
+	return name, nil
+}
+
+func newEnvLockStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether the remote state of an environment is locked, and by whom.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envLockStatusAction struct {
+	azdCtx     *azdcontext.AzdContext
+	envManager environment.Manager
+	console    input.Console
+	formatter  output.Formatter
+	writer     io.Writer
+	flags      *envNameFlags
+}
+
+func newEnvLockStatusAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envNameFlags,
+) actions.Action {
+	return &envLockStatusAction{
+		azdCtx:     azdCtx,
+		envManager: envManager,
+		console:    console,
+		formatter:  formatter,
+		writer:     writer,
+		flags:      flags,
+	}
+}
+
+func (a *envLockStatusAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name, err := a.flags.environmentName(a.azdCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	lockInfo, err := a.envManager.LockStatus(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("getting lock status: %w", err)
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(lockInfo, a.writer, nil)
+	}
+
+	switch {
+	case lockInfo == nil:
+		a.console.Message(ctx, fmt.Sprintf("Environment '%s' is not locked.", name))
+	case lockInfo.IsExpired():
+		a.console.Message(ctx, fmt.Sprintf(
+			"Environment '%s' is not locked. The last lock, held by %s running '%s', expired at %s.",
+			name,
+			lockInfo.Owner,
+			lockInfo.Operation,
+			lockInfo.ExpiresAt.Local().Format(time.RFC1123),
+		))
+	default:
+		a.console.Message(ctx, fmt.Sprintf(
+			"Environment '%s' is locked by %s running '%s' since %s.",
+			output.WithHighLightFormat(name),
+			output.WithHighLightFormat(lockInfo.Owner),
+			lockInfo.Operation,
+			lockInfo.AcquiredAt.Local().Format(time.RFC1123),
+		))
+	}
+
+	return nil, nil
+}
+
+type envLockBreakFlags struct {
+	envNameFlags
+	force bool
+}
+
+func (f *envLockBreakFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.envNameFlags.Bind(local, global)
+	local.BoolVar(&f.force, "force", false, "Breaks the lock without confirmation.")
+}
+
+func newEnvLockBreakFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envLockBreakFlags {
+	flags := &envLockBreakFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newEnvLockBreakCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "break",
+		Short: "Forcibly release the lock of the remote state of an environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envLockBreakAction struct {
+	azdCtx     *azdcontext.AzdContext
+	envManager environment.Manager
+	console    input.Console
+	flags      *envLockBreakFlags
+}
+
+func newEnvLockBreakAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	console input.Console,
+	flags *envLockBreakFlags,
+) actions.Action {
+	return &envLockBreakAction{
+		azdCtx:     azdCtx,
+		envManager: envManager,
+		console:    console,
+		flags:      flags,
+	}
+}
+
+func (a *envLockBreakAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name, err := a.flags.environmentName(a.azdCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	lockInfo, err := a.envManager.LockStatus(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("getting lock status: %w", err)
+	}
+
+	if lockInfo == nil {
+		a.console.Message(ctx, fmt.Sprintf("Environment '%s' is not locked.", name))
+		return nil, nil
+	}
+
+	if !a.flags.force && !lockInfo.IsExpired() {
+		confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf(
+				"Environment '%s' is locked by %s running '%s'. Breaking the lock while the operation is still running "+
+					"may corrupt the environment. Break the lock?",
+				name,
+				lockInfo.Owner,
+				lockInfo.Operation,
+			),
+			DefaultValue: false,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if !confirm {
+			return nil, errors.New("lock was not broken")
+		}
+	}
+
+	if err := a.envManager.BreakLock(ctx, name); err != nil {
+		return nil, fmt.Errorf("breaking lock: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Lock of environment '%s' was broken", name),
+		},
+	}, nil
+}
+
+func newEnvHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "Show the history of the values of an environment.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+// envHistoryEntry is a version of the history of an environment, with the changes from the previous version
+type envHistoryEntry struct {
+	Version   int                  `json:"version"`
+	CreatedAt time.Time            `json:"createdAt"`
+	Owner     string               `json:"owner"`
+	Changes   []environment.Change `json:"changes"`
+}
+
+type envHistoryAction struct {
+	azdCtx     *azdcontext.AzdContext
+	envManager environment.Manager
+	console    input.Console
+	formatter  output.Formatter
+	writer     io.Writer
+	flags      *envNameFlags
+}
+
+func newEnvHistoryAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envNameFlags,
+) actions.Action {
+	return &envHistoryAction{
+		azdCtx:     azdCtx,
+		envManager: envManager,
+		console:    console,
+		formatter:  formatter,
+		writer:     writer,
+		flags:      flags,
+	}
+}
+
+func (a *envHistoryAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name, err := a.flags.environmentName(a.azdCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := a.envManager.History(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("getting history: %w", err)
+	}
+
+	// Latest versions first, each with the changes from the version preceding it
+	entries := []envHistoryEntry{}
+	for i := len(history) - 1; i >= 0; i-- {
+		previous := map[string]string{}
+		if i > 0 {
+			previous = history[i-1].Values
+		}
+
+		entries = append(entries, envHistoryEntry{
+			Version:   history[i].Version,
+			CreatedAt: history[i].CreatedAt,
+			Owner:     history[i].Owner,
+			Changes:   maskedChanges(environment.Diff(previous, history[i].Values)),
+		})
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(entries, a.writer, nil)
+	}
+
+	if len(entries) == 0 {
+		a.console.Message(ctx, fmt.Sprintf("Environment '%s' has no history.", name))
+		return nil, nil
+	}
+
+	for _, entry := range entries {
+		a.console.Message(ctx, fmt.Sprintf(
+			"%s  %s  %s",
+			output.WithHighLightFormat("Version %d", entry.Version),
+			entry.CreatedAt.Local().Format(time.RFC1123),
+			entry.Owner,
+		))
+
+		for _, change := range entry.Changes {
+			a.console.Message(ctx, "  "+change.String())
+		}
+
+		a.console.Message(ctx, "")
+	}
+
+	return nil, nil
+}
+
+func newEnvRestoreCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <version>",
+		Short: "Restore the values of an environment to a version of its history.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type envRestoreFlags struct {
+	internal.EnvFlag
+	force  bool
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envRestoreFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	local.BoolVar(&f.force, "force", false, "Restores the values without confirmation.")
+	f.global = global
+}
+
+func newEnvRestoreFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envRestoreFlags {
+	flags := &envRestoreFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type envRestoreAction struct {
+	env        *environment.Environment
+	envManager environment.Manager
+	console    input.Console
+	flags      *envRestoreFlags
+	args       []string
+}
+
+func newEnvRestoreAction(
+	env *environment.Environment,
+	envManager environment.Manager,
+	console input.Console,
+	flags *envRestoreFlags,
+	args []string,
+) actions.Action {
+	return &envRestoreAction{
+		env:        env,
+		envManager: envManager,
+		console:    console,
+		flags:      flags,
+		args:       args,
+	}
+}
+
+func (a *envRestoreAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	version, err := strconv.Atoi(a.args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid version '%s', expected the number of a version listed by 'azd env history'", a.args[0])
+	}
+
+	history, err := a.envManager.History(ctx, a.env.Name())
+	if err != nil {
+		return nil, fmt.Errorf("getting history: %w", err)
+	}
+
+	index := slices.IndexFunc(history, func(snapshot *environment.Snapshot) bool {
+		return snapshot.Version == version
+	})
+	if index < 0 {
+		return nil, fmt.Errorf(
+			"version %d of environment '%s': %w. Run 'azd env history' to list the available versions",
+			version,
+			a.env.Name(),
+			environment.ErrVersionNotFound,
+		)
+	}
+
+	changes := maskedChanges(environment.Diff(a.env.Dotenv(), history[index].Values))
+	if len(changes) == 0 {
+		a.console.Message(ctx, fmt.Sprintf("Environment '%s' already matches version %d.", a.env.Name(), version))
+		return nil, nil
+	}
+
+	a.console.Message(ctx, fmt.Sprintf("Restoring version %d of environment '%s' changes:", version, a.env.Name()))
+	for _, change := range changes {
+		a.console.Message(ctx, "  "+change.String())
+	}
+	a.console.Message(ctx, "")
+
+	if !a.flags.force {
+		confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+			Message:      "Restore these values?",
+			DefaultValue: false,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if !confirm {
+			return nil, errors.New("environment was not restored")
+		}
+	}
+
+	if _, err := a.envManager.Restore(ctx, a.env, version); err != nil {
+		return nil, fmt.Errorf("restoring environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Environment '%s' was restored to version %d", a.env.Name(), version),
+		},
+	}, nil
+}
+
+func newEnvDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <environment> <environment>",
+		Short: "Compare the values and configuration of two environments.",
+		Args:  cobra.ExactArgs(2),
+	}
+}
+
+// envDiffResult is the difference between two environments
+type envDiffResult struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// The changes of the values of the .env files
+	Values []environment.Change `json:"values"`
+	// The changes of the values of the config.json files, such as the infrastructure parameters
+	Config []environment.Change `json:"config"`
+}
+
+type envDiffAction struct {
+	envManager environment.Manager
+	console    input.Console
+	formatter  output.Formatter
+	writer     io.Writer
+	args       []string
+}
+
+func newEnvDiffAction(
+	envManager environment.Manager,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	args []string,
+) actions.Action {
+	return &envDiffAction{
+		envManager: envManager,
+		console:    console,
+		formatter:  formatter,
+		writer:     writer,
+		args:       args,
+	}
+}
+
+func (a *envDiffAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	envs := make([]*environment.Environment, 0, len(a.args))
+	for _, name := range a.args {
+		env, err := a.envManager.Get(ctx, name)
+		if errors.Is(err, environment.ErrNotFound) {
+			return nil, fmt.Errorf(`environment '%s' does not exist. Run "azd env list" to list the environments`, name)
+		} else if err != nil {
+			return nil, fmt.Errorf("loading environment '%s': %w", name, err)
+		}
+
+		envs = append(envs, env)
+	}
+
+	from, to := envs[0], envs[1]
+	fromValues := from.Dotenv()
+	toValues := to.Dotenv()
+
+	// The name of each environment is part of its values, and always differs
+	delete(fromValues, environment.EnvNameEnvVarName)
+	delete(toValues, environment.EnvNameEnvVarName)
+
+	result := envDiffResult{
+		From:   from.Name(),
+		To:     to.Name(),
+		Values: maskedChanges(environment.Diff(fromValues, toValues)),
+		// The secrets of the configurations, such as secure infrastructure parameters, are masked by DiffConfig
+		Config: environment.DiffConfig(from.Config, to.Config),
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(result, a.writer, nil)
+	}
+
+	if len(result.Values) == 0 && len(result.Config) == 0 {
+		a.console.Message(ctx, fmt.Sprintf(
+			"Environments '%s' and '%s' have the same values and configuration.", result.From, result.To))
+		return nil, nil
+	}
+
+	a.console.Message(ctx, fmt.Sprintf("Changes from environment '%s' to '%s':\n", result.From, result.To))
+
+	sections := []struct {
+		title   string
+		changes []environment.Change
+	}{
+		{title: "Values", changes: result.Values},
+		{title: "Config", changes: result.Config},
+	}
+
+	for _, section := range sections {
+		if len(section.changes) == 0 {
+			continue
+		}
+
+		a.console.Message(ctx, output.WithBold(section.title))
+		for _, change := range section.changes {
+			a.console.Message(ctx, "  "+change.String())
+		}
+		a.console.Message(ctx, "")
+	}
+
+	return nil, nil
+}
+
+// The environment variable holding the passphrase of the encryption of environment bundles, used instead of prompting
+const envBundlePassphraseEnvVarName = "AZD_ENV_BUNDLE_PASSPHRASE"
+
+func newEnvExportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export <environment>",
+		Short: "Export an environment to a portable bundle file.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type envExportFlags struct {
+	file    string
+	from    string
+	encrypt bool
+	global  *internal.GlobalCommandOptions
+}
+
+func (f *envExportFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.file, "file", "", "The path of the bundle file to write.")
+	local.StringVar(
+		&f.from,
+		"from",
+		"",
+		"The data store to export the environment from: local or remote. Defaults to the local environment, "+
+			"downloaded from the remote state when missing.",
+	)
+	local.BoolVar(
+		&f.encrypt,
+		"encrypt",
+		false,
+		fmt.Sprintf(
+			"Encrypts the bundle with a passphrase, read from the %s environment variable or prompted.",
+			envBundlePassphraseEnvVarName,
+		),
+	)
+	f.global = global
+}
+
+func newEnvExportFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envExportFlags {
+	flags := &envExportFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type envExportAction struct {
+	envManager environment.Manager
+	console    input.Console
+	flags      *envExportFlags
+	args       []string
+}
+
+func newEnvExportAction(
+	envManager environment.Manager,
+	console input.Console,
+	flags *envExportFlags,
+	args []string,
+) actions.Action {
+	return &envExportAction{
+		envManager: envManager,
+		console:    console,
+		flags:      flags,
+		args:       args,
+	}
+}
+
+func (a *envExportAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.file == "" {
+		return nil, errors.New("the path of the bundle file must be specified with --file")
+	}
+
+	bundle, err := a.envManager.ExportBundle(ctx, a.args[0], environment.DataStoreKind(a.flags.from))
+	if err != nil {
+		return nil, fmt.Errorf("exporting environment: %w", err)
+	}
+
+	if a.flags.encrypt {
+		passphrase, err := bundlePassphrase(ctx, a.console, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := bundle.Encrypt(passphrase); err != nil {
+			return nil, fmt.Errorf("encrypting bundle: %w", err)
+		}
+	} else if bundle.HasSecrets() {
+		a.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The bundle holds secrets in plain text. Use --encrypt to encrypt the bundle with a passphrase.\n"))
+	}
+
+	bundleFile, err := os.OpenFile(a.flags.file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, osutil.PermissionFileOwnerOnly)
+	if err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+	defer bundleFile.Close()
+
+	if err := bundle.Write(bundleFile); err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Environment '%s' was exported to %s", bundle.Name, a.flags.file),
+			FollowUp: fmt.Sprintf(
+				"Import it with %s", output.WithHighLightFormat("azd env import --file %s", a.flags.file)),
+		},
+	}, nil
+}
+
+func newEnvImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import",
+		Short: "Import an environment from a bundle file written by azd env export.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envImportFlags struct {
+	file   string
+	name   string
+	to     string
+	force  bool
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envImportFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringVar(&f.file, "file", "", "The path of the bundle file to read.")
+	local.StringVar(
+		&f.name, "name", "", "The name of the imported environment. Defaults to the name of the exported environment.")
+	local.StringVar(
+		&f.to,
+		"to",
+		"",
+		"The data store to import the environment to: local or remote. Defaults to the local environment, "+
+			"and the remote state when configured.",
+	)
+	local.BoolVar(&f.force, "force", false, "Overwrites the environment when it already exists.")
+	f.global = global
+}
+
+func newEnvImportFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envImportFlags {
+	flags := &envImportFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+type envImportAction struct {
+	envManager environment.Manager
+	console    input.Console
+	flags      *envImportFlags
+}
+
+func newEnvImportAction(
+	envManager environment.Manager,
+	console input.Console,
+	flags *envImportFlags,
+) actions.Action {
+	return &envImportAction{
+		envManager: envManager,
+		console:    console,
+		flags:      flags,
+	}
+}
+
+func (a *envImportAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.file == "" {
+		return nil, errors.New("the path of the bundle file must be specified with --file")
+	}
+
+	bundleFile, err := os.Open(a.flags.file)
+	if err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	}
+	defer bundleFile.Close()
+
+	bundle, err := environment.ReadBundle(bundleFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if bundle.Encryption != nil {
+		passphrase, err := bundlePassphrase(ctx, a.console, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := bundle.Decrypt(passphrase); err != nil {
+			return nil, fmt.Errorf("decrypting bundle: %w", err)
+		}
+	}
+
+	name := a.flags.name
+	if name == "" {
+		name = bundle.Name
+	}
+
+	env, err := a.envManager.ImportBundle(ctx, bundle, name, environment.DataStoreKind(a.flags.to), a.flags.force)
+	if errors.Is(err, environment.ErrExists) {
+		return nil, fmt.Errorf(
+			"environment '%s' already exists. Use --force to overwrite it, or --name to import it with another name",
+			name,
+		)
+	} else if err != nil {
+		return nil, fmt.Errorf("importing environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Environment '%s' was imported", env.Name()),
+			FollowUp: fmt.Sprintf(
+				"Set it as the default environment with %s", output.WithHighLightFormat("azd env select %s", env.Name())),
+		},
+	}, nil
+}
+
+// bundlePassphrase returns the passphrase of the encryption of environment bundles, read from the environment or
+// prompted. When confirm is true, the prompted passphrase must be entered twice.
+func bundlePassphrase(ctx context.Context, console input.Console, confirm bool) (string, error) {
+	if passphrase := os.Getenv(envBundlePassphraseEnvVarName); passphrase != "" {
+		return passphrase, nil
+	}
+
+	passphrase, err := console.Prompt(ctx, input.ConsoleOptions{
+		Message:    "Enter the passphrase of the bundle:",
+		IsPassword: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("prompting for passphrase: %w", err)
+	}
+
+	if passphrase == "" {
+		return "", fmt.Errorf("the passphrase can't be empty, set it with the %s environment variable",
+			envBundlePassphraseEnvVarName)
+	}
+
+	if confirm {
+		confirmation, err := console.Prompt(ctx, input.ConsoleOptions{
+			Message:    "Confirm the passphrase:",
+			IsPassword: true,
+		})
+		if err != nil {
+			return "", fmt.Errorf("prompting for passphrase: %w", err)
+		}
+
+		if confirmation != passphrase {
+			return "", errors.New("the passphrases don't match")
+		}
+	}
+
+	return passphrase, nil
+}
+
+// maskedChanges returns the changes with the values of secrets masked
+func maskedChanges(changes []environment.Change) []environment.Change {
+	masked := make([]environment.Change, 0, len(changes))
+	for _, change := range changes {
+		masked = append(masked, change.Masked())
+	}
+
+	return masked
+}
+
+func getCmdEnvHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Manage your application environments. With this command group, you can create a new environment or get, set,"+
+			" and list your application environments.",
+		[]string{
+			formatHelpNote("An Application can have multiple environments (ex: dev, test, prod)."),
+			formatHelpNote("Each environment may have a different configuration (that is, connectivity information)" +
+				" for accessing Azure resources."),
+			formatHelpNote(fmt.Sprintf("You can find all environment configuration under the %s folder.",
+				output.WithLinkFormat(".azure/<environment-name>"))),
+			formatHelpNote(fmt.Sprintf("The environment name is stored as the %s environment variable in the %s file.",
+				output.WithHighLightFormat("AZURE_ENV_NAME"),
+				output.WithLinkFormat(".azure/<environment-name>/.env"))),
+		})
+}
+
+func getCmdEnvSetSecretHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Set an environment value referencing a secret stored in Azure Key Vault.",
+		[]string{
+			formatHelpNote("The secret is selected from a key vault of the subscription of the environment, or created."),
+			formatHelpNote(fmt.Sprintf("Only the reference to the secret, such as %s, is stored with the environment.",
+				output.WithHighLightFormat("akvs://<vault>/<secret>"))),
+			formatHelpNote(fmt.Sprintf("The value of the secret is resolved when azd runs hooks, provisions and deploys,"+
+				" and by %s.", output.WithHighLightFormat("azd env get-values"))),
 		})
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
@@ -69,14 +70,15 @@ func (f *hooksRunFlags) Bind(local *pflag.FlagSet, global *internal.GlobalComman
 }
 
 type hooksRunAction struct {
-	projectConfig *project.ProjectConfig
-	env           *environment.Environment
-	envManager    environment.Manager
-	importManager *project.ImportManager
-	commandRunner exec.CommandRunner
-	console       input.Console
-	flags         *hooksRunFlags
-	args          []string
+	projectConfig   *project.ProjectConfig
+	env             *environment.Environment
+	envManager      environment.Manager
+	importManager   *project.ImportManager
+	commandRunner   exec.CommandRunner
+	console         input.Console
+	keyVaultService keyvault.KeyVaultService
+	flags           *hooksRunFlags
+	args            []string
 }
 
 func newHooksRunAction(
@@ -86,18 +88,20 @@ func newHooksRunAction(
 	envManager environment.Manager,
 	commandRunner exec.CommandRunner,
 	console input.Console,
+	keyVaultService keyvault.KeyVaultService,
 	flags *hooksRunFlags,
 	args []string,
 ) actions.Action {
 	return &hooksRunAction{
-		projectConfig: projectConfig,
-		env:           env,
-		envManager:    envManager,
-		commandRunner: commandRunner,
-		console:       console,
-		flags:         flags,
-		args:          args,
-		importManager: importManager,
+		projectConfig:   projectConfig,
+		env:             env,
+		envManager:      envManager,
+		commandRunner:   commandRunner,
+		console:         console,
+		keyVaultService: keyVaultService,
+		flags:           flags,
+		args:            args,
+		importManager:   importManager,
 	}
 }
 
@@ -222,7 +226,8 @@ func (hra *hooksRunAction) execHook(
 	}
 
 	hooksManager := ext.NewHooksManager(cwd)
-	hooksRunner := ext.NewHooksRunner(hooksManager, hra.commandRunner, hra.envManager, hra.console, cwd, hooks, hra.env)
+	hooksRunner := ext.NewHooksRunner(
+		hooksManager, hra.commandRunner, hra.envManager, hra.console, cwd, hooks, hra.env, hra.keyVaultService)
 
 	previewer := hra.console.ShowPreviewer(ctx, &input.ShowPreviewerOptions{
 		Prefix:       "  ",
@@ -237,6 +242,10 @@ func (hra *hooksRunAction) execHook(
 		return err
 	}
 
+	// The outputs of the hook are displayed once the previewer is stopped
+	hra.console.StopPreviewer(ctx, false)
+	hooksRunner.DisplayOutputs(ctx)
+
 	return nil
 }
 
@@ -27,6 +27,7 @@ func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 		}).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	group.
@@ -37,6 +38,7 @@ func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
 			DefaultFormat:  output.NoneFormat,
 		}).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	group.
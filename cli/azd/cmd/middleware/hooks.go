@@ -10,6 +10,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 )
@@ -21,6 +22,7 @@ type HooksMiddleware struct {
 	importManager     *project.ImportManager
 	commandRunner     exec.CommandRunner
 	console           input.Console
+	keyVaultService   keyvault.KeyVaultService
 	options           *Options
 }
 
@@ -32,6 +34,7 @@ func NewHooksMiddleware(
 	importManager *project.ImportManager,
 	commandRunner exec.CommandRunner,
 	console input.Console,
+	keyVaultService keyvault.KeyVaultService,
 	options *Options,
 ) Middleware {
 	return &HooksMiddleware{
@@ -41,6 +44,7 @@ func NewHooksMiddleware(
 		importManager:     importManager,
 		commandRunner:     commandRunner,
 		console:           console,
+		keyVaultService:   keyVaultService,
 		options:           options,
 	}
 }
@@ -95,6 +99,7 @@ func (m *HooksMiddleware) registerCommandHooks(
 		projectConfig.Path,
 		projectConfig.Hooks,
 		env,
+		m.keyVaultService,
 	)
 
 	var actionResult *actions.ActionResult
@@ -153,6 +158,7 @@ func (m *HooksMiddleware) registerServiceHooks(
 			service.Path(),
 			service.Hooks,
 			env,
+			m.keyVaultService,
 		)
 
 		for hookName := range service.Hooks {
@@ -347,6 +347,7 @@ func runMiddleware(
 		project.NewImportManager(nil),
 		mockContext.CommandRunner,
 		mockContext.Console,
+		nil,
 		runOptions,
 	)
 
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+)
+
+// LockMiddleware holds the lock of the remote state of the environment while state-mutating actions run, such as
+// provision, deploy or down, so concurrent runs against a shared environment fail fast.
+type LockMiddleware struct {
+	lazyEnvManager *lazy.Lazy[environment.Manager]
+	lazyEnv        *lazy.Lazy[*environment.Environment]
+	options        *Options
+}
+
+// Creates a new instance of the Lock middleware
+func NewLockMiddleware(
+	lazyEnvManager *lazy.Lazy[environment.Manager],
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	options *Options,
+) Middleware {
+	return &LockMiddleware{
+		lazyEnvManager: lazyEnvManager,
+		lazyEnv:        lazyEnv,
+		options:        options,
+	}
+}
+
+// Runs the Lock middleware
+func (m *LockMiddleware) Run(ctx context.Context, next NextFn) (*actions.ActionResult, error) {
+	// Composite actions such as 'up' already hold the lock for their child actions
+	if m.options.IsChildAction(ctx) {
+		return next(ctx)
+	}
+
+	env, err := m.lazyEnv.GetValue()
+	if err != nil {
+		log.Println("azd environment is not available, skipping environment lock.")
+		return next(ctx)
+	}
+
+	envManager, err := m.lazyEnvManager.GetValue()
+	if err != nil {
+		return nil, fmt.Errorf("failed getting environment manager, %w", err)
+	}
+
+	lease, err := envManager.Lock(ctx, env.Name(), m.options.CommandPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		// The lock must be released even when the action was canceled
+		if err := lease.Release(context.WithoutCancel(ctx)); err != nil {
+			log.Printf("failed releasing lock of environment '%s': %v", env.Name(), err)
+		}
+	}()
+
+	return next(ctx)
+}
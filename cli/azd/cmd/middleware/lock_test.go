@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LockMiddleware(t *testing.T) {
+	env := environment.NewWithValues("test", nil)
+
+	t.Run("HoldsLock", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Lock", mock.Anything, "test", "provision").Return(&environment.Lease{}, nil)
+
+		middleware := NewLockMiddleware(
+			lazy.From[environment.Manager](envManager),
+			lazy.From(env),
+			&Options{CommandPath: "provision"},
+		)
+		nextFn, actionRan := createNextFn()
+
+		result, err := middleware.Run(*mockContext.Context, nextFn)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		require.True(t, *actionRan)
+		envManager.AssertCalled(t, "Lock", mock.Anything, "test", "provision")
+	})
+
+	t.Run("Locked", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		envManager := &mockenv.MockEnvManager{}
+		envManager.
+			On("Lock", mock.Anything, "test", "provision").
+			Return((*environment.Lease)(nil), fmt.Errorf("%w: locked by someone else", environment.ErrLocked))
+
+		middleware := NewLockMiddleware(
+			lazy.From[environment.Manager](envManager),
+			lazy.From(env),
+			&Options{CommandPath: "provision"},
+		)
+		nextFn, actionRan := createNextFn()
+
+		_, err := middleware.Run(*mockContext.Context, nextFn)
+		require.ErrorIs(t, err, environment.ErrLocked)
+		require.False(t, *actionRan)
+	})
+
+	t.Run("ChildAction", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		envManager := &mockenv.MockEnvManager{}
+
+		middleware := NewLockMiddleware(
+			lazy.From[environment.Manager](envManager),
+			lazy.From(env),
+			&Options{CommandPath: "provision"},
+		)
+		nextFn, actionRan := createNextFn()
+
+		_, err := middleware.Run(WithChildAction(*mockContext.Context), nextFn)
+		require.NoError(t, err)
+		require.True(t, *actionRan)
+		envManager.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/cmd"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -21,7 +23,9 @@ type packageFlags struct {
 	all    bool
 	global *internal.GlobalCommandOptions
 	*internal.EnvFlag
-	outputPath string
+	outputPath  string
+	parallelism int
+	force       bool
 }
 
 func newPackageFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *packageFlags {
@@ -50,6 +54,18 @@ func (pf *packageFlags) Bind(local *pflag.FlagSet, global *internal.GlobalComman
 		"",
 		"File or folder path where the generated packages will be saved.",
 	)
+	local.IntVar(
+		&pf.parallelism,
+		"parallelism",
+		1,
+		"The maximum number of services packaged concurrently. Services are packaged after the services they depend on.",
+	)
+	local.BoolVar(
+		&pf.force,
+		"force",
+		false,
+		"Packages the services even when they didn't change since they were last packaged.",
+	)
 }
 
 func newPackageCmd() *cobra.Command {
@@ -71,6 +87,7 @@ type packageAction struct {
 	projectManager project.ProjectManager
 	importManager  *project.ImportManager
 	serviceManager project.ServiceManager
+	buildCache     *project.BuildCache
 	console        input.Console
 	formatter      output.Formatter
 	writer         io.Writer
@@ -82,6 +99,7 @@ func newPackageAction(
 	projectConfig *project.ProjectConfig,
 	projectManager project.ProjectManager,
 	serviceManager project.ServiceManager,
+	buildCache *project.BuildCache,
 	console input.Console,
 	formatter output.Formatter,
 	writer io.Writer,
@@ -93,6 +111,7 @@ func newPackageAction(
 		projectConfig:  projectConfig,
 		projectManager: projectManager,
 		serviceManager: serviceManager,
+		buildCache:     buildCache,
 		console:        console,
 		formatter:      formatter,
 		writer:         writer,
@@ -141,14 +160,14 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		return nil, err
 	}
 
-	packageResults := map[string]*project.ServicePackageResult{}
-
 	serviceTable, err := pa.importManager.ServiceStable(ctx, pa.projectConfig)
 	if err != nil {
 		return nil, err
 	}
-	serviceCount := len(serviceTable)
-	for index, svc := range serviceTable {
+
+	targetServices := []*project.ServiceConfig{}
+	skippedServices := []string{}
+	for _, svc := range serviceTable {
 		// TODO(ellismg): We need to figure out what packaging an containerized dotnet app means. For now, just skip it.
 		//  We "package" the app during deploy when we call `dotnet publish /p:PublishProfile=DefaultContainer` to build
 		//  and push the container image.
@@ -183,24 +202,52 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 			continue
 		}
 
-		stepMessage := fmt.Sprintf("Packaging service %s", svc.Name)
-		pa.console.ShowSpinner(ctx, stepMessage, input.Step)
-
-		// Skip this service if both cases are true:
-		// 1. The user specified a service name
-		// 2. This service is not the one the user specified
+		// Package all the services, unless the user specified a service name
 		if targetServiceName != "" && targetServiceName != svc.Name {
-			pa.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			skippedServices = append(skippedServices, svc.Name)
 			continue
 		}
 
+		targetServices = append(targetServices, svc)
+	}
+
+	serviceGraph, err := project.NewServiceGraph(targetServices)
+	if err != nil {
+		return nil, err
+	}
+
+	if pa.flags.force {
+		for _, svc := range targetServices {
+			err := pa.buildCache.Invalidate(svc.Name, project.ServiceEventBuild, project.ServiceEventPackage)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	progress := cmd.NewServiceProgress(pa.console, targetServices, cmd.ServiceProgressOptions{
+		Verb:            "Packaging",
+		Parallelism:     pa.flags.parallelism,
+		SeparateReports: true,
+	})
+
+	for _, serviceName := range skippedServices {
+		progress.Skip(ctx, serviceName)
+	}
+
+	packageResultsMutex := sync.Mutex{}
+	packageResults := map[string]*project.ServicePackageResult{}
+
+	progress.Start(ctx)
+	err = serviceGraph.Run(ctx, pa.flags.parallelism, func(ctx context.Context, svc *project.ServiceConfig) error {
+		progress.Begin(ctx, svc)
+
 		options := &project.PackageOptions{OutputPath: pa.flags.outputPath}
 		packageTask := pa.serviceManager.Package(ctx, svc, nil, options)
 		done := make(chan struct{})
 		go func() {
 			for packageProgress := range packageTask.Progress() {
-				progressMessage := fmt.Sprintf("Packaging service %s (%s)", svc.Name, packageProgress.Message)
-				pa.console.ShowSpinner(ctx, progressMessage, input.Step)
+				progress.Progress(ctx, svc, packageProgress.Message)
 			}
 			close(done)
 		}()
@@ -208,18 +255,24 @@ func (pa *packageAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		packageResult, err := packageTask.Await()
 		// adding a few seconds to wait for all async ops to be flush
 		<-done
-		pa.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		progress.End(ctx, svc, err)
 
 		if err != nil {
-			return nil, err
+			return err
 		}
+
+		packageResultsMutex.Lock()
 		packageResults[svc.Name] = packageResult
+		packageResultsMutex.Unlock()
 
 		// report package output
-		pa.console.MessageUxItem(ctx, packageResult)
-		if index < serviceCount-1 {
-			pa.console.Message(ctx, "")
-		}
+		progress.Report(ctx, packageResult)
+		return nil
+	})
+	progress.Stop(ctx)
+
+	if err != nil {
+		return nil, err
 	}
 
 	if pa.formatter.Kind() == output.JsonFormat {
@@ -251,6 +304,9 @@ func getCmdPackageHelpDescription(*cobra.Command) string {
 		formatHelpNote(
 			fmt.Sprintf("When %s is set, only the specific service is packaged.", output.WithHighLightFormat("<service>"))),
 		formatHelpNote("After the packaging is complete, the package locations are printed."),
+		formatHelpNote(fmt.Sprintf(
+			"Services which didn't change since they were last packaged are skipped, unless %s is set.",
+			output.WithHighLightFormat("--force"))),
 	})
 }
 
@@ -0,0 +1,285 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type rollbackFlags struct {
+	all    bool
+	global *internal.GlobalCommandOptions
+	*internal.EnvFlag
+}
+
+func newRollbackFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *rollbackFlags {
+	flags := &rollbackFlags{
+		EnvFlag: &internal.EnvFlag{},
+	}
+
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func (rf *rollbackFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	rf.EnvFlag.Bind(local, global)
+	rf.global = global
+
+	local.BoolVar(
+		&rf.all,
+		"all",
+		false,
+		"Rolls back all services that are listed in "+azdcontext.ProjectFileName,
+	)
+}
+
+func newRollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback <service>",
+		Short: "Roll back services to their previous deployment.",
+	}
+	cmd.Args = cobra.MaximumNArgs(1)
+	return cmd
+}
+
+type rollbackAction struct {
+	flags             *rollbackFlags
+	args              []string
+	projectConfig     *project.ProjectConfig
+	projectManager    project.ProjectManager
+	importManager     *project.ImportManager
+	serviceManager    project.ServiceManager
+	buildCache        *project.BuildCache
+	deploymentHistory *project.DeploymentHistory
+	env               *environment.Environment
+	console           input.Console
+	formatter         output.Formatter
+	writer            io.Writer
+}
+
+func newRollbackAction(
+	flags *rollbackFlags,
+	args []string,
+	projectConfig *project.ProjectConfig,
+	projectManager project.ProjectManager,
+	importManager *project.ImportManager,
+	serviceManager project.ServiceManager,
+	buildCache *project.BuildCache,
+	deploymentHistory *project.DeploymentHistory,
+	env *environment.Environment,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+) actions.Action {
+	return &rollbackAction{
+		flags:             flags,
+		args:              args,
+		projectConfig:     projectConfig,
+		projectManager:    projectManager,
+		importManager:     importManager,
+		serviceManager:    serviceManager,
+		buildCache:        buildCache,
+		deploymentHistory: deploymentHistory,
+		env:               env,
+		console:           console,
+		formatter:         formatter,
+		writer:            writer,
+	}
+}
+
+type RollbackResult struct {
+	Timestamp time.Time                               `json:"timestamp"`
+	Services  map[string]*project.ServiceDeployResult `json:"services"`
+}
+
+func (ra *rollbackAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	// Command title
+	ra.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Rolling back services (azd rollback)",
+	})
+
+	startTime := time.Now()
+
+	if ra.env.GetSubscriptionId() == "" {
+		return nil, errors.New(
+			"infrastructure has not been provisioned. Run `azd provision`",
+		)
+	}
+
+	targetServiceName := ""
+	if len(ra.args) == 1 {
+		targetServiceName = ra.args[0]
+	}
+
+	targetServiceName, err := getTargetServiceName(
+		ctx,
+		ra.projectManager,
+		ra.importManager,
+		ra.projectConfig,
+		"rollback",
+		targetServiceName,
+		ra.flags.all,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ra.projectManager.Initialize(ctx, ra.projectConfig); err != nil {
+		return nil, err
+	}
+
+	if err := ra.projectManager.EnsureServiceTargetTools(ctx, ra.projectConfig, func(svc *project.ServiceConfig) bool {
+		return targetServiceName == "" || svc.Name == targetServiceName
+	}); err != nil {
+		return nil, err
+	}
+
+	stableServices, err := ra.importManager.ServiceStable(ctx, ra.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	rollbackResults := map[string]*project.ServiceDeployResult{}
+	for _, svc := range stableServices {
+		stepMessage := fmt.Sprintf("Rolling back service %s", svc.Name)
+		ra.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		// Skip this service if both cases are true:
+		// 1. The user specified a service name
+		// 2. This service is not the one the user specified
+		if targetServiceName != "" && targetServiceName != svc.Name {
+			ra.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			continue
+		}
+
+		record, err := ra.deploymentHistory.Previous(svc.Name)
+		if errors.Is(err, project.ErrNoPreviousDeployment) && targetServiceName == "" {
+			// Services which were deployed at most once are skipped when rolling back all the services
+			ra.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			continue
+		} else if err != nil {
+			ra.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			return nil, fmt.Errorf("rolling back service '%s': %w", svc.Name, err)
+		}
+
+		rollbackResult, err := ra.rollbackService(ctx, svc, record, stepMessage)
+		ra.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+
+		rollbackResults[svc.Name] = rollbackResult
+
+		// report rollback outputs
+		ra.console.MessageUxItem(ctx, rollbackResult)
+	}
+
+	if ra.formatter.Kind() == output.JsonFormat {
+		rollbackResult := RollbackResult{
+			Timestamp: time.Now(),
+			Services:  rollbackResults,
+		}
+
+		if fmtErr := ra.formatter.Format(rollbackResult, ra.writer, nil); fmtErr != nil {
+			return nil, fmt.Errorf("rollback result could not be displayed: %w", fmtErr)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Your application was rolled back in %s.", ux.DurationAsText(since(startTime))),
+		},
+	}, nil
+}
+
+// rollbackService deploys the artifact of the previous deployment of the service again, without building it
+func (ra *rollbackAction) rollbackService(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	record *project.DeploymentRecord,
+	stepMessage string,
+) (*project.ServiceDeployResult, error) {
+	if record.Kind != svc.Host {
+		return nil, fmt.Errorf(
+			"the previous deployment of service '%s' targeted the '%s' host, but the service now uses the '%s' host",
+			svc.Name,
+			record.Kind,
+			svc.Host,
+		)
+	}
+
+	packageResult, err := record.PackageResult()
+	if err != nil {
+		return nil, err
+	}
+
+	// The deployment doesn't match the content of the service, so it must not be skipped, and neither should the
+	// next deployment
+	if err := ra.buildCache.Invalidate(svc.Name, project.ServiceEventDeploy); err != nil {
+		return nil, err
+	}
+
+	deployTask := ra.serviceManager.Deploy(ctx, svc, packageResult)
+	done := make(chan struct{})
+	go func() {
+		for deployProgress := range deployTask.Progress() {
+			progressMessage := fmt.Sprintf("%s (%s)", stepMessage, deployProgress.Message)
+			ra.console.ShowSpinner(ctx, progressMessage, input.Step)
+		}
+		close(done)
+	}()
+
+	deployResult, err := deployTask.Await()
+	// wait for console updates to complete
+	<-done
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ra.buildCache.Invalidate(svc.Name, project.ServiceEventDeploy); err != nil {
+		return nil, err
+	}
+
+	// The previous deployment becomes the last deployment, so rolling back again goes further back
+	if err := ra.deploymentHistory.RemoveLast(svc.Name); err != nil {
+		return nil, err
+	}
+
+	return deployResult, nil
+}
+
+func getCmdRollbackHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription("Roll back services to their previous deployment.", []string{
+		formatHelpNote(
+			"By default, rolls back all services listed in 'azure.yaml' in the current directory," +
+				" or the service described in the project that matches the current directory."),
+		formatHelpNote(
+			fmt.Sprintf("When %s is set, only the specific service is rolled back.",
+				output.WithHighLightFormat("<service>"))),
+		formatHelpNote("The artifact of the previous deployment, such as the container image or the zip package," +
+			" is deployed again without building the service."),
+		formatHelpNote("Rolling back again goes back to the deployment before, azd records the last 5 deployments of" +
+			" each service."),
+	})
+}
+
+func getCmdRollbackHelpFooter(*cobra.Command) string {
+	return generateCmdHelpSamplesBlock(map[string]string{
+		"Roll back all services in the current project.": output.WithHighLightFormat("azd rollback --all"),
+		"Roll back the service named 'api'.":             output.WithHighLightFormat("azd rollback api"),
+	})
+}
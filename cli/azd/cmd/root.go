@@ -233,6 +233,11 @@ func NewRootCmd(
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddlewareWhen("lock", middleware.NewLockMiddleware, func(descriptor *actions.ActionDescriptor) bool {
+			// Previews don't modify the environment
+			onPreview, _ := descriptor.Options.Command.Flags().GetBool("preview")
+			return !onPreview
+		}).
 		UseMiddlewareWhen("hooks", middleware.NewHooksMiddleware, func(descriptor *actions.ActionDescriptor) bool {
 			if onPreview, _ := descriptor.Options.Command.Flags().GetBool("preview"); onPreview {
 				log.Println("Skipping provision hooks due to preview flag.")
@@ -273,6 +278,25 @@ func NewRootCmd(
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
+		UseMiddleware("hooks", middleware.NewHooksMiddleware)
+
+	root.
+		Add("rollback", &actions.ActionDescriptorOptions{
+			Command:        newRollbackCmd(),
+			FlagsResolver:  newRollbackFlags,
+			ActionResolver: newRollbackAction,
+			OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+			DefaultFormat:  output.NoneFormat,
+			HelpOptions: actions.ActionHelpOptions{
+				Description: getCmdRollbackHelpDescription,
+				Footer:      getCmdRollbackHelpFooter,
+			},
+			GroupingOptions: actions.CommandGroupOptions{
+				RootLevelHelp: actions.CmdGroupManage,
+			},
+		}).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.
@@ -289,6 +313,7 @@ func NewRootCmd(
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	root.Add("monitor", &actions.ActionDescriptorOptions{
@@ -319,6 +344,7 @@ func NewRootCmd(
 				RootLevelHelp: actions.CmdGroupManage,
 			},
 		}).
+		UseMiddleware("lock", middleware.NewLockMiddleware).
 		UseMiddleware("hooks", middleware.NewHooksMiddleware)
 
 	// Register any global middleware defined by the caller
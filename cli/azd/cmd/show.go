@@ -285,6 +285,8 @@ func showTypeFromLanguage(language project.ServiceLanguageKind) contracts.ShowTy
 		return contracts.ShowTypeNode
 	case project.ServiceLanguageJava:
 		return contracts.ShowTypeJava
+	case project.ServiceLanguageGo:
+		return contracts.ShowTypeGo
 	default:
 		panic(fmt.Sprintf("unknown language %s", language))
 	}
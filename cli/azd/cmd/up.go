@@ -160,7 +160,9 @@ func getCmdUpHelpDescription(c *cobra.Command) string {
 			    - azd: deploy --all
 			-------------------------
 
-			Any azd command and flags are supported in the workflow steps.`,
+			Any azd command and flags are supported in the workflow steps. Steps can also %s shell scripts,
+			be skipped unless their %s condition on environment values is true, set %s
+			and run in %s groups.`,
 			output.WithHighLightFormat("package"),
 			output.WithHighLightFormat("provision"),
 			output.WithHighLightFormat("deploy"),
@@ -168,6 +170,10 @@ func getCmdUpHelpDescription(c *cobra.Command) string {
 			output.WithHighLightFormat("workflows"),
 			output.WithHighLightFormat("azure.yaml"),
 			output.WithGrayFormat("# azure.yaml"),
+			output.WithHighLightFormat("run"),
+			output.WithHighLightFormat("if"),
+			output.WithHighLightFormat("continueOnError"),
+			output.WithHighLightFormat("parallel"),
 		),
 		nil,
 	)
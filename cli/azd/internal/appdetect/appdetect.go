@@ -26,6 +26,7 @@ const (
 	JavaScript    Language = "js"
 	TypeScript    Language = "ts"
 	Python        Language = "python"
+	Go            Language = "go"
 )
 
 func (pt Language) Display() string {
@@ -42,6 +43,8 @@ func (pt Language) Display() string {
 		return "TypeScript"
 	case Python:
 		return "Python"
+	case Go:
+		return "Go"
 	}
 
 	return ""
@@ -58,6 +61,10 @@ const (
 	PyFlask   Dependency = "flask"
 	PyDjango  Dependency = "django"
 	PyFastApi Dependency = "fastapi"
+
+	GoGin  Dependency = "gin"
+	GoEcho Dependency = "echo"
+	GoChi  Dependency = "chi"
 )
 
 var WebUIFrameworks = map[Dependency]struct{}{
@@ -180,6 +187,7 @@ var allDetectors = []projectDetector{
 	},
 	&pythonDetector{},
 	&javaScriptDetector{},
+	&goDetector{},
 }
 
 // Detect detects projects located under a directory.
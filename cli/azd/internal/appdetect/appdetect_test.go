@@ -85,6 +85,29 @@ func TestDetect(t *testing.T) {
 						DbRedis,
 					},
 				},
+				{
+					Language:      Python,
+					Path:          "python-poetry",
+					DetectionRule: "Inferred by presence of: pyproject.toml",
+					Dependencies: []Dependency{
+						PyFlask,
+					},
+					DatabaseDeps: []DatabaseDep{
+						DbPostgres,
+						DbRedis,
+					},
+				},
+				{
+					Language:      Python,
+					Path:          "python-uv",
+					DetectionRule: "Inferred by presence of: pyproject.toml",
+					Dependencies: []Dependency{
+						PyFastApi,
+					},
+					DatabaseDeps: []DatabaseDep{
+						DbMongo,
+					},
+				},
 				{
 					Language:      TypeScript,
 					Path:          "typescript",
@@ -157,6 +180,29 @@ func TestDetect(t *testing.T) {
 					Path:          "python",
 					DetectionRule: "Inferred by presence of: requirements.txt",
 				},
+				{
+					Language:      Python,
+					Path:          "python-poetry",
+					DetectionRule: "Inferred by presence of: pyproject.toml",
+					Dependencies: []Dependency{
+						PyFlask,
+					},
+					DatabaseDeps: []DatabaseDep{
+						DbPostgres,
+						DbRedis,
+					},
+				},
+				{
+					Language:      Python,
+					Path:          "python-uv",
+					DetectionRule: "Inferred by presence of: pyproject.toml",
+					Dependencies: []Dependency{
+						PyFastApi,
+					},
+					DatabaseDeps: []DatabaseDep{
+						DbMongo,
+					},
+				},
 			},
 		},
 	}
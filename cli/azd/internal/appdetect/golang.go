@@ -0,0 +1,160 @@
+package appdetect
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+type goDetector struct {
+}
+
+func (gd *goDetector) Language() Language {
+	return Go
+}
+
+func (gd *goDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	for _, entry := range entries {
+		if entry.Name() == "go.mod" {
+			project := &Project{
+				Language:      Go,
+				Path:          path,
+				DetectionRule: "Inferred by presence of: " + entry.Name(),
+			}
+
+			goMod, err := readGoMod(filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			databaseDepMap := map[DatabaseDep]struct{}{}
+			for _, module := range goMod.requires {
+				switch module {
+				case "github.com/gin-gonic/gin":
+					project.Dependencies = append(project.Dependencies, GoGin)
+				case "github.com/labstack/echo":
+					project.Dependencies = append(project.Dependencies, GoEcho)
+				case "github.com/go-chi/chi":
+					project.Dependencies = append(project.Dependencies, GoChi)
+				}
+
+				switch module {
+				case "github.com/go-sql-driver/mysql":
+					databaseDepMap[DbMySql] = struct{}{}
+				case "github.com/jackc/pgx",
+					"github.com/lib/pq":
+					databaseDepMap[DbPostgres] = struct{}{}
+				case "go.mongodb.org/mongo-driver":
+					databaseDepMap[DbMongo] = struct{}{}
+				case "github.com/redis/go-redis",
+					"github.com/go-redis/redis":
+					databaseDepMap[DbRedis] = struct{}{}
+				case "github.com/microsoft/go-mssqldb",
+					"github.com/denisenkom/go-mssqldb":
+					databaseDepMap[DbSqlServer] = struct{}{}
+				}
+			}
+
+			if len(databaseDepMap) > 0 {
+				project.DatabaseDeps = maps.Keys(databaseDepMap)
+				slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+					return string(a) < string(b)
+				})
+			}
+
+			slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
+				return string(a) < string(b)
+			})
+
+			return project, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GoVersion returns the Go language version declared by the go directive of the go.mod file in the project directory,
+// for example "1.22". An empty string is returned if the go.mod file does not declare a version.
+func GoVersion(projectPath string) (string, error) {
+	goMod, err := readGoMod(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+
+	return goMod.goVersion, nil
+}
+
+type goModFile struct {
+	goVersion string
+	// Direct module requirements, without the major version suffix, e.g. github.com/labstack/echo
+	requires []string
+}
+
+// Matches a major version suffix of a module path, e.g. the /v5 in github.com/jackc/pgx/v5
+var goMajorVersionSuffix = regexp.MustCompile(`/v[0-9]+$`)
+
+// readGoMod reads the go directive and the direct requirements of a go.mod file.
+// Requirements marked as indirect are ignored since they are not used by the module itself.
+func readGoMod(path string) (*goModFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	goMod := &goModFile{}
+	inRequireBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasSuffix(line, "// indirect") {
+			continue
+		}
+
+		// strip trailing comments
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if inRequireBlock {
+			if fields[0] == ")" {
+				inRequireBlock = false
+				continue
+			}
+
+			goMod.requires = append(goMod.requires, goMajorVersionSuffix.ReplaceAllString(fields[0], ""))
+			continue
+		}
+
+		switch fields[0] {
+		case "go":
+			if len(fields) > 1 {
+				goMod.goVersion = fields[1]
+			}
+		case "require":
+			if len(fields) > 1 && fields[1] == "(" {
+				inRequireBlock = true
+			} else if len(fields) > 1 {
+				goMod.requires = append(goMod.requires, goMajorVersionSuffix.ReplaceAllString(fields[1], ""))
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return goMod, nil
+}
@@ -0,0 +1,50 @@
+package appdetect
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go.mod files can't be embedded as test data since a directory containing a go.mod file is a separate module.
+const testGoMod = `module example.com/api
+
+go 1.22
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	github.com/go-chi/chi/v5 v5.0.12 // router
+	github.com/jackc/pgx/v5 v5.5.5
+	github.com/redis/go-redis/v9 v9.5.1
+	go.mongodb.org/mongo-driver v1.14.0
+	github.com/labstack/echo/v4 v4.11.4 // indirect
+)
+
+require github.com/go-sql-driver/mysql v1.8.0
+`
+
+func TestDetectGo(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(testGoMod), 0600)
+	require.NoError(t, err)
+
+	project, err := DetectDirectory(context.Background(), dir)
+	require.NoError(t, err)
+	require.NotNil(t, project)
+
+	require.Equal(t, Go, project.Language)
+	require.Equal(t, "Inferred by presence of: go.mod", project.DetectionRule)
+	require.Equal(t, []Dependency{GoChi, GoGin}, project.Dependencies)
+	require.Equal(t, []DatabaseDep{DbMongo, DbMySql, DbPostgres, DbRedis}, project.DatabaseDeps)
+
+	version, err := GoVersion(dir)
+	require.NoError(t, err)
+	require.Equal(t, "1.22", version)
+
+	project, err = DetectDirectory(context.Background(), dir, WithoutGo())
+	require.NoError(t, err)
+	require.Nil(t, project)
+}
@@ -0,0 +1,152 @@
+package appdetect
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// PyTool is a tool that manages the dependencies of a Python project.
+type PyTool string
+
+const (
+	PyToolPip    PyTool = "pip"
+	PyToolPoetry PyTool = "poetry"
+	PyToolUv     PyTool = "uv"
+)
+
+// PyProjectTool returns the tool used to manage the dependencies of the Python project located in projectPath.
+// The tool is inferred from the presence of a lockfile, or from the tool tables declared in pyproject.toml.
+// PyToolPip is returned when no other tool is in use.
+func PyProjectTool(projectPath string) (PyTool, error) {
+	if fileExists(filepath.Join(projectPath, "uv.lock")) {
+		return PyToolUv, nil
+	}
+
+	if fileExists(filepath.Join(projectPath, "poetry.lock")) {
+		return PyToolPoetry, nil
+	}
+
+	pyProject, err := readPyProject(filepath.Join(projectPath, "pyproject.toml"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return PyToolPip, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return pyProject.tool, nil
+}
+
+type pyProjectFile struct {
+	// The names of the declared runtime dependencies
+	dependencies []string
+	// The tool declared by the project
+	tool PyTool
+	// Whether the file declares a project, in the PEP 621 project table or in the table of Poetry, rather than only
+	// holding the configuration of tools
+	declaresProject bool
+}
+
+// pyProjectToml is the subset of a pyproject.toml file read to detect the dependencies of a project
+type pyProjectToml struct {
+	Project struct {
+		// PEP 508 dependency specifiers, e.g. "fastapi[all]>=0.100"
+		Dependencies []string `toml:"dependencies"`
+	} `toml:"project"`
+	Tool struct {
+		Poetry struct {
+			// The constraints of the dependencies by name, either a version or a table
+			Dependencies map[string]any `toml:"dependencies"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+// pyLockToml is the subset of a poetry.lock or uv.lock file read to detect the dependencies of a project
+type pyLockToml struct {
+	Packages []struct {
+		Name string `toml:"name"`
+	} `toml:"package"`
+}
+
+// readPyProject reads the runtime dependencies declared in a pyproject.toml file, either in the PEP 621 project table
+// or in the dependencies table of Poetry.
+func readPyProject(path string) (*pyProjectFile, error) {
+	var content pyProjectToml
+	metadata, err := toml.DecodeFile(path, &content)
+	if err != nil {
+		return nil, err
+	}
+
+	pyProject := &pyProjectFile{
+		tool:            PyToolPip,
+		declaresProject: metadata.IsDefined("project") || metadata.IsDefined("tool", "poetry"),
+	}
+
+	for _, requirement := range content.Project.Dependencies {
+		if name := pyRequirementName(requirement); name != "" {
+			pyProject.dependencies = append(pyProject.dependencies, name)
+		}
+	}
+
+	poetryDependencies := maps.Keys(content.Tool.Poetry.Dependencies)
+	slices.Sort(poetryDependencies)
+	for _, name := range poetryDependencies {
+		// the python version constraint is declared as a dependency by poetry
+		if name = strings.ToLower(name); name != "python" {
+			pyProject.dependencies = append(pyProject.dependencies, name)
+		}
+	}
+
+	if metadata.IsDefined("tool", "uv") {
+		pyProject.tool = PyToolUv
+	} else if metadata.IsDefined("tool", "poetry") {
+		pyProject.tool = PyToolPoetry
+	}
+
+	return pyProject, nil
+}
+
+// readPyLockPackages reads the names of the packages locked in a poetry.lock or uv.lock file.
+func readPyLockPackages(path string) ([]string, error) {
+	var content pyLockToml
+	if _, err := toml.DecodeFile(path, &content); err != nil {
+		return nil, err
+	}
+
+	packages := []string{}
+	for _, lockedPackage := range content.Packages {
+		if lockedPackage.Name != "" {
+			packages = append(packages, strings.ToLower(lockedPackage.Name))
+		}
+	}
+
+	return packages, nil
+}
+
+// pyRequirementName returns the lower-cased name of the package of a PEP 508 requirement specifier, or of a line
+// of a requirements.txt file, e.g. "flask" for "Flask[async]>=2.0 ; python_version > '3.8'".
+// An empty string is returned for lines that don't declare a requirement, such as comments and options.
+func pyRequirementName(requirement string) string {
+	requirement = strings.TrimSpace(requirement)
+	if requirement == "" || strings.HasPrefix(requirement, "#") || strings.HasPrefix(requirement, "-") {
+		return ""
+	}
+
+	end := strings.IndexAny(requirement, " \t<>=!~;[@(,")
+	if end >= 0 {
+		requirement = requirement[:end]
+	}
+
+	return strings.ToLower(requirement)
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
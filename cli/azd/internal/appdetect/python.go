@@ -3,6 +3,7 @@ package appdetect
 import (
 	"bufio"
 	"context"
+	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -20,83 +21,144 @@ func (pd *pythonDetector) Language() Language {
 }
 
 func (pd *pythonDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	requirementsTxt := ""
+	pyProjectToml := ""
+	lockFiles := []string{}
 	for _, entry := range entries {
-		// entry.Name() == "pyproject.toml" when azd supports pyproject files
-		if strings.ToLower(entry.Name()) == "requirements.txt" {
-			project := &Project{
-				Language:      Python,
-				Path:          path,
-				DetectionRule: "Inferred by presence of: " + entry.Name(),
-			}
+		switch strings.ToLower(entry.Name()) {
+		case "requirements.txt":
+			requirementsTxt = entry.Name()
+		case "pyproject.toml":
+			pyProjectToml = entry.Name()
+		case "poetry.lock", "uv.lock":
+			lockFiles = append(lockFiles, entry.Name())
+		}
+	}
 
-			file, err := os.Open(filepath.Join(path, entry.Name()))
-			if err != nil {
-				return nil, err
-			}
+	// pyproject.toml files holding only the configuration of tools, such as ruff or black, don't declare a project
+	var pyProject *pyProjectFile
+	if pyProjectToml != "" {
+		var err error
+		pyProject, err = readPyProject(filepath.Join(path, pyProjectToml))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", pyProjectToml, err)
+		}
 
-			scanner := bufio.NewScanner(file)
-			databaseDepMap := map[DatabaseDep]struct{}{}
+		if !pyProject.declaresProject {
+			pyProject = nil
+		}
+	}
 
-			for scanner.Scan() {
-				split := strings.Split(scanner.Text(), "==")
-				if len(split) < 1 {
-					continue
-				}
+	if requirementsTxt == "" && pyProject == nil {
+		return nil, nil
+	}
 
-				// pip is case insensitive: PEP 426
-				// https://peps.python.org/pep-0426/#name
-				module := strings.ToLower(strings.TrimSpace(split[0]))
-				switch module {
-				case "fastapi":
-					project.Dependencies = append(project.Dependencies, PyFastApi)
-				case "flask":
-					project.Dependencies = append(project.Dependencies, PyFlask)
-				case "django":
-					project.Dependencies = append(project.Dependencies, PyDjango)
-				}
+	project := &Project{
+		Language: Python,
+		Path:     path,
+	}
 
-				switch module {
-				case "flask_mysqldb",
-					"mysqlclient",
-					"aiomysql",
-					"asyncmy":
-					databaseDepMap[DbMySql] = struct{}{}
-				case "psycopg2",
-					"psycopg2-binary",
-					"psycopg",
-					"psycopgbinary",
-					"asyncpg",
-					"aiopg":
-					databaseDepMap[DbPostgres] = struct{}{}
-				case "pymongo",
-					"beanie",
-					"motor":
-					databaseDepMap[DbMongo] = struct{}{}
-				case "redis", "redis-om":
-					databaseDepMap[DbRedis] = struct{}{}
-				}
-			}
+	modules := []string{}
+	if requirementsTxt != "" {
+		project.DetectionRule = "Inferred by presence of: " + requirementsTxt
 
-			if err := file.Close(); err != nil {
-				return nil, err
-			}
+		requirements, err := readRequirementsTxt(filepath.Join(path, requirementsTxt))
+		if err != nil {
+			return nil, err
+		}
+		modules = append(modules, requirements...)
+	}
 
-			if len(databaseDepMap) > 0 {
-				project.DatabaseDeps = maps.Keys(databaseDepMap)
-				slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
-					return string(a) < string(b)
-				})
+	if pyProject != nil {
+		if project.DetectionRule == "" {
+			project.DetectionRule = "Inferred by presence of: " + pyProjectToml
+		}
+
+		modules = append(modules, pyProject.dependencies...)
+
+		for _, lockFile := range lockFiles {
+			packages, err := readPyLockPackages(filepath.Join(path, lockFile))
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", lockFile, err)
 			}
+			modules = append(modules, packages...)
+		}
+	}
+
+	dependencyMap := map[Dependency]struct{}{}
+	databaseDepMap := map[DatabaseDep]struct{}{}
+	for _, module := range modules {
+		switch module {
+		case "fastapi":
+			dependencyMap[PyFastApi] = struct{}{}
+		case "flask":
+			dependencyMap[PyFlask] = struct{}{}
+		case "django":
+			dependencyMap[PyDjango] = struct{}{}
+		}
+
+		switch module {
+		case "flask_mysqldb",
+			"mysqlclient",
+			"aiomysql",
+			"asyncmy":
+			databaseDepMap[DbMySql] = struct{}{}
+		case "psycopg2",
+			"psycopg2-binary",
+			"psycopg",
+			"psycopgbinary",
+			"asyncpg",
+			"aiopg":
+			databaseDepMap[DbPostgres] = struct{}{}
+		case "pymongo",
+			"beanie",
+			"motor":
+			databaseDepMap[DbMongo] = struct{}{}
+		case "redis", "redis-om":
+			databaseDepMap[DbRedis] = struct{}{}
+		}
+	}
+
+	if len(dependencyMap) > 0 {
+		project.Dependencies = maps.Keys(dependencyMap)
+		slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
+			return string(a) < string(b)
+		})
+	}
+
+	if len(databaseDepMap) > 0 {
+		project.DatabaseDeps = maps.Keys(databaseDepMap)
+		slices.SortFunc(project.DatabaseDeps, func(a, b DatabaseDep) bool {
+			return string(a) < string(b)
+		})
+	}
 
-			slices.SortFunc(project.Dependencies, func(a, b Dependency) bool {
-				return string(a) < string(b)
-			})
+	return project, nil
+}
 
-			return project, nil
+// readRequirementsTxt reads the names of the packages required by a pip requirements file.
+func readRequirementsTxt(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	modules := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// pip is case insensitive: PEP 426
+		// https://peps.python.org/pep-0426/#name
+		if module := pyRequirementName(scanner.Text()); module != "" {
+			modules = append(modules, module)
 		}
 	}
 
-	return nil, nil
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return modules, nil
 }
 
 // PyFastApiLaunch returns the launch argument for a python FastAPI project to be served by a python web server.
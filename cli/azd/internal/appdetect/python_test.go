@@ -1,6 +1,7 @@
 package appdetect
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -46,3 +47,91 @@ func TestFindFastApiMain(t *testing.T) {
 	require.NoError(t, err)
 	require.Empty(t, s)
 }
+
+func TestPyProjectTool(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  PyTool
+	}{
+		{"RequirementsTxt", map[string]string{"requirements.txt": "flask"}, PyToolPip},
+		{"Pep621", map[string]string{"pyproject.toml": "[project]\nname = \"app\""}, PyToolPip},
+		{"PoetryTable", map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"app\""}, PyToolPoetry},
+		{"PoetryLock", map[string]string{"pyproject.toml": "[project]\n", "poetry.lock": ""}, PyToolPoetry},
+		{"UvTable", map[string]string{"pyproject.toml": "[project]\n[tool.uv]\n"}, PyToolUv},
+		{"UvLock", map[string]string{"pyproject.toml": "[project]\n", "uv.lock": ""}, PyToolUv},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, contents := range tt.files {
+				err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0600)
+				require.NoError(t, err)
+			}
+
+			tool, err := PyProjectTool(dir)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, tool)
+		})
+	}
+}
+
+func TestReadPyProject(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pyproject.toml")
+	contents := `[project]
+name = "app"
+description = """
+A [project] with = signs
+"""
+dependencies = [
+    "fastapi[all]>=0.100", # the web framework
+    'asyncpg ; python_version > "3.8"',
+]
+
+[tool.poetry.dependencies]
+python = "^3.11"
+Flask = { version = "^3.0.0", extras = ["async"] }
+
+[tool.uv]
+dev-dependencies = ["pytest"]
+`
+	err := os.WriteFile(path, []byte(contents), 0600)
+	require.NoError(t, err)
+
+	pyProject, err := readPyProject(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"fastapi", "asyncpg", "flask"}, pyProject.dependencies)
+	require.Equal(t, PyToolUv, pyProject.tool)
+
+	err = os.WriteFile(path, []byte("[project\n"), 0600)
+	require.NoError(t, err)
+
+	_, err = readPyProject(path)
+	require.Error(t, err)
+}
+
+func TestDetectPythonToolConfigOnly(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(
+		filepath.Join(dir, "pyproject.toml"), []byte("[tool.ruff]\nline-length = 120\n\n[tool.black]\n"), 0600)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	// The configuration of tools doesn't make a Python project
+	project, err := (&pythonDetector{}).DetectProject(context.Background(), dir, entries)
+	require.NoError(t, err)
+	require.Nil(t, project)
+
+	err = os.WriteFile(filepath.Join(dir, "requirements.txt"), []byte("flask\n"), 0600)
+	require.NoError(t, err)
+
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+
+	project, err = (&pythonDetector{}).DetectProject(context.Background(), dir, entries)
+	require.NoError(t, err)
+	require.NotNil(t, project)
+	require.Equal(t, "Inferred by presence of: requirements.txt", project.DetectionRule)
+}
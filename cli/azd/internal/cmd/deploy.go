@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
@@ -32,6 +34,13 @@ type DeployFlags struct {
 	serviceName string
 	All         bool
 	fromPackage string
+	parallelism int
+	force       bool
+	promote     bool
+	abort       bool
+	slot        string
+	swap        string
+	preview     bool
 	global      *internal.GlobalCommandOptions
 	*internal.EnvFlag
 }
@@ -72,6 +81,48 @@ func (d *DeployFlags) bindCommon(local *pflag.FlagSet, global *internal.GlobalCo
 		"",
 		"Deploys the application from an existing package.",
 	)
+	local.IntVar(
+		&d.parallelism,
+		"parallelism",
+		1,
+		"The maximum number of services deployed concurrently. Services are deployed after the services they depend on.",
+	)
+	local.BoolVar(
+		&d.force,
+		"force",
+		false,
+		"Deploys the services even when they didn't change since they were last deployed.",
+	)
+	local.BoolVar(
+		&d.promote,
+		"promote",
+		false,
+		"Completes the rollout in progress of the services, sending all the traffic to the new revision.",
+	)
+	local.BoolVar(
+		&d.abort,
+		"abort",
+		false,
+		"Reverts the rollout in progress of the services, sending all the traffic back to the previous revision.",
+	)
+	local.StringVar(
+		&d.slot,
+		"slot",
+		"",
+		"Deploys the App Service services to the specified deployment slot, instead of their configured slot.",
+	)
+	local.StringVar(
+		&d.swap,
+		"swap",
+		"",
+		"Swaps the specified deployment slot of the App Service services with their production slot.",
+	)
+	local.BoolVar(
+		&d.preview,
+		"preview",
+		false,
+		"Shows the changes deploying the services would make, without deploying them.",
+	)
 }
 
 func (d *DeployFlags) SetCommon(envFlag *internal.EnvFlag) {
@@ -110,6 +161,8 @@ type DeployAction struct {
 	env                 *environment.Environment
 	projectManager      project.ProjectManager
 	serviceManager      project.ServiceManager
+	buildCache          *project.BuildCache
+	deploymentHistory   *project.DeploymentHistory
 	resourceManager     project.ResourceManager
 	accountManager      account.Manager
 	azCli               azcli.AzCli
@@ -128,6 +181,8 @@ func NewDeployAction(
 	projectConfig *project.ProjectConfig,
 	projectManager project.ProjectManager,
 	serviceManager project.ServiceManager,
+	buildCache *project.BuildCache,
+	deploymentHistory *project.DeploymentHistory,
 	resourceManager project.ResourceManager,
 	azdCtx *azdcontext.AzdContext,
 	environment *environment.Environment,
@@ -149,6 +204,8 @@ func NewDeployAction(
 		env:                 environment,
 		projectManager:      projectManager,
 		serviceManager:      serviceManager,
+		buildCache:          buildCache,
+		deploymentHistory:   deploymentHistory,
 		resourceManager:     resourceManager,
 		accountManager:      accountManager,
 		portalUrlBase:       string(portalUrlBase),
@@ -206,6 +263,39 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 		)
 	}
 
+	if da.flags.promote && da.flags.abort {
+		return nil, errors.New("'--promote' and '--abort' cannot be specified together")
+	}
+
+	if (da.flags.promote || da.flags.abort) && da.flags.fromPackage != "" {
+		return nil, errors.New("'--from-package' cannot be specified when '--promote' or '--abort' is set")
+	}
+
+	if da.flags.swap != "" && (da.flags.promote || da.flags.abort || da.flags.fromPackage != "" || da.flags.slot != "") {
+		return nil, errors.New(
+			"'--swap' cannot be specified with '--from-package', '--slot', '--promote' or '--abort'")
+	}
+
+	if da.flags.slot != "" && (da.flags.promote || da.flags.abort) {
+		return nil, errors.New("'--slot' cannot be specified when '--promote' or '--abort' is set")
+	}
+
+	if da.flags.preview &&
+		(da.flags.promote || da.flags.abort || da.flags.fromPackage != "" || da.flags.slot != "" || da.flags.swap != "") {
+		return nil, errors.New(
+			"'--preview' cannot be specified with '--from-package', '--slot', '--swap', '--promote' or '--abort'")
+	}
+
+	for _, slotName := range []string{da.flags.slot, da.flags.swap} {
+		if slotName == "" {
+			continue
+		}
+
+		if err := project.ValidateAppServiceSlot(slotName); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := da.projectManager.Initialize(ctx, da.projectConfig); err != nil {
 		return nil, err
 	}
@@ -223,80 +313,104 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 
 	startTime := time.Now()
 
-	deployResults := map[string]*project.ServiceDeployResult{}
 	stableServices, err := da.importManager.ServiceStable(ctx, da.projectConfig)
 	if err != nil {
 		return nil, err
 	}
 
+	targetServices := []*project.ServiceConfig{}
 	for _, svc := range stableServices {
-		stepMessage := fmt.Sprintf("Deploying service %s", svc.Name)
-		da.console.ShowSpinner(ctx, stepMessage, input.Step)
-
-		// Skip this service if both cases are true:
-		// 1. The user specified a service name
-		// 2. This service is not the one the user specified
-		if targetServiceName != "" && targetServiceName != svc.Name {
-			da.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
-			continue
+		// Deploy all the services, unless the user specified a service name
+		if targetServiceName == "" || targetServiceName == svc.Name {
+			targetServices = append(targetServices, svc)
 		}
+	}
 
-		if alphaFeatureId, isAlphaFeature := alpha.IsFeatureKey(string(svc.Host)); isAlphaFeature {
-			// alpha feature on/off detection for host is done during initialization.
-			// This is just for displaying the warning during deployment.
-			da.console.WarnForFeature(ctx, alphaFeatureId)
-		}
+	if da.flags.promote || da.flags.abort {
+		return da.completeRollouts(ctx, targetServices, startTime)
+	}
+
+	if da.flags.swap != "" {
+		return da.swapSlots(ctx, targetServices, startTime)
+	}
 
-		var packageResult *project.ServicePackageResult
-		if da.flags.fromPackage != "" {
-			// --from-package set, skip packaging
-			packageResult = &project.ServicePackageResult{
-				PackagePath: da.flags.fromPackage,
+	if da.flags.preview {
+		return da.previewDeployments(ctx, targetServices, startTime)
+	}
+
+	if da.flags.slot != "" {
+		for _, svc := range targetServices {
+			if svc.Host == project.AppServiceTarget {
+				// The slot is part of the configuration of the service, so the service is deployed again
+				svc.AppService.Slot = da.flags.slot
+			} else if targetServiceName != "" {
+				return nil, fmt.Errorf(
+					"'--slot' cannot be specified for service '%s', deployment slots are only supported for the '%s' host",
+					svc.Name,
+					project.AppServiceTarget,
+				)
 			}
-		} else {
-			//  --from-package not set, package the application
-			packageTask := da.serviceManager.Package(ctx, svc, nil, nil)
-			done := make(chan struct{})
-			go func() {
-				for packageProgress := range packageTask.Progress() {
-					progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, packageProgress.Message)
-					da.console.ShowSpinner(ctx, progressMessage, input.Step)
-				}
-				close(done)
-			}()
-
-			packageResult, err = packageTask.Await()
-			// wait for console updates to complete
-			<-done
-			// do not stop progress here as next step is to deploy
+		}
+	}
+
+	serviceGraph, err := project.NewServiceGraph(targetServices)
+	if err != nil {
+		return nil, err
+	}
+
+	// A package from --from-package isn't related to the content of the service, so it is always deployed
+	if da.flags.force || da.flags.fromPackage != "" {
+		for _, svc := range targetServices {
+			err := da.buildCache.Invalidate(
+				svc.Name, project.ServiceEventBuild, project.ServiceEventPackage, project.ServiceEventDeploy)
 			if err != nil {
-				da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
 				return nil, err
 			}
 		}
+	}
 
-		deployTask := da.serviceManager.Deploy(ctx, svc, packageResult)
-		done := make(chan struct{})
-		go func() {
-			for deployProgress := range deployTask.Progress() {
-				progressMessage := fmt.Sprintf("Deploying service %s (%s)", svc.Name, deployProgress.Message)
-				da.console.ShowSpinner(ctx, progressMessage, input.Step)
-			}
-			close(done)
-		}()
+	progress := NewServiceProgress(da.console, targetServices, ServiceProgressOptions{
+		Verb:        "Deploying",
+		Parallelism: da.flags.parallelism,
+	})
 
-		deployResult, err := deployTask.Await()
-		// wait for console updates to complete
-		<-done
-		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+	for _, svc := range stableServices {
+		if targetServiceName != "" && targetServiceName != svc.Name {
+			progress.Skip(ctx, svc.Name)
+		}
+	}
+
+	deployResultsMutex := sync.Mutex{}
+	deployResults := map[string]*project.ServiceDeployResult{}
+
+	progress.Start(ctx)
+	err = serviceGraph.Run(ctx, da.flags.parallelism, func(ctx context.Context, svc *project.ServiceConfig) error {
+		progress.Begin(ctx, svc)
+
+		if alphaFeatureId, isAlphaFeature := alpha.IsFeatureKey(string(svc.Host)); isAlphaFeature {
+			// alpha feature on/off detection for host is done during initialization.
+			// This is just for displaying the warning during deployment.
+			da.console.WarnForFeature(ctx, alphaFeatureId)
+		}
+
+		deployResult, err := da.deployService(ctx, svc, progress)
+		progress.End(ctx, svc, err)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
+		deployResultsMutex.Lock()
 		deployResults[svc.Name] = deployResult
+		deployResultsMutex.Unlock()
 
 		// report deploy outputs
-		da.console.MessageUxItem(ctx, deployResult)
+		progress.Report(ctx, deployResult)
+		return nil
+	})
+	progress.Stop(ctx)
+
+	if err != nil {
+		return nil, err
 	}
 
 	aspireDashboardUrl := apphost.AspireDashboardUrl(ctx, da.env, da.alphaFeatureManager)
@@ -330,6 +444,246 @@ func (da *DeployAction) Run(ctx context.Context) (*actions.ActionResult, error)
 	}, nil
 }
 
+// completeRollouts promotes or aborts the rollouts in progress of the services using a deployment strategy
+func (da *DeployAction) completeRollouts(
+	ctx context.Context,
+	targetServices []*project.ServiceConfig,
+	startTime time.Time,
+) (*actions.ActionResult, error) {
+	verb, header := "Promoting", "Your rollouts were promoted in %s."
+	if da.flags.abort {
+		verb, header = "Aborting", "Your rollouts were aborted in %s."
+	}
+
+	for _, svc := range targetServices {
+		stepMessage := fmt.Sprintf("%s rollout of service %s", verb, output.WithHighLightFormat(svc.Name))
+		da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		if svc.Deployment.Strategy == project.DeploymentStrategyNone {
+			da.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			continue
+		}
+
+		err := da.completeRollout(ctx, svc)
+		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(header, ux.DurationAsText(since(startTime))),
+		},
+	}, nil
+}
+
+func (da *DeployAction) completeRollout(ctx context.Context, svc *project.ServiceConfig) error {
+	serviceTarget, err := da.serviceManager.GetServiceTarget(ctx, svc)
+	if err != nil {
+		return err
+	}
+
+	rolloutTarget, ok := serviceTarget.(project.RolloutTarget)
+	if !ok {
+		return fmt.Errorf("service '%s' doesn't support rollouts with the '%s' host", svc.Name, svc.Host)
+	}
+
+	targetResource, err := da.resourceManager.GetTargetResource(ctx, da.env.GetSubscriptionId(), svc)
+	if err != nil {
+		return fmt.Errorf("getting target resource: %w", err)
+	}
+
+	if da.flags.promote {
+		return rolloutTarget.Promote(ctx, svc, targetResource)
+	}
+
+	if err := rolloutTarget.Abort(ctx, svc, targetResource); err != nil {
+		return err
+	}
+
+	// The revision which was deployed no longer runs, so the service is deployed again by the next deployment
+	return da.buildCache.Invalidate(svc.Name, project.ServiceEventDeploy)
+}
+
+// swapSlots swaps the deployment slot of --swap with the production slot of the App Service services
+func (da *DeployAction) swapSlots(
+	ctx context.Context,
+	targetServices []*project.ServiceConfig,
+	startTime time.Time,
+) (*actions.ActionResult, error) {
+	for _, svc := range targetServices {
+		stepMessage := fmt.Sprintf(
+			"Swapping slot %s of service %s with production",
+			output.WithHighLightFormat(da.flags.swap),
+			output.WithHighLightFormat(svc.Name),
+		)
+		da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		if svc.Host != project.AppServiceTarget {
+			da.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			continue
+		}
+
+		err := da.swapSlot(ctx, svc)
+		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Your slots were swapped in %s.", ux.DurationAsText(since(startTime))),
+		},
+	}, nil
+}
+
+func (da *DeployAction) swapSlot(ctx context.Context, svc *project.ServiceConfig) error {
+	serviceTarget, err := da.serviceManager.GetServiceTarget(ctx, svc)
+	if err != nil {
+		return err
+	}
+
+	slotTarget, ok := serviceTarget.(project.SlotTarget)
+	if !ok {
+		return fmt.Errorf("service '%s' doesn't support deployment slots with the '%s' host", svc.Name, svc.Host)
+	}
+
+	targetResource, err := da.resourceManager.GetTargetResource(ctx, da.env.GetSubscriptionId(), svc)
+	if err != nil {
+		return fmt.Errorf("getting target resource: %w", err)
+	}
+
+	if err := slotTarget.SwapSlot(ctx, svc, targetResource, da.flags.swap); err != nil {
+		return err
+	}
+
+	// The slots exchanged their content, so the service is deployed again by the next deployment
+	return da.buildCache.Invalidate(svc.Name, project.ServiceEventDeploy)
+}
+
+// previewDeployments shows the changes deploying the services would make, for the services whose host supports it
+func (da *DeployAction) previewDeployments(
+	ctx context.Context,
+	targetServices []*project.ServiceConfig,
+	startTime time.Time,
+) (*actions.ActionResult, error) {
+	for _, svc := range targetServices {
+		stepMessage := fmt.Sprintf("Previewing deployment of service %s", output.WithHighLightFormat(svc.Name))
+		da.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		serviceTarget, err := da.serviceManager.GetServiceTarget(ctx, svc)
+		if err != nil {
+			da.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			return nil, err
+		}
+
+		previewTarget, ok := serviceTarget.(project.PreviewTarget)
+		if !ok {
+			da.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			continue
+		}
+
+		preview, err := da.previewDeployment(ctx, svc, previewTarget)
+		da.console.StopSpinner(ctx, stepMessage, input.GetStepResultFormat(err))
+		if err != nil {
+			return nil, err
+		}
+
+		if preview == "" {
+			da.console.Message(ctx, "  No changes\n")
+		} else {
+			da.console.Message(ctx, preview)
+		}
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Your deployments were previewed in %s.", ux.DurationAsText(since(startTime))),
+		},
+	}, nil
+}
+
+func (da *DeployAction) previewDeployment(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	previewTarget project.PreviewTarget,
+) (string, error) {
+	targetResource, err := da.resourceManager.GetTargetResource(ctx, da.env.GetSubscriptionId(), svc)
+	if err != nil {
+		return "", fmt.Errorf("getting target resource: %w", err)
+	}
+
+	return previewTarget.Preview(ctx, svc, targetResource)
+}
+
+// deployService packages, unless --from-package is set, and deploys the service
+func (da *DeployAction) deployService(
+	ctx context.Context,
+	svc *project.ServiceConfig,
+	progress *ServiceProgress,
+) (*project.ServiceDeployResult, error) {
+	var packageResult *project.ServicePackageResult
+	if da.flags.fromPackage != "" {
+		// --from-package set, skip packaging
+		packageResult = &project.ServicePackageResult{
+			PackagePath: da.flags.fromPackage,
+		}
+	} else {
+		//  --from-package not set, package the application
+		packageTask := da.serviceManager.Package(ctx, svc, nil, nil)
+		done := make(chan struct{})
+		go func() {
+			for packageProgress := range packageTask.Progress() {
+				progress.Progress(ctx, svc, packageProgress.Message)
+			}
+			close(done)
+		}()
+
+		var err error
+		packageResult, err = packageTask.Await()
+		// wait for console updates to complete
+		<-done
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Service targets remove the packages they deploy, the package is retained so the service can be rolled back to it
+	retainedPackage, err := da.deploymentHistory.RetainPackage(svc.Name, packageResult.PackagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	deployTask := da.serviceManager.Deploy(ctx, svc, packageResult)
+	done := make(chan struct{})
+	go func() {
+		for deployProgress := range deployTask.Progress() {
+			progress.Progress(ctx, svc, deployProgress.Message)
+		}
+		close(done)
+	}()
+
+	deployResult, err := deployTask.Await()
+	// wait for console updates to complete
+	<-done
+
+	if err != nil {
+		if retainedPackage != "" {
+			_ = os.Remove(retainedPackage)
+		}
+
+		return nil, err
+	}
+
+	if err := da.deploymentHistory.Record(svc.Name, deployResult, retainedPackage); err != nil {
+		return nil, fmt.Errorf("recording deployment: %w", err)
+	}
+
+	return deployResult, nil
+}
+
 func GetCmdDeployHelpDescription(*cobra.Command) string {
 	return generateCmdHelpDescription("Deploy application to Azure.", []string{
 		formatHelpNote(
@@ -339,6 +693,24 @@ func GetCmdDeployHelpDescription(*cobra.Command) string {
 			fmt.Sprintf("When %s is set, only the specific service is deployed.", output.WithHighLightFormat("<service>"))),
 		formatHelpNote("After the deployment is complete, the endpoint is printed. To start the service, select" +
 			" the endpoint or paste it in a browser."),
+		formatHelpNote(fmt.Sprintf(
+			"Services which didn't change since they were last deployed are skipped, unless %s is set, such as"+
+				" after their Azure resources were recreated.",
+			output.WithHighLightFormat("--force"))),
+		formatHelpNote(fmt.Sprintf(
+			"Services with a deployment strategy can be left with a rollout in progress, which %s completes and %s"+
+				" reverts.",
+			output.WithHighLightFormat("--promote"),
+			output.WithHighLightFormat("--abort"))),
+		formatHelpNote(fmt.Sprintf(
+			"App Service services are deployed to their configured deployment slot, or to the slot of %s, and %s"+
+				" swaps a slot with the production slot.",
+			output.WithHighLightFormat("--slot"),
+			output.WithHighLightFormat("--swap"))),
+		formatHelpNote(fmt.Sprintf(
+			"%s shows the changes deploying the services would make, such as the helm diff of the helm releases of"+
+				" AKS services, without deploying them.",
+			output.WithHighLightFormat("--preview"))),
 	})
 }
 
@@ -356,5 +728,14 @@ func GetCmdDeployHelpFooter(*cobra.Command) string {
 		"Deploy the service named 'api' to Azure from a previously generated package.": output.WithHighLightFormat(
 			"azd deploy api --from-package <package-path>",
 		),
+		"Complete the rollout in progress of the service named 'api'.": output.WithHighLightFormat(
+			"azd deploy api --promote",
+		),
+		"Swap the 'staging' slot of the service named 'web' with its production slot.": output.WithHighLightFormat(
+			"azd deploy web --swap staging",
+		),
+		"Preview the changes deploying the service named 'api' would make.": output.WithHighLightFormat(
+			"azd deploy api --preview",
+		),
 	})
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -29,6 +30,8 @@ type ProvisionFlags struct {
 	noProgress            bool
 	preview               bool
 	ignoreDeploymentState bool
+	planOut               string
+	fromPlan              string
 	global                *internal.GlobalCommandOptions
 	*internal.EnvFlag
 }
@@ -59,6 +62,16 @@ func (i *ProvisionFlags) bindCommon(local *pflag.FlagSet, global *internal.Globa
 		"no-state",
 		false,
 		"Do not use latest Deployment State (bicep only).")
+	local.StringVar(
+		&i.planOut,
+		"plan-out",
+		"",
+		"Writes the planned changes to a file without applying them. Apply the plan later with --from-plan.")
+	local.StringVar(
+		&i.fromPlan,
+		"from-plan",
+		"",
+		"Applies a plan file created with --plan-out. Fails if the infrastructure changed since the plan was created.")
 
 	i.EnvFlag = &internal.EnvFlag{}
 	i.EnvFlag.Bind(local, global)
@@ -104,6 +117,7 @@ type ProvisionAction struct {
 	console          input.Console
 	subManager       *account.SubscriptionsManager
 	importManager    *project.ImportManager
+	buildCache       *project.BuildCache
 	portalUrlBase    string
 }
 
@@ -120,6 +134,7 @@ func NewProvisionAction(
 	formatter output.Formatter,
 	writer io.Writer,
 	subManager *account.SubscriptionsManager,
+	buildCache *project.BuildCache,
 	portalUrlBase cloud.PortalUrlBase,
 ) actions.Action {
 	return &ProvisionAction{
@@ -135,6 +150,7 @@ func NewProvisionAction(
 		console:          console,
 		subManager:       subManager,
 		importManager:    importManager,
+		buildCache:       buildCache,
 		portalUrlBase:    string(portalUrlBase),
 	}
 }
@@ -159,6 +175,25 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		)
 	}
 	previewMode := p.flags.preview
+	planMode := p.flags.planOut != ""
+
+	if p.flags.planOut != "" && p.flags.fromPlan != "" {
+		return nil, errors.New("--plan-out and --from-plan cannot be used together")
+	}
+
+	if previewMode && (p.flags.planOut != "" || p.flags.fromPlan != "") {
+		return nil, errors.New("--preview cannot be used together with --plan-out or --from-plan")
+	}
+
+	// Load the plan before doing any work, so an invalid plan file fails fast
+	var savedPlan *provisioning.Plan
+	if p.flags.fromPlan != "" {
+		plan, err := provisioning.ReadPlan(p.flags.fromPlan)
+		if err != nil {
+			return nil, err
+		}
+		savedPlan = plan
+	}
 
 	// Command title
 	defaultTitle := "Provisioning Azure resources (azd provision)"
@@ -166,6 +201,9 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 	if previewMode {
 		defaultTitle = "Previewing Azure resource changes (azd provision --preview)"
 		defaultTitleNote = "This is a preview. No changes will be applied to your Azure resources."
+	} else if planMode {
+		defaultTitle = "Planning Azure resource changes (azd provision --plan-out)"
+		defaultTitleNote = "This is a plan. No changes will be applied to your Azure resources."
 	}
 
 	p.console.MessageUxItem(ctx, &ux.MessageTitle{
@@ -224,19 +262,25 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 
 	var deployResult *provisioning.DeployResult
 	var deployPreviewResult *provisioning.DeployPreviewResult
+	var plan *provisioning.Plan
 
 	projectEventArgs := project.ProjectLifecycleEventArgs{
 		Project: p.projectConfig,
 		Args: map[string]any{
-			"preview": previewMode,
+			"preview": previewMode || planMode,
 		},
 	}
 
 	err = p.projectConfig.Invoke(ctx, project.ProjectEventProvision, projectEventArgs, func() error {
 		var err error
-		if previewMode {
+		switch {
+		case previewMode:
 			deployPreviewResult, err = p.provisionManager.Preview(ctx)
-		} else {
+		case planMode:
+			plan, err = p.provisionManager.Plan(ctx)
+		case savedPlan != nil:
+			deployResult, err = p.provisionManager.DeployPlan(ctx, savedPlan)
+		default:
 			deployResult, err = p.provisionManager.Deploy(ctx)
 		}
 		return err
@@ -261,6 +305,14 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 			}
 		}
 
+		if errors.Is(err, provisioning.ErrPlanMismatch) {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: err,
+				Suggestion: fmt.Sprintf("\nSuggested Action: Create a new plan with %s and review it before applying.",
+					output.WithHighLightFormat("azd provision --plan-out <file>")),
+			}
+		}
+
 		//if user don't have access to openai
 		errorMsg := err.Error()
 		if strings.Contains(errorMsg, specialFeatureOrQuotaIdRequired) && strings.Contains(errorMsg, "OpenAI") {
@@ -318,6 +370,28 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		}, nil
 	}
 
+	if planMode {
+		if err := provisioning.WritePlan(plan, p.flags.planOut); err != nil {
+			return nil, err
+		}
+
+		p.console.MessageUxItem(ctx, deployResultToUx(plan.PreviewResult()))
+
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf(
+					"Saved provisioning plan to %s in %s.",
+					output.WithHighLightFormat(p.flags.planOut),
+					ux.DurationAsText(since(startTime)),
+				),
+				FollowUp: fmt.Sprintf(
+					"Run %s to apply the plan.",
+					output.WithHighLightFormat("azd provision --from-plan %s", p.flags.planOut),
+				),
+			},
+		}, nil
+	}
+
 	if deployResult.SkippedReason == provisioning.DeploymentStateSkipped {
 		return &actions.ActionResult{
 			Message: &actions.ResultMessage{
@@ -326,6 +400,11 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 		}, nil
 	}
 
+	// Provisioning may have recreated the resources of the services, which are then deployed again
+	if err := p.buildCache.InvalidateAll(project.ServiceEventDeploy); err != nil {
+		return nil, err
+	}
+
 	servicesStable, err := p.importManager.ServiceStable(ctx, p.projectConfig)
 	if err != nil {
 		return nil, err
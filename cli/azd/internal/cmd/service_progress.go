@@ -0,0 +1,163 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// ServiceProgressOptions configures how the progress of services is displayed
+type ServiceProgressOptions struct {
+	// The verb describing what is done to the services (Example: Deploying)
+	Verb string
+	// The number of services processed concurrently
+	Parallelism int
+	// When true the reports of the services are separated by a blank line
+	SeparateReports bool
+}
+
+// ServiceProgress displays the progress of the services processed by a project.ServiceGraph.
+// Services processed one at a time are displayed with the spinner, while services processed concurrently are
+// displayed with a status line each. The reports of services processed concurrently are displayed once all the services
+// have completed, so they don't interleave.
+type ServiceProgress struct {
+	console  input.Console
+	options  ServiceProgressOptions
+	services []*project.ServiceConfig
+
+	mutex    sync.Mutex
+	started  map[string]bool
+	reports  []ux.UxItem
+	reported int
+}
+
+// NewServiceProgress creates a progress display for the specified services
+func NewServiceProgress(
+	console input.Console,
+	services []*project.ServiceConfig,
+	options ServiceProgressOptions,
+) *ServiceProgress {
+	return &ServiceProgress{
+		console:  console,
+		options:  options,
+		services: services,
+		started:  map[string]bool{},
+	}
+}
+
+func (p *ServiceProgress) concurrent() bool {
+	return p.options.Parallelism > 1 && len(p.services) > 1
+}
+
+func (p *ServiceProgress) title(serviceName string) string {
+	return fmt.Sprintf("%s service %s", p.options.Verb, serviceName)
+}
+
+// Start displays the status lines of the services when they are processed concurrently
+func (p *ServiceProgress) Start(ctx context.Context) {
+	if !p.concurrent() {
+		return
+	}
+
+	titles := make([]string, len(p.services))
+	for i, svc := range p.services {
+		titles[i] = p.title(svc.Name)
+	}
+
+	p.console.ShowStatusLines(ctx, titles)
+}
+
+// Skip displays a service which isn't processed, such as a service which isn't targeted by the command
+func (p *ServiceProgress) Skip(ctx context.Context, serviceName string) {
+	title := p.title(serviceName)
+	p.console.ShowSpinner(ctx, title, input.Step)
+	p.console.StopSpinner(ctx, title, input.StepSkipped)
+}
+
+// Begin marks the service as running
+func (p *ServiceProgress) Begin(ctx context.Context, svc *project.ServiceConfig) {
+	p.mutex.Lock()
+	p.started[svc.Name] = true
+	p.mutex.Unlock()
+
+	p.Progress(ctx, svc, "")
+}
+
+// Progress updates the progress message of the running service
+func (p *ServiceProgress) Progress(ctx context.Context, svc *project.ServiceConfig, message string) {
+	title := p.title(svc.Name)
+	if p.concurrent() {
+		p.console.UpdateStatusLine(ctx, title, message, input.Step)
+		return
+	}
+
+	if message != "" {
+		title = fmt.Sprintf("%s (%s)", title, message)
+	}
+
+	p.console.ShowSpinner(ctx, title, input.Step)
+}
+
+// End marks the service as completed, failed when err is not nil
+func (p *ServiceProgress) End(ctx context.Context, svc *project.ServiceConfig, err error) {
+	title := p.title(svc.Name)
+	if p.concurrent() {
+		p.console.UpdateStatusLine(ctx, title, "", input.GetStepResultFormat(err))
+		return
+	}
+
+	p.console.StopSpinner(ctx, title, input.GetStepResultFormat(err))
+}
+
+// Report displays the result of a service
+func (p *ServiceProgress) Report(ctx context.Context, item ux.UxItem) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.concurrent() {
+		p.reports = append(p.reports, item)
+		return
+	}
+
+	p.report(ctx, item)
+}
+
+func (p *ServiceProgress) report(ctx context.Context, item ux.UxItem) {
+	if p.options.SeparateReports && p.reported > 0 {
+		p.console.Message(ctx, "")
+	}
+
+	p.console.MessageUxItem(ctx, item)
+	p.reported++
+}
+
+// Stop completes the display. Services which haven't started, since a service they depend on failed, are displayed as
+// skipped and the reports of services processed concurrently are displayed.
+func (p *ServiceProgress) Stop(ctx context.Context) {
+	if p.concurrent() {
+		p.console.StopStatusLines(ctx)
+
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		for _, item := range p.reports {
+			p.report(ctx, item)
+		}
+
+		p.reports = nil
+		return
+	}
+
+	for _, svc := range p.services {
+		if !p.started[svc.Name] {
+			p.Skip(ctx, svc.Name)
+		}
+	}
+}
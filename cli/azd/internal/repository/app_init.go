@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"text/template"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/internal"
@@ -30,6 +31,7 @@ var languageMap = map[appdetect.Language]project.ServiceLanguageKind{
 	appdetect.JavaScript: project.ServiceLanguageJavaScript,
 	appdetect.TypeScript: project.ServiceLanguageTypeScript,
 	appdetect.Python:     project.ServiceLanguagePython,
+	appdetect.Go:         project.ServiceLanguageGo,
 }
 
 var dbMap = map[appdetect.DatabaseDep]struct{}{
@@ -262,6 +264,16 @@ func (i *Initializer) InitFromApp(
 	tracing.SetUsageAttributes(fields.AppInitLastStep.String("generate"))
 
 	i.console.Message(ctx, "\n"+output.WithBold("Generating files to run your app on Azure:")+"\n")
+	t, err := scaffold.Load()
+	if err != nil {
+		return fmt.Errorf("loading scaffold templates: %w", err)
+	}
+
+	err = i.genDockerfiles(ctx, t, azdCtx, &detect)
+	if err != nil {
+		return err
+	}
+
 	err = i.genProjectFile(ctx, azdCtx, detect)
 	if err != nil {
 		return err
@@ -278,11 +290,6 @@ func (i *Initializer) InitFromApp(
 	}
 
 	defer func() { _ = os.RemoveAll(staging) }()
-	t, err := scaffold.Load()
-	if err != nil {
-		return fmt.Errorf("loading scaffold templates: %w", err)
-	}
-
 	err = scaffold.ExecInfra(t, spec, staging)
 	if err != nil {
 		return err
@@ -321,6 +328,55 @@ func (i *Initializer) InitFromApp(
 	return nil
 }
 
+// genDockerfiles generates a Dockerfile for Go services that don't have one, since Go services can't be built by the
+// default container builder. The services are updated to reference the generated Dockerfile.
+func (i *Initializer) genDockerfiles(
+	ctx context.Context,
+	t *template.Template,
+	azdCtx *azdcontext.AzdContext,
+	detect *detectConfirm) error {
+	for idx, svc := range detect.Services {
+		if svc.Language != appdetect.Go || svc.Docker != nil {
+			continue
+		}
+
+		goVersion, err := appdetect.GoVersion(svc.Path)
+		if err != nil {
+			return fmt.Errorf("reading go version of %s: %w", svc.Path, err)
+		}
+
+		if goVersion == "" {
+			goVersion = "1"
+		}
+
+		dockerfilePath := filepath.Join(svc.Path, "Dockerfile")
+		err = scaffold.Execute(
+			t,
+			"go.Dockerfile",
+			scaffold.DockerfileGo{
+				GoVersion: goVersion,
+				Port:      goServicePort,
+			},
+			dockerfilePath)
+		if err != nil {
+			return fmt.Errorf("scaffolding Dockerfile for %s: %w", svc.Path, err)
+		}
+
+		detect.Services[idx].Docker = &appdetect.Docker{Path: dockerfilePath}
+
+		rel, err := filepath.Rel(azdCtx.ProjectDirectory(), dockerfilePath)
+		if err != nil {
+			return err
+		}
+
+		i.console.MessageUxItem(ctx, &ux.DoneMessage{
+			Message: "Generating " + output.WithHighLightFormat("./"+filepath.ToSlash(rel)),
+		})
+	}
+
+	return nil
+}
+
 func (i *Initializer) genProjectFile(
 	ctx context.Context,
 	azdCtx *azdcontext.AzdContext,
@@ -17,6 +17,9 @@ import (
 // A regex that matches against "likely" well-formed database names
 var wellFormedDbNameRegex = regexp.MustCompile(`^[a-zA-Z\-_0-9]*$`)
 
+// The port of Go services that are containerized with a generated Dockerfile.
+const goServicePort = 8080
+
 // infraSpecFromDetect creates an InfraSpec from the results of app detection confirmation,
 // prompting for additional inputs if necessary.
 func (i *Initializer) infraSpecFromDetect(
@@ -107,6 +110,11 @@ func (i *Initializer) infraSpecFromDetect(
 			if svc.Language == appdetect.Java {
 				serviceSpec.Port = 8080
 			}
+
+			// the Dockerfile generated for Go services listens on goServicePort
+			if svc.Language == appdetect.Go {
+				serviceSpec.Port = goServicePort
+			}
 		}
 
 		for _, framework := range svc.Dependencies {
@@ -158,3 +158,17 @@ func TestExecInfra(t *testing.T) {
 		})
 	}
 }
+
+func TestExecDockerfileGo(t *testing.T) {
+	template, err := Load()
+	require.NoError(t, err)
+
+	dest := filepath.Join(t.TempDir(), "Dockerfile")
+	err = Execute(template, "go.Dockerfile", DockerfileGo{GoVersion: "1.22", Port: 8080}, dest)
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "FROM golang:1.22 AS build")
+	require.Contains(t, string(contents), "EXPOSE 8080")
+}
@@ -110,3 +110,11 @@ func serviceDefPlaceholder(serviceName string) Parameter {
 		Secret: true,
 	}
 }
+
+// DockerfileGo describes a Dockerfile generated for a Go service.
+type DockerfileGo struct {
+	// The Go version of the builder image, as declared in go.mod.
+	GoVersion string
+	// The port the service listens on, provided to the service with the PORT environment variable.
+	Port int
+}
@@ -2,4 +2,5 @@ package alpha
 
 const (
 	TerraformId FeatureId = "terraform"
+	PulumiId    FeatureId = "pulumi"
 )
@@ -153,6 +153,13 @@ func NewManager(
 
 // LoginScopes returns the scopes that we request an access token for when checking if a user is signed in.
 func LoginScopes(cloud *cloud.Cloud) []string {
+	// The audience of the resource manager of a custom cloud, such as Azure Stack Hub, may differ from its endpoint
+	if cloud.IsCustom() {
+		return []string{
+			fmt.Sprintf("%s/.default", cloud.Configuration.Services[azcloud.ResourceManager].Audience),
+		}
+	}
+
 	resourceManagerUrl := cloud.Configuration.Services[azcloud.ResourceManager].Endpoint
 	return []string{
 		fmt.Sprintf("%s//.default", resourceManagerUrl),
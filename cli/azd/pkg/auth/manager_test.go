@@ -53,6 +53,21 @@ func TestReadUserProperties(t *testing.T) {
 	})
 }
 
+func TestLoginScopes(t *testing.T) {
+	require.Equal(t, []string{"https://management.azure.com//.default"}, LoginScopes(cloud.AzurePublic()))
+
+	customCloud, err := cloud.NewCloud(context.Background(), &cloud.Config{
+		ResourceManagerEndpoint: "https://management.local.azurestack.external",
+		ResourceManagerAudience: "https://management.adfs.azurestack.external/00000000-0000-0000-0000-000000000000",
+		AuthorityHost:           "https://adfs.local.azurestack.external/adfs",
+		PortalUrl:               "https://portal.local.azurestack.external",
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t,
+		[]string{"https://management.adfs.azurestack.external/00000000-0000-0000-0000-000000000000/.default"},
+		LoginScopes(customCloud))
+}
+
 func TestServicePrincipalLoginClientSecret(t *testing.T) {
 	credentialCache := &memoryCache{
 		cache: make(map[string][]byte),
@@ -2,7 +2,9 @@ package azd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
@@ -10,14 +12,18 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	infraBicep "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
+	infraPulumi "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/pulumi"
 	infraTerraform "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/terraform"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/platform"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/pkg/sqldb"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
+	"github.com/azure/azure-dev/cli/azd/pkg/state/filesystem"
+	"github.com/azure/azure-dev/cli/azd/pkg/state/s3"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/pulumi"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
 )
 
@@ -47,11 +53,13 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 	// Tools
 	container.MustRegisterSingleton(terraform.NewTerraformCli)
 	container.MustRegisterSingleton(bicep.NewBicepCli)
+	container.MustRegisterSingleton(pulumi.NewPulumiCli)
 
 	// Provisioning Providers
 	provisionProviderMap := map[provisioning.ProviderKind]any{
 		provisioning.Bicep:     infraBicep.NewBicepProvider,
 		provisioning.Terraform: infraTerraform.NewTerraformProvider,
+		provisioning.Pulumi:    infraPulumi.NewPulumiProvider,
 	}
 
 	for provider, constructor := range provisionProviderMap {
@@ -68,6 +76,8 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 	// Remote Environment State Providers
 	remoteStateProviderMap := map[environment.RemoteKind]any{
 		environment.RemoteKindAzureBlobStorage: environment.NewStorageBlobDataStore,
+		environment.RemoteKindFileSystem:       environment.NewFileSystemDataStore,
+		environment.RemoteKindS3:               environment.NewS3DataStore,
 	}
 
 	for remoteKind, constructor := range remoteStateProviderMap {
@@ -83,13 +93,8 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 		}
 
 		var storageAccountConfig *storage.AccountConfig
-		jsonBytes, err := json.Marshal(remoteStateConfig.Config)
-		if err != nil {
-			return nil, fmt.Errorf("marshalling remote state config: %w", err)
-		}
-
-		if err := json.Unmarshal(jsonBytes, &storageAccountConfig); err != nil {
-			return nil, fmt.Errorf("unmarshalling remote state config: %w", err)
+		if err := unmarshalRemoteStateConfig(remoteStateConfig, &storageAccountConfig); err != nil {
+			return nil, err
 		}
 
 		// If a container name has not been explicitly configured
@@ -103,6 +108,60 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 		return storageAccountConfig, nil
 	})
 
+	container.MustRegisterSingleton(func(
+		remoteStateConfig *state.RemoteConfig,
+		projectConfig *project.ProjectConfig,
+	) (*filesystem.Config, error) {
+		if remoteStateConfig == nil {
+			return nil, nil
+		}
+
+		fsConfig := &filesystem.Config{}
+		if err := unmarshalRemoteStateConfig(remoteStateConfig, fsConfig); err != nil {
+			return nil, err
+		}
+
+		if fsConfig.Path == "" {
+			return nil, errors.New("remote state configuration is invalid. The 'path' of the directory is required.")
+		}
+
+		if !filepath.IsAbs(fsConfig.Path) {
+			fsConfig.Path = filepath.Join(projectConfig.Path, fsConfig.Path)
+		}
+
+		// Default to use the project name as the folder of the project within the shared directory
+		if fsConfig.Folder == "" {
+			fsConfig.Folder = projectConfig.Name
+		}
+
+		return fsConfig, nil
+	})
+
+	container.MustRegisterSingleton(func(
+		remoteStateConfig *state.RemoteConfig,
+		projectConfig *project.ProjectConfig,
+	) (*s3.Config, error) {
+		if remoteStateConfig == nil {
+			return nil, nil
+		}
+
+		s3Config := &s3.Config{}
+		if err := unmarshalRemoteStateConfig(remoteStateConfig, s3Config); err != nil {
+			return nil, err
+		}
+
+		if s3Config.Bucket == "" {
+			return nil, errors.New("remote state configuration is invalid. The 'bucket' name is required.")
+		}
+
+		// Default to use the project name as the prefix of the keys of the project within the bucket
+		if s3Config.Prefix == "" {
+			s3Config.Prefix = projectConfig.Name
+		}
+
+		return s3Config, nil
+	})
+
 	// Storage components
 	container.MustRegisterSingleton(storage.NewBlobClient)
 	container.MustRegisterSingleton(storage.NewBlobSdkClient)
@@ -125,3 +184,17 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 
 	return nil
 }
+
+// unmarshalRemoteStateConfig converts the free-form configuration of the remote state backend to its typed configuration
+func unmarshalRemoteStateConfig(remoteStateConfig *state.RemoteConfig, target any) error {
+	jsonBytes, err := json.Marshal(remoteStateConfig.Config)
+	if err != nil {
+		return fmt.Errorf("marshalling remote state config: %w", err)
+	}
+
+	if err := json.Unmarshal(jsonBytes, target); err != nil {
+		return fmt.Errorf("unmarshalling remote state config: %w", err)
+	}
+
+	return nil
+}
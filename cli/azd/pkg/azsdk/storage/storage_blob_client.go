@@ -9,7 +9,10 @@ import (
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"github.com/azure/azure-dev/cli/azd/pkg/auth"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 )
@@ -23,18 +26,42 @@ type AccountConfig struct {
 
 var (
 	ErrContainerNotFound = errors.New("container not found")
+	ErrBlobNotFound      = errors.New("blob not found")
+	// Returned by conditional operations when the condition isn't met
+	ErrPreconditionFailed = errors.New("precondition failed")
 )
 
+// Condition is the precondition of a conditional blob operation.
+// The zero value is unconditional.
+type Condition struct {
+	// When set, the operation is performed only when the ETag of the blob matches.
+	IfMatch string
+	// When true, the operation is performed only when the blob doesn't exist.
+	IfNotExists bool
+}
+
 type BlobClient interface {
 	// Download downloads a blob from the configured storage account container.
 	Download(ctx context.Context, blobPath string) (io.ReadCloser, error)
 
+	// DownloadWithETag downloads a blob from the configured storage account container, along with the ETag of the
+	// downloaded version of the blob.
+	DownloadWithETag(ctx context.Context, blobPath string) (io.ReadCloser, string, error)
+
 	// Upload uploads a blob to the configured storage account container.
 	Upload(ctx context.Context, blobPath string, reader io.Reader) error
 
+	// UploadIf uploads a blob to the configured storage account container when the condition is met, returning the
+	// ETag of the uploaded blob. ErrPreconditionFailed is returned when the condition isn't met.
+	UploadIf(ctx context.Context, blobPath string, reader io.Reader, condition Condition) (string, error)
+
 	// Delete deletes a blob from the configured storage account container.
 	Delete(ctx context.Context, blobPath string) error
 
+	// DeleteIf deletes a blob from the configured storage account container when the condition is met.
+	// ErrPreconditionFailed is returned when the condition isn't met.
+	DeleteIf(ctx context.Context, blobPath string, condition Condition) error
+
 	// Items returns a list of blobs in the configured storage account container.
 	Items(ctx context.Context) ([]*Blob, error)
 }
@@ -61,6 +88,7 @@ type Blob struct {
 	Path         string
 	CreationTime time.Time
 	LastModified time.Time
+	ETag         string
 }
 
 // Items returns a list of blobs in the configured storage account container.
@@ -84,6 +112,7 @@ func (bc *blobClient) Items(ctx context.Context) ([]*Blob, error) {
 				Path:         *blob.Name,
 				CreationTime: *blob.Properties.CreationTime,
 				LastModified: *blob.Properties.LastModified,
+				ETag:         string(*blob.Properties.ETag),
 			})
 		}
 	}
@@ -93,46 +122,112 @@ func (bc *blobClient) Items(ctx context.Context) ([]*Blob, error) {
 
 // Download downloads a blob from the configured storage account container.
 func (bc *blobClient) Download(ctx context.Context, blobPath string) (io.ReadCloser, error) {
+	body, _, err := bc.DownloadWithETag(ctx, blobPath)
+	return body, err
+}
+
+// DownloadWithETag downloads a blob from the configured storage account container, along with the ETag of the
+// downloaded version of the blob.
+func (bc *blobClient) DownloadWithETag(ctx context.Context, blobPath string) (io.ReadCloser, string, error) {
 	if err := bc.ensureContainerExists(ctx); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	resp, err := bc.client.DownloadStream(ctx, bc.config.ContainerName, blobPath, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to download blob '%s', %w", blobPath, err)
+		return nil, "", fmt.Errorf("failed to download blob '%s', %w", blobPath, describeError(err))
 	}
 
-	return resp.Body, nil
+	etag := ""
+	if resp.ETag != nil {
+		etag = string(*resp.ETag)
+	}
+
+	return resp.Body, etag, nil
 }
 
 // Upload uploads a blob to the configured storage account container.
 func (bc *blobClient) Upload(ctx context.Context, blobPath string, reader io.Reader) error {
+	_, err := bc.UploadIf(ctx, blobPath, reader, Condition{})
+	return err
+}
+
+// UploadIf uploads a blob to the configured storage account container when the condition is met, returning the
+// ETag of the uploaded blob.
+func (bc *blobClient) UploadIf(
+	ctx context.Context,
+	blobPath string,
+	reader io.Reader,
+	condition Condition,
+) (string, error) {
 	if err := bc.ensureContainerExists(ctx); err != nil {
-		return err
+		return "", err
 	}
 
-	_, err := bc.client.UploadStream(ctx, bc.config.ContainerName, blobPath, reader, nil)
+	resp, err := bc.client.UploadStream(ctx, bc.config.ContainerName, blobPath, reader, &azblob.UploadStreamOptions{
+		AccessConditions: accessConditions(condition),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, describeError(err))
 	}
 
-	return nil
+	etag := ""
+	if resp.ETag != nil {
+		etag = string(*resp.ETag)
+	}
+
+	return etag, nil
 }
 
 // Delete deletes a blob from the configured storage account container.
 func (bc *blobClient) Delete(ctx context.Context, blobPath string) error {
+	return bc.DeleteIf(ctx, blobPath, Condition{})
+}
+
+// DeleteIf deletes a blob from the configured storage account container when the condition is met.
+func (bc *blobClient) DeleteIf(ctx context.Context, blobPath string, condition Condition) error {
 	if err := bc.ensureContainerExists(ctx); err != nil {
 		return err
 	}
 
-	_, err := bc.client.DeleteBlob(ctx, bc.config.ContainerName, blobPath, nil)
+	_, err := bc.client.DeleteBlob(ctx, bc.config.ContainerName, blobPath, &azblob.DeleteBlobOptions{
+		AccessConditions: accessConditions(condition),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to delete blob '%s', %w", blobPath, err)
+		return fmt.Errorf("failed to delete blob '%s', %w", blobPath, describeError(err))
 	}
 
 	return nil
 }
 
+// accessConditions converts a condition to the access conditions of a blob operation
+func accessConditions(condition Condition) *blob.AccessConditions {
+	modifiedConditions := &blob.ModifiedAccessConditions{}
+	if condition.IfMatch != "" {
+		modifiedConditions.IfMatch = to.Ptr(azcore.ETag(condition.IfMatch))
+	}
+
+	if condition.IfNotExists {
+		modifiedConditions.IfNoneMatch = to.Ptr(azcore.ETagAny)
+	}
+
+	return &blob.AccessConditions{
+		ModifiedAccessConditions: modifiedConditions,
+	}
+}
+
+// describeError wraps well-known storage errors with the corresponding sentinel errors
+func describeError(err error) error {
+	switch {
+	case bloberror.HasCode(err, bloberror.ConditionNotMet, bloberror.BlobAlreadyExists):
+		return fmt.Errorf("%w: %w", ErrPreconditionFailed, err)
+	case bloberror.HasCode(err, bloberror.BlobNotFound):
+		return fmt.Errorf("%w: %w", ErrBlobNotFound, err)
+	}
+
+	return err
+}
+
 // Check if the specified container exists
 // If it doesn't already exist then create it
 func (bc *blobClient) ensureContainerExists(ctx context.Context) error {
@@ -1,10 +1,17 @@
 package cloud
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 )
 
 const (
@@ -33,20 +40,96 @@ type Cloud struct {
 	// known values and can be found at:
 	// https://<management-endpoint>/metadata/endpoints?api-version=2023-12-01
 	ContainerRegistryEndpointSuffix string
+
+	// The suffix for the cloud's key vault endpoints (e.g. vault.azure.net for
+	// Azure public cloud).
+	KeyVaultEndpointSuffix string
+
+	// custom is true for the clouds defined by their endpoints, such as Azure Stack Hub or air-gapped clouds
+	custom bool
+}
+
+// IsCustom returns true when the cloud is defined by its endpoints in the cloud configuration, rather than by the name
+// of a well known cloud
+func (c *Cloud) IsCustom() bool {
+	return c.custom
 }
 
 type Config struct {
 	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+
+	// The https URL or the path of an ARM metadata/endpoints document defining a custom cloud, such as
+	// https://management.local.azurestack.external/metadata/endpoints?api-version=2022-09-01. The endpoints set in the
+	// configuration override the endpoints of the document.
+	Metadata string `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	// The endpoints of a custom cloud
+	ResourceManagerEndpoint string `json:"resourceManagerEndpoint,omitempty" yaml:"resourceManagerEndpoint,omitempty"`
+	ResourceManagerAudience string `json:"resourceManagerAudience,omitempty" yaml:"resourceManagerAudience,omitempty"`
+	AuthorityHost           string `json:"authorityHost,omitempty" yaml:"authorityHost,omitempty"`
+	PortalUrl               string `json:"portalUrl,omitempty" yaml:"portalUrl,omitempty"`
+	StorageSuffix           string `json:"storageSuffix,omitempty" yaml:"storageSuffix,omitempty"`
+	ContainerRegistrySuffix string `json:"containerRegistrySuffix,omitempty" yaml:"containerRegistrySuffix,omitempty"`
+	KeyVaultSuffix          string `json:"keyVaultSuffix,omitempty" yaml:"keyVaultSuffix,omitempty"`
 }
 
-func NewCloud(config *Config) (*Cloud, error) {
-	if cloud, err := parseCloudName(config.Name); err != nil {
-		return nil, err
-	} else {
-		return cloud, nil
+// isCustom returns true when the configuration defines a custom cloud
+func (c *Config) isCustom() bool {
+	return c.Metadata != "" || c.ResourceManagerEndpoint != "" || c.AuthorityHost != ""
+}
+
+// Validate checks the configuration defines a cloud without loading the metadata document of a custom cloud, which is
+// loaded when the cloud is created by [NewCloud]
+func (c *Config) Validate() error {
+	if !c.isCustom() {
+		_, err := parseCloudName(c.Name)
+		return err
+	}
+
+	if c.Metadata != "" {
+		_, err := isMetadataUrl(c.Metadata)
+		return err
+	}
+
+	return validateEndpoints(c)
+}
+
+// displayName returns the name identifying the cloud of the configuration in messages
+func (c *Config) displayName() string {
+	switch {
+	case c.Name != "":
+		return c.Name
+	case c.Metadata != "":
+		return c.Metadata
+	default:
+		return c.ResourceManagerEndpoint
 	}
 }
 
+// NewCloud returns the cloud of the configuration, either a well known cloud or a custom cloud. The endpoints of a custom
+// cloud are loaded from its metadata document when configured.
+func NewCloud(ctx context.Context, config *Config, httpClient httputil.HttpClient) (*Cloud, error) {
+	if !config.isCustom() {
+		return parseCloudName(config.Name)
+	}
+
+	if config.Metadata != "" {
+		metadata, err := loadMetadata(ctx, config.Metadata, httpClient)
+		if err != nil {
+			return nil, fmt.Errorf("loading custom cloud '%s': %w", config.displayName(), err)
+		}
+
+		config = mergeConfig(config, metadata.config())
+	}
+
+	customCloud, err := newCustomCloud(config)
+	if err != nil {
+		return nil, fmt.Errorf("loading custom cloud '%s': %w", config.displayName(), err)
+	}
+
+	return customCloud, nil
+}
+
 func ParseCloudConfig(partialConfig any) (*Config, error) {
 	var config *Config
 
@@ -68,6 +151,7 @@ func AzurePublic() *Cloud {
 		PortalUrlBase:                   "https://portal.azure.com",
 		StorageEndpointSuffix:           "core.windows.net",
 		ContainerRegistryEndpointSuffix: "azurecr.io",
+		KeyVaultEndpointSuffix:          "vault.azure.net",
 	}
 }
 
@@ -77,6 +161,7 @@ func AzureGovernment() *Cloud {
 		PortalUrlBase:                   "https://portal.azure.us",
 		StorageEndpointSuffix:           "core.usgovcloudapi.net",
 		ContainerRegistryEndpointSuffix: "azurecr.us",
+		KeyVaultEndpointSuffix:          "vault.usgovcloudapi.net",
 	}
 }
 
@@ -86,6 +171,7 @@ func AzureChina() *Cloud {
 		PortalUrlBase:                   "https://portal.azure.cn",
 		StorageEndpointSuffix:           "core.chinacloudapi.cn",
 		ContainerRegistryEndpointSuffix: "azurecr.cn",
+		KeyVaultEndpointSuffix:          "vault.azure.cn",
 	}
 }
 
@@ -100,3 +186,190 @@ func parseCloudName(name string) (*Cloud, error) {
 
 	return &Cloud{}, fmt.Errorf("Cloud name '%s' not found.", name)
 }
+
+// newCustomCloud returns the cloud defined by the endpoints of the configuration
+func newCustomCloud(config *Config) (*Cloud, error) {
+	if err := validateEndpoints(config); err != nil {
+		return nil, err
+	}
+
+	audience := config.ResourceManagerAudience
+	if audience == "" {
+		audience = config.ResourceManagerEndpoint
+	}
+
+	return &Cloud{
+		Configuration: cloud.Configuration{
+			ActiveDirectoryAuthorityHost: ensureTrailingSlash(config.AuthorityHost),
+			Services: map[cloud.ServiceName]cloud.ServiceConfiguration{
+				cloud.ResourceManager: {
+					Audience: audience,
+					Endpoint: strings.TrimSuffix(config.ResourceManagerEndpoint, "/"),
+				},
+			},
+		},
+		PortalUrlBase:                   strings.TrimSuffix(config.PortalUrl, "/"),
+		StorageEndpointSuffix:           strings.TrimPrefix(config.StorageSuffix, "."),
+		ContainerRegistryEndpointSuffix: strings.TrimPrefix(config.ContainerRegistrySuffix, "."),
+		KeyVaultEndpointSuffix:          strings.TrimPrefix(config.KeyVaultSuffix, "."),
+		custom:                          true,
+	}, nil
+}
+
+// validateEndpoints checks the endpoints required by a custom cloud are set
+func validateEndpoints(config *Config) error {
+	if config.ResourceManagerEndpoint == "" {
+		return errors.New("the resource manager endpoint of the custom cloud isn't set, set 'resourceManagerEndpoint'")
+	}
+
+	if config.AuthorityHost == "" {
+		return errors.New("the authority host of the custom cloud isn't set, set 'authorityHost'")
+	}
+
+	// The portal url is used to link to the deployments and the resources of the cloud
+	if config.PortalUrl == "" {
+		return errors.New("the portal url of the custom cloud isn't set, set 'portalUrl'")
+	}
+
+	return nil
+}
+
+// mergeConfig returns the configuration with the endpoints which aren't set taken from the defaults
+func mergeConfig(config *Config, defaults *Config) *Config {
+	merged := *config
+	for _, field := range []struct {
+		value        *string
+		defaultValue string
+	}{
+		{&merged.ResourceManagerEndpoint, defaults.ResourceManagerEndpoint},
+		{&merged.ResourceManagerAudience, defaults.ResourceManagerAudience},
+		{&merged.AuthorityHost, defaults.AuthorityHost},
+		{&merged.PortalUrl, defaults.PortalUrl},
+		{&merged.StorageSuffix, defaults.StorageSuffix},
+		{&merged.ContainerRegistrySuffix, defaults.ContainerRegistrySuffix},
+		{&merged.KeyVaultSuffix, defaults.KeyVaultSuffix},
+	} {
+		if *field.value == "" {
+			*field.value = field.defaultValue
+		}
+	}
+
+	return &merged
+}
+
+func ensureTrailingSlash(url string) string {
+	if strings.HasSuffix(url, "/") {
+		return url
+	}
+
+	return url + "/"
+}
+
+// cloudMetadata is an ARM metadata/endpoints document. Both the current format (api-version 2022-09-01) and the legacy
+// format of Azure Stack Hub (api-version 2015-01-01) are supported.
+type cloudMetadata struct {
+	ResourceManager string `json:"resourceManager"`
+	Portal          string `json:"portal"`
+	PortalEndpoint  string `json:"portalEndpoint"`
+	Authentication  struct {
+		LoginEndpoint string   `json:"loginEndpoint"`
+		Audiences     []string `json:"audiences"`
+	} `json:"authentication"`
+	Suffixes struct {
+		Storage        string `json:"storage"`
+		AcrLoginServer string `json:"acrLoginServer"`
+		KeyVaultDns    string `json:"keyVaultDns"`
+	} `json:"suffixes"`
+
+	// The resource manager endpoint the document was loaded from, for the legacy format which doesn't include it
+	endpoint string
+}
+
+// config returns the endpoints of the metadata document as a cloud configuration
+func (m *cloudMetadata) config() *Config {
+	config := &Config{
+		ResourceManagerEndpoint: m.ResourceManager,
+		AuthorityHost:           m.Authentication.LoginEndpoint,
+		PortalUrl:               m.Portal,
+		StorageSuffix:           m.Suffixes.Storage,
+		ContainerRegistrySuffix: m.Suffixes.AcrLoginServer,
+		KeyVaultSuffix:          m.Suffixes.KeyVaultDns,
+	}
+
+	if config.ResourceManagerEndpoint == "" {
+		config.ResourceManagerEndpoint = m.endpoint
+	}
+
+	if config.PortalUrl == "" {
+		config.PortalUrl = m.PortalEndpoint
+	}
+
+	if len(m.Authentication.Audiences) > 0 {
+		config.ResourceManagerAudience = m.Authentication.Audiences[0]
+	}
+
+	return config
+}
+
+// isMetadataUrl returns true when the metadata document is loaded from a URL rather than from a file. Only https URLs are
+// supported, since the document defines the endpoints the credentials of the user are sent to.
+func isMetadataUrl(location string) (bool, error) {
+	if !strings.Contains(location, "://") {
+		return false, nil
+	}
+
+	if strings.HasPrefix(strings.ToLower(location), "https://") {
+		return true, nil
+	}
+
+	return false, fmt.Errorf("the cloud metadata '%s' must be an https URL or the path of a file", location)
+}
+
+// loadMetadata reads the metadata document from its URL or its path
+func loadMetadata(ctx context.Context, location string, httpClient httputil.HttpClient) (*cloudMetadata, error) {
+	var content []byte
+	var endpoint string
+
+	isUrl, err := isMetadataUrl(location)
+	if err != nil {
+		return nil, err
+	}
+
+	if isUrl {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+
+		res, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("loading cloud metadata from '%s': %w", location, err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("loading cloud metadata from '%s': unexpected status %s", location, res.Status)
+		}
+
+		content, err = io.ReadAll(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("loading cloud metadata from '%s': %w", location, err)
+		}
+
+		// The metadata of the resource manager is served by the resource manager
+		endpoint = fmt.Sprintf("%s://%s", req.URL.Scheme, req.URL.Host)
+	} else {
+		content, err = os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("loading cloud metadata: %w", err)
+		}
+	}
+
+	metadata := &cloudMetadata{}
+	if err := json.Unmarshal(content, metadata); err != nil {
+		return nil, fmt.Errorf("parsing cloud metadata from '%s': %w", location, err)
+	}
+
+	metadata.endpoint = endpoint
+	return metadata, nil
+}
@@ -0,0 +1,191 @@
+package cloud
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/stretchr/testify/require"
+)
+
+// The metadata/endpoints document of an Azure Stack Hub, in the legacy format
+const azureStackMetadata = `{
+	"galleryEndpoint": "https://providers.local.azurestack.external:30016/",
+	"graphEndpoint": "https://graph.windows.net/",
+	"portalEndpoint": "https://portal.local.azurestack.external/",
+	"authentication": {
+		"loginEndpoint": "https://adfs.local.azurestack.external/adfs",
+		"audiences": ["https://management.adfs.azurestack.external/00000000-0000-0000-0000-000000000000"]
+	}
+}`
+
+// The metadata/endpoints document of an air-gapped cloud
+const airGappedMetadata = `{
+	"portal": "https://portal.contoso.cloud",
+	"resourceManager": "https://management.contoso.cloud/",
+	"authentication": {
+		"loginEndpoint": "https://login.contoso.cloud",
+		"audiences": ["https://management.core.contoso.cloud/"]
+	},
+	"suffixes": {
+		"storage": "core.contoso.cloud",
+		"acrLoginServer": "azurecr.contoso.cloud",
+		"keyVaultDns": "vault.contoso.cloud"
+	}
+}`
+
+type httpClientFunc func(req *http.Request) (*http.Response, error)
+
+func (f httpClientFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func metadataHttpClient(t *testing.T, metadata string) httpClientFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "/metadata/endpoints", req.URL.Path)
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(metadata)),
+			Request:    req,
+		}, nil
+	}
+}
+
+func Test_NewCloud(t *testing.T) {
+	t.Run("WellKnown", func(t *testing.T) {
+		azureCloud, err := NewCloud(context.Background(), &Config{Name: AzureUSGovernmentName}, nil)
+		require.NoError(t, err)
+		require.Equal(t, AzureGovernment(), azureCloud)
+		require.False(t, azureCloud.IsCustom())
+
+		_, err = NewCloud(context.Background(), &Config{Name: "AzureStackCloud"}, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("Inline", func(t *testing.T) {
+		azureCloud, err := NewCloud(context.Background(), &Config{
+			ResourceManagerEndpoint: "https://management.contoso.cloud/",
+			AuthorityHost:           "https://login.contoso.cloud",
+			PortalUrl:               "https://portal.contoso.cloud/",
+			StorageSuffix:           ".core.contoso.cloud",
+			ContainerRegistrySuffix: "azurecr.contoso.cloud",
+		}, nil)
+		require.NoError(t, err)
+		require.True(t, azureCloud.IsCustom())
+
+		require.Equal(t, "https://login.contoso.cloud/", azureCloud.Configuration.ActiveDirectoryAuthorityHost)
+		require.Equal(t, cloud.ServiceConfiguration{
+			Audience: "https://management.contoso.cloud/",
+			Endpoint: "https://management.contoso.cloud",
+		}, azureCloud.Configuration.Services[cloud.ResourceManager])
+		require.Equal(t, "https://portal.contoso.cloud", azureCloud.PortalUrlBase)
+		require.Equal(t, "core.contoso.cloud", azureCloud.StorageEndpointSuffix)
+		require.Equal(t, "azurecr.contoso.cloud", azureCloud.ContainerRegistryEndpointSuffix)
+	})
+
+	t.Run("MissingEndpoints", func(t *testing.T) {
+		_, err := NewCloud(context.Background(), &Config{
+			ResourceManagerEndpoint: "https://management.contoso.cloud",
+		}, nil)
+		require.ErrorContains(t, err, "'authorityHost'")
+	})
+
+	t.Run("MissingPortalUrl", func(t *testing.T) {
+		_, err := NewCloud(context.Background(), &Config{
+			Metadata: "https://management.local.azurestack.external/metadata/endpoints?api-version=2015-01-01",
+		}, metadataHttpClient(t, `{"authentication": {"loginEndpoint": "https://adfs.local.azurestack.external/adfs"}}`))
+		require.ErrorContains(t, err, "'portalUrl'")
+		require.ErrorContains(t, err,
+			"custom cloud 'https://management.local.azurestack.external/metadata/endpoints?api-version=2015-01-01'")
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		azureCloud, err := NewCloud(context.Background(), &Config{
+			Metadata:       "https://management.contoso.cloud/metadata/endpoints?api-version=2022-09-01",
+			KeyVaultSuffix: "vault.override.cloud",
+		}, metadataHttpClient(t, airGappedMetadata))
+		require.NoError(t, err)
+		require.True(t, azureCloud.IsCustom())
+
+		require.Equal(t, "https://login.contoso.cloud/", azureCloud.Configuration.ActiveDirectoryAuthorityHost)
+		require.Equal(t, cloud.ServiceConfiguration{
+			Audience: "https://management.core.contoso.cloud/",
+			Endpoint: "https://management.contoso.cloud",
+		}, azureCloud.Configuration.Services[cloud.ResourceManager])
+		require.Equal(t, "https://portal.contoso.cloud", azureCloud.PortalUrlBase)
+		require.Equal(t, "core.contoso.cloud", azureCloud.StorageEndpointSuffix)
+		require.Equal(t, "azurecr.contoso.cloud", azureCloud.ContainerRegistryEndpointSuffix)
+
+		// The endpoints of the configuration override the endpoints of the document
+		require.Equal(t, "vault.override.cloud", azureCloud.KeyVaultEndpointSuffix)
+	})
+
+	t.Run("LegacyMetadata", func(t *testing.T) {
+		azureCloud, err := NewCloud(context.Background(), &Config{
+			Metadata: "https://management.local.azurestack.external/metadata/endpoints?api-version=2015-01-01",
+		}, metadataHttpClient(t, azureStackMetadata))
+		require.NoError(t, err)
+
+		require.Equal(t, "https://adfs.local.azurestack.external/adfs/",
+			azureCloud.Configuration.ActiveDirectoryAuthorityHost)
+		require.Equal(t, cloud.ServiceConfiguration{
+			Audience: "https://management.adfs.azurestack.external/00000000-0000-0000-0000-000000000000",
+			Endpoint: "https://management.local.azurestack.external",
+		}, azureCloud.Configuration.Services[cloud.ResourceManager])
+		require.Equal(t, "https://portal.local.azurestack.external", azureCloud.PortalUrlBase)
+	})
+
+	t.Run("MetadataFile", func(t *testing.T) {
+		metadataPath := filepath.Join(t.TempDir(), "endpoints.json")
+		require.NoError(t, os.WriteFile(metadataPath, []byte(airGappedMetadata), 0600))
+
+		azureCloud, err := NewCloud(context.Background(), &Config{Metadata: metadataPath}, nil)
+		require.NoError(t, err)
+		require.Equal(t, "https://management.contoso.cloud",
+			azureCloud.Configuration.Services[cloud.ResourceManager].Endpoint)
+	})
+
+	t.Run("MetadataNotFound", func(t *testing.T) {
+		_, err := NewCloud(context.Background(), &Config{
+			Metadata: "https://management.contoso.cloud/metadata/endpoints?api-version=2022-09-01",
+		}, httpClientFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Status:     "404 Not Found",
+				Body:       io.NopCloser(strings.NewReader("")),
+				Request:    req,
+			}, nil
+		}))
+		require.ErrorContains(t, err, "unexpected status 404 Not Found")
+	})
+
+	t.Run("MetadataInsecureUrl", func(t *testing.T) {
+		_, err := NewCloud(context.Background(), &Config{
+			Metadata: "http://management.contoso.cloud/metadata/endpoints?api-version=2022-09-01",
+		}, httpClientFunc(func(req *http.Request) (*http.Response, error) {
+			require.Fail(t, "the metadata document must not be requested over http")
+			return nil, nil
+		}))
+		require.ErrorContains(t, err, "must be an https URL or the path of a file")
+	})
+}
+
+func Test_Config_Validate(t *testing.T) {
+	require.NoError(t, (&Config{Name: AzureChinaCloudName}).Validate())
+	require.Error(t, (&Config{Name: "AzureStackCloud"}).Validate())
+
+	// The metadata document isn't loaded
+	require.NoError(t, (&Config{Metadata: "https://management.contoso.cloud/metadata/endpoints"}).Validate())
+	require.Error(t, (&Config{Metadata: "http://management.contoso.cloud/metadata/endpoints"}).Validate())
+
+	require.ErrorContains(t, (&Config{
+		ResourceManagerEndpoint: "https://management.contoso.cloud",
+		AuthorityHost:           "https://login.contoso.cloud",
+	}).Validate(), "'portalUrl'")
+}
@@ -263,6 +263,11 @@ func (c *config) GetSection(path string, section any) (bool, error) {
 
 // getSecret retrieves the secret stored at the specified path from a local user vault
 func (c *config) getSecret(vaultRef string) (string, bool) {
+	// The vault is only loaded with configurations stored in files, such as the local config of environments
+	if c.vault == nil {
+		return "", false
+	}
+
 	encodedValue, ok := c.vault.GetString(filepath.Base(vaultRef))
 	if !ok {
 		return "", false
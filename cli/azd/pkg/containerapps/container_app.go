@@ -45,7 +45,7 @@ type ContainerAppService interface {
 		containerAppYaml []byte,
 		progressLog func(string),
 	) error
-	// Adds and activates a new revision to the specified container app
+	// Adds and activates a new revision to the specified container app, and returns the name of the revision
 	AddRevision(
 		ctx context.Context,
 		subscriptionId string,
@@ -53,12 +53,46 @@ type ContainerAppService interface {
 		appName string,
 		imageName string,
 		progressLog func(string),
-	) error
+	) (string, error)
 	ListSecrets(ctx context.Context,
 		subscriptionId string,
 		resourceGroupName string,
 		appName string,
 	) ([]*armappcontainers.ContainerAppSecret, error)
+	// Adds a new revision to the specified container app without moving traffic to it, so the traffic can be shifted
+	// gradually with SetRevisionTraffic. The container app must use the multiple revisions mode, and
+	// ErrRolloutInProgress is returned while the rollout of a previous revision is in progress.
+	AddCandidateRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		imageName string,
+		progressLog func(string),
+	) (*RevisionTraffic, error)
+	// Gets the traffic split of the rollout in progress for the specified container app
+	GetRevisionTraffic(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+	) (*RevisionTraffic, error)
+	// Splits the traffic of the specified container app between the stable and the candidate revision
+	SetRevisionTraffic(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		traffic *RevisionTraffic,
+	) error
+	// Deactivates the revision of the specified container app
+	DeactivateRevision(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		appName string,
+		revisionName string,
+	) error
 }
 
 // NewContainerAppService creates a new ContainerAppService
@@ -221,6 +255,7 @@ func (cas *containerAppService) DeployYaml(
 
 // Adds and activates a new revision to the specified container app, by applying a new revision with only the image
 // updated to the specified image name. The revision is waited on until it is in a running state.
+// Returns the name of the new revision.
 func (cas *containerAppService) AddRevision(
 	ctx context.Context,
 	subscriptionId string,
@@ -228,60 +263,79 @@ func (cas *containerAppService) AddRevision(
 	appName string,
 	imageName string,
 	progress func(string),
-) error {
+) (string, error) {
 	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
 	if err != nil {
-		return fmt.Errorf("getting container app: %w", err)
-	}
-
-	// Retrieve the latest revision name to perform a patch on
-	currentRevisionName := *containerApp.Properties.LatestRevisionName
-	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId)
-	if err != nil {
-		return err
+		return "", fmt.Errorf("getting container app: %w", err)
 	}
 
-	revisionResponse, err := revisionsClient.GetRevision(ctx, resourceGroupName, appName, currentRevisionName, nil)
+	newRevisionName, err := cas.setRevisionTemplate(ctx, subscriptionId, resourceGroupName, appName, containerApp, imageName)
 	if err != nil {
-		return fmt.Errorf("getting revision '%s': %w", currentRevisionName, err)
+		return "", err
 	}
 
-	// Update the revision with the new image name and suffix
-	revision := revisionResponse.Revision
-	newRevisionSuffix := fmt.Sprintf("azd-%d", cas.clock.Now().Unix())
-	// New revision name is always appName--revisionSuffix
-	// see https://learn.microsoft.com/en-us/azure/container-apps/revisions#name-suffix
-	newRevisionName := fmt.Sprintf("%s--%s", appName, newRevisionSuffix)
-	revision.Properties.Template.RevisionSuffix = &newRevisionSuffix
-	revision.Properties.Template.Containers[0].Image = convert.RefOf(imageName)
-
-	// Update the container app with the new revision
-	containerApp.Properties.Template = revision.Properties.Template
 	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
 	if err != nil {
-		return fmt.Errorf("syncing secrets: %w", err)
+		return "", fmt.Errorf("syncing secrets: %w", err)
 	}
 
 	// Update the container app
 	err = cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp)
 	if err != nil {
-		return fmt.Errorf("updating container app revision: %w", err)
+		return "", fmt.Errorf("updating container app revision: %w", err)
 	}
 
 	err = cas.waitForRevisionReady(ctx, subscriptionId, resourceGroupName, appName, newRevisionName, progress)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// If the container app is in multiple revision mode, update the traffic to point to the new revision
 	if *containerApp.Properties.Configuration.ActiveRevisionsMode == armappcontainers.ActiveRevisionsModeMultiple {
 		err = cas.setTrafficWeights(ctx, subscriptionId, resourceGroupName, appName, containerApp, newRevisionName)
 		if err != nil {
-			return fmt.Errorf("setting traffic weights: %w", err)
+			return "", fmt.Errorf("setting traffic weights: %w", err)
 		}
 	}
 
-	return nil
+	return newRevisionName, nil
+}
+
+// setRevisionTemplate sets the template of the container app to a new revision, based on its latest revision with only
+// the image updated to the specified image name. Returns the name of the new revision.
+func (cas *containerAppService) setRevisionTemplate(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	containerApp *armappcontainers.ContainerApp,
+	imageName string,
+) (string, error) {
+	// Retrieve the latest revision name to perform a patch on
+	currentRevisionName := *containerApp.Properties.LatestRevisionName
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId)
+	if err != nil {
+		return "", err
+	}
+
+	revisionResponse, err := revisionsClient.GetRevision(ctx, resourceGroupName, appName, currentRevisionName, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting revision '%s': %w", currentRevisionName, err)
+	}
+
+	// Update the revision with the new image name and suffix
+	revision := revisionResponse.Revision
+	newRevisionSuffix := fmt.Sprintf("azd-%d", cas.clock.Now().Unix())
+	// New revision name is always appName--revisionSuffix
+	// see https://learn.microsoft.com/en-us/azure/container-apps/revisions#name-suffix
+	newRevisionName := fmt.Sprintf("%s--%s", appName, newRevisionSuffix)
+	revision.Properties.Template.RevisionSuffix = &newRevisionSuffix
+	revision.Properties.Template.Containers[0].Image = convert.RefOf(imageName)
+
+	// Update the container app with the new revision
+	containerApp.Properties.Template = revision.Properties.Template
+
+	return newRevisionName, nil
 }
 
 func (cas *containerAppService) waitForRevisionReady(
@@ -153,8 +153,10 @@ func Test_ContainerApp_AddRevision(t *testing.T) {
 		cloud.AzurePublic().PortalUrlBase,
 	)
 	progressLog := func(_ string) {}
-	err := cas.AddRevision(*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, progressLog)
+	revisionName, err := cas.AddRevision(
+		*mockContext.Context, subscriptionId, resourceGroup, appName, updatedImageName, progressLog)
 	require.NoError(t, err)
+	require.Equal(t, appName+"--azd-0", revisionName)
 
 	// Verify lastest revision is read
 	expectedGetRevisionPath := fmt.Sprintf(
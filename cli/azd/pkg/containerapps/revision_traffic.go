@@ -0,0 +1,303 @@
+package containerapps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// ErrNoRolloutInProgress is returned when the traffic of a container app isn't split between a stable revision and a
+// candidate revision
+var ErrNoRolloutInProgress = errors.New("no rollout is in progress")
+
+// ErrRolloutInProgress is returned when a new revision is rolled out while the rollout of a previous revision is in
+// progress
+var ErrRolloutInProgress = errors.New("a rollout is in progress")
+
+// RevisionTraffic is the split of the traffic of a container app between the stable revision, which received the traffic
+// before the rollout, and the candidate revision being rolled out
+type RevisionTraffic struct {
+	StableRevision    string
+	CandidateRevision string
+	// The percentage of the traffic sent to the candidate revision
+	CandidateWeight int32
+	// The label of the candidate revision, which makes the revision reachable through its own URL
+	CandidateLabel string
+}
+
+// trafficWeights returns the traffic configuration of the ingress of the container app
+func (t *RevisionTraffic) trafficWeights() []*armappcontainers.TrafficWeight {
+	weights := []*armappcontainers.TrafficWeight{}
+
+	if t.CandidateWeight < 100 {
+		weights = append(weights, &armappcontainers.TrafficWeight{
+			RevisionName:   convert.RefOf(t.StableRevision),
+			Weight:         convert.RefOf(100 - t.CandidateWeight),
+			LatestRevision: convert.RefOf(false),
+		})
+	}
+
+	if t.CandidateWeight > 0 || t.CandidateLabel != "" {
+		weight := &armappcontainers.TrafficWeight{
+			RevisionName:   convert.RefOf(t.CandidateRevision),
+			Weight:         convert.RefOf(t.CandidateWeight),
+			LatestRevision: convert.RefOf(false),
+		}
+
+		if t.CandidateLabel != "" {
+			weight.Label = convert.RefOf(t.CandidateLabel)
+		}
+
+		weights = append(weights, weight)
+	}
+
+	return weights
+}
+
+// LabelHostName returns the host name of the revision with the specified label, given the host name of the container app.
+// See https://learn.microsoft.com/azure/container-apps/revisions#labels
+func LabelHostName(hostName string, label string) string {
+	appName, domain, found := strings.Cut(hostName, ".")
+	if !found {
+		return hostName
+	}
+
+	return fmt.Sprintf("%s---%s.%s", appName, label, domain)
+}
+
+// revisionTraffic returns the traffic split of the container app. The candidate revision is the latest revision, while the
+// stable revision is the other revision receiving the most traffic. A rollout is in progress while the candidate revision
+// receives part of the traffic, or is labeled.
+func revisionTraffic(containerApp *armappcontainers.ContainerApp) (*RevisionTraffic, error) {
+	traffic := &RevisionTraffic{
+		CandidateRevision: convert.ToValueWithDefault(containerApp.Properties.LatestRevisionName, ""),
+	}
+
+	stableWeight := int32(-1)
+	for _, weight := range containerApp.Properties.Configuration.Ingress.Traffic {
+		revisionName := convert.ToValueWithDefault(weight.RevisionName, "")
+		if convert.ToValueWithDefault(weight.LatestRevision, false) {
+			revisionName = traffic.CandidateRevision
+		}
+
+		if revisionName == traffic.CandidateRevision {
+			traffic.CandidateWeight += convert.ToValueWithDefault(weight.Weight, 0)
+			if weight.Label != nil {
+				traffic.CandidateLabel = *weight.Label
+			}
+
+			continue
+		}
+
+		if convert.ToValueWithDefault(weight.Weight, 0) > stableWeight {
+			traffic.StableRevision = revisionName
+			stableWeight = convert.ToValueWithDefault(weight.Weight, 0)
+		}
+	}
+
+	if traffic.StableRevision == "" || (traffic.CandidateWeight == 0 && traffic.CandidateLabel == "") {
+		return nil, ErrNoRolloutInProgress
+	}
+
+	return traffic, nil
+}
+
+// mostTrafficRevision returns the name of the revision receiving the most traffic
+func mostTrafficRevision(containerApp *armappcontainers.ContainerApp) string {
+	latestRevision := convert.ToValueWithDefault(containerApp.Properties.LatestRevisionName, "")
+
+	revisionName := latestRevision
+	maxWeight := int32(-1)
+	for _, weight := range containerApp.Properties.Configuration.Ingress.Traffic {
+		if convert.ToValueWithDefault(weight.Weight, 0) <= maxWeight {
+			continue
+		}
+
+		maxWeight = convert.ToValueWithDefault(weight.Weight, 0)
+		revisionName = convert.ToValueWithDefault(weight.RevisionName, "")
+		if convert.ToValueWithDefault(weight.LatestRevision, false) {
+			revisionName = latestRevision
+		}
+	}
+
+	return revisionName
+}
+
+// validateTrafficSplitting ensures the traffic of the container app can be split between revisions
+func validateTrafficSplitting(appName string, containerApp *armappcontainers.ContainerApp) error {
+	configuration := containerApp.Properties.Configuration
+	if configuration.ActiveRevisionsMode == nil ||
+		*configuration.ActiveRevisionsMode != armappcontainers.ActiveRevisionsModeMultiple {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("container app '%s' doesn't use the multiple revisions mode", appName),
+			Suggestion: "Set 'activeRevisionsMode' to 'Multiple' in the configuration of the container app, " +
+				"so its traffic can be split between revisions.",
+		}
+	}
+
+	if configuration.Ingress == nil {
+		return fmt.Errorf("container app '%s' has no ingress, so its traffic can't be split between revisions", appName)
+	}
+
+	return nil
+}
+
+// validateNoRolloutInProgress ensures the rollout of a previous revision isn't in progress, which would leave the previous
+// candidate revision running, and receiving traffic, alongside the new one
+func validateNoRolloutInProgress(appName string, containerApp *armappcontainers.ContainerApp) error {
+	traffic, err := revisionTraffic(containerApp)
+	if errors.Is(err, ErrNoRolloutInProgress) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	return &internal.ErrorWithSuggestion{
+		Err: fmt.Errorf(
+			"container app '%s': %w: revision '%s' receives %d%% of the traffic",
+			appName,
+			ErrRolloutInProgress,
+			traffic.CandidateRevision,
+			traffic.CandidateWeight,
+		),
+		Suggestion: "Run 'azd deploy <service> --promote' to complete the rollout in progress, or " +
+			"'azd deploy <service> --abort' to revert it, before deploying a new revision.",
+	}
+}
+
+// Adds a new revision to the specified container app without moving traffic to it. The traffic is pinned to the revision
+// receiving the most traffic, which becomes the stable revision of the rollout. The rollout of a previous revision must be
+// promoted or aborted first.
+func (cas *containerAppService) AddCandidateRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	imageName string,
+	progressLog func(string),
+) (*RevisionTraffic, error) {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app: %w", err)
+	}
+
+	if err := validateTrafficSplitting(appName, containerApp); err != nil {
+		return nil, err
+	}
+
+	if err := validateNoRolloutInProgress(appName, containerApp); err != nil {
+		return nil, err
+	}
+
+	// The revision receiving the most traffic is the stable revision
+	stableRevision := mostTrafficRevision(containerApp)
+
+	newRevisionName, err := cas.setRevisionTemplate(ctx, subscriptionId, resourceGroupName, appName, containerApp, imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pin the traffic so the new revision doesn't receive any traffic once it is created
+	traffic := &RevisionTraffic{
+		StableRevision:    stableRevision,
+		CandidateRevision: newRevisionName,
+	}
+	containerApp.Properties.Configuration.Ingress.Traffic = traffic.trafficWeights()
+
+	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
+	if err != nil {
+		return nil, fmt.Errorf("syncing secrets: %w", err)
+	}
+
+	err = cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp)
+	if err != nil {
+		return nil, fmt.Errorf("updating container app revision: %w", err)
+	}
+
+	err = cas.waitForRevisionReady(ctx, subscriptionId, resourceGroupName, appName, newRevisionName, progressLog)
+	if err != nil {
+		return nil, err
+	}
+
+	return traffic, nil
+}
+
+// Gets the traffic split of the rollout in progress for the specified container app. ErrNoRolloutInProgress is returned
+// when no rollout is in progress.
+func (cas *containerAppService) GetRevisionTraffic(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+) (*RevisionTraffic, error) {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return nil, fmt.Errorf("getting container app: %w", err)
+	}
+
+	if err := validateTrafficSplitting(appName, containerApp); err != nil {
+		return nil, err
+	}
+
+	traffic, err := revisionTraffic(containerApp)
+	if err != nil {
+		return nil, fmt.Errorf("container app '%s': %w", appName, err)
+	}
+
+	return traffic, nil
+}
+
+// Splits the traffic of the specified container app between the stable and the candidate revision
+func (cas *containerAppService) SetRevisionTraffic(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	traffic *RevisionTraffic,
+) error {
+	containerApp, err := cas.getContainerApp(ctx, subscriptionId, resourceGroupName, appName)
+	if err != nil {
+		return fmt.Errorf("getting container app: %w", err)
+	}
+
+	if err := validateTrafficSplitting(appName, containerApp); err != nil {
+		return err
+	}
+
+	containerApp.Properties.Configuration.Ingress.Traffic = traffic.trafficWeights()
+	containerApp, err = cas.syncSecrets(ctx, subscriptionId, resourceGroupName, appName, containerApp)
+	if err != nil {
+		return fmt.Errorf("syncing secrets: %w", err)
+	}
+
+	if err := cas.updateContainerApp(ctx, subscriptionId, resourceGroupName, appName, containerApp); err != nil {
+		return fmt.Errorf("setting traffic weights: %w", err)
+	}
+
+	return nil
+}
+
+// Deactivates the revision of the specified container app, so it stops running
+func (cas *containerAppService) DeactivateRevision(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroupName string,
+	appName string,
+	revisionName string,
+) error {
+	revisionsClient, err := cas.createRevisionsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := revisionsClient.DeactivateRevision(ctx, resourceGroupName, appName, revisionName, nil); err != nil {
+		return fmt.Errorf("deactivating revision '%s': %w", revisionName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package containerapps
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appcontainers/armappcontainers/v3"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RevisionTraffic_TrafficWeights(t *testing.T) {
+	t.Run("Pinned", func(t *testing.T) {
+		traffic := &RevisionTraffic{StableRevision: "app--v1", CandidateRevision: "app--v2"}
+
+		weights := traffic.trafficWeights()
+		require.Len(t, weights, 1)
+		require.Equal(t, "app--v1", *weights[0].RevisionName)
+		require.Equal(t, int32(100), *weights[0].Weight)
+		require.False(t, *weights[0].LatestRevision)
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		traffic := &RevisionTraffic{StableRevision: "app--v1", CandidateRevision: "app--v2", CandidateWeight: 20}
+
+		weights := traffic.trafficWeights()
+		require.Len(t, weights, 2)
+		require.Equal(t, "app--v1", *weights[0].RevisionName)
+		require.Equal(t, int32(80), *weights[0].Weight)
+		require.Equal(t, "app--v2", *weights[1].RevisionName)
+		require.Equal(t, int32(20), *weights[1].Weight)
+		require.Nil(t, weights[1].Label)
+	})
+
+	t.Run("Labeled", func(t *testing.T) {
+		traffic := &RevisionTraffic{StableRevision: "app--v1", CandidateRevision: "app--v2", CandidateLabel: "green"}
+
+		weights := traffic.trafficWeights()
+		require.Len(t, weights, 2)
+		require.Equal(t, int32(100), *weights[0].Weight)
+		require.Equal(t, int32(0), *weights[1].Weight)
+		require.Equal(t, "green", *weights[1].Label)
+	})
+
+	t.Run("Promoted", func(t *testing.T) {
+		traffic := &RevisionTraffic{StableRevision: "app--v1", CandidateRevision: "app--v2", CandidateWeight: 100}
+
+		weights := traffic.trafficWeights()
+		require.Len(t, weights, 1)
+		require.Equal(t, "app--v2", *weights[0].RevisionName)
+		require.Equal(t, int32(100), *weights[0].Weight)
+	})
+}
+
+func Test_LabelHostName(t *testing.T) {
+	require.Equal(t,
+		"app---green.region.azurecontainerapps.io",
+		LabelHostName("app.region.azurecontainerapps.io", "green"),
+	)
+	require.Equal(t, "localhost", LabelHostName("localhost", "green"))
+}
+
+func Test_RevisionTraffic(t *testing.T) {
+	containerApp := func(latestRevision string, traffic ...*armappcontainers.TrafficWeight) *armappcontainers.ContainerApp {
+		return &armappcontainers.ContainerApp{
+			Properties: &armappcontainers.ContainerAppProperties{
+				LatestRevisionName: convert.RefOf(latestRevision),
+				Configuration: &armappcontainers.Configuration{
+					ActiveRevisionsMode: convert.RefOf(armappcontainers.ActiveRevisionsModeMultiple),
+					Ingress: &armappcontainers.Ingress{
+						Traffic: traffic,
+					},
+				},
+			},
+		}
+	}
+
+	weight := func(revisionName string, weight int32) *armappcontainers.TrafficWeight {
+		return &armappcontainers.TrafficWeight{
+			RevisionName: convert.RefOf(revisionName),
+			Weight:       convert.RefOf(weight),
+		}
+	}
+
+	t.Run("Canary", func(t *testing.T) {
+		traffic, err := revisionTraffic(containerApp("app--v3", weight("app--v2", 70), weight("app--v3", 30)))
+		require.NoError(t, err)
+		require.Equal(t, &RevisionTraffic{
+			StableRevision:    "app--v2",
+			CandidateRevision: "app--v3",
+			CandidateWeight:   30,
+		}, traffic)
+	})
+
+	t.Run("BlueGreen", func(t *testing.T) {
+		labeled := weight("app--v3", 0)
+		labeled.Label = convert.RefOf("green")
+
+		traffic, err := revisionTraffic(containerApp("app--v3", weight("app--v2", 100), labeled))
+		require.NoError(t, err)
+		require.Equal(t, "app--v2", traffic.StableRevision)
+		require.Equal(t, int32(0), traffic.CandidateWeight)
+		require.Equal(t, "green", traffic.CandidateLabel)
+	})
+
+	t.Run("LatestRevisionReceivesAllTraffic", func(t *testing.T) {
+		latest := &armappcontainers.TrafficWeight{
+			LatestRevision: convert.RefOf(true),
+			Weight:         convert.RefOf(int32(100)),
+		}
+
+		_, err := revisionTraffic(containerApp("app--v3", latest))
+		require.ErrorIs(t, err, ErrNoRolloutInProgress)
+	})
+
+	t.Run("Aborted", func(t *testing.T) {
+		// An aborted candidate remains the latest revision, without any traffic
+		_, err := revisionTraffic(containerApp("app--v3", weight("app--v2", 100)))
+		require.ErrorIs(t, err, ErrNoRolloutInProgress)
+	})
+
+	t.Run("MostTraffic", func(t *testing.T) {
+		app := containerApp("app--v3", weight("app--v1", 10), weight("app--v2", 90))
+		require.Equal(t, "app--v2", mostTrafficRevision(app))
+	})
+
+	t.Run("RolloutInProgress", func(t *testing.T) {
+		err := validateNoRolloutInProgress("app", containerApp("app--v3", weight("app--v2", 70), weight("app--v3", 30)))
+		require.ErrorIs(t, err, ErrRolloutInProgress)
+
+		// The rollout must be promoted or aborted before rolling out a new revision
+		var errWithSuggestion *internal.ErrorWithSuggestion
+		require.True(t, errors.As(err, &errWithSuggestion))
+		require.Contains(t, errWithSuggestion.Suggestion, "--promote")
+		require.Contains(t, errWithSuggestion.Suggestion, "--abort")
+
+		require.NoError(t, validateNoRolloutInProgress("app", containerApp("app--v3", weight("app--v2", 100))))
+	})
+
+	t.Run("SingleRevisionMode", func(t *testing.T) {
+		app := containerApp("app--v1")
+		app.Properties.Configuration.ActiveRevisionsMode = convert.RefOf(armappcontainers.ActiveRevisionsModeSingle)
+
+		err := validateTrafficSplitting("app", app)
+		var errWithSuggestion *internal.ErrorWithSuggestion
+		require.True(t, errors.As(err, &errWithSuggestion))
+	})
+}
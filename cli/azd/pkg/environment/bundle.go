@@ -0,0 +1,302 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Error returned when decrypting a bundle with a passphrase other than the one it was encrypted with
+var ErrInvalidPassphrase = errors.New("invalid passphrase")
+
+// The version of the format of the bundles, incremented on breaking changes
+const bundleFormatVersion = 1
+
+const (
+	bundleAlgorithm  = "AES-256-GCM"
+	bundleKdf        = "PBKDF2-SHA256"
+	bundleKeyLength  = 32
+	bundleSaltLength = 16
+)
+
+// The number of iterations of the derivation of the encryption key from the passphrase
+var bundleKdfIterations = 600_000
+
+// Bundle is a portable copy of an environment, used to move environments between machines and data stores
+type Bundle struct {
+	FormatVersion int `json:"formatVersion"`
+	// The name of the environment the bundle was exported from
+	Name   string            `json:"name"`
+	Values map[string]string `json:"values,omitempty"`
+	// The configuration of the environment, without its secrets
+	Config map[string]any `json:"config,omitempty"`
+	// The secrets of the configuration, such as the values of secure infrastructure parameters, by path
+	ConfigSecrets map[string]string `json:"configSecrets,omitempty"`
+	// Set when the bundle is encrypted
+	Encryption *BundleEncryption `json:"encryption,omitempty"`
+	// The encrypted values, configuration and secrets of the configuration, set when the bundle is encrypted
+	Payload string `json:"payload,omitempty"`
+}
+
+// BundleEncryption describes how the payload of a bundle is encrypted, with a key derived from a passphrase
+type BundleEncryption struct {
+	Algorithm  string `json:"algorithm"`
+	Kdf        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       string `json:"salt"`
+}
+
+// NewBundle returns a bundle of the values and configuration of the environment.
+// The secrets of the configuration are resolved from its vault, when available.
+func NewBundle(env *Environment) *Bundle {
+	bundle := &Bundle{
+		FormatVersion: bundleFormatVersion,
+		Name:          env.Name(),
+		Values:        env.Dotenv(),
+		Config:        map[string]any{},
+		ConfigSecrets: map[string]string{},
+	}
+
+	if env.Config != nil {
+		bundle.Config = bundleConfig(env.Config, "", env.Config.Raw(), bundle.ConfigSecrets)
+	}
+
+	return bundle
+}
+
+// bundleConfig returns a copy of a node of a configuration without its secrets, which are added to secrets by path
+func bundleConfig(cfg config.Config, prefix string, node map[string]any, secrets map[string]string) map[string]any {
+	copied := map[string]any{}
+	for key, value := range node {
+		path := prefix + key
+		if path == configVaultKey {
+			continue
+		}
+
+		switch typed := value.(type) {
+		case map[string]any:
+			copied[key] = bundleConfig(cfg, path+".", typed, secrets)
+		case string:
+			if !strings.HasPrefix(typed, "vault://") {
+				copied[key] = typed
+				continue
+			}
+
+			if secret, ok := cfg.GetString(path); ok {
+				secrets[path] = secret
+			} else {
+				log.Printf("skipping secret '%s' of the configuration, which isn't available from its vault", path)
+			}
+		default:
+			copied[key] = typed
+		}
+	}
+
+	return copied
+}
+
+// ReadBundle reads a bundle written by Write
+func ReadBundle(reader io.Reader) (*Bundle, error) {
+	var bundle *Bundle
+	if err := json.NewDecoder(reader).Decode(&bundle); err != nil {
+		return nil, fmt.Errorf("reading bundle: %w", err)
+	}
+
+	if bundle == nil || bundle.FormatVersion == 0 {
+		return nil, errors.New("reading bundle: the file isn't an environment bundle")
+	}
+
+	if bundle.FormatVersion > bundleFormatVersion {
+		return nil, fmt.Errorf(
+			"reading bundle: the format version %d isn't supported, upgrade azd to import it", bundle.FormatVersion)
+	}
+
+	return bundle, nil
+}
+
+// Write writes the bundle as JSON
+func (b *Bundle) Write(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(b)
+}
+
+// Environment returns a new environment with the specified name, holding the values and configuration of the bundle.
+// The secrets of the configuration are stored in a new vault.
+func (b *Bundle) Environment(name string) (*Environment, error) {
+	if b.Encryption != nil {
+		return nil, errors.New("the bundle must be decrypted first")
+	}
+
+	env := New(name)
+	for key, value := range b.Values {
+		env.DotenvSet(key, value)
+	}
+	env.DotenvSet(EnvNameEnvVarName, name)
+
+	env.Config = config.NewConfig(cloneConfigNode(b.Config))
+	for path, secret := range b.ConfigSecrets {
+		if err := env.Config.SetSecret(path, secret); err != nil {
+			return nil, fmt.Errorf("setting secret '%s': %w", path, err)
+		}
+	}
+
+	return env, nil
+}
+
+// HasSecrets returns true when the bundle holds secrets, which are only protected once the bundle is encrypted
+func (b *Bundle) HasSecrets() bool {
+	if len(b.ConfigSecrets) > 0 {
+		return true
+	}
+
+	for key := range b.Values {
+		if IsSecretKey(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bundlePayload is the content of a bundle which is encrypted as a whole by Encrypt
+type bundlePayload struct {
+	Values        map[string]string `json:"values"`
+	Config        map[string]any    `json:"config"`
+	ConfigSecrets map[string]string `json:"configSecrets,omitempty"`
+}
+
+// Encrypt encrypts the values, the configuration and the secrets of the configuration of the bundle as a single payload,
+// with a key derived from the passphrase. Only the name of the environment is left readable.
+func (b *Bundle) Encrypt(passphrase string) error {
+	if b.Encryption != nil {
+		return errors.New("the bundle is already encrypted")
+	}
+
+	salt := make([]byte, bundleSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	encryption := &BundleEncryption{
+		Algorithm:  bundleAlgorithm,
+		Kdf:        bundleKdf,
+		Iterations: bundleKdfIterations,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+	}
+
+	gcm, err := encryption.cipher(passphrase)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(bundlePayload{
+		Values:        b.Values,
+		Config:        b.Config,
+		ConfigSecrets: b.ConfigSecrets,
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling bundle: %w", err)
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	b.Payload = base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil))
+	b.Values = nil
+	b.Config = nil
+	b.ConfigSecrets = nil
+	b.Encryption = encryption
+	return nil
+}
+
+// Decrypt decrypts the payload of a bundle encrypted by Encrypt.
+// ErrInvalidPassphrase is returned when the passphrase isn't the one the bundle was encrypted with.
+func (b *Bundle) Decrypt(passphrase string) error {
+	if b.Encryption == nil {
+		return nil
+	}
+
+	if b.Encryption.Algorithm != bundleAlgorithm || b.Encryption.Kdf != bundleKdf {
+		return fmt.Errorf(
+			"the encryption '%s' with '%s' isn't supported", b.Encryption.Algorithm, b.Encryption.Kdf)
+	}
+
+	gcm, err := b.Encryption.cipher(passphrase)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(b.Payload)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return errors.New("the encrypted payload of the bundle is invalid")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return ErrInvalidPassphrase
+	}
+
+	var payload bundlePayload
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return fmt.Errorf("reading the encrypted payload of the bundle: %w", err)
+	}
+
+	b.Values = payload.Values
+	b.Config = payload.Config
+	b.ConfigSecrets = payload.ConfigSecrets
+	b.Payload = ""
+	b.Encryption = nil
+	return nil
+}
+
+// cipher returns the cipher of the key derived from the passphrase
+func (e *BundleEncryption) cipher(passphrase string) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, errors.New("the passphrase is empty")
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(e.Salt)
+	if err != nil || e.Iterations < 1 {
+		return nil, errors.New("the encryption of the bundle is invalid")
+	}
+
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(passphrase), salt, e.Iterations, bundleKeyLength, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// cloneConfigNode returns a deep copy of a node of a configuration
+func cloneConfigNode(node map[string]any) map[string]any {
+	cloned := make(map[string]any, len(node))
+	for key, value := range node {
+		if child, ok := value.(map[string]any); ok {
+			cloned[key] = cloneConfigNode(child)
+		} else {
+			cloned[key] = value
+		}
+	}
+
+	return cloned
+}
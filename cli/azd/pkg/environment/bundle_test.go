@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Bundle_RoundTrip(t *testing.T) {
+	env := New("dev")
+	env.DotenvSet("AZURE_LOCATION", "eastus")
+	env.DotenvSet("DB_PASSWORD", "p@ssw0rd")
+	env.Config = config.NewEmptyConfig()
+	require.NoError(t, env.Config.Set("infra.parameters.location", "eastus"))
+	require.NoError(t, env.Config.SetSecret("infra.parameters.adminPassword", "secret"))
+
+	bundle := NewBundle(env)
+	require.Equal(t, "dev", bundle.Name)
+	require.Equal(t, map[string]string{"infra.parameters.adminPassword": "secret"}, bundle.ConfigSecrets)
+	require.NotContains(t, bundle.Config, configVaultKey)
+	require.True(t, bundle.HasSecrets())
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, bundle.Write(buffer))
+
+	read, err := ReadBundle(buffer)
+	require.NoError(t, err)
+
+	imported, err := read.Environment("test")
+	require.NoError(t, err)
+	require.Equal(t, "test", imported.Getenv(EnvNameEnvVarName))
+	require.Equal(t, "eastus", imported.Getenv("AZURE_LOCATION"))
+	require.Equal(t, "p@ssw0rd", imported.Getenv("DB_PASSWORD"))
+
+	location, _ := imported.Config.GetString("infra.parameters.location")
+	require.Equal(t, "eastus", location)
+
+	adminPassword, _ := imported.Config.GetString("infra.parameters.adminPassword")
+	require.Equal(t, "secret", adminPassword)
+}
+
+func Test_Bundle_Encryption(t *testing.T) {
+	defaultIterations := bundleKdfIterations
+	bundleKdfIterations = 10
+	t.Cleanup(func() { bundleKdfIterations = defaultIterations })
+
+	bundle := &Bundle{
+		FormatVersion: bundleFormatVersion,
+		Values: map[string]string{
+			"AZURE_LOCATION": "eastus",
+			"DB_PASSWORD":    "p@ssw0rd",
+		},
+		Config: map[string]any{
+			"location": "eastus",
+		},
+		ConfigSecrets: map[string]string{
+			"infra.parameters.adminPassword": "secret",
+		},
+	}
+
+	require.NoError(t, bundle.Encrypt("passphrase"))
+	require.NotNil(t, bundle.Encryption)
+	require.NotEmpty(t, bundle.Payload)
+	require.Nil(t, bundle.Values)
+	require.Nil(t, bundle.ConfigSecrets)
+
+	// Neither the secrets nor the other values are readable
+	buffer := &bytes.Buffer{}
+	require.NoError(t, bundle.Write(buffer))
+	require.NotContains(t, buffer.String(), "eastus")
+	require.NotContains(t, buffer.String(), "p@ssw0rd")
+
+	read, err := ReadBundle(buffer)
+	require.NoError(t, err)
+
+	_, err = read.Environment("dev")
+	require.Error(t, err)
+
+	require.ErrorIs(t, read.Decrypt("wrong"), ErrInvalidPassphrase)
+	require.NotNil(t, read.Encryption)
+
+	require.NoError(t, read.Decrypt("passphrase"))
+	require.Nil(t, read.Encryption)
+	require.Empty(t, read.Payload)
+	require.Equal(t, "eastus", read.Values["AZURE_LOCATION"])
+	require.Equal(t, "p@ssw0rd", read.Values["DB_PASSWORD"])
+	require.Equal(t, "eastus", read.Config["location"])
+	require.Equal(t, "secret", read.ConfigSecrets["infra.parameters.adminPassword"])
+}
+
+func Test_ReadBundle_Invalid(t *testing.T) {
+	_, err := ReadBundle(strings.NewReader(`{"values": {}}`))
+	require.Error(t, err)
+
+	_, err = ReadBundle(strings.NewReader(`{"formatVersion": 100}`))
+	require.Error(t, err)
+}
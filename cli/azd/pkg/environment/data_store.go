@@ -12,10 +12,14 @@ type RemoteKind string
 
 const (
 	RemoteKindAzureBlobStorage RemoteKind = "AzureBlobStorage"
+	RemoteKindFileSystem       RemoteKind = "FileSystem"
+	RemoteKindS3               RemoteKind = "S3"
 )
 
 var ValidRemoteKinds = []string{
 	string(RemoteKindAzureBlobStorage),
+	string(RemoteKindFileSystem),
+	string(RemoteKindS3),
 }
 
 type DataStore interface {
@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+// The key of the id of the vault holding the secrets of a configuration
+const configVaultKey = "vault"
+
+// DiffConfig returns the changes from one configuration to another, keyed by the path of each value, e.g.
+// infra.parameters.location. The values of secrets stored in the vault of either configuration, such as the values of
+// secure infrastructure parameters, are masked in both configurations.
+func DiffConfig(from config.Config, to config.Config) []Change {
+	fromValues, fromSecrets := configValues(from)
+	toValues, toSecrets := configValues(to)
+
+	changes := Diff(fromValues, toValues)
+	for i, change := range changes {
+		if !fromSecrets[change.Key] && !toSecrets[change.Key] {
+			continue
+		}
+
+		if change.OldValue != "" {
+			changes[i].OldValue = maskedValue
+		}
+
+		if change.NewValue != "" {
+			changes[i].NewValue = maskedValue
+		}
+	}
+
+	return changes
+}
+
+// configValues returns the values of the leaves of a configuration by path, along with the paths of the secrets
+func configValues(cfg config.Config) (map[string]string, map[string]bool) {
+	values := map[string]string{}
+	secrets := map[string]bool{}
+	if cfg == nil {
+		return values, secrets
+	}
+
+	var walk func(prefix string, node map[string]any)
+	walk = func(prefix string, node map[string]any) {
+		for key, value := range node {
+			path := prefix + key
+			if path == configVaultKey {
+				continue
+			}
+
+			switch typed := value.(type) {
+			case map[string]any:
+				walk(path+".", typed)
+			case string:
+				values[path] = typed
+				// Secrets are references to the vault, which are unique to each configuration
+				if strings.HasPrefix(typed, "vault://") {
+					secrets[path] = true
+				}
+			default:
+				encoded, err := json.Marshal(typed)
+				if err != nil {
+					encoded = []byte(fmt.Sprint(typed))
+				}
+				values[path] = string(encoded)
+			}
+		}
+	}
+	walk("", cfg.Raw())
+
+	return values, secrets
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DiffConfig(t *testing.T) {
+	from := config.NewConfig(map[string]any{
+		"infra": map[string]any{
+			"parameters": map[string]any{
+				"location":    "eastus",
+				"replicas":    1,
+				"password":    "vault://00000000-0000-0000-0000-000000000000/00000000-0000-0000-0000-000000000001",
+				"tokenIssuer": "https://login.contoso.com",
+				"adminLogin":  "admin",
+			},
+		},
+		"vault": "00000000-0000-0000-0000-000000000000",
+	})
+	to := config.NewConfig(map[string]any{
+		"infra": map[string]any{
+			"parameters": map[string]any{
+				"location":    "westus",
+				"replicas":    1,
+				"password":    "vault://00000000-0000-0000-0000-000000000002/00000000-0000-0000-0000-000000000003",
+				"enabled":     true,
+				"tokenIssuer": "https://login.fabrikam.com",
+				"adminLogin":  "vault://00000000-0000-0000-0000-000000000002/00000000-0000-0000-0000-000000000004",
+			},
+		},
+		"vault": "00000000-0000-0000-0000-000000000002",
+	})
+
+	changes := DiffConfig(from, to)
+	// Values are masked when they are secrets in either configuration, regardless of their names
+	require.Equal(t, []Change{
+		{Key: "infra.parameters.adminLogin", Kind: ChangeChanged, OldValue: maskedValue, NewValue: maskedValue},
+		{Key: "infra.parameters.enabled", Kind: ChangeAdded, NewValue: "true"},
+		{Key: "infra.parameters.location", Kind: ChangeChanged, OldValue: "eastus", NewValue: "westus"},
+		{Key: "infra.parameters.password", Kind: ChangeChanged, OldValue: maskedValue, NewValue: maskedValue},
+		{
+			Key:      "infra.parameters.tokenIssuer",
+			Kind:     ChangeChanged,
+			OldValue: "https://login.contoso.com",
+			NewValue: "https://login.fabrikam.com",
+		},
+	}, changes)
+}
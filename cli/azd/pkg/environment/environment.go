@@ -11,6 +11,7 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 
 	"maps"
 
@@ -51,6 +52,12 @@ const PlatformTypeEnvVarName = "AZD_PLATFORM_TYPE"
 type Environment struct {
 	name string
 
+	// mu guards dotenv and deletedKeys, since services can be packaged and deployed concurrently
+	mu sync.RWMutex
+
+	// persistMu serializes saving and reloading the environment
+	persistMu sync.Mutex
+
 	// dotenv is a map of keys to values, persisted to the `.env` file stored in this environment's [Root].
 	dotenv map[string]string
 
@@ -60,6 +67,27 @@ type Environment struct {
 
 	// Config is environment specific config
 	Config config.Config
+
+	// secretResolver resolves the values referencing secrets when building the environment of a process
+	secretResolver SecretResolver
+
+	// secrets caches the values of the resolved secrets by reference. The values are never persisted.
+	secrets *secretCache
+}
+
+// SecretResolver resolves the values of an environment which reference secrets, such as `akvs://<vault>/<secret>` for the
+// secrets stored in Azure Key Vault. Only the references are stored with the environment, the values of the secrets are
+// resolved when the environment of a process is built.
+type SecretResolver interface {
+	// IsSecretReference returns true when the value references a secret
+	IsSecretReference(value string) bool
+	// ResolveSecret returns the value of the secret referenced by the value
+	ResolveSecret(ctx context.Context, subscriptionId string, reference string) (string, error)
+}
+
+type secretCache struct {
+	mu     sync.Mutex
+	values map[string]string
 }
 
 const AzdInitialEnvironmentConfigName = "AZD_INITIAL_ENVIRONMENT_CONFIG"
@@ -146,6 +174,9 @@ func CleanName(name string) string {
 // Getenv behaves like os.Getenv, except that any keys in the `.env` file associated with this environment are considered
 // first.
 func (e *Environment) Getenv(key string) string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	if v, has := e.dotenv[key]; has {
 		return v
 	}
@@ -156,6 +187,9 @@ func (e *Environment) Getenv(key string) string {
 // LookupEnv behaves like os.LookupEnv, except that any keys in the `.env` file associated with this environment are
 // considered first.
 func (e *Environment) LookupEnv(key string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	if v, has := e.dotenv[key]; has {
 		return v, true
 	}
@@ -166,27 +200,75 @@ func (e *Environment) LookupEnv(key string) (string, bool) {
 // DotenvDelete removes the given key from the .env file in the environment, it is a no-op if the key
 // does not exist. [Save] should be called to ensure this change is persisted.
 func (e *Environment) DotenvDelete(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	delete(e.dotenv, key)
 	e.deletedKeys[key] = struct{}{}
 }
 
 // Dotenv returns a copy of the key value pairs from the .env file in the environment.
 func (e *Environment) Dotenv() map[string]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	return maps.Clone(e.dotenv)
 }
 
 // DotenvSet sets the value of [key] to [value] in the .env file associated with the environment. [Save] should be
 // called to ensure this change is persisted.
 func (e *Environment) DotenvSet(key string, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	e.dotenv[key] = value
 	delete(e.deletedKeys, key)
 }
 
+// DotenvSetValues sets the values in the .env file associated with the environment at once, so that concurrent readers
+// see either none or all of them. [Save] should be called to ensure this change is persisted.
+func (e *Environment) DotenvSetValues(values map[string]string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for key, value := range values {
+		e.dotenv[key] = value
+		delete(e.deletedKeys, key)
+	}
+}
+
+// setDotenv replaces the values of the environment with the persisted values, discarding any deleted key
+func (e *Environment) setDotenv(values map[string]string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.dotenv = values
+	e.deletedKeys = make(map[string]struct{})
+}
+
+// mergeDotenv overlays the values of the environment on the persisted values, replays the deleted keys, and replaces the
+// values of the environment with the result, as done before saving the environment
+func (e *Environment) mergeDotenv(persisted map[string]string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for key, value := range e.dotenv {
+		persisted[key] = value
+	}
+
+	for key := range e.deletedKeys {
+		delete(persisted, key)
+	}
+
+	e.dotenv = persisted
+	e.deletedKeys = make(map[string]struct{})
+}
+
 // Name gets the name of the environment
 // If empty will fallback to the value of the AZURE_ENV_NAME environment variable
 func (e *Environment) Name() string {
 	if e.name == "" {
-		e.name = e.Getenv(EnvNameEnvVarName)
+		return e.Getenv(EnvNameEnvVarName)
 	}
 
 	return e.name
@@ -231,15 +313,91 @@ func (e *Environment) SetServiceProperty(serviceName string, propertyName string
 	e.DotenvSet(fmt.Sprintf("SERVICE_%s_%s", normalize(serviceName), propertyName), value)
 }
 
+// SetSecretResolver sets the resolver of the values referencing secrets, which are resolved by [Environ] and
+// [ResolvedDotenv].
+func (e *Environment) SetSecretResolver(resolver SecretResolver) {
+	e.secretResolver = resolver
+	if e.secrets == nil {
+		e.secrets = &secretCache{values: map[string]string{}}
+	}
+}
+
+// ResolvedDotenv returns a copy of the key value pairs from the .env file in the environment, where the values referencing
+// secrets are replaced by the values of the secrets. The values of the secrets are resolved once, and never persisted.
+func (e *Environment) ResolvedDotenv(ctx context.Context) (map[string]string, error) {
+	values := e.Dotenv()
+	if e.secretResolver == nil {
+		return values, nil
+	}
+
+	for key, value := range values {
+		resolved, err := e.resolveSecret(ctx, key, value)
+		if err != nil {
+			return nil, err
+		}
+
+		values[key] = resolved
+	}
+
+	return values, nil
+}
+
+// ResolvedGetenv behaves like [Getenv], except that a value from the .env file referencing a secret is replaced by the
+// value of the secret, so that only the secrets which are used are resolved.
+func (e *Environment) ResolvedGetenv(ctx context.Context, key string) (string, error) {
+	e.mu.RLock()
+	value, has := e.dotenv[key]
+	e.mu.RUnlock()
+
+	if !has {
+		return os.Getenv(key), nil
+	}
+
+	if e.secretResolver == nil {
+		return value, nil
+	}
+
+	return e.resolveSecret(ctx, key, value)
+}
+
+// resolveSecret returns the value of the secret referenced by the value of the key, or the value when it doesn't
+// reference a secret. The values of the secrets are cached.
+func (e *Environment) resolveSecret(ctx context.Context, key string, value string) (string, error) {
+	if !e.secretResolver.IsSecretReference(value) {
+		return value, nil
+	}
+
+	e.secrets.mu.Lock()
+	defer e.secrets.mu.Unlock()
+
+	if secret, has := e.secrets.values[value]; has {
+		return secret, nil
+	}
+
+	secret, err := e.secretResolver.ResolveSecret(ctx, e.GetSubscriptionId(), value)
+	if err != nil {
+		return "", fmt.Errorf("resolving secret of environment value '%s': %w", key, err)
+	}
+
+	e.secrets.values[value] = secret
+	return secret, nil
+}
+
 // Creates a slice of key value pairs, based on the entries in the `.env` file like `KEY=VALUE` that
-// can be used to pass into command runner or similar constructs.
-func (e *Environment) Environ() []string {
+// can be used to pass into command runner or similar constructs. The values referencing secrets are replaced by the values
+// of the secrets.
+func (e *Environment) Environ(ctx context.Context) ([]string, error) {
+	values, err := e.ResolvedDotenv(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	envVars := []string{}
-	for k, v := range e.dotenv {
+	for k, v := range values {
 		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	return envVars
+	return envVars, nil
 }
 
 // fixupUnquotedDotenv is a workaround for behavior in how godotenv.Marshal handles numeric like values.  Marshaling
@@ -281,6 +439,9 @@ func fixupUnquotedDotenv(values map[string]string, dotenv string) string {
 // Instead of calling `godotenv.Write` directly, we need to save the file ourselves, so we can fixup any numeric values
 // that were incorrectly unquoted.
 func marshallDotEnv(env *Environment) (string, error) {
+	env.mu.RLock()
+	defer env.mu.RUnlock()
+
 	marshalled, err := godotenv.Marshal(env.dotenv)
 	if err != nil {
 		return "", fmt.Errorf("marshalling .env: %w", err)
@@ -6,8 +6,10 @@ package environment
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
@@ -273,3 +275,99 @@ func createEnvManager(t *testing.T, mockContext *mocks.MockContext, root string)
 
 	return newManagerForTest(azdCtx, mockContext.Console, localDataStore, nil), azdCtx
 }
+
+// fakeSecretResolver resolves the values prefixed with `secret://`, counting the resolutions
+type fakeSecretResolver struct {
+	resolved int
+}
+
+func (r *fakeSecretResolver) IsSecretReference(value string) bool {
+	return strings.HasPrefix(value, "secret://")
+}
+
+func (r *fakeSecretResolver) ResolveSecret(ctx context.Context, subscriptionId string, reference string) (string, error) {
+	if reference == "secret://missing" {
+		return "", errors.New("secret not found")
+	}
+
+	r.resolved++
+	return subscriptionId + "/" + strings.TrimPrefix(reference, "secret://"), nil
+}
+
+func Test_ResolvedDotenv(t *testing.T) {
+	t.Run("Resolved", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		envManager, azdCtx := createEnvManager(t, mockContext, t.TempDir())
+
+		env := NewWithValues("test", map[string]string{
+			SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+			"DB_PASSWORD":            "secret://password",
+			"DB_HOST":                "db.example.com",
+		})
+
+		resolver := &fakeSecretResolver{}
+		env.SetSecretResolver(resolver)
+
+		values, err := env.ResolvedDotenv(*mockContext.Context)
+		require.NoError(t, err)
+		require.Equal(t, "SUBSCRIPTION_ID/password", values["DB_PASSWORD"])
+		require.Equal(t, "db.example.com", values["DB_HOST"])
+
+		environ, err := env.Environ(*mockContext.Context)
+		require.NoError(t, err)
+		require.Contains(t, environ, "DB_PASSWORD=SUBSCRIPTION_ID/password")
+
+		// The secrets are resolved once
+		require.Equal(t, 1, resolver.resolved)
+
+		// Only the reference is stored with the environment
+		require.Equal(t, "secret://password", env.Getenv("DB_PASSWORD"))
+		require.NoError(t, envManager.Save(*mockContext.Context, env))
+
+		envMap, err := godotenv.Read(filepath.Join(azdCtx.EnvironmentRoot("test"), azdcontext.DotEnvFileName))
+		require.NoError(t, err)
+		require.Equal(t, "secret://password", envMap["DB_PASSWORD"])
+	})
+
+	t.Run("ResolvedGetenv", func(t *testing.T) {
+		env := NewWithValues("test", map[string]string{
+			SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+			"DB_PASSWORD":            "secret://password",
+			"API_KEY":                "secret://missing",
+		})
+
+		resolver := &fakeSecretResolver{}
+		env.SetSecretResolver(resolver)
+
+		value, err := env.ResolvedGetenv(context.Background(), "DB_PASSWORD")
+		require.NoError(t, err)
+		require.Equal(t, "SUBSCRIPTION_ID/password", value)
+
+		value, err = env.ResolvedGetenv(context.Background(), "DB_PASSWORD")
+		require.NoError(t, err)
+		require.Equal(t, "SUBSCRIPTION_ID/password", value)
+
+		// Only the secret which is used is resolved, once
+		require.Equal(t, 1, resolver.resolved)
+
+		value, err = env.ResolvedGetenv(context.Background(), "SUBSCRIPTION_ID")
+		require.NoError(t, err)
+		require.Empty(t, value)
+	})
+
+	t.Run("NoResolver", func(t *testing.T) {
+		env := NewWithValues("test", map[string]string{"DB_PASSWORD": "secret://password"})
+
+		values, err := env.ResolvedDotenv(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "secret://password", values["DB_PASSWORD"])
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		env := NewWithValues("test", map[string]string{"DB_PASSWORD": "secret://missing"})
+		env.SetSecretResolver(&fakeSecretResolver{})
+
+		_, err := env.Environ(context.Background())
+		require.ErrorContains(t, err, "resolving secret of environment value 'DB_PASSWORD': secret not found")
+	})
+}
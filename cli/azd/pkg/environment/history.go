@@ -0,0 +1,174 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Error returned when a version cannot be found in the history of an environment
+var ErrVersionNotFound = errors.New("version not found")
+
+// The name of the folder holding the snapshots of the history of an environment, within the folder of the environment
+const historyFolderName = ".history"
+
+// The number of snapshots kept in the history of an environment, older snapshots are removed when saving
+var historyLimit = 50
+
+// Snapshot is a version of the values of an environment, recorded each time the values are saved
+type Snapshot struct {
+	// The version of the snapshot, starting at 1 and incremented by each save
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// The user and machine that saved the values, e.g. user@host
+	Owner  string            `json:"owner"`
+	Values map[string]string `json:"values"`
+}
+
+// HistoryStore is implemented by the data stores recording the history of the values of environments
+type HistoryStore interface {
+	// History returns the snapshots of the values of the environment, ordered from the oldest to the latest
+	History(ctx context.Context, name string) ([]*Snapshot, error)
+}
+
+// ChangeKind is the kind of change of the value of a key
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is a change of the value of a key between two versions of the values of an environment
+type Change struct {
+	Key      string     `json:"key"`
+	Kind     ChangeKind `json:"kind"`
+	OldValue string     `json:"oldValue,omitempty"`
+	NewValue string     `json:"newValue,omitempty"`
+}
+
+// Masked returns the change with its values masked when the key holds a secret
+func (c Change) Masked() Change {
+	if !IsSecretKey(c.Key) {
+		return c
+	}
+
+	if c.OldValue != "" {
+		c.OldValue = maskedValue
+	}
+
+	if c.NewValue != "" {
+		c.NewValue = maskedValue
+	}
+
+	return c
+}
+
+// String returns a single line description of the change, e.g. "+ KEY=value"
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s=%s", c.Key, c.NewValue)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s=%s", c.Key, c.OldValue)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", c.Key, c.OldValue, c.NewValue)
+	}
+}
+
+// Diff returns the changes from one set of values to another, ordered by key
+func Diff(from map[string]string, to map[string]string) []Change {
+	changes := []Change{}
+
+	for key, oldValue := range from {
+		newValue, has := to[key]
+		switch {
+		case !has:
+			changes = append(changes, Change{Key: key, Kind: ChangeRemoved, OldValue: oldValue})
+		case newValue != oldValue:
+			changes = append(changes, Change{Key: key, Kind: ChangeChanged, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+
+	for key, newValue := range to {
+		if _, has := from[key]; !has {
+			changes = append(changes, Change{Key: key, Kind: ChangeAdded, NewValue: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+
+	return changes
+}
+
+const maskedValue = "******"
+
+// Matches the names of the keys commonly holding secrets, e.g. STORAGE_ACCOUNT_KEY or DB_PASSWORD
+var secretKeyRegex = regexp.MustCompile(
+	`(?i)(SECRET|PASSWORD|PASSWD|PWD|TOKEN|CREDENTIAL|CONNECTION_?STRING|(^|_)SAS(_|$)|KEY$)`,
+)
+
+// IsSecretKey returns true when the name of the key suggests its value is a secret
+func IsSecretKey(key string) bool {
+	return secretKeyRegex.MatchString(key)
+}
+
+// newSnapshot returns the snapshot following the latest snapshot of a history, or the first snapshot when latest is nil.
+// nil is returned when the values are the same as those of the latest snapshot.
+func newSnapshot(latest *Snapshot, values map[string]string) *Snapshot {
+	version := 1
+	if latest != nil {
+		if maps.Equal(latest.Values, values) {
+			return nil
+		}
+
+		version = latest.Version + 1
+	}
+
+	return &Snapshot{
+		Version:   version,
+		CreatedAt: time.Now().UTC(),
+		Owner:     currentOwner(),
+		Values:    values,
+	}
+}
+
+// snapshotVersion returns the version of the snapshot stored in the file with the specified name, e.g. 12.json
+func snapshotVersion(fileName string) (int, bool) {
+	versionText, found := strings.CutSuffix(fileName, ".json")
+	if !found {
+		return 0, false
+	}
+
+	version, err := strconv.Atoi(versionText)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+
+	return version, true
+}
+
+func snapshotFileName(version int) string {
+	return fmt.Sprintf("%d.json", version)
+}
+
+// expiredVersions returns the sorted versions exceeding the history limit, which are the oldest ones
+func expiredVersions(versions []int) []int {
+	if len(versions) <= historyLimit {
+		return nil
+	}
+
+	return versions[:len(versions)-historyLimit]
+}
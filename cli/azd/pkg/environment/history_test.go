@@ -0,0 +1,73 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Diff(t *testing.T) {
+	from := map[string]string{
+		"KEEP":    "same",
+		"REMOVED": "old",
+		"CHANGED": "old",
+	}
+	to := map[string]string{
+		"KEEP":    "same",
+		"CHANGED": "new",
+		"ADDED":   "new",
+	}
+
+	changes := Diff(from, to)
+	require.Equal(t, []Change{
+		{Key: "ADDED", Kind: ChangeAdded, NewValue: "new"},
+		{Key: "CHANGED", Kind: ChangeChanged, OldValue: "old", NewValue: "new"},
+		{Key: "REMOVED", Kind: ChangeRemoved, OldValue: "old"},
+	}, changes)
+
+	require.Empty(t, Diff(from, from))
+}
+
+func Test_Change_Masked(t *testing.T) {
+	change := Change{Key: "STORAGE_ACCOUNT_KEY", Kind: ChangeChanged, OldValue: "old", NewValue: "new"}
+	require.Equal(t, "~ STORAGE_ACCOUNT_KEY: ****** -> ******", change.Masked().String())
+
+	change = Change{Key: "AZURE_KEY_VAULT_NAME", Kind: ChangeAdded, NewValue: "vault"}
+	require.Equal(t, "+ AZURE_KEY_VAULT_NAME=vault", change.Masked().String())
+}
+
+func Test_IsSecretKey(t *testing.T) {
+	tests := map[string]bool{
+		"DB_PASSWORD":                     true,
+		"STORAGE_ACCOUNT_KEY":             true,
+		"API_TOKEN":                       true,
+		"CLIENT_SECRET":                   true,
+		"SERVICEBUS_CONNECTION_STRING":    true,
+		"AZURE_STORAGE_SAS_URL":           true,
+		"AZURE_KEY_VAULT_ENDPOINT":        false,
+		"AZURE_LOCATION":                  false,
+		"SERVICE_API_ENDPOINTS":           false,
+		"AZURE_CONTAINER_REGISTRY_SERVER": false,
+	}
+
+	for key, expected := range tests {
+		t.Run(key, func(t *testing.T) {
+			require.Equal(t, expected, IsSecretKey(key))
+		})
+	}
+}
+
+func Test_NewSnapshot(t *testing.T) {
+	first := newSnapshot(nil, map[string]string{"KEY": "value1"})
+	require.NotNil(t, first)
+	require.Equal(t, 1, first.Version)
+
+	require.Nil(t, newSnapshot(first, map[string]string{"KEY": "value1"}))
+
+	second := newSnapshot(first, map[string]string{"KEY": "value2"})
+	require.NotNil(t, second)
+	require.Equal(t, 2, second.Version)
+}
@@ -2,6 +2,7 @@ package environment
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"golang.org/x/exp/slices"
@@ -99,16 +101,13 @@ func (fs *LocalFileDataStore) Get(ctx context.Context, name string) (*Environmen
 // Reload reloads the environment from the persistent data store
 func (fs *LocalFileDataStore) Reload(ctx context.Context, env *Environment) error {
 	// Reload env values
-	if envMap, err := godotenv.Read(fs.EnvPath(env)); errors.Is(err, os.ErrNotExist) {
-		env.dotenv = make(map[string]string)
-		env.deletedKeys = make(map[string]struct{})
-	} else if err != nil {
-		return fmt.Errorf("loading .env: %w", err)
-	} else {
-		env.dotenv = envMap
-		env.deletedKeys = make(map[string]struct{})
+	envMap, err := fs.readDotenv(env)
+	if err != nil {
+		return err
 	}
 
+	env.setDotenv(envMap)
+
 	// Reload env config
 	if cfg, err := fs.configManager.Load(fs.ConfigPath(env)); errors.Is(err, os.ErrNotExist) {
 		env.Config = config.NewEmptyConfig()
@@ -131,6 +130,18 @@ func (fs *LocalFileDataStore) Reload(ctx context.Context, env *Environment) erro
 	return nil
 }
 
+// readDotenv reads the values persisted in the .env file of the environment
+func (fs *LocalFileDataStore) readDotenv(env *Environment) (map[string]string, error) {
+	envMap, err := godotenv.Read(fs.EnvPath(env))
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	} else if err != nil {
+		return nil, fmt.Errorf("loading .env: %w", err)
+	}
+
+	return envMap, nil
+}
+
 // Save saves the environment to the persistent data store
 func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment) error {
 	// Update configuration
@@ -138,22 +149,13 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment) error
 		return fmt.Errorf("saving config: %w", err)
 	}
 
-	// Cache current values & reload to get any new env vars
-	currentValues := env.dotenv
-	deletedValues := env.deletedKeys
-	if err := fs.Reload(ctx, env); err != nil {
+	// Overlay current values & replay deletions on the persisted values, to keep any new env vars
+	persisted, err := fs.readDotenv(env)
+	if err != nil {
 		return fmt.Errorf("failed reloading env vars, %w", err)
 	}
 
-	// Overlay current values before saving
-	for key, value := range currentValues {
-		env.dotenv[key] = value
-	}
-
-	// Replay deletion
-	for key := range deletedValues {
-		delete(env.dotenv, key)
-	}
+	env.mergeDotenv(persisted)
 
 	marshalled, err := marshallDotEnv(env)
 	if err != nil {
@@ -175,6 +177,10 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment) error
 		return fmt.Errorf("saving .env: %w", err)
 	}
 
+	if err := fs.recordSnapshot(ctx, env); err != nil {
+		return fmt.Errorf("recording history: %w", err)
+	}
+
 	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
 	return nil
 }
@@ -194,3 +200,105 @@ func (fs *LocalFileDataStore) Delete(ctx context.Context, name string) error {
 
 	return nil
 }
+
+// History returns the snapshots of the values of the environment, ordered from the oldest to the latest
+func (fs *LocalFileDataStore) History(ctx context.Context, name string) ([]*Snapshot, error) {
+	versions, err := fs.historyVersions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*Snapshot, 0, len(versions))
+	for _, version := range versions {
+		snapshot, err := fs.readSnapshot(name, version)
+		if err != nil {
+			return nil, err
+		}
+
+		history = append(history, snapshot)
+	}
+
+	return history, nil
+}
+
+// recordSnapshot records a new snapshot of the values of the environment, unless they are unchanged
+func (fs *LocalFileDataStore) recordSnapshot(ctx context.Context, env *Environment) error {
+	versions, err := fs.historyVersions(env.name)
+	if err != nil {
+		return err
+	}
+
+	var latest *Snapshot
+	if len(versions) > 0 {
+		latest, err = fs.readSnapshot(env.name, versions[len(versions)-1])
+		if err != nil {
+			return err
+		}
+	}
+
+	snapshot := newSnapshot(latest, env.Dotenv())
+	if snapshot == nil {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	historyDir := fs.historyDirectory(env.name)
+	if err := os.MkdirAll(historyDir, osutil.PermissionDirectory); err != nil {
+		return err
+	}
+
+	snapshotPath := filepath.Join(historyDir, snapshotFileName(snapshot.Version))
+	if err := os.WriteFile(snapshotPath, content, osutil.PermissionFile); err != nil {
+		return err
+	}
+
+	for _, version := range expiredVersions(append(versions, snapshot.Version)) {
+		if err := os.Remove(filepath.Join(historyDir, snapshotFileName(version))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (fs *LocalFileDataStore) historyDirectory(name string) string {
+	return filepath.Join(fs.azdContext.EnvironmentRoot(name), historyFolderName)
+}
+
+// historyVersions returns the sorted versions of the snapshots of the history of the environment
+func (fs *LocalFileDataStore) historyVersions(name string) ([]int, error) {
+	entries, err := os.ReadDir(fs.historyDirectory(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return []int{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("listing history: %w", err)
+	}
+
+	versions := []int{}
+	for _, entry := range entries {
+		if version, ok := snapshotVersion(entry.Name()); ok && !entry.IsDir() {
+			versions = append(versions, version)
+		}
+	}
+
+	slices.Sort(versions)
+	return versions, nil
+}
+
+func (fs *LocalFileDataStore) readSnapshot(name string, version int) (*Snapshot, error) {
+	content, err := os.ReadFile(filepath.Join(fs.historyDirectory(name), snapshotFileName(version)))
+	if err != nil {
+		return nil, fmt.Errorf("reading version %d: %w", version, err)
+	}
+
+	var snapshot *Snapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, fmt.Errorf("reading version %d: %w", version, err)
+	}
+
+	return snapshot, nil
+}
@@ -83,3 +83,44 @@ func Test_LocalFileDataStore_ConfigPath(t *testing.T) {
 
 	require.Equal(t, expected, actual)
 }
+
+func Test_LocalFileDataStore_History(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	dataStore := NewLocalFileDataStore(azdContext, fileConfigManager)
+	historyStore := dataStore.(HistoryStore)
+
+	env := New("env1")
+	env.DotenvSet("key1", "value1")
+	require.NoError(t, dataStore.Save(*mockContext.Context, env))
+
+	// Saving unchanged values doesn't record a new version
+	require.NoError(t, dataStore.Save(*mockContext.Context, env))
+
+	env.DotenvSet("key1", "value2")
+	require.NoError(t, dataStore.Save(*mockContext.Context, env))
+
+	history, err := historyStore.History(*mockContext.Context, "env1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, 1, history[0].Version)
+	require.Equal(t, "value1", history[0].Values["key1"])
+	require.Equal(t, 2, history[1].Version)
+	require.Equal(t, "value2", history[1].Values["key1"])
+
+	t.Run("Limit", func(t *testing.T) {
+		defaultLimit := historyLimit
+		historyLimit = 2
+		t.Cleanup(func() { historyLimit = defaultLimit })
+
+		env.DotenvSet("key1", "value3")
+		require.NoError(t, dataStore.Save(*mockContext.Context, env))
+
+		history, err := historyStore.History(*mockContext.Context, "env1")
+		require.NoError(t, err)
+		require.Len(t, history, 2)
+		require.Equal(t, 2, history[0].Version)
+		require.Equal(t, 3, history[1].Version)
+	})
+}
@@ -0,0 +1,113 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// Error returned when the environment is locked by another user or process
+	ErrLocked = errors.New("environment is locked")
+
+	// Error returned when locking an environment whose state isn't stored in a remote backend supporting locks
+	ErrLockNotSupported = errors.New("locking requires a remote state backend")
+
+	// Error returned when saving an environment whose remote state was modified since it was last downloaded
+	ErrRemoteConflict = errors.New("remote environment was modified")
+)
+
+// LockInfo describes the holder of the lock of an environment
+type LockInfo struct {
+	// The unique id of the lock
+	Id string `json:"id"`
+	// The user and machine holding the lock, e.g. user@host
+	Owner string `json:"owner"`
+	// The operation holding the lock, e.g. "provision"
+	Operation  string    `json:"operation"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	// The lock can be acquired by someone else after it expires, which happens when the holder exits without releasing it
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// IsExpired returns true when the lock is no longer held
+func (li *LockInfo) IsExpired() bool {
+	return time.Now().After(li.ExpiresAt)
+}
+
+// Lease is a lock held on an environment, renewed in the background until it's released
+type Lease struct {
+	Info    LockInfo
+	release func(ctx context.Context) error
+}
+
+// Release releases the lock
+func (l *Lease) Release(ctx context.Context) error {
+	if l == nil || l.release == nil {
+		return nil
+	}
+
+	release := l.release
+	l.release = nil
+
+	return release(ctx)
+}
+
+// Locker is implemented by the data stores supporting locking environments
+type Locker interface {
+	// Lock acquires the lock of the environment for the duration of an operation.
+	// An error wrapping ErrLocked is returned when the lock is held by someone else.
+	Lock(ctx context.Context, name string, operation string) (*Lease, error)
+
+	// LockStatus returns the current lock of the environment, or nil when it isn't locked.
+	LockStatus(ctx context.Context, name string) (*LockInfo, error)
+
+	// BreakLock forcibly releases the lock of the environment, whoever holds it.
+	BreakLock(ctx context.Context, name string) error
+}
+
+func newLockInfo(operation string, duration time.Duration) LockInfo {
+	now := time.Now().UTC()
+	return LockInfo{
+		Id:         uuid.NewString(),
+		Owner:      currentOwner(),
+		Operation:  operation,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(duration),
+	}
+}
+
+func lockedError(name string, info *LockInfo) error {
+	return fmt.Errorf(
+		"%w: environment '%s' is locked by %s running '%s' since %s. "+
+			"Wait for the operation to complete, or run 'azd env lock break -e %s' if it's no longer running",
+		ErrLocked,
+		name,
+		info.Owner,
+		info.Operation,
+		info.AcquiredAt.Local().Format(time.RFC1123),
+		name,
+	)
+}
+
+// currentOwner returns the current user and machine, e.g. user@host
+func currentOwner() string {
+	owner := "unknown"
+	if currentUser, err := user.Current(); err == nil {
+		owner = currentUser.Username
+	}
+
+	if hostname, err := os.Hostname(); err == nil {
+		owner = fmt.Sprintf("%s@%s", owner, hostname)
+	}
+
+	return owner
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
@@ -49,6 +51,17 @@ var (
 
 	// Error returned when an environment name is not specified
 	ErrNameNotSpecified = errors.New("environment not specified")
+
+	// Error returned when using the remote data store of environments when remote state isn't configured
+	ErrRemoteNotConfigured = errors.New("remote state is not configured")
+)
+
+// DataStoreKind identifies one of the data stores of environments
+type DataStoreKind string
+
+const (
+	DataStoreKindLocal  DataStoreKind = "local"
+	DataStoreKindRemote DataStoreKind = "remote"
 )
 
 // Manager is the interface used for managing instances of environments
@@ -73,6 +86,37 @@ type Manager interface {
 
 	EnvPath(env *Environment) string
 	ConfigPath(env *Environment) string
+
+	// Lock acquires the lock of the remote state of the environment for the duration of an operation, so concurrent
+	// operations on a shared environment fail fast instead of overwriting each other's state.
+	// A lease that does nothing is returned when the environment state isn't stored remotely.
+	Lock(ctx context.Context, name string, operation string) (*Lease, error)
+
+	// LockStatus returns the current lock of the remote state of the environment, or nil when it isn't locked.
+	// ErrLockNotSupported is returned when the environment state isn't stored remotely.
+	LockStatus(ctx context.Context, name string) (*LockInfo, error)
+
+	// BreakLock forcibly releases the lock of the remote state of the environment.
+	// ErrLockNotSupported is returned when the environment state isn't stored remotely.
+	BreakLock(ctx context.Context, name string) error
+
+	// History returns the snapshots of the values of the environment recorded by each save, ordered from the oldest to
+	// the latest. The history is read from the remote state when configured, so it includes the saves of other users.
+	History(ctx context.Context, name string) ([]*Snapshot, error)
+
+	// Restore restores the values of the environment to those of the specified version of its history, which records
+	// a new version. ErrVersionNotFound is returned when the version isn't part of the history.
+	Restore(ctx context.Context, env *Environment, version int) (*Snapshot, error)
+
+	// ExportBundle returns a bundle of the environment read from the specified data store.
+	// When the data store is empty, the environment is read the same way as Get.
+	ExportBundle(ctx context.Context, name string, from DataStoreKind) (*Bundle, error)
+
+	// ImportBundle saves the environment of the bundle with the specified name to the specified data store, or to all
+	// the data stores the same way as Save when the data store is empty. An error wrapping ErrExists is returned when
+	// the environment already exists, unless overwrite is true.
+	ImportBundle(
+		ctx context.Context, bundle *Bundle, name string, to DataStoreKind, overwrite bool) (*Environment, error)
 }
 
 type manager struct {
@@ -379,6 +423,9 @@ func (m *manager) Get(ctx context.Context, name string) (*Environment, error) {
 
 // Save saves the environment to the persistent data store
 func (m *manager) Save(ctx context.Context, env *Environment) error {
+	env.persistMu.Lock()
+	defer env.persistMu.Unlock()
+
 	if err := m.local.Save(ctx, env); err != nil {
 		return fmt.Errorf("saving local environment, %w", err)
 	}
@@ -396,6 +443,9 @@ func (m *manager) Save(ctx context.Context, env *Environment) error {
 
 // Reload reloads the environment from the persistent data store
 func (m *manager) Reload(ctx context.Context, env *Environment) error {
+	env.persistMu.Lock()
+	defer env.persistMu.Unlock()
+
 	return m.local.Reload(ctx, env)
 }
 
@@ -467,3 +517,210 @@ func invalidEnvironmentNameMsg(environmentName string) string {
 		environmentName,
 	)
 }
+
+// Lock acquires the lock of the remote state of the environment for the duration of an operation
+func (m *manager) Lock(ctx context.Context, name string, operation string) (*Lease, error) {
+	if name == "" {
+		return nil, ErrNameNotSpecified
+	}
+
+	locker, ok := m.remote.(Locker)
+	if !ok {
+		return &Lease{}, nil
+	}
+
+	return locker.Lock(ctx, name, operation)
+}
+
+// LockStatus returns the current lock of the remote state of the environment, or nil when it isn't locked
+func (m *manager) LockStatus(ctx context.Context, name string) (*LockInfo, error) {
+	if name == "" {
+		return nil, ErrNameNotSpecified
+	}
+
+	locker, ok := m.remote.(Locker)
+	if !ok {
+		return nil, ErrLockNotSupported
+	}
+
+	return locker.LockStatus(ctx, name)
+}
+
+// BreakLock forcibly releases the lock of the remote state of the environment
+func (m *manager) BreakLock(ctx context.Context, name string) error {
+	if name == "" {
+		return ErrNameNotSpecified
+	}
+
+	locker, ok := m.remote.(Locker)
+	if !ok {
+		return ErrLockNotSupported
+	}
+
+	return locker.BreakLock(ctx, name)
+}
+
+// History returns the snapshots of the values of the environment, ordered from the oldest to the latest
+func (m *manager) History(ctx context.Context, name string) ([]*Snapshot, error) {
+	if name == "" {
+		return nil, ErrNameNotSpecified
+	}
+
+	if historyStore, ok := m.remote.(HistoryStore); ok {
+		return historyStore.History(ctx, name)
+	}
+
+	historyStore, ok := m.local.(HistoryStore)
+	if !ok {
+		return []*Snapshot{}, nil
+	}
+
+	return historyStore.History(ctx, name)
+}
+
+// Restore restores the values of the environment to those of the specified version of its history
+func (m *manager) Restore(ctx context.Context, env *Environment, version int) (*Snapshot, error) {
+	history, err := m.History(ctx, env.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(history, func(snapshot *Snapshot) bool {
+		return snapshot.Version == version
+	})
+	if index < 0 {
+		return nil, fmt.Errorf("version %d of environment '%s': %w", version, env.Name(), ErrVersionNotFound)
+	}
+
+	snapshot := history[index]
+	for key := range env.Dotenv() {
+		if _, has := snapshot.Values[key]; !has {
+			env.DotenvDelete(key)
+		}
+	}
+
+	for key, value := range snapshot.Values {
+		env.DotenvSet(key, value)
+	}
+
+	if err := m.Save(ctx, env); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// ExportBundle returns a bundle of the environment read from the specified data store
+func (m *manager) ExportBundle(ctx context.Context, name string, from DataStoreKind) (*Bundle, error) {
+	if name == "" {
+		return nil, ErrNameNotSpecified
+	}
+
+	var env *Environment
+	var err error
+	if from == "" {
+		env, err = m.Get(ctx, name)
+	} else {
+		var dataStore DataStore
+		dataStore, err = m.dataStore(from)
+		if err != nil {
+			return nil, err
+		}
+
+		env, err = dataStore.Get(ctx, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewBundle(env), nil
+}
+
+// ImportBundle saves the environment of the bundle with the specified name to the specified data store
+func (m *manager) ImportBundle(
+	ctx context.Context,
+	bundle *Bundle,
+	name string,
+	to DataStoreKind,
+	overwrite bool,
+) (*Environment, error) {
+	if !IsValidEnvironmentName(name) {
+		return nil, errors.New(strings.TrimSpace(invalidEnvironmentNameMsg(name)))
+	}
+
+	env, err := bundle.Environment(name)
+	if err != nil {
+		return nil, err
+	}
+
+	dataStores := []DataStore{m.local}
+	if m.remote != nil {
+		dataStores = append(dataStores, m.remote)
+	}
+
+	if to != "" {
+		dataStore, err := m.dataStore(to)
+		if err != nil {
+			return nil, err
+		}
+
+		dataStores = []DataStore{dataStore}
+	}
+
+	for _, dataStore := range dataStores {
+		existing, err := dataStore.Get(ctx, name)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("checking for existing environment: %w", err)
+		}
+
+		if !overwrite {
+			return nil, fmt.Errorf("environment '%s': %w", name, ErrExists)
+		}
+
+		// Saving merges the values with the existing ones, the values missing from the bundle must be deleted
+		values := env.Dotenv()
+		for key := range existing.Dotenv() {
+			if _, has := values[key]; !has {
+				env.DotenvDelete(key)
+			}
+		}
+	}
+
+	// Saving to the remote data store records the versions of the remote environment alongside the local environment,
+	// which must not be created when importing only to the remote data store
+	envRoot := m.azdContext.EnvironmentRoot(name)
+	_, statErr := os.Stat(envRoot)
+	hasLocal := statErr == nil
+
+	for _, dataStore := range dataStores {
+		if err := dataStore.Save(ctx, env); err != nil {
+			return nil, fmt.Errorf("saving environment: %w", err)
+		}
+	}
+
+	if to == DataStoreKindRemote && !hasLocal {
+		if err := os.RemoveAll(envRoot); err != nil {
+			return nil, fmt.Errorf("removing local environment: %w", err)
+		}
+	}
+
+	return env, nil
+}
+
+// dataStore returns the data store of the specified kind
+func (m *manager) dataStore(kind DataStoreKind) (DataStore, error) {
+	switch kind {
+	case DataStoreKindLocal:
+		return m.local, nil
+	case DataStoreKindRemote:
+		if m.remote == nil {
+			return nil, ErrRemoteNotConfigured
+		}
+
+		return m.remote, nil
+	default:
+		return nil, fmt.Errorf("invalid data store '%s', valid values are 'local' and 'remote'", kind)
+	}
+}
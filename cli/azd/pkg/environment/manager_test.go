@@ -18,6 +18,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
+	"github.com/azure/azure-dev/cli/azd/pkg/state/filesystem"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -315,6 +316,131 @@ func Test_EnvManager_Save(t *testing.T) {
 	})
 }
 
+func Test_EnvManager_Lock(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+
+	t.Run("LocalOnly", func(t *testing.T) {
+		manager := newManagerForTest(azdContext, mockContext.Console, &MockDataStore{}, nil)
+
+		lease, err := manager.Lock(*mockContext.Context, "env1", "provision")
+		require.NoError(t, err)
+		require.NoError(t, lease.Release(*mockContext.Context))
+
+		_, err = manager.LockStatus(*mockContext.Context, "env1")
+		require.ErrorIs(t, err, ErrLockNotSupported)
+
+		err = manager.BreakLock(*mockContext.Context, "env1")
+		require.ErrorIs(t, err, ErrLockNotSupported)
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		fsConfig := &filesystem.Config{
+			Path:   t.TempDir(),
+			Folder: "project",
+		}
+		remoteDataStore := NewFileSystemDataStore(config.NewManager(), fsConfig, azdContext)
+		manager := newManagerForTest(azdContext, mockContext.Console, &MockDataStore{}, remoteDataStore)
+
+		lease, err := manager.Lock(*mockContext.Context, "env1", "provision")
+		require.NoError(t, err)
+
+		status, err := manager.LockStatus(*mockContext.Context, "env1")
+		require.NoError(t, err)
+		require.Equal(t, lease.Info.Id, status.Id)
+
+		require.NoError(t, manager.BreakLock(*mockContext.Context, "env1"))
+
+		status, err = manager.LockStatus(*mockContext.Context, "env1")
+		require.NoError(t, err)
+		require.Nil(t, status)
+	})
+}
+
+func Test_EnvManager_Restore(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	localDataStore := NewLocalFileDataStore(azdContext, config.NewFileConfigManager(config.NewManager()))
+	manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, nil)
+
+	env := New("env1")
+	env.DotenvSet("key1", "value1")
+	require.NoError(t, manager.Save(*mockContext.Context, env))
+
+	env.DotenvSet("key1", "value2")
+	env.DotenvSet("key2", "value2")
+	require.NoError(t, manager.Save(*mockContext.Context, env))
+
+	snapshot, err := manager.Restore(*mockContext.Context, env, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, snapshot.Version)
+	require.Equal(t, "value1", env.Getenv("key1"))
+	_, has := env.LookupEnv("key2")
+	require.False(t, has)
+
+	// Restoring records a new version
+	history, err := manager.History(*mockContext.Context, "env1")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, history[0].Values, history[2].Values)
+
+	_, err = manager.Restore(*mockContext.Context, env, 10)
+	require.ErrorIs(t, err, ErrVersionNotFound)
+}
+
+func Test_EnvManager_Bundle(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	localDataStore := NewLocalFileDataStore(azdContext, config.NewFileConfigManager(config.NewManager()))
+	fsConfig := &filesystem.Config{
+		Path:   t.TempDir(),
+		Folder: "project",
+	}
+	remoteDataStore := NewFileSystemDataStore(config.NewManager(), fsConfig, azdContext)
+	manager := newManagerForTest(azdContext, mockContext.Console, localDataStore, remoteDataStore)
+
+	env := New("env1")
+	env.DotenvSet("key1", "value1")
+	require.NoError(t, localDataStore.Save(*mockContext.Context, env))
+
+	bundle, err := manager.ExportBundle(*mockContext.Context, "env1", DataStoreKindLocal)
+	require.NoError(t, err)
+	require.Equal(t, "value1", bundle.Values["key1"])
+
+	t.Run("ToRemote", func(t *testing.T) {
+		_, err := manager.ImportBundle(*mockContext.Context, bundle, "env2", DataStoreKindRemote, false)
+		require.NoError(t, err)
+
+		remoteEnv, err := remoteDataStore.Get(*mockContext.Context, "env2")
+		require.NoError(t, err)
+		require.Equal(t, "value1", remoteEnv.Getenv("key1"))
+		require.Equal(t, "env2", remoteEnv.Getenv(EnvNameEnvVarName))
+
+		// The environment isn't created locally
+		_, err = localDataStore.Get(*mockContext.Context, "env2")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Exists", func(t *testing.T) {
+		_, err := manager.ImportBundle(*mockContext.Context, bundle, "env1", "", false)
+		require.ErrorIs(t, err, ErrExists)
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		env.DotenvSet("key2", "value2")
+		require.NoError(t, localDataStore.Save(*mockContext.Context, env))
+
+		_, err := manager.ImportBundle(*mockContext.Context, bundle, "env1", DataStoreKindLocal, true)
+		require.NoError(t, err)
+
+		localEnv, err := localDataStore.Get(*mockContext.Context, "env1")
+		require.NoError(t, err)
+		require.Equal(t, "value1", localEnv.Getenv("key1"))
+		_, has := localEnv.LookupEnv("key2")
+		require.False(t, has)
+	})
+}
+
 func Test_EnvManager_CreateFromContainer(t *testing.T) {
 	t.Run("WithRemoteConfig", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
@@ -337,6 +463,41 @@ func Test_EnvManager_CreateFromContainer(t *testing.T) {
 		require.IsType(t, new(StorageBlobDataStore), manager.remote)
 	})
 
+	t.Run("WithFileSystemRemoteConfig", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registerContainerComponents(t, mockContext)
+
+		mockContext.Container.MustRegisterSingleton(func() *state.RemoteConfig {
+			return &state.RemoteConfig{
+				Backend: string(RemoteKindFileSystem),
+				Config:  map[string]interface{}{},
+			}
+		})
+
+		var envManager Manager
+		err := mockContext.Container.Resolve(&envManager)
+		require.NoError(t, err)
+
+		manager := envManager.(*manager)
+		require.NotNil(t, manager.remote)
+		require.IsType(t, new(StorageBlobDataStore), manager.remote)
+	})
+
+	t.Run("WithInvalidRemoteConfig", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		registerContainerComponents(t, mockContext)
+
+		mockContext.Container.MustRegisterSingleton(func() *state.RemoteConfig {
+			return &state.RemoteConfig{
+				Backend: "Unknown",
+			}
+		})
+
+		var envManager Manager
+		err := mockContext.Container.Resolve(&envManager)
+		require.Error(t, err)
+	})
+
 	t.Run("WithoutRemoteConfig", func(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		registerContainerComponents(t, mockContext)
@@ -369,6 +530,7 @@ func registerContainerComponents(t *testing.T, mockContext *mocks.MockContext) {
 	mockContext.Container.MustRegisterSingleton(NewManager)
 	mockContext.Container.MustRegisterSingleton(NewLocalFileDataStore)
 	mockContext.Container.MustRegisterNamedSingleton(string(RemoteKindAzureBlobStorage), NewStorageBlobDataStore)
+	mockContext.Container.MustRegisterNamedSingleton(string(RemoteKindFileSystem), NewFileSystemDataStore)
 
 	mockContext.Container.MustRegisterSingleton(func() *azcore.ClientOptions {
 		return mockContext.CoreClientOptions
@@ -393,6 +555,14 @@ func registerContainerComponents(t *testing.T, mockContext *mocks.MockContext) {
 		return storageAccountConfig
 	})
 
+	fsConfig := &filesystem.Config{
+		Path:   t.TempDir(),
+		Folder: "test",
+	}
+	mockContext.Container.MustRegisterSingleton(func() *filesystem.Config {
+		return fsConfig
+	})
+
 	mockContext.Container.MustRegisterSingleton(func() *cloud.Cloud {
 		return cloud.AzurePublic()
 	})
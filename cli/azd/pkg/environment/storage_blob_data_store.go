@@ -3,10 +3,14 @@ package environment
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
@@ -14,6 +18,11 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/state/filesystem"
+	"github.com/azure/azure-dev/cli/azd/pkg/state/s3"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"golang.org/x/exp/slices"
@@ -24,18 +33,58 @@ var (
 	ErrInvalidContainer = errors.New("storage container name is invalid.")
 )
 
+// StorageBlobDataStore is a remote data store persisting environments as blobs.
+// The blobs are stored in Azure Blob Storage, or in any other backend implementing storage.BlobClient.
+//
+// Writes are conditional on the ETags of the blobs last downloaded or uploaded from this machine, which are recorded
+// alongside the local environment, so concurrent updates of a shared environment fail instead of overwriting each other.
 type StorageBlobDataStore struct {
 	configManager config.Manager
 	blobClient    storage.BlobClient
+	azdContext    *azdcontext.AzdContext
 }
 
-func NewStorageBlobDataStore(configManager config.Manager, blobClient storage.BlobClient) RemoteDataStore {
+func NewStorageBlobDataStore(
+	configManager config.Manager,
+	blobClient storage.BlobClient,
+	azdContext *azdcontext.AzdContext,
+) RemoteDataStore {
 	return &StorageBlobDataStore{
 		configManager: configManager,
 		blobClient:    blobClient,
+		azdContext:    azdContext,
 	}
 }
 
+// NewFileSystemDataStore creates a remote data store persisting environments as files in a directory,
+// such as a network share.
+func NewFileSystemDataStore(
+	configManager config.Manager,
+	fsConfig *filesystem.Config,
+	azdContext *azdcontext.AzdContext,
+) RemoteDataStore {
+	return NewStorageBlobDataStore(configManager, filesystem.NewBlobClient(fsConfig), azdContext)
+}
+
+// NewS3DataStore creates a remote data store persisting environments as objects in an S3 compatible bucket.
+func NewS3DataStore(
+	configManager config.Manager,
+	s3Config *s3.Config,
+	httpClient httputil.HttpClient,
+	azdContext *azdcontext.AzdContext,
+) RemoteDataStore {
+	return NewStorageBlobDataStore(configManager, s3.NewBlobClient(s3Config, httpClient), azdContext)
+}
+
+// The name of the blob holding the lock of an environment
+const lockBlobName = ".lock"
+
+// The name of the file recording the ETags of the remote blobs of an environment, stored in the local environment root
+const remoteVersionsFileName = ".remote-versions.json"
+
+// The duration a lock is held without being renewed, after which it can be acquired by someone else
+var lockDuration = 10 * time.Minute
+
 // EnvPath returns the path to the .env file for the given environment
 func (fs *StorageBlobDataStore) EnvPath(env *Environment) string {
 	return fmt.Sprintf("%s/%s", env.name, DotEnvFileName)
@@ -61,7 +110,13 @@ func (sbd *StorageBlobDataStore) List(ctx context.Context) ([]*contracts.EnvList
 	envMap := map[string]*contracts.EnvListEnvironment{}
 
 	for _, blob := range blobs {
-		envName := filepath.Base(filepath.Dir(blob.Path))
+		// Only the blobs located directly under the folder of an environment describe it,
+		// others such as locks are ignored
+		envName, blobName, found := strings.Cut(blob.Path, "/")
+		if !found || (blobName != ConfigFileName && blobName != DotEnvFileName) {
+			continue
+		}
+
 		env, has := envMap[envName]
 		if !has {
 			env = &contracts.EnvListEnvironment{
@@ -117,6 +172,16 @@ func (sbd *StorageBlobDataStore) Get(ctx context.Context, name string) (*Environ
 }
 
 func (sbd *StorageBlobDataStore) Save(ctx context.Context, env *Environment) error {
+	versions, err := sbd.loadVersions(env.name)
+	if err != nil {
+		return err
+	}
+
+	// Both blobs are checked before writing any of them, to avoid partially overwriting a modified environment
+	if err := sbd.checkVersions(ctx, env, versions); err != nil {
+		return err
+	}
+
 	// Update configuration
 	cfgWriter := new(bytes.Buffer)
 
@@ -124,9 +189,14 @@ func (sbd *StorageBlobDataStore) Save(ctx context.Context, env *Environment) err
 		return fmt.Errorf("saving config: %w", err)
 	}
 
-	if err := sbd.blobClient.Upload(ctx, sbd.ConfigPath(env), cfgWriter); err != nil {
+	configPath := sbd.ConfigPath(env)
+	etag, err := sbd.blobClient.UploadIf(ctx, configPath, cfgWriter, versionCondition(versions, configPath))
+	if errors.Is(err, storage.ErrPreconditionFailed) {
+		return sbd.conflictError(env)
+	} else if err != nil {
 		return fmt.Errorf("uploading config: %w", describeError(err))
 	}
+	versions[configPath] = etag
 
 	marshalled, err := marshallDotEnv(env)
 	if err != nil {
@@ -135,39 +205,57 @@ func (sbd *StorageBlobDataStore) Save(ctx context.Context, env *Environment) err
 
 	buffer := bytes.NewBuffer([]byte(marshalled))
 
-	if err := sbd.blobClient.Upload(ctx, sbd.EnvPath(env), buffer); err != nil {
+	envPath := sbd.EnvPath(env)
+	etag, err = sbd.blobClient.UploadIf(ctx, envPath, buffer, versionCondition(versions, envPath))
+	if errors.Is(err, storage.ErrPreconditionFailed) {
+		return sbd.conflictError(env)
+	} else if err != nil {
 		return fmt.Errorf("uploading .env: %w", describeError(err))
 	}
+	versions[envPath] = etag
+
+	if err := sbd.saveVersions(env.name, versions); err != nil {
+		return err
+	}
+
+	if err := sbd.recordSnapshot(ctx, env); err != nil {
+		return fmt.Errorf("recording history: %w", err)
+	}
 
 	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
 	return nil
 }
 
 func (sbd *StorageBlobDataStore) Reload(ctx context.Context, env *Environment) error {
+	versions, err := sbd.loadVersions(env.name)
+	if err != nil {
+		return err
+	}
+
 	// Reload .env file
-	dotEnvBuffer, err := sbd.blobClient.Download(ctx, sbd.EnvPath(env))
+	dotEnvBuffer, etag, err := sbd.blobClient.DownloadWithETag(ctx, sbd.EnvPath(env))
 	if err != nil {
 		return describeError(err)
 	}
 
 	defer dotEnvBuffer.Close()
+	versions[sbd.EnvPath(env)] = etag
 
 	envMap, err := godotenv.Parse(dotEnvBuffer)
 	if err != nil {
-		env.dotenv = make(map[string]string)
-		env.deletedKeys = make(map[string]struct{})
-	} else {
-		env.dotenv = envMap
-		env.deletedKeys = make(map[string]struct{})
+		envMap = make(map[string]string)
 	}
 
+	env.setDotenv(envMap)
+
 	// Reload config file
-	configBuffer, err := sbd.blobClient.Download(ctx, sbd.ConfigPath(env))
+	configBuffer, etag, err := sbd.blobClient.DownloadWithETag(ctx, sbd.ConfigPath(env))
 	if err != nil {
 		return describeError(err)
 	}
 
 	defer configBuffer.Close()
+	versions[sbd.ConfigPath(env)] = etag
 
 	if cfg, err := sbd.configManager.Load(configBuffer); errors.Is(err, os.ErrNotExist) {
 		env.Config = config.NewEmptyConfig()
@@ -177,6 +265,10 @@ func (sbd *StorageBlobDataStore) Reload(ctx context.Context, env *Environment) e
 		env.Config = cfg
 	}
 
+	if err := sbd.saveVersions(env.name, versions); err != nil {
+		return err
+	}
+
 	if env.Name() != "" {
 		tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
 	}
@@ -222,6 +314,361 @@ func (sbd *StorageBlobDataStore) Delete(ctx context.Context, name string) error
 	return nil
 }
 
+// Lock acquires the lock of the environment for the duration of an operation.
+// The lock is renewed in the background until the lease is released.
+func (sbd *StorageBlobDataStore) Lock(ctx context.Context, name string, operation string) (*Lease, error) {
+	info := newLockInfo(operation, lockDuration)
+
+	// The lock may be released or taken over by someone else in between attempts
+	for attempt := 0; attempt < 3; attempt++ {
+		etag, err := sbd.writeLock(ctx, name, info, storage.Condition{IfNotExists: true})
+		if err == nil {
+			return sbd.newLease(name, info, etag), nil
+		} else if !errors.Is(err, storage.ErrPreconditionFailed) {
+			return nil, err
+		}
+
+		current, currentETag, err := sbd.readLock(ctx, name)
+		if errors.Is(err, storage.ErrBlobNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if !current.IsExpired() {
+			return nil, lockedError(name, current)
+		}
+
+		log.Printf("taking over expired lock '%s' of environment '%s' held by %s", current.Id, name, current.Owner)
+		etag, err = sbd.writeLock(ctx, name, info, storage.Condition{IfMatch: currentETag})
+		if err == nil {
+			return sbd.newLease(name, info, etag), nil
+		} else if !errors.Is(err, storage.ErrPreconditionFailed) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w: failed acquiring the lock of environment '%s'", ErrLocked, name)
+}
+
+// LockStatus returns the current lock of the environment, or nil when it isn't locked.
+func (sbd *StorageBlobDataStore) LockStatus(ctx context.Context, name string) (*LockInfo, error) {
+	info, _, err := sbd.readLock(ctx, name)
+	if errors.Is(err, storage.ErrBlobNotFound) || errors.Is(err, storage.ErrContainerNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// BreakLock forcibly releases the lock of the environment.
+func (sbd *StorageBlobDataStore) BreakLock(ctx context.Context, name string) error {
+	err := sbd.blobClient.Delete(ctx, sbd.lockPath(name))
+	if err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+		return fmt.Errorf("deleting lock: %w", describeError(err))
+	}
+
+	return nil
+}
+
+// newLease returns the lease of an acquired lock, renewing the lock until the lease is released
+func (sbd *StorageBlobDataStore) newLease(name string, info LockInfo, etag string) *Lease {
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(lockDuration / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				info.ExpiresAt = time.Now().UTC().Add(lockDuration)
+				newETag, err := sbd.writeLock(context.Background(), name, info, storage.Condition{IfMatch: etag})
+				if err != nil {
+					log.Printf("failed renewing lock of environment '%s': %v", name, err)
+					if errors.Is(err, storage.ErrPreconditionFailed) {
+						// The lock was broken, there is nothing left to renew
+						return
+					}
+
+					continue
+				}
+
+				etag = newETag
+			}
+		}
+	}()
+
+	return &Lease{
+		Info: info,
+		release: func(ctx context.Context) error {
+			close(stop)
+			<-stopped
+
+			err := sbd.blobClient.DeleteIf(ctx, sbd.lockPath(name), storage.Condition{IfMatch: etag})
+			if errors.Is(err, storage.ErrPreconditionFailed) || errors.Is(err, storage.ErrBlobNotFound) {
+				log.Printf("lock of environment '%s' was broken before being released", name)
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("releasing lock: %w", describeError(err))
+			}
+
+			return nil
+		},
+	}
+}
+
+func (sbd *StorageBlobDataStore) lockPath(name string) string {
+	return fmt.Sprintf("%s/%s", name, lockBlobName)
+}
+
+func (sbd *StorageBlobDataStore) writeLock(
+	ctx context.Context,
+	name string,
+	info LockInfo,
+	condition storage.Condition,
+) (string, error) {
+	content, err := json.Marshal(info)
+	if err != nil {
+		return "", err
+	}
+
+	etag, err := sbd.blobClient.UploadIf(ctx, sbd.lockPath(name), bytes.NewReader(content), condition)
+	if err != nil {
+		if errors.Is(err, storage.ErrPreconditionFailed) {
+			return "", err
+		}
+
+		return "", fmt.Errorf("writing lock: %w", describeError(err))
+	}
+
+	return etag, nil
+}
+
+func (sbd *StorageBlobDataStore) readLock(ctx context.Context, name string) (*LockInfo, string, error) {
+	reader, etag, err := sbd.blobClient.DownloadWithETag(ctx, sbd.lockPath(name))
+	if err != nil {
+		return nil, "", describeError(err)
+	}
+	defer reader.Close()
+
+	var info *LockInfo
+	if err := json.NewDecoder(reader).Decode(&info); err != nil {
+		return nil, "", fmt.Errorf("reading lock: %w", err)
+	}
+
+	return info, etag, nil
+}
+
+// History returns the snapshots of the values of the environment, ordered from the oldest to the latest
+func (sbd *StorageBlobDataStore) History(ctx context.Context, name string) ([]*Snapshot, error) {
+	versions, err := sbd.historyVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*Snapshot, 0, len(versions))
+	for _, version := range versions {
+		snapshot, err := sbd.readSnapshot(ctx, name, version)
+		if err != nil {
+			return nil, err
+		}
+
+		history = append(history, snapshot)
+	}
+
+	return history, nil
+}
+
+// recordSnapshot records a new snapshot of the values of the environment, unless they are unchanged
+func (sbd *StorageBlobDataStore) recordSnapshot(ctx context.Context, env *Environment) error {
+	versions, err := sbd.historyVersions(ctx, env.name)
+	if err != nil {
+		return err
+	}
+
+	var latest *Snapshot
+	if len(versions) > 0 {
+		latest, err = sbd.readSnapshot(ctx, env.name, versions[len(versions)-1])
+		if err != nil {
+			return err
+		}
+	}
+
+	snapshot := newSnapshot(latest, env.Dotenv())
+	if snapshot == nil {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// The version is only written once, in case another save recorded it concurrently
+	_, err = sbd.blobClient.UploadIf(
+		ctx,
+		sbd.snapshotPath(env.name, snapshot.Version),
+		bytes.NewReader(content),
+		storage.Condition{IfNotExists: true},
+	)
+	if errors.Is(err, storage.ErrPreconditionFailed) {
+		log.Printf("version %d of environment '%s' was already recorded", snapshot.Version, env.name)
+		return nil
+	} else if err != nil {
+		return describeError(err)
+	}
+
+	for _, version := range expiredVersions(append(versions, snapshot.Version)) {
+		err := sbd.blobClient.Delete(ctx, sbd.snapshotPath(env.name, version))
+		if err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+			return describeError(err)
+		}
+	}
+
+	return nil
+}
+
+func (sbd *StorageBlobDataStore) snapshotPath(name string, version int) string {
+	return fmt.Sprintf("%s/%s/%s", name, historyFolderName, snapshotFileName(version))
+}
+
+// historyVersions returns the sorted versions of the snapshots of the history of the environment
+func (sbd *StorageBlobDataStore) historyVersions(ctx context.Context, name string) ([]int, error) {
+	blobs, err := sbd.blobClient.Items(ctx)
+	if errors.Is(err, storage.ErrContainerNotFound) {
+		return []int{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("listing history: %w", describeError(err))
+	}
+
+	historyPrefix := fmt.Sprintf("%s/%s/", name, historyFolderName)
+	versions := []int{}
+	for _, blob := range blobs {
+		fileName, found := strings.CutPrefix(blob.Path, historyPrefix)
+		if !found {
+			continue
+		}
+
+		if version, ok := snapshotVersion(fileName); ok {
+			versions = append(versions, version)
+		}
+	}
+
+	slices.Sort(versions)
+	return versions, nil
+}
+
+func (sbd *StorageBlobDataStore) readSnapshot(ctx context.Context, name string, version int) (*Snapshot, error) {
+	reader, err := sbd.blobClient.Download(ctx, sbd.snapshotPath(name, version))
+	if err != nil {
+		return nil, fmt.Errorf("reading version %d: %w", version, describeError(err))
+	}
+	defer reader.Close()
+
+	var snapshot *Snapshot
+	if err := json.NewDecoder(reader).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("reading version %d: %w", version, err)
+	}
+
+	return snapshot, nil
+}
+
+// checkVersions ensures the blobs of the environment weren't modified since they were last downloaded or uploaded
+func (sbd *StorageBlobDataStore) checkVersions(ctx context.Context, env *Environment, versions map[string]string) error {
+	if len(versions) == 0 {
+		return nil
+	}
+
+	blobs, err := sbd.blobClient.Items(ctx)
+	if err != nil && !errors.Is(err, storage.ErrContainerNotFound) {
+		return fmt.Errorf("listing blobs: %w", describeError(err))
+	}
+
+	current := map[string]string{}
+	for _, blob := range blobs {
+		current[blob.Path] = blob.ETag
+	}
+
+	for _, blobPath := range []string{sbd.ConfigPath(env), sbd.EnvPath(env)} {
+		expected, has := versions[blobPath]
+		if !has {
+			continue
+		}
+
+		// ETags are quoted in some responses, e.g. in http headers, but not in others
+		if strings.Trim(current[blobPath], `"`) != strings.Trim(expected, `"`) {
+			return sbd.conflictError(env)
+		}
+	}
+
+	return nil
+}
+
+func (sbd *StorageBlobDataStore) conflictError(env *Environment) error {
+	return fmt.Errorf(
+		"%w: environment '%s' was updated by another user or process since it was last downloaded. "+
+			"To overwrite the remote version with your local changes, delete only the version tracking file '%s' "+
+			"and try again. Don't delete the folder of the environment, which also holds its local state",
+		ErrRemoteConflict,
+		env.name,
+		filepath.Join(sbd.azdContext.EnvironmentRoot(env.name), remoteVersionsFileName),
+	)
+}
+
+// loadVersions returns the ETags of the blobs of the environment last downloaded or uploaded, by path
+func (sbd *StorageBlobDataStore) loadVersions(name string) (map[string]string, error) {
+	versions := map[string]string{}
+
+	content, err := os.ReadFile(filepath.Join(sbd.azdContext.EnvironmentRoot(name), remoteVersionsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return versions, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading remote versions: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &versions); err != nil {
+		// The versions are only used to detect conflicts, the next save records them again
+		log.Printf("ignoring invalid remote versions of environment '%s': %v", name, err)
+		return map[string]string{}, nil
+	}
+
+	return versions, nil
+}
+
+func (sbd *StorageBlobDataStore) saveVersions(name string, versions map[string]string) error {
+	content, err := json.MarshalIndent(versions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	envRoot := sbd.azdContext.EnvironmentRoot(name)
+	if err := os.MkdirAll(envRoot, osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("saving remote versions: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(envRoot, remoteVersionsFileName), content, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("saving remote versions: %w", err)
+	}
+
+	return nil
+}
+
+// versionCondition returns the condition of a write of a blob, so it only succeeds when the blob wasn't modified since
+// it was last downloaded or uploaded. Writes of blobs that were never downloaded or uploaded are unconditional.
+func versionCondition(versions map[string]string, blobPath string) storage.Condition {
+	return storage.Condition{
+		IfMatch: versions[blobPath],
+	}
+}
+
 func describeError(err error) error {
 	var responseErr *azcore.ResponseError
 
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/state/filesystem"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -40,7 +43,7 @@ func Test_StorageBlobDataStore_List(t *testing.T) {
 	t.Run("List", func(t *testing.T) {
 		blobClient := &MockBlobClient{}
 		blobClient.On("Items", *mockContext.Context).Return(validBlobItems, nil)
-		dataStore := NewStorageBlobDataStore(configManager, blobClient)
+		dataStore := NewStorageBlobDataStore(configManager, blobClient, azdcontext.NewAzdContextWithDirectory(t.TempDir()))
 
 		envList, err := dataStore.List(*mockContext.Context)
 		require.NoError(t, err)
@@ -53,7 +56,7 @@ func Test_StorageBlobDataStore_List(t *testing.T) {
 	t.Run("Empty", func(t *testing.T) {
 		blobClient := &MockBlobClient{}
 		blobClient.On("Items", *mockContext.Context).Return(nil, storage.ErrContainerNotFound)
-		dataStore := NewStorageBlobDataStore(configManager, blobClient)
+		dataStore := NewStorageBlobDataStore(configManager, blobClient, azdcontext.NewAzdContextWithDirectory(t.TempDir()))
 
 		envList, err := dataStore.List(*mockContext.Context)
 		require.NoError(t, err)
@@ -66,15 +69,20 @@ func Test_StorageBlobDataStore_SaveAndGet(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	configManager := config.NewManager()
 	blobClient := &MockBlobClient{}
-	dataStore := NewStorageBlobDataStore(configManager, blobClient)
+	dataStore := NewStorageBlobDataStore(configManager, blobClient, azdcontext.NewAzdContextWithDirectory(t.TempDir()))
 
 	t.Run("Success", func(t *testing.T) {
 		envReader := io.NopCloser(bytes.NewReader([]byte("key1=value1")))
 		configReader := io.NopCloser(bytes.NewReader([]byte("{}")))
 		blobClient.On("Items", *mockContext.Context).Return(validBlobItems, nil)
-		blobClient.On("Download", *mockContext.Context, "env1/.env").Return(envReader, nil)
-		blobClient.On("Download", *mockContext.Context, "env1/config.json").Return(configReader, nil)
-		blobClient.On("Upload", *mockContext.Context, mock.AnythingOfType("string"), mock.Anything).Return(nil)
+		blobClient.On("DownloadWithETag", *mockContext.Context, "env1/.env").Return(envReader, "1", nil)
+		blobClient.On("DownloadWithETag", *mockContext.Context, "env1/config.json").Return(configReader, "1", nil)
+		blobClient.
+			On("UploadIf", *mockContext.Context, mock.AnythingOfType("string"), mock.Anything, storage.Condition{}).
+			Return("1", nil)
+		blobClient.
+			On("UploadIf", *mockContext.Context, "env1/.history/1.json", mock.Anything, storage.Condition{IfNotExists: true}).
+			Return("1", nil)
 
 		env1 := New("env1")
 		env1.DotenvSet("key1", "value1")
@@ -93,7 +101,7 @@ func Test_StorageBlobDataStore_SaveAndGet(t *testing.T) {
 func Test_StorageBlobDataStore_Path(t *testing.T) {
 	configManager := config.NewManager()
 	blobClient := &MockBlobClient{}
-	dataStore := NewStorageBlobDataStore(configManager, blobClient)
+	dataStore := NewStorageBlobDataStore(configManager, blobClient, azdcontext.NewAzdContextWithDirectory(t.TempDir()))
 
 	env := New("env1")
 	expected := fmt.Sprintf("%s/%s", env.name, DotEnvFileName)
@@ -105,7 +113,7 @@ func Test_StorageBlobDataStore_Path(t *testing.T) {
 func Test_StorageBlobDataStore_ConfigPath(t *testing.T) {
 	configManager := config.NewManager()
 	blobClient := &MockBlobClient{}
-	dataStore := NewStorageBlobDataStore(configManager, blobClient)
+	dataStore := NewStorageBlobDataStore(configManager, blobClient, azdcontext.NewAzdContextWithDirectory(t.TempDir()))
 
 	env := New("env1")
 	expected := fmt.Sprintf("%s/%s", env.name, ConfigFileName)
@@ -114,6 +122,110 @@ func Test_StorageBlobDataStore_ConfigPath(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func Test_StorageBlobDataStore_Conflict(t *testing.T) {
+	ctx := context.Background()
+	configManager := config.NewManager()
+	fsConfig := &filesystem.Config{
+		Path:   t.TempDir(),
+		Folder: "project",
+	}
+
+	// Two machines sharing the same remote environment
+	dataStore1 := NewFileSystemDataStore(configManager, fsConfig, azdcontext.NewAzdContextWithDirectory(t.TempDir()))
+	dataStore2 := NewFileSystemDataStore(configManager, fsConfig, azdcontext.NewAzdContextWithDirectory(t.TempDir()))
+
+	env := New("env1")
+	env.DotenvSet("key1", "value1")
+	require.NoError(t, dataStore1.Save(ctx, env))
+
+	env2, err := dataStore2.Get(ctx, "env1")
+	require.NoError(t, err)
+
+	env2.DotenvSet("key1", "value2")
+	require.NoError(t, dataStore2.Save(ctx, env2))
+
+	// The first machine didn't download the update of the second one
+	env.DotenvSet("key1", "value3")
+	err = dataStore1.Save(ctx, env)
+	require.ErrorIs(t, err, ErrRemoteConflict)
+	require.ErrorContains(t, err, remoteVersionsFileName)
+	require.NotContains(t, err.Error(), "delete the folder")
+
+	env, err = dataStore1.Get(ctx, "env1")
+	require.NoError(t, err)
+	require.Equal(t, "value2", env.Getenv("key1"))
+
+	env.DotenvSet("key1", "value3")
+	require.NoError(t, dataStore1.Save(ctx, env))
+}
+
+func Test_StorageBlobDataStore_Lock(t *testing.T) {
+	ctx := context.Background()
+	configManager := config.NewManager()
+	fsConfig := &filesystem.Config{
+		Path:   t.TempDir(),
+		Folder: "project",
+	}
+	dataStore := NewFileSystemDataStore(configManager, fsConfig, azdcontext.NewAzdContextWithDirectory(t.TempDir()))
+	locker := dataStore.(Locker)
+
+	status, err := locker.LockStatus(ctx, "env1")
+	require.NoError(t, err)
+	require.Nil(t, status)
+
+	lease, err := locker.Lock(ctx, "env1", "provision")
+	require.NoError(t, err)
+
+	status, err = locker.LockStatus(ctx, "env1")
+	require.NoError(t, err)
+	require.NotNil(t, status)
+	require.Equal(t, lease.Info.Id, status.Id)
+	require.Equal(t, "provision", status.Operation)
+
+	_, err = locker.Lock(ctx, "env1", "deploy")
+	require.ErrorIs(t, err, ErrLocked)
+
+	// Locks don't make environments
+	envs, err := dataStore.List(ctx)
+	require.NoError(t, err)
+	require.Empty(t, envs)
+
+	require.NoError(t, lease.Release(ctx))
+
+	status, err = locker.LockStatus(ctx, "env1")
+	require.NoError(t, err)
+	require.Nil(t, status)
+
+	t.Run("Break", func(t *testing.T) {
+		lease, err := locker.Lock(ctx, "env1", "provision")
+		require.NoError(t, err)
+
+		require.NoError(t, locker.BreakLock(ctx, "env1"))
+
+		lease2, err := locker.Lock(ctx, "env1", "deploy")
+		require.NoError(t, err)
+
+		// Releasing a broken lock doesn't release the lock acquired since
+		require.NoError(t, lease.Release(ctx))
+
+		status, err := locker.LockStatus(ctx, "env1")
+		require.NoError(t, err)
+		require.Equal(t, lease2.Info.Id, status.Id)
+
+		require.NoError(t, lease2.Release(ctx))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		expired := newLockInfo("provision", -time.Minute)
+		_, err := dataStore.(*StorageBlobDataStore).writeLock(ctx, "env1", expired, storage.Condition{})
+		require.NoError(t, err)
+
+		lease, err := locker.Lock(ctx, "env1", "deploy")
+		require.NoError(t, err)
+		require.NoError(t, lease.Release(ctx))
+	})
+}
+
 type MockBlobClient struct {
 	mock.Mock
 }
@@ -143,3 +255,23 @@ func (m *MockBlobClient) Items(ctx context.Context) ([]*storage.Blob, error) {
 
 	return value, args.Error(1)
 }
+
+func (m *MockBlobClient) DownloadWithETag(ctx context.Context, blobPath string) (io.ReadCloser, string, error) {
+	args := m.Called(ctx, blobPath)
+	return args.Get(0).(io.ReadCloser), args.String(1), args.Error(2)
+}
+
+func (m *MockBlobClient) UploadIf(
+	ctx context.Context,
+	blobPath string,
+	reader io.Reader,
+	condition storage.Condition,
+) (string, error) {
+	args := m.Called(ctx, blobPath, reader, condition)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockBlobClient) DeleteIf(ctx context.Context, blobPath string, condition storage.Condition) error {
+	args := m.Called(ctx, blobPath, condition)
+	return args.Error(0)
+}
@@ -8,12 +8,16 @@ package exec
 
 import (
 	"os/exec"
+	"strconv"
+	"strings"
 	"syscall"
 )
 
 // CmdTree represents an `exec.Cmd` run inside a process group. When
 // `Kill` is called, SIGKILL is sent to the process group, which will
 // kill any lingering child processes launched by the root process.
+// Interactive commands don't run in their own process group, their
+// child processes are killed one by one instead.
 type CmdTree struct {
 	CmdTreeOptions
 	*exec.Cmd
@@ -31,9 +35,76 @@ func (o *CmdTree) Start() error {
 		}
 	}
 
+	// Commands created with a context only kill the root process when the context is done, which would leave the
+	// child processes of interactive commands running
+	if o.Interactive && o.Cmd.Cancel != nil {
+		o.Cmd.Cancel = func() error {
+			o.killTree()
+			return nil
+		}
+	}
+
 	return o.Cmd.Start()
 }
 
 func (o *CmdTree) Kill() {
+	if o.Interactive {
+		o.killTree()
+		return
+	}
+
 	_ = syscall.Kill(-o.Cmd.Process.Pid, syscall.SIGKILL)
 }
+
+// killTree kills the root process and all of its descendants. The root process is stopped first so that it doesn't
+// launch new child processes while they are listed.
+func (o *CmdTree) killTree() {
+	// The process has already exited and been waited for
+	if err := o.Cmd.Process.Signal(syscall.SIGSTOP); err != nil {
+		return
+	}
+
+	for _, pid := range descendants(o.Cmd.Process.Pid) {
+		_ = syscall.Kill(pid, syscall.SIGKILL)
+	}
+
+	_ = o.Cmd.Process.Kill()
+}
+
+// descendants returns the ids of the descendant processes of the process, from the parent process ids listed by ps
+func descendants(pid int) []int {
+	output, err := exec.Command("ps", "-A", "-o", "pid=", "-o", "ppid=").Output()
+	if err != nil {
+		return nil
+	}
+
+	children := map[int][]int{}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		childPid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		parentPid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+
+		children[parentPid] = append(children[parentPid], childPid)
+	}
+
+	result := []int{}
+	pending := children[pid]
+	for len(pending) > 0 {
+		childPid := pending[0]
+		pending = append(pending[1:], children[childPid]...)
+		result = append(result, childPid)
+	}
+
+	return result
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build !windows
+// +build !windows
+
+package exec
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCmdTreeKillInteractive(t *testing.T) {
+	pidPath := filepath.Join(t.TempDir(), "pid")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	// The child process writes its pid and outlives the timeout
+	cmdTree := CmdTree{
+		CmdTreeOptions: CmdTreeOptions{Interactive: true},
+		Cmd: exec.CommandContext(
+			ctx, "/bin/sh", "-c", "sh -c 'sleep 30 & echo $! > "+pidPath+"; wait'; echo done"),
+	}
+	require.NoError(t, cmdTree.Start())
+
+	go func() {
+		<-ctx.Done()
+		cmdTree.Kill()
+	}()
+
+	_ = cmdTree.Wait()
+
+	content, err := os.ReadFile(pidPath)
+	require.NoError(t, err)
+	pid, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	require.NoError(t, err)
+
+	// The child process is killed, it may remain as a zombie until reaped
+	require.Eventually(t, func() bool {
+		state, _ := exec.Command("ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+		return len(state) == 0 || strings.HasPrefix(strings.TrimSpace(string(state)), "Z")
+	}, 5*time.Second, 100*time.Millisecond)
+}
@@ -281,7 +281,7 @@ func (l *logBuilder) Write(debug bool, sensitiveArgsData []string) {
 
 	if debug && len(l.env) > 0 {
 		msg.WriteString("Additional env:\n")
-		for _, kv := range l.env {
+		for _, kv := range RedactSensitiveArgs(l.env, sensitiveArgsData) {
 			msg.WriteString(fmt.Sprintf("   %s\n", RedactSensitiveData(kv)))
 		}
 	}
@@ -0,0 +1,238 @@
+package ext
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// HookOutputEnvVarName is the environment variable set to the path of the file hooks write their outputs to.
+// Each output is written as a `<name>=<value>` line, multiline values are written as `<name><<<delimiter>` followed by
+// the lines of the value and by a `<delimiter>` line, like the outputs of GitHub Actions steps.
+const HookOutputEnvVarName = "AZD_HOOK_OUTPUT"
+
+// The value displayed in place of the value of secret outputs
+const maskedOutputValue = "******"
+
+// Configures an output written by a hook
+type HookOutput struct {
+	// When set to true the value of the output is masked when displayed. Unless a vault is set, the value is still stored
+	// in plain text in the .env file of the environment.
+	Secret bool `yaml:"secret,omitempty"`
+	// The name of the key vault storing the value of the output, which supports environment variable substitution.
+	// The secret is named after the environment and the output, and the environment then stores a reference to the
+	// secret rather than the value of the output, which is secret.
+	Vault osutil.ExpandableString `yaml:"vault,omitempty"`
+}
+
+// An output written by a hook
+type hookOutputValue struct {
+	Name   string
+	Value  string
+	Secret bool
+}
+
+// The outputs written by a hook, as displayed once the hook has run
+type hookOutputs struct {
+	hookName string
+	values   []*hookOutputValue
+}
+
+// parseHookOutputs parses the content of the output file of a hook. Outputs written more than once keep the last value.
+func parseHookOutputs(content string) ([]*hookOutputValue, error) {
+	outputs := []*hookOutputValue{}
+	outputsByName := map[string]*hookOutputValue{}
+
+	setOutput := func(name string, value string) error {
+		if name == "" {
+			return fmt.Errorf("output name is empty")
+		}
+
+		if existing, has := outputsByName[name]; has {
+			existing.Value = value
+			return nil
+		}
+
+		hookOutput := &hookOutputValue{Name: name, Value: value}
+		outputs = append(outputs, hookOutput)
+		outputsByName[name] = hookOutput
+		return nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		equalsIndex := strings.Index(line, "=")
+		delimiterIndex := strings.Index(line, "<<")
+
+		switch {
+		case delimiterIndex >= 0 && (equalsIndex < 0 || delimiterIndex < equalsIndex):
+			name := strings.TrimSpace(line[:delimiterIndex])
+			delimiter := line[delimiterIndex+2:]
+			if delimiter == "" {
+				return nil, fmt.Errorf("the delimiter of output '%s' is empty", name)
+			}
+
+			valueLines := []string{}
+			closed := false
+			for scanner.Scan() {
+				valueLine := strings.TrimSuffix(scanner.Text(), "\r")
+				if valueLine == delimiter {
+					closed = true
+					break
+				}
+
+				valueLines = append(valueLines, valueLine)
+			}
+
+			if !closed {
+				return nil, fmt.Errorf("the value of output '%s' isn't terminated by delimiter '%s'", name, delimiter)
+			}
+
+			if err := setOutput(name, strings.Join(valueLines, "\n")); err != nil {
+				return nil, err
+			}
+		case equalsIndex >= 0:
+			if err := setOutput(strings.TrimSpace(line[:equalsIndex]), line[equalsIndex+1:]); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid output '%s', expected '<name>=<value>' or '<name><<<delimiter>'", line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return outputs, nil
+}
+
+// mergeOutputs merges the outputs written by the hook to the output file into the environment, and saves the
+// environment. Outputs stored in a key vault are merged as references to their secrets.
+func (h *HooksRunner) mergeOutputs(
+	ctx context.Context,
+	hookConfig *HookConfig,
+	outputPath string,
+) (*hookOutputs, error) {
+	content, err := os.ReadFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading outputs of hook '%s': %w", hookConfig.Name, err)
+	}
+
+	values, err := parseHookOutputs(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("parsing outputs of hook '%s': %w", hookConfig.Name, err)
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	// The outputs are set at once, since hooks run concurrently by workflows share the environment
+	merged := map[string]string{}
+	for _, hookOutput := range values {
+		value := hookOutput.Value
+
+		if outputConfig, has := hookConfig.Outputs[hookOutput.Name]; has && outputConfig != nil {
+			hookOutput.Secret = outputConfig.Secret || !outputConfig.Vault.Empty()
+
+			if !outputConfig.Vault.Empty() {
+				value, err = h.storeSecretOutput(ctx, hookOutput, outputConfig)
+				if err != nil {
+					return nil, fmt.Errorf("storing output '%s' of hook '%s': %w", hookOutput.Name, hookConfig.Name, err)
+				}
+			} else if outputConfig.Secret {
+				h.console.Message(ctx, output.WithWarningFormat(
+					"WARNING: The secret output '%s' of hook '%s' is stored in plain text in the .env file of the "+
+						"environment. Set the 'vault' of the output to store it in a key vault instead.",
+					hookOutput.Name,
+					hookConfig.Name,
+				))
+			}
+		}
+
+		merged[hookOutput.Name] = value
+	}
+
+	h.env.DotenvSetValues(merged)
+
+	if err := h.envManager.Save(ctx, h.env); err != nil {
+		return nil, fmt.Errorf("saving outputs of hook '%s': %w", hookConfig.Name, err)
+	}
+
+	return &hookOutputs{
+		hookName: hookConfig.Name,
+		values:   values,
+	}, nil
+}
+
+// storeSecretOutput stores the value of the output as a secret of the configured key vault, and returns the reference to
+// the secret
+func (h *HooksRunner) storeSecretOutput(
+	ctx context.Context,
+	hookOutput *hookOutputValue,
+	outputConfig *HookOutput,
+) (string, error) {
+	if h.keyVaultService == nil {
+		return "", fmt.Errorf("key vaults are not supported by this command")
+	}
+
+	vaultName, err := outputConfig.Vault.Envsubst(h.env.Getenv)
+	if err != nil {
+		return "", fmt.Errorf("resolving key vault name: %w", err)
+	}
+
+	if vaultName == "" {
+		return "", fmt.Errorf("the key vault name is empty")
+	}
+
+	secretReference := &keyvault.SecretReference{
+		VaultName:  vaultName,
+		SecretName: keyvault.SecretNameFromEnvironmentKey(h.env.Name(), hookOutput.Name),
+	}
+
+	err = h.keyVaultService.CreateKeyVaultSecret(
+		ctx, h.env.GetSubscriptionId(), secretReference.VaultName, secretReference.SecretName, hookOutput.Value)
+	if err != nil {
+		return "", err
+	}
+
+	return secretReference.String(), nil
+}
+
+// displayOutputs displays the outputs written by a hook, with the values of secret outputs masked
+func (h *HooksRunner) displayOutputs(ctx context.Context, outputs *hookOutputs) {
+	h.console.Message(ctx, fmt.Sprintf("  Outputs of hook %s:", output.WithHighLightFormat(outputs.hookName)))
+
+	for _, hookOutput := range outputs.values {
+		value := hookOutput.Value
+		if hookOutput.Secret {
+			value = maskedOutputValue
+		}
+
+		h.console.Message(ctx, fmt.Sprintf("    %s: %s", hookOutput.Name, output.WithGrayFormat(value)))
+	}
+
+	h.console.Message(ctx, "")
+}
+
+// DisplayOutputs displays the outputs written by the hooks which ran with the output of the caller, such as the previewer
+// of the caller, which the caller must have stopped.
+func (h *HooksRunner) DisplayOutputs(ctx context.Context) {
+	for _, outputs := range h.pendingOutputs {
+		h.displayOutputs(ctx, outputs)
+	}
+
+	h.pendingOutputs = nil
+}
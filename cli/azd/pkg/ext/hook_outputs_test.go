@@ -0,0 +1,224 @@
+package ext
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeyVaultService records the secrets created in key vaults, by vault and secret name
+type fakeKeyVaultService struct {
+	keyvault.KeyVaultService
+	secrets map[string]string
+}
+
+func (f *fakeKeyVaultService) CreateKeyVaultSecret(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+	value string,
+) error {
+	f.secrets[vaultName+"/"+secretName] = value
+	return nil
+}
+
+func Test_parseHookOutputs(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		content := strings.Join([]string{
+			"API_URL=https://api.contoso.com/?a=b",
+			"",
+			"CERTIFICATE<<EOF",
+			"-----BEGIN CERTIFICATE-----",
+			"MIIB",
+			"EOF",
+			"EMPTY=",
+			"API_URL=https://api2.contoso.com\r",
+		}, "\n")
+
+		outputs, err := parseHookOutputs(content)
+		require.NoError(t, err)
+		require.Equal(t, []*hookOutputValue{
+			{Name: "API_URL", Value: "https://api2.contoso.com"},
+			{Name: "CERTIFICATE", Value: "-----BEGIN CERTIFICATE-----\nMIIB"},
+			{Name: "EMPTY", Value: ""},
+		}, outputs)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, content := range []string{
+			"API_URL",
+			"=https://api.contoso.com",
+			"CERTIFICATE<<",
+			"CERTIFICATE<<EOF\nMIIB",
+		} {
+			_, err := parseHookOutputs(content)
+			require.Error(t, err, content)
+		}
+	})
+}
+
+func Test_Hooks_Outputs(t *testing.T) {
+	cwd := t.TempDir()
+
+	// Mocks a hook writing the content to its output file
+	runHook := func(mockContext *mocks.MockContext, content string, hookErr error) {
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			for _, envVar := range args.Env {
+				if outputPath, has := strings.CutPrefix(envVar, HookOutputEnvVarName+"="); has {
+					require.NoError(t, os.WriteFile(outputPath, []byte(content), osutil.PermissionFile))
+				}
+			}
+
+			if hookErr != nil {
+				return exec.NewRunResult(1, "", ""), hookErr
+			}
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+	}
+
+	content := "DATABASE_HOST=db.contoso.com\nDATABASE_PASSWORD=P@ssw0rd\n"
+
+	t.Run("Merged", func(t *testing.T) {
+		env := environment.NewWithValues("test", map[string]string{})
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Save", mock.Anything, env).Return(nil)
+
+		mockContext := mocks.NewMockContext(context.Background())
+		runHook(mockContext, content, nil)
+
+		runner := NewHooksRunner(
+			NewHooksManager(cwd), mockContext.CommandRunner, envManager, mockContext.Console, cwd, nil, env, nil)
+		err := runner.RunScript(*mockContext.Context, "predeploy", &HookConfig{
+			Shell: ShellTypeBash,
+			Run:   "echo 'Hello'",
+			Outputs: map[string]*HookOutput{
+				"DATABASE_PASSWORD": {Secret: true},
+			},
+		}, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, "db.contoso.com", env.Getenv("DATABASE_HOST"))
+		require.Equal(t, "P@ssw0rd", env.Getenv("DATABASE_PASSWORD"))
+		envManager.AssertCalled(t, "Save", mock.Anything, env)
+
+		// The values of secret outputs are masked
+		consoleOutput := strings.Join(mockContext.Console.Output(), "\n")
+		require.Contains(t, consoleOutput, "db.contoso.com")
+		require.Contains(t, consoleOutput, maskedOutputValue)
+		require.NotContains(t, consoleOutput, "P@ssw0rd")
+
+		// Secret outputs without a vault are stored in plain text
+		require.Contains(t, consoleOutput, "WARNING: The secret output 'DATABASE_PASSWORD' of hook 'predeploy'")
+	})
+
+	t.Run("KeyVault", func(t *testing.T) {
+		env := environment.NewWithValues("test", map[string]string{"AZURE_KEY_VAULT_NAME": "contoso-kv"})
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Save", mock.Anything, env).Return(nil)
+		keyVaultService := &fakeKeyVaultService{secrets: map[string]string{}}
+
+		mockContext := mocks.NewMockContext(context.Background())
+		runHook(mockContext, content, nil)
+
+		runner := NewHooksRunner(
+			NewHooksManager(cwd), mockContext.CommandRunner, envManager, mockContext.Console, cwd, nil, env, keyVaultService)
+		err := runner.RunScript(*mockContext.Context, "predeploy", &HookConfig{
+			Shell: ShellTypeBash,
+			Run:   "echo 'Hello'",
+			Outputs: map[string]*HookOutput{
+				"DATABASE_PASSWORD": {Vault: osutil.NewExpandableString("${AZURE_KEY_VAULT_NAME}")},
+			},
+		}, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]string{"contoso-kv/test-database-password": "P@ssw0rd"}, keyVaultService.secrets)
+		require.Equal(t, "akvs://contoso-kv/test-database-password", env.Getenv("DATABASE_PASSWORD"))
+		consoleOutput := strings.Join(mockContext.Console.Output(), "\n")
+		require.NotContains(t, consoleOutput, "P@ssw0rd")
+		require.NotContains(t, consoleOutput, "WARNING")
+	})
+
+	t.Run("KeyVaultSharedByEnvironments", func(t *testing.T) {
+		keyVaultService := &fakeKeyVaultService{secrets: map[string]string{}}
+		hookConfig := &HookConfig{
+			Shell: ShellTypeBash,
+			Run:   "echo 'Hello'",
+			Outputs: map[string]*HookOutput{
+				"DATABASE_PASSWORD": {Vault: osutil.NewExpandableString("contoso-kv")},
+			},
+		}
+
+		envs := map[string]string{"dev": "DevP@ssw0rd", "prod": "ProdP@ssw0rd"}
+		for envName, password := range envs {
+			env := environment.NewWithValues(envName, map[string]string{})
+			envManager := &mockenv.MockEnvManager{}
+			envManager.On("Save", mock.Anything, env).Return(nil)
+
+			mockContext := mocks.NewMockContext(context.Background())
+			runHook(mockContext, "DATABASE_PASSWORD="+password+"\n", nil)
+
+			runner := NewHooksRunner(
+				NewHooksManager(cwd), mockContext.CommandRunner, envManager, mockContext.Console, cwd, nil, env, keyVaultService)
+			err := runner.RunScript(*mockContext.Context, "predeploy", hookConfig, nil)
+			require.NoError(t, err)
+
+			require.Equal(t, "akvs://contoso-kv/"+envName+"-database-password", env.Getenv("DATABASE_PASSWORD"))
+		}
+
+		// Each environment stores its own secret
+		require.Equal(t, map[string]string{
+			"contoso-kv/dev-database-password":  "DevP@ssw0rd",
+			"contoso-kv/prod-database-password": "ProdP@ssw0rd",
+		}, keyVaultService.secrets)
+	})
+
+	t.Run("KeyVaultNotSupported", func(t *testing.T) {
+		env := environment.NewWithValues("test", map[string]string{})
+		mockContext := mocks.NewMockContext(context.Background())
+		runHook(mockContext, content, nil)
+
+		runner := NewHooksRunner(
+			NewHooksManager(cwd), mockContext.CommandRunner, &mockenv.MockEnvManager{}, mockContext.Console, cwd, nil, env, nil)
+		err := runner.RunScript(*mockContext.Context, "predeploy", &HookConfig{
+			Shell: ShellTypeBash,
+			Run:   "echo 'Hello'",
+			Outputs: map[string]*HookOutput{
+				"DATABASE_PASSWORD": {Vault: osutil.NewExpandableString("contoso-kv")},
+			},
+		}, nil)
+		require.ErrorContains(t, err, "storing output 'DATABASE_PASSWORD' of hook 'predeploy'")
+	})
+
+	t.Run("FailedHook", func(t *testing.T) {
+		env := environment.NewWithValues("test", map[string]string{})
+		mockContext := mocks.NewMockContext(context.Background())
+		runHook(mockContext, content, errors.New("exit code: 1"))
+
+		runner := NewHooksRunner(
+			NewHooksManager(cwd), mockContext.CommandRunner, &mockenv.MockEnvManager{}, mockContext.Console, cwd, nil, env, nil)
+		err := runner.RunScript(*mockContext.Context, "predeploy", &HookConfig{
+			Shell:           ShellTypeBash,
+			Run:             "echo 'Hello'",
+			ContinueOnError: true,
+		}, nil)
+		require.NoError(t, err)
+
+		// The outputs of failed hooks aren't merged
+		require.Empty(t, env.Getenv("DATABASE_HOST"))
+	})
+}
@@ -2,18 +2,27 @@ package ext
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bash"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/npm"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/powershell"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+	"github.com/sethvargo/go-retry"
+	"golang.org/x/exp/maps"
 )
 
 // Hooks enable support to invoke integration scripts before & after commands
@@ -26,6 +35,10 @@ type HooksRunner struct {
 	hooks         map[string]*HookConfig
 	env           *environment.Environment
 	envManager    environment.Manager
+	// Stores the secret outputs of hooks configured with a key vault, may be nil
+	keyVaultService keyvault.KeyVaultService
+	// The outputs of the hooks which ran with the output of the caller, displayed by DisplayOutputs
+	pendingOutputs []*hookOutputs
 }
 
 // NewHooks creates a new instance of CommandHooks
@@ -38,6 +51,7 @@ func NewHooksRunner(
 	cwd string,
 	hooks map[string]*HookConfig,
 	env *environment.Environment,
+	keyVaultService keyvault.KeyVaultService,
 ) *HooksRunner {
 	if cwd == "" {
 		osWd, err := os.Getwd()
@@ -49,13 +63,14 @@ func NewHooksRunner(
 	}
 
 	return &HooksRunner{
-		hooksManager:  hooksManager,
-		commandRunner: commandRunner,
-		envManager:    envManager,
-		console:       console,
-		cwd:           cwd,
-		hooks:         hooks,
-		env:           env,
+		hooksManager:    hooksManager,
+		commandRunner:   commandRunner,
+		envManager:      envManager,
+		console:         console,
+		cwd:             cwd,
+		hooks:           hooks,
+		env:             env,
+		keyVaultService: keyVaultService,
 	}
 }
 
@@ -109,32 +124,144 @@ func (h *HooksRunner) RunHooks(
 	return nil
 }
 
+// Runs the script of the specified hook configuration, which isn't bound to a command, like the scripts of workflow steps.
+// The environment isn't reloaded, so scripts can run concurrently.
+func (h *HooksRunner) RunScript(
+	ctx context.Context,
+	name string,
+	hookConfig *HookConfig,
+	options *tools.ExecOptions,
+) error {
+	hooks, err := h.hooksManager.GetAll(map[string]*HookConfig{name: hookConfig})
+	if err != nil {
+		return fmt.Errorf("failed running script '%s', %w", name, err)
+	}
+
+	for _, hookConfig := range hooks {
+		if err := h.execHook(ctx, hookConfig, options); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // Gets the script to execute based on the hook configuration values
 // For inline scripts this will also create a temporary script file to execute
-func (h *HooksRunner) GetScript(hookConfig *HookConfig) (tools.Script, error) {
+func (h *HooksRunner) GetScript(ctx context.Context, hookConfig *HookConfig) (tools.Script, error) {
+	return h.getScript(ctx, hookConfig, nil)
+}
+
+// Gets the script to execute, with the additional environment variables set for the script
+func (h *HooksRunner) getScript(
+	ctx context.Context,
+	hookConfig *HookConfig,
+	additionalEnvVars []string,
+) (tools.Script, error) {
 	if err := hookConfig.validate(); err != nil {
 		return nil, err
 	}
 
+	envVars, err := h.env.Environ(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The environment variables of the hook override the values of the environment
+	envNames := maps.Keys(hookConfig.Env)
+	slices.Sort(envNames)
+
+	for _, name := range envNames {
+		value, err := hookConfig.Env[name].Envsubst(h.env.Getenv)
+		if err != nil {
+			return nil, fmt.Errorf("resolving environment variable '%s' of hook '%s': %w", name, hookConfig.Name, err)
+		}
+
+		envVars = append(envVars, fmt.Sprintf("%s=%s", name, value))
+	}
+
+	envVars = append(envVars, additionalEnvVars...)
+	cwd := h.hookCwd(hookConfig)
+
 	switch hookConfig.Shell {
 	case ShellTypeBash:
-		return bash.NewBashScript(h.commandRunner, h.cwd, h.env.Environ()), nil
+		return bash.NewBashScript(h.commandRunner, cwd, envVars), nil
 	case ShellTypePowershell:
-		return powershell.NewPowershellScript(h.commandRunner, h.cwd, h.env.Environ()), nil
+		return powershell.NewPowershellScript(h.commandRunner, cwd, envVars), nil
+	case ShellTypePython:
+		return python.NewPythonScript(h.commandRunner, cwd, envVars), nil
+	case ShellTypeNode:
+		return npm.NewNodeScript(h.commandRunner, cwd, envVars), nil
+	case ShellTypeExecutable:
+		return tools.NewExecutableScript(h.commandRunner, cwd, envVars), nil
 	default:
 		return nil, fmt.Errorf(
-			"shell type '%s' is not a valid option. Only 'sh' and 'pwsh' are supported",
+			"shell type '%s' is not a valid option. Only 'sh', 'pwsh', 'python', 'node' and 'exec' are supported",
 			hookConfig.Shell,
 		)
 	}
 }
 
+// Gets the working directory of the hook, which defaults to the directory of the project or service
+func (h *HooksRunner) hookCwd(hookConfig *HookConfig) string {
+	if hookConfig.Cwd == "" {
+		return h.cwd
+	}
+
+	if filepath.IsAbs(hookConfig.Cwd) {
+		return hookConfig.Cwd
+	}
+
+	return filepath.Join(h.cwd, hookConfig.Cwd)
+}
+
+// Gets the path of the script to execute. Scripts of the project or service remain relative to the project or service
+// when the hook runs in another working directory.
+func (h *HooksRunner) scriptPath(hookConfig *HookConfig) string {
+	if hookConfig.Cwd == "" || hookConfig.location != ScriptLocationPath || filepath.IsAbs(hookConfig.path) {
+		return hookConfig.path
+	}
+
+	// Executables which aren't files of the project are resolved from the PATH
+	fullPath := filepath.Join(h.cwd, hookConfig.path)
+	if _, err := os.Stat(fullPath); err != nil {
+		return hookConfig.path
+	}
+
+	return fullPath
+}
+
 func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig, options *tools.ExecOptions) error {
 	if options == nil {
 		options = &tools.ExecOptions{}
 	}
 
-	script, err := h.GetScript(hookConfig)
+	if err := hookConfig.validate(); err != nil {
+		return err
+	}
+
+	run, err := workflow.EvaluateCondition(hookConfig.If, h.env.Dotenv())
+	if err != nil {
+		return fmt.Errorf("evaluating condition of hook '%s': %w", hookConfig.Name, err)
+	}
+
+	if !run {
+		log.Printf("skipping hook '%s', condition '%s' is false", hookConfig.Name, hookConfig.If)
+		h.console.Message(ctx, output.WithGrayFormat("Skipping '%s' hook since its condition is false.", hookConfig.Name))
+		return nil
+	}
+
+	// Hooks write their outputs to a file, merged into the environment once the hook has run successfully
+	outputFile, err := os.CreateTemp("", "azd-hook-output-*")
+	if err != nil {
+		return fmt.Errorf("creating hook output file: %w", err)
+	}
+	outputFile.Close()
+	defer os.Remove(outputFile.Name())
+
+	script, err := h.getScript(ctx, hookConfig, []string{
+		fmt.Sprintf("%s=%s", HookOutputEnvVarName, outputFile.Name()),
+	})
 	if err != nil {
 		return err
 	}
@@ -143,43 +270,108 @@ func (h *HooksRunner) execHook(ctx context.Context, hookConfig *HookConfig, opti
 	consoleInteractive := (formatter == nil || formatter.Kind() == output.NoneFormat)
 	scriptInteractive := consoleInteractive && hookConfig.Interactive
 
-	if options.Interactive == nil {
-		options.Interactive = &scriptInteractive
+	// The options of the caller are shared by the hooks it runs
+	execOptions := *options
+	execOptions.Args = hookConfig.Args
+
+	if execOptions.Interactive == nil {
+		execOptions.Interactive = &scriptInteractive
 	}
 
+	// The outputs of the hook are displayed once the hook has run, unless the hook runs with the output of the caller
+	displayOutputs := execOptions.StdOut == nil
+	previewerStarted := false
+
 	// When the hook is not configured to run in interactive mode and no stdout has been configured
 	// Then show the hook execution output within the console previewer pane
-	if !*options.Interactive && options.StdOut == nil {
+	if !*execOptions.Interactive && execOptions.StdOut == nil {
 		previewer := h.console.ShowPreviewer(ctx, &input.ShowPreviewerOptions{
 			Prefix:       "  ",
 			Title:        fmt.Sprintf("%s Hook Output", hookConfig.Name),
 			MaxLineCount: 8,
 		})
-		options.StdOut = previewer
-		defer h.console.StopPreviewer(ctx, false)
+		execOptions.StdOut = previewer
+		previewerStarted = true
 	}
 
-	log.Printf("Executing script '%s'\n", hookConfig.path)
-	res, err := script.Execute(ctx, hookConfig.path, *options)
-	if err != nil {
-		execErr := fmt.Errorf(
-			"'%s' hook failed with exit code: '%d', Path: '%s'. : %w",
-			hookConfig.Name,
-			res.ExitCode,
-			hookConfig.path,
-			err,
-		)
+	scriptPath := h.scriptPath(hookConfig)
+
+	var retryCount uint64
+	if hookConfig.Retry != nil {
+		retryCount = hookConfig.Retry.Count
+	}
+
+	attempt := uint64(0)
+	backoff := retry.WithMaxRetries(retryCount, retry.NewExponential(hookConfig.retryBackoff))
+	err = retry.Do(ctx, backoff, func(ctx context.Context) error {
+		if attempt > 0 {
+			log.Printf("retrying hook '%s' (%d/%d)", hookConfig.Name, attempt, retryCount)
+			h.console.Message(
+				ctx, output.WithWarningFormat("Retrying '%s' hook (%d/%d)", hookConfig.Name, attempt, retryCount))
+		}
+		attempt++
+
+		// The outputs of failed attempts are discarded
+		if err := os.Truncate(outputFile.Name(), 0); err != nil {
+			return fmt.Errorf("resetting hook output file: %w", err)
+		}
+
+		scriptCtx := ctx
+		if hookConfig.timeout > 0 {
+			var cancel context.CancelFunc
+			scriptCtx, cancel = context.WithTimeout(ctx, hookConfig.timeout)
+			defer cancel()
+		}
+
+		// The command runner kills the process tree of the script once the context is done
+		log.Printf("Executing script '%s'\n", scriptPath)
+		res, err := script.Execute(scriptCtx, scriptPath, execOptions)
+		if err != nil && errors.Is(scriptCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s: %w", hookConfig.timeout, err)
+		}
+
+		if err != nil {
+			return retry.RetryableError(fmt.Errorf(
+				"'%s' hook failed with exit code: '%d', Path: '%s'. : %w",
+				hookConfig.Name,
+				res.ExitCode,
+				scriptPath,
+				err,
+			))
+		}
 
+		return nil
+	})
+
+	// The previewer is stopped before displaying any warning or output of the hook
+	if previewerStarted {
+		h.console.StopPreviewer(ctx, false)
+	}
+
+	if err != nil {
 		// If an error occurred log the failure but continue
 		if hookConfig.ContinueOnError {
-			h.console.Message(ctx, output.WithBold(output.WithWarningFormat("WARNING: %s", execErr.Error())))
+			h.console.Message(ctx, output.WithBold(output.WithWarningFormat("WARNING: %s", err.Error())))
 			h.console.Message(
 				ctx,
 				output.WithWarningFormat("Execution will continue since ContinueOnError has been set to true."),
 			)
-			log.Println(execErr.Error())
+			log.Println(err.Error())
 		} else {
-			return execErr
+			return err
+		}
+	} else {
+		outputs, err := h.mergeOutputs(ctx, hookConfig, outputFile.Name())
+		if err != nil {
+			return err
+		}
+
+		if outputs != nil {
+			if displayOutputs {
+				h.displayOutputs(ctx, outputs)
+			} else {
+				h.pendingOutputs = append(h.pendingOutputs, outputs)
+			}
 		}
 	}
 
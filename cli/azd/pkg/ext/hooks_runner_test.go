@@ -2,14 +2,21 @@ package ext
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
 	"github.com/azure/azure-dev/cli/azd/test/ostest"
@@ -65,14 +72,16 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPreHook = true
 			require.Equal(t, "scripts/precommand.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			environ, err := env.Environ(*mockContext.Context)
+			require.NoError(t, err)
+			require.ElementsMatch(t, environ, args.Env)
 			require.Equal(t, false, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env, nil)
 		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "command")
 
 		require.True(t, ranPreHook)
@@ -91,14 +100,16 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPostHook = true
 			require.Equal(t, "scripts/postcommand.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			environ, err := env.Environ(*mockContext.Context)
+			require.NoError(t, err)
+			require.ElementsMatch(t, environ, args.Env)
 			require.Equal(t, false, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env, nil)
 		err := runner.RunHooks(*mockContext.Context, HookTypePost, nil, "command")
 
 		require.False(t, ranPreHook)
@@ -117,14 +128,16 @@ func Test_Hooks_Execute(t *testing.T) {
 			ranPostHook = true
 			require.Equal(t, "scripts/preinteractive.sh", args.Args[0])
 			require.Equal(t, cwd, args.Cwd)
-			require.ElementsMatch(t, env.Environ(), args.Env)
+			environ, err := env.Environ(*mockContext.Context)
+			require.NoError(t, err)
+			require.ElementsMatch(t, environ, args.Env)
 			require.Equal(t, true, args.Interactive)
 
 			return exec.NewRunResult(0, "", ""), nil
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env, nil)
 		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "interactive")
 
 		require.False(t, ranPreHook)
@@ -146,7 +159,7 @@ func Test_Hooks_Execute(t *testing.T) {
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env, nil)
 		err := runner.RunHooks(*mockContext.Context, HookTypePre, nil, "inline")
 
 		require.False(t, ranPreHook)
@@ -183,7 +196,7 @@ func Test_Hooks_Execute(t *testing.T) {
 		})
 
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env, nil)
 		err := runner.Invoke(*mockContext.Context, []string{"command"}, func() error {
 			ranAction = true
 			hookLog = append(hookLog, "action")
@@ -203,6 +216,34 @@ func Test_Hooks_Execute(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+
+	t.Run("RunScript", func(t *testing.T) {
+		ranScript := false
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "azd-step-")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ranScript = true
+			require.Equal(t, cwd, args.Cwd)
+			environ, err := env.Environ(*mockContext.Context)
+			require.NoError(t, err)
+			require.ElementsMatch(t, environ, args.Env)
+			require.Equal(t, false, args.Interactive)
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		hooksManager := NewHooksManager(cwd)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, nil, env, nil)
+		err := runner.RunScript(*mockContext.Context, "step", &HookConfig{
+			Shell: ShellTypeBash,
+			Run:   "echo 'Hello'",
+		}, &tools.ExecOptions{StdOut: io.Discard})
+
+		require.True(t, ranScript)
+		require.NoError(t, err)
+	})
 }
 
 func Test_Hooks_GetScript(t *testing.T) {
@@ -242,9 +283,9 @@ func Test_Hooks_GetScript(t *testing.T) {
 		hookConfig := hooks["bash"]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env, nil)
 
-		script, err := runner.GetScript(hookConfig)
+		script, err := runner.GetScript(*mockContext.Context, hookConfig)
 		require.NotNil(t, script)
 		require.Equal(t, "*bash.bashScript", reflect.TypeOf(script).String())
 		require.Equal(t, ScriptLocationPath, hookConfig.location)
@@ -256,9 +297,9 @@ func Test_Hooks_GetScript(t *testing.T) {
 		hookConfig := hooks["pwsh"]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env, nil)
 
-		script, err := runner.GetScript(hookConfig)
+		script, err := runner.GetScript(*mockContext.Context, hookConfig)
 		require.NotNil(t, script)
 		require.Equal(t, "*powershell.powershellScript", reflect.TypeOf(script).String())
 		require.Equal(t, ScriptLocationPath, hookConfig.location)
@@ -273,9 +314,9 @@ func Test_Hooks_GetScript(t *testing.T) {
 		hookConfig := hooks["inline"]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env, nil)
 
-		script, err := runner.GetScript(hookConfig)
+		script, err := runner.GetScript(*mockContext.Context, hookConfig)
 		require.NotNil(t, script)
 		require.Equal(t, "*bash.bashScript", reflect.TypeOf(script).String())
 		require.Equal(t, ScriptLocationInline, hookConfig.location)
@@ -296,9 +337,9 @@ func Test_Hooks_GetScript(t *testing.T) {
 		hookConfig := hooks["inlineWithUrl"]
 		mockContext := mocks.NewMockContext(context.Background())
 		hooksManager := NewHooksManager(cwd)
-		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env)
+		runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, hooks, env, nil)
 
-		script, err := runner.GetScript(hookConfig)
+		script, err := runner.GetScript(*mockContext.Context, hookConfig)
 		require.NotNil(t, script)
 		require.Equal(t, "*powershell.powershellScript", reflect.TypeOf(script).String())
 		require.Equal(t, ScriptLocationInline, hookConfig.location)
@@ -346,6 +387,7 @@ func Test_GetScript_Validation(t *testing.T) {
 		tempDir,
 		map[string]*HookConfig{},
 		env,
+		nil,
 	)
 
 	scriptValidations := []scriptValidationTest{
@@ -390,6 +432,44 @@ func Test_GetScript_Validation(t *testing.T) {
 				Run:   "echo 'Hello'",
 			},
 		},
+		{
+			name: "Valid Inline Python",
+			config: &HookConfig{
+				Name:  "test6",
+				Shell: ShellTypePython,
+				Run:   "print('Hello')",
+			},
+		},
+		{
+			name: "Args Not Supported",
+			config: &HookConfig{
+				Name:  "test7",
+				Shell: ShellTypeBash,
+				Run:   "echo 'Hello'",
+				Args:  []string{"--verbose"},
+			},
+			expectedError: ErrArgsNotSupported,
+		},
+		{
+			name: "Invalid Timeout",
+			config: &HookConfig{
+				Name:    "test8",
+				Shell:   ShellTypeBash,
+				Run:     "echo 'Hello'",
+				Timeout: "10",
+			},
+			expectedError: ErrInvalidDuration,
+		},
+		{
+			name: "Invalid Retry Backoff",
+			config: &HookConfig{
+				Name:  "test9",
+				Shell: ShellTypeBash,
+				Run:   "echo 'Hello'",
+				Retry: &HookRetry{Count: 3, Backoff: "-5s"},
+			},
+			expectedError: ErrInvalidDuration,
+		},
 	}
 
 	for _, test := range scriptValidations {
@@ -398,7 +478,7 @@ func Test_GetScript_Validation(t *testing.T) {
 		}
 
 		t.Run(test.name, func(t *testing.T) {
-			res, err := runner.GetScript(test.config)
+			res, err := runner.GetScript(*mockContext.Context, test.config)
 			if test.expectedError != nil {
 				require.Nil(t, res)
 				require.ErrorIs(t, err, test.expectedError)
@@ -409,3 +489,205 @@ func Test_GetScript_Validation(t *testing.T) {
 		})
 	}
 }
+
+func Test_Hooks_Execute_Runtimes(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	env := environment.NewWithValues("test", map[string]string{"a": "apple"})
+
+	hooks := map[string]*HookConfig{
+		"python": {
+			Run:  "hooks/seed.py",
+			Args: []string{"--count", "3"},
+		},
+		"javascript": {
+			Run: "hooks/seed.js",
+		},
+		"typescript": {
+			Run:  "hooks/seed.ts",
+			Args: []string{"--dry-run"},
+		},
+		"executable": {
+			Shell: ShellTypeExecutable,
+			Run:   "hooks/seed",
+			Args:  []string{"--count", "3"},
+		},
+		"command": {
+			Shell: ShellTypeExecutable,
+			Run:   "terraform",
+			Args:  []string{"fmt", "-check"},
+		},
+	}
+
+	ensureScriptsExist(t, hooks)
+	require.NoError(t, os.WriteFile(filepath.Join("hooks", "seed"), nil, osutil.PermissionExecutableFile))
+
+	// TypeScript scripts run with the tsx installed in the project
+	tsxName := "tsx"
+	if runtime.GOOS == "windows" {
+		tsxName = "tsx.cmd"
+	}
+	tsxPath := filepath.Join(cwd, "node_modules", ".bin", tsxName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(tsxPath), osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(tsxPath, nil, osutil.PermissionExecutableFile))
+
+	envManager := &mockenv.MockEnvManager{}
+
+	tests := []struct {
+		name         string
+		expectedType string
+		expectedCmd  string
+		expectedArgs []string
+	}{
+		{"python", "*python.pythonScript", "", []string{filepath.Join("hooks", "seed.py"), "--count", "3"}},
+		{"javascript", "*npm.nodeScript", "node", []string{filepath.Join("hooks", "seed.js")}},
+		{"typescript", "*npm.nodeScript", tsxPath, []string{filepath.Join("hooks", "seed.ts"), "--dry-run"}},
+		{"executable", "*tools.executableScript", "." + string(os.PathSeparator) + filepath.Join("hooks", "seed"),
+			[]string{"--count", "3"}},
+		{"command", "*tools.executableScript", "terraform", []string{"fmt", "-check"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hookConfig := hooks[test.name]
+			ranHook := false
+
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return true
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				ranHook = true
+
+				// The python interpreter depends on the platform
+				if test.expectedCmd != "" {
+					require.Equal(t, test.expectedCmd, args.Cmd)
+				}
+				require.Equal(t, test.expectedArgs, args.Args)
+				require.Equal(t, cwd, args.Cwd)
+				require.Contains(t, args.Env, "a=apple")
+
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+			hooksManager := NewHooksManager(cwd)
+			runner := NewHooksRunner(hooksManager, mockContext.CommandRunner, envManager, mockContext.Console, cwd, nil, env, nil)
+
+			script, err := runner.GetScript(*mockContext.Context, hookConfig)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedType, reflect.TypeOf(script).String())
+
+			err = runner.RunScript(*mockContext.Context, test.name, hookConfig, &tools.ExecOptions{StdOut: io.Discard})
+			require.NoError(t, err)
+			require.True(t, ranHook)
+		})
+	}
+}
+
+func Test_Hooks_Execute_Options(t *testing.T) {
+	cwd := t.TempDir()
+	ostest.Chdir(t, cwd)
+
+	env := environment.NewWithValues("test", map[string]string{
+		"AZURE_ENV_NAME": "dev",
+		"SEED_DATABASE":  "false",
+	})
+
+	hooks := map[string]*HookConfig{
+		"seed": {
+			Run: "scripts/seed.sh",
+		},
+	}
+
+	ensureScriptsExist(t, hooks)
+	require.NoError(t, os.MkdirAll("src", osutil.PermissionDirectory))
+
+	envManager := &mockenv.MockEnvManager{}
+
+	// Runs the hook, failing the first attempts of the hook
+	runHook := func(hookConfig *HookConfig, failures int, delay time.Duration) ([]exec.RunArgs, error) {
+		runs := []exec.RunArgs{}
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return true
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			runs = append(runs, args)
+			time.Sleep(delay)
+
+			if len(runs) <= failures {
+				return exec.NewRunResult(1, "", ""), errors.New("exit code: 1")
+			}
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		runner := NewHooksRunner(
+			NewHooksManager(cwd), mockContext.CommandRunner, envManager, mockContext.Console, cwd, nil, env, nil)
+		err := runner.RunScript(*mockContext.Context, "seed", hookConfig, &tools.ExecOptions{StdOut: io.Discard})
+
+		return runs, err
+	}
+
+	t.Run("Condition", func(t *testing.T) {
+		runs, err := runHook(&HookConfig{
+			Run: "scripts/seed.sh",
+			If:  "${SEED_DATABASE} || ${AZURE_ENV_NAME} == 'test'",
+		}, 0, 0)
+		require.NoError(t, err)
+		require.Empty(t, runs)
+
+		runs, err = runHook(&HookConfig{
+			Run: "scripts/seed.sh",
+			If:  "!${SEED_DATABASE} && ${AZURE_ENV_NAME} == 'dev'",
+		}, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+	})
+
+	t.Run("EnvAndCwd", func(t *testing.T) {
+		runs, err := runHook(&HookConfig{
+			Run: "scripts/seed.sh",
+			Env: map[string]osutil.ExpandableString{
+				"DATABASE_NAME": osutil.NewExpandableString("${AZURE_ENV_NAME}-db"),
+				"SEED_DATABASE": osutil.NewExpandableString("true"),
+			},
+			Cwd: "src",
+		}, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, runs, 1)
+
+		require.Equal(t, filepath.Join(cwd, "src"), runs[0].Cwd)
+		require.Contains(t, runs[0].Env, "DATABASE_NAME=dev-db")
+
+		// The variables of the hook override the values of the environment
+		require.Greater(t, slices.Index(runs[0].Env, "SEED_DATABASE=true"), slices.Index(runs[0].Env, "SEED_DATABASE=false"))
+
+		// The script remains relative to the project
+		require.Contains(t, runs[0].Args, filepath.ToSlash(filepath.Join(cwd, "scripts", "seed.sh")))
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		runs, err := runHook(&HookConfig{
+			Run:   "scripts/seed.sh",
+			Retry: &HookRetry{Count: 2, Backoff: "1ms"},
+		}, 2, 0)
+		require.NoError(t, err)
+		require.Len(t, runs, 3)
+
+		runs, err = runHook(&HookConfig{
+			Run:   "scripts/seed.sh",
+			Retry: &HookRetry{Count: 1, Backoff: "1ms"},
+		}, 2, 0)
+		require.ErrorContains(t, err, "'seed' hook failed with exit code: '1'")
+		require.Len(t, runs, 2)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		_, err := runHook(&HookConfig{
+			Run:     "scripts/seed.sh",
+			Timeout: "10ms",
+		}, 1, 50*time.Millisecond)
+		require.ErrorContains(t, err, "timed out after 10ms")
+	})
+}
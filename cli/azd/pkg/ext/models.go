@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 )
@@ -19,6 +20,9 @@ type ScriptLocation string
 const (
 	ShellTypeBash         ShellType      = "sh"
 	ShellTypePowershell   ShellType      = "pwsh"
+	ShellTypePython       ShellType      = "python"
+	ShellTypeNode         ShellType      = "node"
+	ShellTypeExecutable   ShellType      = "exec"
 	ScriptTypeUnknown     ShellType      = ""
 	ScriptLocationInline  ScriptLocation = "inline"
 	ScriptLocationPath    ScriptLocation = "path"
@@ -37,7 +41,12 @@ var (
 		"unable to determine script type. Ensure 'Shell' parameter is set in configuration options",
 	)
 	ErrRunRequired           error = errors.New("run is always required")
-	ErrUnsupportedScriptType error = errors.New("script type is not valid. Only '.sh' and '.ps1' are supported")
+	ErrUnsupportedScriptType error = errors.New(
+		"script type is not valid. Only '.sh', '.ps1', '.py', '.js' and '.ts' are supported, " +
+			"set 'shell' to 'exec' to run executables",
+	)
+	ErrArgsNotSupported error = errors.New("args are only supported by 'python', 'node' and 'exec' hooks")
+	ErrInvalidDuration  error = errors.New("durations are decimal numbers with a unit, such as '30s' or '10m'")
 )
 
 // Generic action function that may return an error
@@ -55,23 +64,55 @@ type HookConfig struct {
 	cwd string
 	// When location is `inline` a script must be defined inline
 	script string
+	// The parsed timeout of the hook, zero when the hook has no timeout
+	timeout time.Duration
+	// The parsed delay before the first retry of the hook
+	retryBackoff time.Duration
 
 	// Internal name of the hook running for a given command
 	Name string `yaml:",omitempty"`
-	// The type of script hook (bash or powershell)
+	// The type of script hook (sh, pwsh, python, node or exec)
 	Shell ShellType `yaml:"shell,omitempty"`
-	// The inline script to execute or path to existing file
+	// The inline script to execute or path to existing file. For `exec` hooks, the path or the name of the executable.
 	Run string `yaml:"run,omitempty"`
+	// The arguments passed to the script or the executable. Not supported by `sh` and `pwsh` hooks.
+	Args []string `yaml:"args,omitempty"`
 	// When set to true will not halt command execution even when a script error occurs.
 	ContinueOnError bool `yaml:"continueOnError,omitempty"`
 	// When set to true will bind the stdin, stdout & stderr to the running console
 	Interactive bool `yaml:"interactive,omitempty"`
+	// The maximum duration of the hook, such as '10m'. The process tree of the hook is killed once the duration elapses.
+	Timeout string `yaml:"timeout,omitempty"`
+	// Retries the hook when it fails or times out
+	Retry *HookRetry `yaml:"retry,omitempty"`
+	// Additional environment variables set for the hook, which support environment variable substitution
+	Env map[string]osutil.ExpandableString `yaml:"env,omitempty"`
+	// The working directory of the hook, relative to the project or service. Script paths remain relative to the project
+	// or service.
+	Cwd string `yaml:"cwd,omitempty"`
+	// A condition on environment values, the hook is skipped when the condition is false.
+	// (Example: ${AZURE_ENV_TYPE} == 'prod' && !${SKIP_SEED})
+	If string `yaml:"if,omitempty"`
+	// Configures the outputs the hook writes to the file at the path of the AZD_HOOK_OUTPUT environment variable.
+	// Outputs are merged into the environment once the hook has run, including outputs which aren't configured.
+	Outputs map[string]*HookOutput `yaml:"outputs,omitempty"`
 	// When running on windows use this override config
 	Windows *HookConfig `yaml:"windows,omitempty"`
 	// When running on linux/macos use this override config
 	Posix *HookConfig `yaml:"posix,omitempty"`
 }
 
+// Configures the retries of a hook which failed
+type HookRetry struct {
+	// The maximum number of times the hook is retried
+	Count uint64 `yaml:"count,omitempty"`
+	// The delay before the first retry, such as '10s', doubled before each next retry. (Default: 5s)
+	Backoff string `yaml:"backoff,omitempty"`
+}
+
+// The delay before the first retry of a hook, when the backoff isn't configured
+const defaultHookRetryBackoff = 5 * time.Second
+
 // Validates and normalizes the hook configuration
 func (hc *HookConfig) validate() error {
 	if hc.validated {
@@ -82,6 +123,25 @@ func (hc *HookConfig) validate() error {
 		return ErrRunRequired
 	}
 
+	if hc.Timeout != "" {
+		timeout, err := time.ParseDuration(hc.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("timeout '%s' is not valid. %w", hc.Timeout, ErrInvalidDuration)
+		}
+
+		hc.timeout = timeout
+	}
+
+	hc.retryBackoff = defaultHookRetryBackoff
+	if hc.Retry != nil && hc.Retry.Backoff != "" {
+		backoff, err := time.ParseDuration(hc.Retry.Backoff)
+		if err != nil || backoff <= 0 {
+			return fmt.Errorf("retry backoff '%s' is not valid. %w", hc.Retry.Backoff, ErrInvalidDuration)
+		}
+
+		hc.retryBackoff = backoff
+	}
+
 	relativeCheckPath := strings.ReplaceAll(hc.Run, "/", string(os.PathSeparator))
 	fullCheckPath := relativeCheckPath
 	if hc.cwd != "" {
@@ -92,6 +152,15 @@ func (hc *HookConfig) validate() error {
 	if err == nil && !stats.IsDir() {
 		hc.location = ScriptLocationPath
 		hc.path = relativeCheckPath
+
+		// Executables of the project run from their path rather than being resolved from the PATH
+		if hc.Shell == ShellTypeExecutable && !filepath.IsAbs(hc.path) {
+			hc.path = "." + string(os.PathSeparator) + filepath.Clean(hc.path)
+		}
+	} else if hc.Shell == ShellTypeExecutable {
+		// Executables which aren't files of the project are resolved from the PATH
+		hc.location = ScriptLocationPath
+		hc.path = hc.Run
 	} else {
 		hc.location = ScriptLocationInline
 		hc.script = hc.Run
@@ -127,6 +196,10 @@ func (hc *HookConfig) validate() error {
 		hc.Shell = scriptType
 	}
 
+	if len(hc.Args) > 0 && (hc.Shell == ShellTypeBash || hc.Shell == ShellTypePowershell) {
+		return ErrArgsNotSupported
+	}
+
 	hc.validated = true
 
 	return nil
@@ -152,6 +225,10 @@ func inferScriptTypeFromFilePath(path string) (ShellType, error) {
 		return ShellTypeBash, nil
 	case ".ps1":
 		return ShellTypePowershell, nil
+	case ".py":
+		return ShellTypePython, nil
+	case ".js", ".mjs", ".cjs", ".ts", ".mts":
+		return ShellTypeNode, nil
 	default:
 		return "", fmt.Errorf(
 			"script with file extension '%s' is not valid. %w.",
@@ -165,6 +242,7 @@ func createTempScript(hookConfig *HookConfig) (string, error) {
 	var ext string
 	scriptHeader := []string{}
 	scriptFooter := []string{}
+	commentPrefix := "#"
 
 	switch hookConfig.Shell {
 	case ShellTypeBash:
@@ -181,6 +259,11 @@ func createTempScript(hookConfig *HookConfig) (string, error) {
 		scriptFooter = []string{
 			"if ((Test-Path -LiteralPath variable:\\LASTEXITCODE)) { exit $LASTEXITCODE }",
 		}
+	case ShellTypePython:
+		ext = "py"
+	case ShellTypeNode:
+		ext = "js"
+		commentPrefix = "//"
 	}
 
 	// Write the temporary script file to OS temp dir
@@ -197,7 +280,7 @@ func createTempScript(hookConfig *HookConfig) (string, error) {
 	}
 
 	scriptBuilder.WriteString("\n")
-	scriptBuilder.WriteString("# Auto generated file from Azure Developer CLI\n")
+	scriptBuilder.WriteString(fmt.Sprintf("%s Auto generated file from Azure Developer CLI\n", commentPrefix))
 	scriptBuilder.WriteString(hookConfig.script)
 	scriptBuilder.WriteString("\n")
 
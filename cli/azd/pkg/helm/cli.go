@@ -5,12 +5,36 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 )
 
+// The helm plugin which diffs releases
+const diffPluginUrl = "https://github.com/databus23/helm-diff"
+
+// UpgradeOptions are the values passed to helm when upgrading a release, once rendered
+type UpgradeOptions struct {
+	// The paths of the values files, in order of precedence
+	ValuesFiles []string
+	// The values set on the command line, as `<key>=<value>`
+	Set []string
+	// The values of Set resolved from the environment, which may be secrets and are redacted from the logs
+	SensitiveValues []string
+}
+
+// sensitiveData returns the values of the options redacted from the logs
+func (o *UpgradeOptions) sensitiveData() []string {
+	if o == nil {
+		return nil
+	}
+
+	return o.SensitiveValues
+}
+
 type Cli struct {
 	commandRunner exec.CommandRunner
 }
@@ -88,15 +112,17 @@ func (c *Cli) Install(ctx context.Context, release *Release) error {
 
 // Upgrade upgrades a helm release to the specified version
 // If the release did not previously exist, it will be installed
-func (c *Cli) Upgrade(ctx context.Context, release *Release) error {
-	runArgs := exec.NewRunArgs("helm", "upgrade", release.Name, release.Chart, "--install", "--wait")
+func (c *Cli) Upgrade(ctx context.Context, release *Release, options *UpgradeOptions) error {
+	runArgs := exec.NewRunArgsWithSensitiveData(
+		"helm",
+		[]string{"upgrade", release.Name, release.Chart, "--install", "--wait"},
+		options.sensitiveData(),
+	)
 	if release.Version != "" {
 		runArgs = runArgs.AppendParams("--version", release.Version)
 	}
 
-	if release.Values != "" {
-		runArgs = runArgs.AppendParams("--values", release.Values)
-	}
+	runArgs = appendValues(runArgs, release, options)
 
 	if release.Namespace != "" {
 		runArgs = runArgs.AppendParams(
@@ -113,6 +139,77 @@ func (c *Cli) Upgrade(ctx context.Context, release *Release) error {
 	return nil
 }
 
+// Diff returns the changes upgrading the helm release would make, without upgrading it.
+// Requires the helm-diff plugin.
+func (c *Cli) Diff(ctx context.Context, release *Release, options *UpgradeOptions) (string, error) {
+	runArgs := exec.NewRunArgsWithSensitiveData(
+		"helm",
+		[]string{"diff", "upgrade", release.Name, release.Chart, "--allow-unreleased"},
+		options.sensitiveData(),
+	)
+	if release.Version != "" {
+		runArgs = runArgs.AppendParams("--version", release.Version)
+	}
+
+	runArgs = appendValues(runArgs, release, options)
+
+	if release.Namespace != "" {
+		runArgs = runArgs.AppendParams("--namespace", release.Namespace)
+	}
+
+	runResult, err := c.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		if strings.Contains(runResult.Stderr, "unknown command \"diff\"") {
+			return "", fmt.Errorf(
+				"previewing helm releases requires the helm-diff plugin. Install it with 'helm plugin install %s': %w",
+				diffPluginUrl,
+				err,
+			)
+		}
+
+		return "", fmt.Errorf("failed to diff helm chart %s: %w", release.Chart, err)
+	}
+
+	return runResult.Stdout, nil
+}
+
+// Rollback rolls back a helm release to the specified revision
+func (c *Cli) Rollback(ctx context.Context, release *Release, revision int) error {
+	runArgs := exec.NewRunArgs("helm", "rollback", release.Name, strconv.Itoa(revision), "--wait")
+	if release.Namespace != "" {
+		runArgs = runArgs.AppendParams("--namespace", release.Namespace)
+	}
+
+	_, err := c.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to roll back helm release %s to revision %d: %w", release.Name, revision, err)
+	}
+
+	return nil
+}
+
+// appendValues appends the values of the release to the arguments. The rendered values of the options are used instead of
+// the values file of the release when set.
+func appendValues(runArgs exec.RunArgs, release *Release, options *UpgradeOptions) exec.RunArgs {
+	if options == nil {
+		if release.Values != "" {
+			runArgs = runArgs.AppendParams("--values", release.Values)
+		}
+
+		return runArgs
+	}
+
+	for _, valuesFile := range options.ValuesFiles {
+		runArgs = runArgs.AppendParams("--values", valuesFile)
+	}
+
+	for _, value := range options.Set {
+		runArgs = runArgs.AppendParams("--set", value)
+	}
+
+	return runArgs
+}
+
 // Status returns the status of a helm release
 func (c *Cli) Status(ctx context.Context, release *Release) (*StatusResult, error) {
 	runArgs := exec.NewRunArgs("helm", "status", release.Name, "--output", "json")
@@ -164,4 +261,6 @@ type StatusKind string
 const (
 	// StatusKindDeployed is the status of a helm release that has been deployed
 	StatusKindDeployed StatusKind = "deployed"
+	// StatusKindFailed is the status of a helm release whose last install or upgrade failed
+	StatusKindFailed StatusKind = "failed"
 )
@@ -231,7 +231,7 @@ func Test_Cli_Upgrade(t *testing.T) {
 			})
 
 		cli := NewCli(mockContext.CommandRunner)
-		err := cli.Upgrade(*mockContext.Context, release)
+		err := cli.Upgrade(*mockContext.Context, release, nil)
 		require.True(t, ran)
 		require.NoError(t, err)
 
@@ -264,7 +264,7 @@ func Test_Cli_Upgrade(t *testing.T) {
 			})
 
 		cli := NewCli(mockContext.CommandRunner)
-		err := cli.Upgrade(*mockContext.Context, &releaseWithValues)
+		err := cli.Upgrade(*mockContext.Context, &releaseWithValues, nil)
 		require.True(t, ran)
 		require.NoError(t, err)
 
@@ -280,6 +280,54 @@ func Test_Cli_Upgrade(t *testing.T) {
 		}, runArgs.Args)
 	})
 
+	t.Run("WithUpgradeOptions", func(t *testing.T) {
+		ran := false
+		var runArgs exec.RunArgs
+
+		// The rendered values files are used instead of the values file of the release
+		releaseWithValues := *release
+		releaseWithValues.Values = "values.yaml"
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm upgrade")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				ran = true
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Upgrade(*mockContext.Context, &releaseWithValues, &UpgradeOptions{
+			ValuesFiles:     []string{"rendered/values.yaml", "rendered/values.dev.yaml"},
+			Set:             []string{"image.tag=v1", "replicas=2", "db.password=P@ssw0rd"},
+			SensitiveValues: []string{"P@ssw0rd"},
+		})
+		require.True(t, ran)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{
+			"upgrade",
+			"test",
+			"test/chart",
+			"--install",
+			"--wait",
+			"--values",
+			"rendered/values.yaml",
+			"--values",
+			"rendered/values.dev.yaml",
+			"--set",
+			"image.tag=v1",
+			"--set",
+			"replicas=2",
+			"--set",
+			"db.password=P@ssw0rd",
+		}, runArgs.Args)
+		require.Equal(t, []string{"P@ssw0rd"}, runArgs.SensitiveData)
+	})
+
 	t.Run("WithVersion", func(t *testing.T) {
 		ran := false
 		var runArgs exec.RunArgs
@@ -299,7 +347,7 @@ func Test_Cli_Upgrade(t *testing.T) {
 			})
 
 		cli := NewCli(mockContext.CommandRunner)
-		err := cli.Upgrade(*mockContext.Context, &releaseWithVersion)
+		err := cli.Upgrade(*mockContext.Context, &releaseWithVersion, nil)
 		require.True(t, ran)
 		require.NoError(t, err)
 
@@ -334,7 +382,7 @@ func Test_Cli_Upgrade(t *testing.T) {
 			})
 
 		cli := NewCli(mockContext.CommandRunner)
-		err := cli.Upgrade(*mockContext.Context, &releaseWithNamespace)
+		err := cli.Upgrade(*mockContext.Context, &releaseWithNamespace, nil)
 		require.True(t, ran)
 		require.NoError(t, err)
 
@@ -365,7 +413,7 @@ func Test_Cli_Upgrade(t *testing.T) {
 			})
 
 		cli := NewCli(mockContext.CommandRunner)
-		err := cli.Upgrade(*mockContext.Context, release)
+		err := cli.Upgrade(*mockContext.Context, release, nil)
 
 		require.True(t, ran)
 		require.Error(t, err)
@@ -472,3 +520,117 @@ func Test_Cli_Status(t *testing.T) {
 		require.ErrorContains(t, err, "failed to get status")
 	})
 }
+
+func Test_Cli_Diff(t *testing.T) {
+	release := &Release{
+		Name:      "test",
+		Chart:     "test/chart",
+		Version:   "1.0.0",
+		Namespace: "test-namespace",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var runArgs exec.RunArgs
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm diff upgrade")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				runArgs = args
+				return exec.NewRunResult(0, "default, test, Deployment (apps) has changed:", ""), nil
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		diff, err := cli.Diff(*mockContext.Context, release, &UpgradeOptions{
+			ValuesFiles: []string{"rendered/values.yaml"},
+			Set:         []string{"image.tag=v1"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "default, test, Deployment (apps) has changed:", diff)
+
+		require.Equal(t, "helm", runArgs.Cmd)
+		require.Equal(t, []string{
+			"diff",
+			"upgrade",
+			"test",
+			"test/chart",
+			"--allow-unreleased",
+			"--version",
+			"1.0.0",
+			"--values",
+			"rendered/values.yaml",
+			"--set",
+			"image.tag=v1",
+			"--namespace",
+			"test-namespace",
+		}, runArgs.Args)
+	})
+
+	t.Run("MissingPlugin", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm diff upgrade")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(1, "", `Error: unknown command "diff" for "helm"`), errors.New("exit code: 1")
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		_, err := cli.Diff(*mockContext.Context, release, nil)
+		require.ErrorContains(t, err, "helm plugin install https://github.com/databus23/helm-diff")
+	})
+}
+
+func Test_Cli_Rollback(t *testing.T) {
+	release := &Release{
+		Name:      "test",
+		Chart:     "test/chart",
+		Namespace: "test-namespace",
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		var runArgs exec.RunArgs
+
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm rollback")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				runArgs = args
+				return exec.NewRunResult(0, "", ""), nil
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Rollback(*mockContext.Context, release, 3)
+		require.NoError(t, err)
+
+		require.Equal(t, "helm", runArgs.Cmd)
+		require.Equal(t, []string{
+			"rollback",
+			"test",
+			"3",
+			"--wait",
+			"--namespace",
+			"test-namespace",
+		}, runArgs.Args)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		mockContext.CommandRunner.
+			When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "helm rollback")
+			}).
+			RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				return exec.NewRunResult(1, "", ""), errors.New("release has no 3 version")
+			})
+
+		cli := NewCli(mockContext.CommandRunner)
+		err := cli.Rollback(*mockContext.Context, release, 3)
+		require.ErrorContains(t, err, "failed to roll back helm release test to revision 3")
+	})
+}
@@ -1,5 +1,7 @@
 package helm
 
+import "github.com/azure/azure-dev/cli/azd/pkg/osutil"
+
 type Config struct {
 	Repositories []*Repository `yaml:"repositories"`
 	Releases     []*Release    `yaml:"releases"`
@@ -16,4 +18,8 @@ type Release struct {
 	Version   string `yaml:"version"`
 	Namespace string `yaml:"namespace"`
 	Values    string `yaml:"values"`
+	// Additional values files, which take precedence over the values file in order
+	ValuesFiles []string `yaml:"valuesFiles"`
+	// Values set on the command line, which take precedence over the values files
+	Set map[string]osutil.ExpandableString `yaml:"set"`
 }
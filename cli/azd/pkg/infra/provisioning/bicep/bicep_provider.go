@@ -605,6 +605,34 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*DeployResult, error) {
 		logDS(err.Error())
 	}
 
+	deploymentTags := map[string]*string{
+		azure.TagKeyAzdEnvName: to.Ptr(p.env.Name()),
+	}
+	if parametersHashErr == nil {
+		deploymentTags[azure.TagKeyAzdDeploymentStateParamHashName] = to.Ptr(currentParamsHash)
+	}
+	deployResult, err := p.applyDeployment(ctx, bicepDeploymentData, deploymentTags)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment.Outputs = p.createOutputParameters(
+		bicepDeploymentData.CompiledBicep.Template.Outputs,
+		azapi.CreateDeploymentOutput(deployResult.Properties.Outputs),
+	)
+
+	return &DeployResult{
+		Deployment: deployment,
+	}, nil
+}
+
+// applyDeployment starts the deployment of the compiled template to the target scope and reports the progress of the
+// deployment until it completes.
+func (p *BicepProvider) applyDeployment(
+	ctx context.Context,
+	bicepDeploymentData *deploymentDetails,
+	deploymentTags map[string]*string,
+) (*armresources.DeploymentExtended, error) {
 	cancelProgress := make(chan bool)
 	defer func() { cancelProgress <- true }()
 	go func() {
@@ -643,31 +671,13 @@ func (p *BicepProvider) Deploy(ctx context.Context) (*DeployResult, error) {
 	// Start the deployment
 	p.console.ShowSpinner(ctx, "Creating/Updating resources", input.Step)
 
-	deploymentTags := map[string]*string{
-		azure.TagKeyAzdEnvName: to.Ptr(p.env.Name()),
-	}
-	if parametersHashErr == nil {
-		deploymentTags[azure.TagKeyAzdDeploymentStateParamHashName] = to.Ptr(currentParamsHash)
-	}
-	deployResult, err := p.deployModule(
+	return p.deployModule(
 		ctx,
 		bicepDeploymentData.Target,
 		bicepDeploymentData.CompiledBicep.RawArmTemplate,
 		bicepDeploymentData.CompiledBicep.Parameters,
 		deploymentTags,
 	)
-	if err != nil {
-		return nil, err
-	}
-
-	deployment.Outputs = p.createOutputParameters(
-		bicepDeploymentData.CompiledBicep.Template.Outputs,
-		azapi.CreateDeploymentOutput(deployResult.Properties.Outputs),
-	)
-
-	return &DeployResult{
-		Deployment: deployment,
-	}, nil
 }
 
 // Preview runs deploy using the what-if argument
@@ -677,6 +687,21 @@ func (p *BicepProvider) Preview(ctx context.Context) (*DeployPreviewResult, erro
 		return nil, err
 	}
 
+	preview, err := p.previewDeployment(ctx, bicepDeploymentData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeployPreviewResult{
+		Preview: preview,
+	}, nil
+}
+
+// previewDeployment runs a what-if deployment of the compiled template against the target scope.
+func (p *BicepProvider) previewDeployment(
+	ctx context.Context,
+	bicepDeploymentData *deploymentDetails,
+) (*DeploymentPreview, error) {
 	p.console.ShowSpinner(ctx, "Generating infrastructure preview", input.Step)
 
 	targetScope := bicepDeploymentData.Target
@@ -726,16 +751,124 @@ func (p *BicepProvider) Preview(ctx context.Context) (*DeployPreviewResult, erro
 		})
 	}
 
-	return &DeployPreviewResult{
-		Preview: &DeploymentPreview{
-			Status: *deployPreviewResult.Status,
-			Properties: &DeploymentPreviewProperties{
-				Changes: changes,
-			},
+	return &DeploymentPreview{
+		Status: *deployPreviewResult.Status,
+		Properties: &DeploymentPreviewProperties{
+			Changes: changes,
 		},
 	}, nil
 }
 
+// Plan creates a plan for the deployment, including the what-if result for the compiled template and parameters.
+func (p *BicepProvider) Plan(ctx context.Context) (*Plan, error) {
+	bicepDeploymentData, err := p.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := p.planFromDeploymentDetails(bicepDeploymentData)
+	if err != nil {
+		return nil, err
+	}
+
+	preview, err := p.previewDeployment(ctx, bicepDeploymentData)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Preview = preview
+	return plan, nil
+}
+
+// ApplyPlan deploys the template after verifying the compiled template and the resolved parameters still match the
+// ones recorded in the plan. Deployment state is not considered, a plan is always applied.
+func (p *BicepProvider) ApplyPlan(ctx context.Context, plan *Plan) (*DeployResult, error) {
+	bicepDeploymentData, err := p.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := p.planFromDeploymentDetails(bicepDeploymentData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := plan.Verify(current); err != nil {
+		return nil, err
+	}
+
+	deployment, err := p.convertToDeployment(bicepDeploymentData.CompiledBicep.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	deploymentTags := map[string]*string{
+		azure.TagKeyAzdEnvName:                      to.Ptr(p.env.Name()),
+		azure.TagKeyAzdDeploymentStateParamHashName: to.Ptr(current.ParametersHash),
+	}
+	deployResult, err := p.applyDeployment(ctx, bicepDeploymentData, deploymentTags)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment.Outputs = p.createOutputParameters(
+		bicepDeploymentData.CompiledBicep.Template.Outputs,
+		azapi.CreateDeploymentOutput(deployResult.Properties.Outputs),
+	)
+
+	return &DeployResult{
+		Deployment: deployment,
+	}, nil
+}
+
+// planFromDeploymentDetails creates the provider neutral plan for the compiled template and its resolved parameters.
+// The values of secure parameters are not included in the plan.
+func (p *BicepProvider) planFromDeploymentDetails(bicepDeploymentData *deploymentDetails) (*Plan, error) {
+	template := bicepDeploymentData.CompiledBicep.Template
+	parameters := bicepDeploymentData.CompiledBicep.Parameters
+
+	paramsHash, err := parametersHash(template.Parameters, parameters)
+	if err != nil {
+		return nil, fmt.Errorf("hashing parameters: %w", err)
+	}
+
+	planParameters := PlanParameters{}
+	for paramName, paramDefinition := range template.Parameters {
+		if paramDefinition.Secure() {
+			planParameters[paramName] = PlanParameter{Secure: true}
+			continue
+		}
+
+		value := paramDefinition.DefaultValue
+		if param, has := parameters[paramName]; has {
+			value = param.Value
+		}
+		planParameters[paramName] = PlanParameter{Value: value}
+	}
+
+	deploymentScope, err := template.TargetScope()
+	if err != nil {
+		return nil, err
+	}
+
+	scope := PlanScope{
+		Kind:           string(deploymentScope),
+		SubscriptionId: p.env.GetSubscriptionId(),
+	}
+	if deploymentScope == azure.DeploymentScopeSubscription {
+		scope.Location = p.env.GetLocation()
+	} else {
+		scope.ResourceGroup = p.env.Getenv(environment.ResourceGroupEnvVarName)
+	}
+
+	return &Plan{
+		TemplateHash:   fmt.Sprintf("%x", sha256.Sum256(bicepDeploymentData.CompiledBicep.RawArmTemplate)),
+		ParametersHash: paramsHash,
+		Parameters:     planParameters,
+		Scope:          scope,
+	}, nil
+}
+
 type itemToPurge struct {
 	resourceType      string
 	count             int
@@ -1644,17 +1777,29 @@ func (p *BicepProvider) loadParameters(ctx context.Context) (map[string]azure.Ar
 		return nil, fmt.Errorf("fetching current principal id: %w", err)
 	}
 
+	// The values referencing secrets are substituted with the values of the secrets, only the secrets referenced by the
+	// parameters are resolved
+	var resolveErr error
 	replaced, err := envsubst.Eval(string(parametersBytes), func(name string) string {
 		if name == environment.PrincipalIdEnvVarName {
 			return principalId
 		}
 
-		return p.env.Getenv(name)
+		value, err := p.env.ResolvedGetenv(ctx, name)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+
+		return value
 	})
 	if err != nil {
 		return nil, fmt.Errorf("substituting environment variables inside parameter file: %w", err)
 	}
 
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+
 	if cmdsubst.ContainsCommandInvocation(replaced, cmdsubst.SecretOrRandomPasswordCommandName) {
 		cmdExecutor := cmdsubst.NewSecretOrRandomPasswordExecutor(p.keyvaultService, p.env.GetSubscriptionId())
 		replaced, err = cmdsubst.Eval(ctx, replaced, cmdExecutor)
@@ -1696,7 +1841,11 @@ func (p *BicepProvider) compileBicep(
 	var parameters azure.ArmParameters
 
 	if isBicepParamFile(modulePath) {
-		azdEnv := p.env.Environ()
+		azdEnv, err := p.env.Environ(ctx)
+		if err != nil {
+			return nil, err
+		}
+
 		// append principalID (not stored to .env by default). For non-bicepparam, principalId is resolved
 		// without looking at .env
 		if _, exists := p.env.LookupEnv(environment.PrincipalIdEnvVarName); !exists {
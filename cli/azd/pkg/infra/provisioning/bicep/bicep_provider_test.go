@@ -395,6 +395,7 @@ func createBicepProvider(t *testing.T, mockContext *mocks.MockContext) *BicepPro
 				}),
 			mockContext.ArmClientOptions,
 			mockContext.CoreClientOptions,
+			cloud.AzurePublic(),
 		),
 		cloud.AzurePublic().PortalUrlBase,
 	)
@@ -942,6 +943,7 @@ func TestUserDefinedTypes(t *testing.T) {
 				}),
 			mockContext.ArmClientOptions,
 			mockContext.CoreClientOptions,
+			cloud.AzurePublic(),
 		),
 		cloud.AzurePublic().PortalUrlBase,
 	)
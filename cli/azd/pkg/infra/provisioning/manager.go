@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
@@ -26,6 +27,7 @@ type Manager struct {
 	env                 *environment.Environment
 	console             input.Console
 	provider            Provider
+	providerKind        ProviderKind
 	alphaFeatureManager *alpha.FeatureManager
 	projectPath         string
 	options             *Options
@@ -103,27 +105,105 @@ func (m *Manager) Preview(ctx context.Context) (*DeployPreviewResult, error) {
 
 	// apply resource mapping
 	filteredResult := DeployPreviewResult{
-		Preview: &DeploymentPreview{
-			Status:     deployResult.Preview.Status,
-			Properties: &DeploymentPreviewProperties{},
-		},
+		Preview: previewWithDisplayNames(deployResult.Preview),
 	}
 
-	for index, result := range deployResult.Preview.Properties.Changes {
-		mappingName := infra.GetResourceTypeDisplayName(infra.AzureResourceType(result.ResourceType))
+	// make sure any spinner is stopped
+	m.console.StopSpinner(ctx, "", input.StepDone)
+
+	return &filteredResult, nil
+}
+
+// previewWithDisplayNames returns a copy of the preview with only the changes for resource types that have a display
+// name, using the display name as the resource type.
+func previewWithDisplayNames(preview *DeploymentPreview) *DeploymentPreview {
+	filtered := &DeploymentPreview{
+		Status:     preview.Status,
+		Properties: &DeploymentPreviewProperties{},
+	}
+
+	if preview.Properties == nil {
+		return filtered
+	}
+
+	for _, change := range preview.Properties.Changes {
+		mappingName := infra.GetResourceTypeDisplayName(infra.AzureResourceType(change.ResourceType))
 		if mappingName == "" {
 			// ignore
 			continue
 		}
-		deployResult.Preview.Properties.Changes[index].ResourceType = mappingName
-		filteredResult.Preview.Properties.Changes = append(
-			filteredResult.Preview.Properties.Changes, deployResult.Preview.Properties.Changes[index])
+
+		mappedChange := *change
+		mappedChange.ResourceType = mappingName
+		filtered.Properties.Changes = append(filtered.Properties.Changes, &mappedChange)
 	}
 
+	return filtered
+}
+
+// Plan computes the changes required to provision the infrastructure without applying them. The returned plan can be
+// saved and applied later with DeployPlan.
+func (m *Manager) Plan(ctx context.Context) (*Plan, error) {
+	planProvider, ok := m.provider.(PlanProvider)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrPlanNotSupported, m.provider.Name())
+	}
+
+	plan, err := planProvider.Plan(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error planning infrastructure: %w", err)
+	}
+
+	plan.Version = PlanVersion
+	plan.Provider = m.providerKind
+	plan.EnvironmentName = m.env.Name()
+	plan.CreatedAt = time.Now().UTC()
+
 	// make sure any spinner is stopped
 	m.console.StopSpinner(ctx, "", input.StepDone)
 
-	return &filteredResult, nil
+	return plan, nil
+}
+
+// DeployPlan applies a plan previously created by Plan. The deployment fails when the plan was created for a
+// different provider or environment, or when the template or parameters have changed since the plan was created.
+func (m *Manager) DeployPlan(ctx context.Context, plan *Plan) (*DeployResult, error) {
+	planProvider, ok := m.provider.(PlanProvider)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrPlanNotSupported, m.provider.Name())
+	}
+
+	if plan.Provider != m.providerKind {
+		return nil, fmt.Errorf(
+			"%w: the plan was created by provider '%s', but the project uses '%s'",
+			ErrPlanMismatch,
+			plan.Provider,
+			m.providerKind,
+		)
+	}
+
+	if plan.EnvironmentName != m.env.Name() {
+		return nil, fmt.Errorf(
+			"%w: the plan was created for environment '%s', but the current environment is '%s'",
+			ErrPlanMismatch,
+			plan.EnvironmentName,
+			m.env.Name(),
+		)
+	}
+
+	deployResult, err := planProvider.ApplyPlan(ctx, plan)
+	if err != nil {
+		return nil, fmt.Errorf("error deploying infrastructure: %w", err)
+	}
+
+	if err := m.UpdateEnvironment(ctx, deployResult.Deployment.Outputs); err != nil {
+		return nil, fmt.Errorf("updating environment with deployment outputs: %w", err)
+	}
+
+	// make sure any spinner is stopped
+	m.console.StopSpinner(ctx, "", input.StepDone)
+
+	return deployResult, nil
 }
 
 // Destroys the Azure infrastructure for the specified project
@@ -273,5 +353,6 @@ func (m *Manager) newProvider(ctx context.Context) (Provider, error) {
 		return nil, fmt.Errorf("failed resolving IaC provider '%s': %w", providerKey, err)
 	}
 
+	m.providerKind = providerKey
 	return provider, nil
 }
@@ -5,10 +5,12 @@ package provisioning_test
 
 import (
 	"context"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
@@ -60,6 +62,24 @@ func TestProvisionInitializesEnvironment(t *testing.T) {
 	require.Equal(t, "location", env.GetLocation())
 }
 
+func TestManagerPulumiRequiresAlphaFeature(t *testing.T) {
+	env := environment.NewWithValues("test-env", nil)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	registerContainerDependencies(mockContext, env)
+
+	mgr := NewManager(
+		mockContext.Container,
+		defaultProvider,
+		&mockenv.MockEnvManager{},
+		env,
+		mockContext.Console,
+		mockContext.AlphaFeaturesManager,
+	)
+	err := mgr.Initialize(*mockContext.Context, "", Options{Provider: Pulumi})
+	require.ErrorContains(t, err, alpha.GetEnableCommand(alpha.PulumiId))
+}
+
 func TestManagerPreview(t *testing.T) {
 	env := environment.NewWithValues("test-env", map[string]string{
 		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
@@ -141,6 +161,65 @@ func TestManagerDeploy(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestManagerPlanAndDeployPlan(t *testing.T) {
+	env := environment.NewWithValues("test-env", map[string]string{
+		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
+		"AZURE_LOCATION":        "eastus2",
+	})
+
+	mockContext := mocks.NewMockContext(context.Background())
+	registerContainerDependencies(mockContext, env)
+
+	envManager := &mockenv.MockEnvManager{}
+	mgr := NewManager(
+		mockContext.Container,
+		defaultProvider,
+		envManager,
+		env,
+		mockContext.Console,
+		mockContext.AlphaFeaturesManager,
+	)
+	err := mgr.Initialize(*mockContext.Context, "", Options{Provider: "test"})
+	require.NoError(t, err)
+
+	plan, err := mgr.Plan(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, PlanVersion, plan.Version)
+	require.Equal(t, Test, plan.Provider)
+	require.Equal(t, "test-env", plan.EnvironmentName)
+	require.Equal(t, "eastus2", plan.Parameters["location"].Value)
+
+	planPath := filepath.Join(t.TempDir(), "provision.plan.json")
+	require.NoError(t, WritePlan(plan, planPath))
+
+	savedPlan, err := ReadPlan(planPath)
+	require.NoError(t, err)
+
+	t.Run("Unchanged", func(t *testing.T) {
+		deployResult, err := mgr.DeployPlan(*mockContext.Context, savedPlan)
+		require.NoError(t, err)
+		require.NotNil(t, deployResult)
+	})
+
+	t.Run("ParametersChanged", func(t *testing.T) {
+		env.SetLocation("westus")
+		defer env.SetLocation("eastus2")
+
+		deployResult, err := mgr.DeployPlan(*mockContext.Context, savedPlan)
+		require.ErrorIs(t, err, ErrPlanMismatch)
+		require.Nil(t, deployResult)
+	})
+
+	t.Run("DifferentEnvironment", func(t *testing.T) {
+		otherEnvPlan := *savedPlan
+		otherEnvPlan.EnvironmentName = "other-env"
+
+		deployResult, err := mgr.DeployPlan(*mockContext.Context, &otherEnvPlan)
+		require.ErrorIs(t, err, ErrPlanMismatch)
+		require.Nil(t, deployResult)
+	})
+}
+
 func TestManagerDestroyWithPositiveConfirmation(t *testing.T) {
 	env := environment.NewWithValues("test-env", map[string]string{
 		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
@@ -0,0 +1,155 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// PlanVersion is the version of the plan file format written by azd.
+const PlanVersion = 1
+
+var (
+	// ErrPlanMismatch is returned when a saved plan no longer matches the current template, parameters or scope.
+	ErrPlanMismatch = errors.New("the saved plan does not match the current infrastructure")
+	// ErrPlanNotSupported is returned when the configured provider can't create or apply saved plans.
+	ErrPlanNotSupported = errors.New("saved plans are not supported by the provider")
+)
+
+// Plan is a serialized, provider neutral description of a provisioning operation. A plan is created by
+// `azd provision --plan-out` and applied later by `azd provision --from-plan`. Applying a plan fails when the template,
+// the resolved parameters or the deployment scope are different from the ones recorded in the plan.
+type Plan struct {
+	Version         int            `json:"version"`
+	Provider        ProviderKind   `json:"provider"`
+	EnvironmentName string         `json:"environmentName"`
+	CreatedAt       time.Time      `json:"createdAt"`
+	TemplateHash    string         `json:"templateHash"`
+	ParametersHash  string         `json:"parametersHash"`
+	Parameters      PlanParameters `json:"parameters"`
+	Scope           PlanScope      `json:"scope"`
+	// The changes that would be applied by the plan, as reported by the provider at plan time.
+	Preview *DeploymentPreview `json:"preview,omitempty"`
+	// Opaque provider specific data required to apply the plan, e.g. the terraform plan file.
+	ProviderData json.RawMessage `json:"providerData,omitempty"`
+}
+
+// PlanParameters are the resolved input parameters of a plan, by name.
+type PlanParameters map[string]PlanParameter
+
+// PlanParameter is a resolved input parameter of a plan. The value of secure parameters is never written to the plan,
+// but it is still part of the parameters hash.
+type PlanParameter struct {
+	Value  any  `json:"value,omitempty"`
+	Secure bool `json:"secure,omitempty"`
+}
+
+// PlanScope describes where the resources of a plan are deployed.
+type PlanScope struct {
+	// The kind of deployment scope, e.g. subscription or resourceGroup.
+	Kind           string `json:"kind,omitempty"`
+	SubscriptionId string `json:"subscriptionId"`
+	Location       string `json:"location,omitempty"`
+	ResourceGroup  string `json:"resourceGroup,omitempty"`
+}
+
+// PlanProvider is implemented by providers that support splitting provisioning into a plan that can be reviewed
+// and saved, and a later apply of exactly that plan.
+type PlanProvider interface {
+	// Plan computes the changes to apply without applying them.
+	Plan(ctx context.Context) (*Plan, error)
+	// ApplyPlan applies a plan previously returned by Plan. Implementations must return an error wrapping
+	// ErrPlanMismatch when the plan is no longer valid for the current template and parameters.
+	ApplyPlan(ctx context.Context, plan *Plan) (*DeployResult, error)
+}
+
+// Verify compares the plan with a plan computed for the current state of the project, returning an error wrapping
+// ErrPlanMismatch describing the first difference found.
+func (p *Plan) Verify(current *Plan) error {
+	if p.TemplateHash != current.TemplateHash {
+		return fmt.Errorf("%w: the template has changed since the plan was created", ErrPlanMismatch)
+	}
+
+	if p.ParametersHash != current.ParametersHash {
+		return fmt.Errorf("%w: the parameters have changed since the plan was created", ErrPlanMismatch)
+	}
+
+	if p.Scope != current.Scope {
+		return fmt.Errorf("%w: the deployment scope has changed since the plan was created", ErrPlanMismatch)
+	}
+
+	return nil
+}
+
+// PreviewResult returns the changes of the plan in the same form returned by Manager.Preview.
+func (p *Plan) PreviewResult() *DeployPreviewResult {
+	if p.Preview == nil {
+		return &DeployPreviewResult{
+			Preview: &DeploymentPreview{
+				Properties: &DeploymentPreviewProperties{},
+			},
+		}
+	}
+
+	return &DeployPreviewResult{
+		Preview: previewWithDisplayNames(p.Preview),
+	}
+}
+
+// HashPlanValue returns the hex encoded sha256 hash of the JSON representation of value. Map keys are sorted when
+// encoded, which makes the hash stable across runs.
+func HashPlanValue(value any) (string, error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(bytes)), nil
+}
+
+// WritePlan writes the plan as JSON to the file at path.
+func WritePlan(plan *Plan, path string) error {
+	bytes, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling plan: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating directory structure: %w", err)
+	}
+
+	if err := os.WriteFile(path, bytes, osutil.PermissionFileOwnerOnly); err != nil {
+		return fmt.Errorf("writing plan file: %w", err)
+	}
+
+	return nil
+}
+
+// ReadPlan reads a plan previously written by WritePlan.
+func ReadPlan(path string) (*Plan, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading plan file: %w", err)
+	}
+
+	var plan Plan
+	if err := json.Unmarshal(bytes, &plan); err != nil {
+		return nil, fmt.Errorf("parsing plan file: %w", err)
+	}
+
+	if plan.Version != PlanVersion {
+		return nil, fmt.Errorf("unsupported plan version %d, expected %d", plan.Version, PlanVersion)
+	}
+
+	return &plan, nil
+}
@@ -0,0 +1,633 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pulumi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/pulumi"
+	"github.com/drone/envsubst"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/maps"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultModule = "main"
+	defaultPath   = "infra"
+
+	// The prefix of the config of the azure-native provider
+	azureNativeConfigPrefix = "azure-native:"
+)
+
+// PulumiProvider exposes infrastructure provisioning using Pulumi programs
+type PulumiProvider struct {
+	envManager   environment.Manager
+	env          *environment.Environment
+	prompters    prompt.Prompter
+	console      input.Console
+	cli          pulumi.PulumiCli
+	curPrincipal CurrentPrincipalIdProvider
+	projectPath  string
+	options      Options
+}
+
+// Name gets the name of the infra provider
+func (p *PulumiProvider) Name() string {
+	return "Pulumi"
+}
+
+func (p *PulumiProvider) RequiredExternalTools() []tools.ExternalTool {
+	return []tools.ExternalTool{p.cli}
+}
+
+// NewPulumiProvider creates a new instance of a Pulumi Infra provider
+func NewPulumiProvider(
+	cli pulumi.PulumiCli,
+	envManager environment.Manager,
+	env *environment.Environment,
+	console input.Console,
+	curPrincipal CurrentPrincipalIdProvider,
+	prompters prompt.Prompter,
+) Provider {
+	return &PulumiProvider{
+		envManager:   envManager,
+		env:          env,
+		console:      console,
+		cli:          cli,
+		curPrincipal: curPrincipal,
+		prompters:    prompters,
+	}
+}
+
+func (p *PulumiProvider) Initialize(ctx context.Context, projectPath string, options Options) error {
+	p.projectPath = projectPath
+	p.options = options
+	if p.options.Module == "" {
+		p.options.Module = defaultModule
+	}
+	if p.options.Path == "" {
+		p.options.Path = defaultPath
+	}
+
+	requiredTools := p.RequiredExternalTools()
+	if err := tools.EnsureInstalled(ctx, requiredTools...); err != nil {
+		return err
+	}
+
+	if err := p.EnsureEnv(ctx); err != nil {
+		return err
+	}
+
+	isRemoteBackendConfig, err := p.isRemoteBackendConfig()
+	if err != nil {
+		return fmt.Errorf("reading backend config: %w", err)
+	}
+
+	envVars := []string{
+		// Required when using service principal login
+		fmt.Sprintf("ARM_TENANT_ID=%s", os.Getenv("ARM_TENANT_ID")),
+		fmt.Sprintf("ARM_SUBSCRIPTION_ID=%s", p.env.GetSubscriptionId()),
+		fmt.Sprintf("ARM_CLIENT_ID=%s", os.Getenv("ARM_CLIENT_ID")),
+		fmt.Sprintf("ARM_CLIENT_SECRET=%s", os.Getenv("ARM_CLIENT_SECRET")),
+		// azd manages the lifetime of the CLI, update checks only add noise to the output
+		"PULUMI_SKIP_UPDATE_CHECK=true",
+	}
+
+	if !isRemoteBackendConfig {
+		// Local stacks are kept alongside the rest of the environment state in the .azure folder.
+		envVars = append(envVars, fmt.Sprintf("PULUMI_BACKEND_URL=%s", p.localBackendUrl()))
+
+		// Stacks on the local backend default to the passphrase secrets provider, which fails when running without
+		// a terminal unless a passphrase has been configured.
+		passphraseEnvVar, err := p.passphraseEnvVar(ctx)
+		if err != nil {
+			return err
+		}
+
+		if passphraseEnvVar != "" {
+			envVars = append(envVars, passphraseEnvVar)
+		}
+	}
+
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if spanCtx.HasTraceID() {
+		envVars = append(envVars, fmt.Sprintf("ARM_CORRELATION_REQUEST_ID=%s", spanCtx.TraceID().String()))
+	}
+
+	p.cli.SetEnv(envVars)
+	return nil
+}
+
+// passphraseEnvVar returns the environment variable setting the passphrase of the secrets of a stack on the local backend,
+// prompted when it isn't already configured by the PULUMI_CONFIG_PASSPHRASE or PULUMI_CONFIG_PASSPHRASE_FILE environment
+// variables. An empty string is returned when the passphrase is already configured.
+func (p *PulumiProvider) passphraseEnvVar(ctx context.Context) (string, error) {
+	for _, name := range []string{"PULUMI_CONFIG_PASSPHRASE", "PULUMI_CONFIG_PASSPHRASE_FILE"} {
+		if _, has := os.LookupEnv(name); has {
+			return "", nil
+		}
+	}
+
+	passphrase, err := p.console.Prompt(ctx, input.ConsoleOptions{
+		Message:    "Enter the passphrase protecting the secrets of the Pulumi stack:",
+		Help:       "Set PULUMI_CONFIG_PASSPHRASE or PULUMI_CONFIG_PASSPHRASE_FILE to skip this prompt.",
+		IsPassword: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf(
+			"prompting for the passphrase of the Pulumi stack, set PULUMI_CONFIG_PASSPHRASE or "+
+				"PULUMI_CONFIG_PASSPHRASE_FILE when running without prompts: %w",
+			err,
+		)
+	}
+
+	if passphrase == "" {
+		p.console.Message(ctx, output.WithWarningFormat(
+			"WARNING: The secrets of the Pulumi stack are protected by an empty passphrase."))
+	}
+
+	return "PULUMI_CONFIG_PASSPHRASE=" + passphrase, nil
+}
+
+// EnsureEnv ensures that the environment is in a provision-ready state with required values set, prompting the user if
+// values are unset.
+//
+// An environment is considered to be in a provision-ready state if it contains both an AZURE_SUBSCRIPTION_ID and
+// AZURE_LOCATION value.
+func (p *PulumiProvider) EnsureEnv(ctx context.Context) error {
+	return EnsureSubscriptionAndLocation(
+		ctx,
+		p.envManager,
+		p.env,
+		p.prompters,
+		nil,
+	)
+}
+
+// Deploy the infrastructure within the specified program through pulumi up
+func (p *PulumiProvider) Deploy(ctx context.Context) (*DeployResult, error) {
+	p.console.Message(ctx, "Preparing pulumi stack...")
+
+	deployment, err := p.prepareStack(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// pulumi doesn't use the `p.console`, we must ensure no spinner is running before calling Up
+	p.console.StopSpinner(ctx, "", input.Step)
+	runResult, err := p.cli.Up(ctx, p.modulePath(), p.stackName(), p.configFileArgs()...)
+	if err != nil {
+		return nil, fmt.Errorf("template Deploy failed: %s , err:%w", runResult, err)
+	}
+
+	outputs, err := p.createOutputParameters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading pulumi stack outputs failed: %w", err)
+	}
+
+	deployment.Outputs = outputs
+	return &DeployResult{
+		Deployment: deployment,
+	}, nil
+}
+
+func (p *PulumiProvider) Preview(ctx context.Context) (*DeployPreviewResult, error) {
+	// pulumi displays its own diff of the changes as part of `pulumi preview`
+	// no changes are added to the properties
+	if _, err := p.prepareStack(ctx); err != nil {
+		return nil, err
+	}
+
+	p.console.StopSpinner(ctx, "", input.Step)
+	runResult, err := p.cli.Preview(ctx, p.modulePath(), p.stackName(), p.configFileArgs()...)
+	if err != nil {
+		return nil, fmt.Errorf("pulumi preview failed: %s, err: %w", runResult, err)
+	}
+
+	return &DeployPreviewResult{
+		Preview: &DeploymentPreview{
+			Status:     "done",
+			Properties: &DeploymentPreviewProperties{},
+		},
+	}, nil
+}
+
+// Destroys the specified stack through pulumi destroy
+func (p *PulumiProvider) Destroy(ctx context.Context, options DestroyOptions) (*DestroyResult, error) {
+	p.console.Message(ctx, "Preparing pulumi stack...")
+	if _, err := p.prepareStack(ctx); err != nil {
+		return nil, err
+	}
+
+	//load the deployment result
+	outputs, err := p.createOutputParameters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load pulumi stack outputs failed: %w", err)
+	}
+
+	p.console.Message(ctx, "Deleting pulumi stack resources...")
+	// pulumi doesn't use the `p.console`, we must ensure no spinner is running before calling Destroy
+	// as it could be an interactive operation if it needs confirmation
+	p.console.StopSpinner(ctx, "", input.Step)
+
+	destroyArgs := p.configFileArgs()
+	if options.Force() {
+		destroyArgs = append(destroyArgs, "--yes")
+	}
+
+	runResult, err := p.cli.Destroy(ctx, p.modulePath(), p.stackName(), destroyArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("template Destroy failed: %s, err: %w", runResult, err)
+	}
+
+	return &DestroyResult{
+		InvalidatedEnvKeys: maps.Keys(outputs),
+	}, nil
+}
+
+func (p *PulumiProvider) State(ctx context.Context, options *StateOptions) (*StateResult, error) {
+	p.console.Message(ctx, "Retrieving pulumi state...")
+
+	if _, err := p.selectStack(ctx); err != nil {
+		return nil, err
+	}
+
+	outputs, err := p.createOutputParameters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading pulumi stack outputs failed: %w", err)
+	}
+
+	exportResult, err := p.cli.StackExport(ctx, p.modulePath(), p.stackName())
+	if err != nil {
+		return nil, fmt.Errorf("fetching pulumi state failed: %w", err)
+	}
+
+	var export pulumiStackExport
+	if err := json.Unmarshal([]byte(exportResult), &export); err != nil {
+		return nil, fmt.Errorf("parsing pulumi state: %w", err)
+	}
+
+	return &StateResult{
+		State: &State{
+			Outputs:   outputs,
+			Resources: p.collectAzureResources(export),
+		},
+	}, nil
+}
+
+// selectStack makes the stack for the current environment the active stack, creating it when needed.
+func (p *PulumiProvider) selectStack(ctx context.Context) (string, error) {
+	if err := os.MkdirAll(p.stateDirPath(), osutil.PermissionDirectory); err != nil {
+		return "", fmt.Errorf("creating directory structure: %w", err)
+	}
+
+	runResult, err := p.cli.SelectStack(ctx, p.modulePath(), p.stackName())
+	if err != nil {
+		return runResult, fmt.Errorf("pulumi stack select failed: %s, err: %w", runResult, err)
+	}
+
+	return runResult, nil
+}
+
+// prepareStack selects the stack for the current environment and applies the stack configuration derived from the
+// azd environment. The returned deployment contains the configuration that was applied.
+func (p *PulumiProvider) prepareStack(ctx context.Context) (*Deployment, error) {
+	if _, err := p.selectStack(ctx); err != nil {
+		return nil, err
+	}
+
+	config, err := p.createStackConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating stack config: %w", err)
+	}
+
+	templateParameters := make(map[string]InputParameter, len(config))
+
+	// Sort the keys to keep the order of the pulumi commands stable
+	keys := maps.Keys(config)
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		value := config[key]
+		configValue, isJson, err := configValueString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for config '%s': %w", key, err)
+		}
+
+		// The values of the parameters file are resolved from the environment and may be secrets, they are stored
+		// encrypted in the stack config. Only the settings of the azure-native provider are stored in plain text.
+		secret := !strings.HasPrefix(key, azureNativeConfigPrefix)
+
+		runResult, err := p.cli.SetConfig(
+			ctx, p.modulePath(), p.stackName(), p.stackConfigFilePath(), key, configValue, isJson, secret)
+		if err != nil {
+			return nil, fmt.Errorf("setting stack config '%s' failed: %s, err: %w", key, runResult, err)
+		}
+
+		templateParameters[key] = InputParameter{
+			Type:  string(p.mapPulumiTypeToInterfaceType(value)),
+			Value: value,
+		}
+	}
+
+	return &Deployment{
+		Parameters: templateParameters,
+	}, nil
+}
+
+// createStackConfig builds the set of stack configuration values for the current environment.
+//
+// The location and subscription of the environment are always mapped to the azure-native provider configuration.
+// Additional values are read from the `<module>.parameters.json` file next to `Pulumi.yaml`, after replacing
+// environment variable references in its contents.
+func (p *PulumiProvider) createStackConfig(ctx context.Context) (map[string]any, error) {
+	config := map[string]any{
+		azureNativeConfigPrefix + "location":       p.env.GetLocation(),
+		azureNativeConfigPrefix + "subscriptionId": p.env.GetSubscriptionId(),
+	}
+
+	templateFilePath := p.parametersTemplateFilePath()
+	if _, err := os.Stat(templateFilePath); errors.Is(err, os.ErrNotExist) {
+		log.Printf("parameters file '%s' not found, using default stack config", templateFilePath)
+		return config, nil
+	}
+
+	if err := p.createInputParametersFile(ctx, templateFilePath, p.parametersFilePath()); err != nil {
+		return nil, fmt.Errorf("creating parameters file: %w", err)
+	}
+
+	parametersBytes, err := os.ReadFile(p.parametersFilePath())
+	if err != nil {
+		return nil, fmt.Errorf("reading parameters file: %w", err)
+	}
+
+	var parameters map[string]any
+	if err := json.Unmarshal(parametersBytes, &parameters); err != nil {
+		return nil, fmt.Errorf("error unmarshalling template parameters: %w", err)
+	}
+
+	for key, value := range parameters {
+		config[key] = value
+	}
+
+	return config, nil
+}
+
+// configValueString converts a config value into the form expected by `pulumi config set`. Non string values are
+// encoded as JSON.
+func configValueString(value any) (string, bool, error) {
+	if strValue, ok := value.(string); ok {
+		return strValue, false, nil
+	}
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return "", false, err
+	}
+
+	return string(bytes), true, nil
+}
+
+// Creates a normalized view of the pulumi stack outputs.
+func (p *PulumiProvider) createOutputParameters(ctx context.Context) (map[string]OutputParameter, error) {
+	runResult, err := p.cli.StackOutput(ctx, p.modulePath(), p.stackName())
+	if err != nil {
+		return nil, err
+	}
+
+	var outputMap map[string]any
+	if err := json.Unmarshal([]byte(runResult), &outputMap); err != nil {
+		return nil, err
+	}
+
+	return p.convertOutputs(outputMap), nil
+}
+
+// convertOutputs converts pulumi stack outputs to the canonical format shared by all provider implementations.
+func (p *PulumiProvider) convertOutputs(outputMap map[string]any) map[string]OutputParameter {
+	outputParameters := make(map[string]OutputParameter)
+	for k, v := range outputMap {
+		if v == nil {
+			// omit null
+			continue
+		}
+
+		outputParameters[k] = OutputParameter{
+			Type:  p.mapPulumiTypeToInterfaceType(v),
+			Value: v,
+		}
+	}
+	return outputParameters
+}
+
+func (p *PulumiProvider) mapPulumiTypeToInterfaceType(value any) ParameterType {
+	// stack outputs and config values don't carry any type information, the type is inferred from the decoded JSON value.
+	switch value.(type) {
+	case bool:
+		return ParameterTypeBoolean
+	case float64:
+		return ParameterTypeNumber
+	case []any:
+		return ParameterTypeArray
+	case map[string]any:
+		return ParameterTypeObject
+	}
+
+	return ParameterTypeString
+}
+
+// collectAzureResources collects the set of Azure resources from a pulumi stack export. Only custom resources with
+// an Azure resource id are considered, component resources and providers are ignored.
+func (p *PulumiProvider) collectAzureResources(export pulumiStackExport) []Resource {
+	resources := []Resource{}
+	seen := map[string]struct{}{}
+
+	for _, r := range export.Deployment.Resources {
+		if !r.Custom || !strings.HasPrefix(strings.ToLower(r.Id), "/subscriptions/") {
+			continue
+		}
+
+		if _, has := seen[r.Id]; has {
+			continue
+		}
+
+		seen[r.Id] = struct{}{}
+		resources = append(resources, Resource{
+			Id: r.Id,
+		})
+	}
+
+	return resources
+}
+
+// The name of the pulumi stack backing the current environment
+func (p *PulumiProvider) stackName() string {
+	return p.env.Name()
+}
+
+func (p *PulumiProvider) configFileArgs() []string {
+	return []string{"--config-file", p.stackConfigFilePath()}
+}
+
+// Gets the folder path to the pulumi program
+func (p *PulumiProvider) modulePath() string {
+	infraPath := p.options.Path
+	if strings.TrimSpace(infraPath) == "" {
+		infraPath = "infra"
+	}
+
+	return filepath.Join(p.projectPath, infraPath)
+}
+
+// Gets the path to the project parameters file path
+func (p *PulumiProvider) parametersTemplateFilePath() string {
+	parametersFilename := fmt.Sprintf("%s.parameters.json", p.options.Module)
+	return filepath.Join(p.modulePath(), parametersFilename)
+}
+
+// Gets the path to the staging .azure folder for the current env.
+func (p *PulumiProvider) stateDirPath() string {
+	return filepath.Join(p.projectPath, ".azure", p.env.Name(), p.options.Path)
+}
+
+// Gets the path to the staging .azure parameters file path
+func (p *PulumiProvider) parametersFilePath() string {
+	parametersFilename := fmt.Sprintf("%s.parameters.json", p.options.Module)
+	return filepath.Join(p.stateDirPath(), parametersFilename)
+}
+
+// Gets the path to the staging .azure stack config file path
+func (p *PulumiProvider) stackConfigFilePath() string {
+	return filepath.Join(p.stateDirPath(), fmt.Sprintf("Pulumi.%s.yaml", p.stackName()))
+}
+
+// Gets the url of the local file backend used when no remote backend is configured
+func (p *PulumiProvider) localBackendUrl() string {
+	return fmt.Sprintf("file://%s", filepath.ToSlash(filepath.Join(p.stateDirPath(), ".pulumi")))
+}
+
+// Check the pulumi project file for a remote backend
+func (p *PulumiProvider) isRemoteBackendConfig() (bool, error) {
+	var projectFile pulumiProjectFile
+	found := false
+
+	for _, fileName := range []string{"Pulumi.yaml", "Pulumi.yml"} {
+		fileContent, err := os.ReadFile(filepath.Join(p.modulePath(), fileName))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return false, fmt.Errorf("reading pulumi project file: %w", err)
+		}
+
+		if err := yaml.Unmarshal(fileContent, &projectFile); err != nil {
+			return false, fmt.Errorf("parsing pulumi project file: %w", err)
+		}
+
+		found = true
+		break
+	}
+
+	if !found {
+		return false, fmt.Errorf("pulumi project file not found in '%s'", p.modulePath())
+	}
+
+	backendUrl := projectFile.Backend.Url
+	return backendUrl != "" && !strings.HasPrefix(backendUrl, "file://"), nil
+}
+
+// Copies the an input parameters file templateFilePath to inputFilePath after replacing environment variable references in
+// the contents.
+func (p *PulumiProvider) createInputParametersFile(
+	ctx context.Context,
+	templateFilePath string,
+	inputFilePath string,
+) error {
+	principalId, err := p.curPrincipal.CurrentPrincipalId(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching current principal id: %w", err)
+	}
+
+	// Copy the parameter template file to the environment working directory and do substitutions.
+	log.Printf("Reading parameters template file from: %s", templateFilePath)
+	parametersBytes, err := os.ReadFile(templateFilePath)
+	if err != nil {
+		return fmt.Errorf("reading parameter file template: %w", err)
+	}
+	var resolveErr error
+	replaced, err := envsubst.Eval(string(parametersBytes), func(name string) string {
+		if name == environment.PrincipalIdEnvVarName {
+			return principalId
+		}
+
+		value, err := p.env.ResolvedGetenv(ctx, name)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+
+		return value
+	})
+
+	if err != nil {
+		return fmt.Errorf("substituting parameter file: %w", err)
+	}
+
+	if resolveErr != nil {
+		return fmt.Errorf("resolving parameter file secrets: %w", resolveErr)
+	}
+
+	writeDir := filepath.Dir(inputFilePath)
+	if err := os.MkdirAll(writeDir, osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating directory structure: %w", err)
+	}
+
+	log.Printf("Writing parameters file to: %s", inputFilePath)
+	err = os.WriteFile(inputFilePath, []byte(replaced), 0600)
+	if err != nil {
+		return fmt.Errorf("writing parameter file: %w", err)
+	}
+
+	return nil
+}
+
+// pulumiProjectFile is a model type for the parts of `Pulumi.yaml` azd cares about.
+// see https://www.pulumi.com/docs/concepts/projects/project-file/ for more information.
+type pulumiProjectFile struct {
+	Name    string `yaml:"name"`
+	Runtime any    `yaml:"runtime"`
+	Backend struct {
+		Url string `yaml:"url"`
+	} `yaml:"backend"`
+}
+
+// pulumiStackExport is a model type for the output of `pulumi stack export`.
+type pulumiStackExport struct {
+	Version    int `json:"version"`
+	Deployment struct {
+		Resources []pulumiResource `json:"resources"`
+	} `json:"deployment"`
+}
+
+// pulumiResource is the model type for a resource in a pulumi checkpoint.
+type pulumiResource struct {
+	Urn    string `json:"urn"`
+	Custom bool   `json:"custom"`
+	Type   string `json:"type"`
+	Id     string `json:"id"`
+}
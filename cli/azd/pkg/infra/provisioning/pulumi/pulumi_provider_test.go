@@ -0,0 +1,340 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pulumi
+
+import (
+	"context"
+	_ "embed"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	. "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	pulumiTools "github.com/azure/azure-dev/cli/azd/pkg/tools/pulumi"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPulumiDeploy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareStackMocks(mockContext.CommandRunner)
+	prepareOutputMocks(mockContext.CommandRunner)
+
+	configArgs := map[string][]string{}
+	configValues := map[string]string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "config set")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		value, err := io.ReadAll(args.StdIn)
+		require.NoError(t, err)
+
+		configArgs[args.Args[2]] = args.Args
+		configValues[args.Args[2]] = string(value)
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	upRan := false
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, " up ")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		upRan = true
+		require.Contains(t, args.Args, "--config-file")
+		return exec.NewRunResult(0, "Resources: 1 created", ""), nil
+	})
+
+	infraProvider := createPulumiProvider(t, mockContext, "infra")
+	deployResult, err := infraProvider.Deploy(*mockContext.Context)
+
+	require.NoError(t, err)
+	require.True(t, upRan)
+	require.NotNil(t, deployResult.Deployment)
+
+	require.Equal(t, "westus2", deployResult.Deployment.Parameters["azure-native:location"].Value)
+	require.Equal(t, "test-env", deployResult.Deployment.Parameters["resourcegrouppulumi:environmentName"].Value)
+	require.Equal(
+		t,
+		"11111111-1111-1111-1111-111111111111",
+		deployResult.Deployment.Parameters["resourcegrouppulumi:principalId"].Value,
+	)
+
+	require.Equal(t, "string", deployResult.Deployment.Parameters["resourcegrouppulumi:environmentName"].Type)
+	require.Equal(t, "object", deployResult.Deployment.Parameters["resourcegrouppulumi:tags"].Type)
+
+	// values are passed to pulumi on stdin, object values as JSON
+	require.Len(t, configArgs, 5)
+	require.Equal(t, `{"azd-env-name":"test-env"}`, configValues["resourcegrouppulumi:tags"])
+	require.Contains(t, configArgs["resourcegrouppulumi:tags"], "--json")
+	require.NotContains(t, configArgs["resourcegrouppulumi:environmentName"], "test-env")
+
+	// the values of the parameters file are stored as secrets, unlike the settings of the azure-native provider
+	require.Contains(t, configArgs["resourcegrouppulumi:principalId"], "--secret")
+	require.NotContains(t, configArgs["azure-native:location"], "--secret")
+
+	require.Equal(t, "rg-test-env", deployResult.Deployment.Outputs["RG_NAME"].Value)
+	require.Equal(t, ParameterTypeString, deployResult.Deployment.Outputs["RG_NAME"].Type)
+	require.Equal(t, ParameterTypeNumber, deployResult.Deployment.Outputs["INSTANCE_COUNT"].Type)
+	require.Equal(t, ParameterTypeObject, deployResult.Deployment.Outputs["TAGS"].Type)
+	require.NotContains(t, deployResult.Deployment.Outputs, "UNSET")
+}
+
+func TestPulumiDestroy(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareStackMocks(mockContext.CommandRunner)
+	prepareOutputMocks(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "config set")
+	}).Respond(exec.NewRunResult(0, "", ""))
+
+	destroyRan := false
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "destroy")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		destroyRan = true
+		require.Contains(t, args.Args, "--yes")
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createPulumiProvider(t, mockContext, "infra")
+	destroyOptions := NewDestroyOptions(true, false)
+	destroyResult, err := infraProvider.Destroy(*mockContext.Context, destroyOptions)
+
+	require.NoError(t, err)
+	require.True(t, destroyRan)
+	require.NotNil(t, destroyResult)
+
+	require.Contains(t, destroyResult.InvalidatedEnvKeys, "RG_NAME")
+	require.Contains(t, destroyResult.InvalidatedEnvKeys, "TAGS")
+}
+
+//go:embed testdata/stack_export_mock.json
+var stackExportMockOutput string
+
+func TestPulumiState(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareStackMocks(mockContext.CommandRunner)
+	prepareOutputMocks(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "stack export")
+	}).Respond(exec.NewRunResult(0, stackExportMockOutput, ""))
+
+	infraProvider := createPulumiProvider(t, mockContext, "infra")
+	getStateResult, err := infraProvider.State(*mockContext.Context, nil)
+
+	require.NoError(t, err)
+	require.NotNil(t, getStateResult.State)
+
+	require.Equal(t, "rg-test-env", getStateResult.State.Outputs["RG_NAME"].Value)
+	require.Len(t, getStateResult.State.Resources, 1)
+	require.Equal(
+		t,
+		"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env",
+		getStateResult.State.Resources[0].Id,
+	)
+}
+
+func TestPulumiBackendConfig(t *testing.T) {
+	t.Run("Local", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		infraProvider := createPulumiProvider(t, mockContext, "infra")
+
+		isRemote, err := infraProvider.isRemoteBackendConfig()
+		require.NoError(t, err)
+		require.False(t, isRemote)
+
+		var envVars []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "pulumi"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			envVars = args.Env
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		_, err = infraProvider.selectStack(*mockContext.Context)
+		require.NoError(t, err)
+		require.Contains(t, envVars, "PULUMI_BACKEND_URL="+infraProvider.localBackendUrl())
+	})
+
+	t.Run("Remote", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		infraProvider := createPulumiProvider(t, mockContext, "remote")
+
+		isRemote, err := infraProvider.isRemoteBackendConfig()
+		require.NoError(t, err)
+		require.True(t, isRemote)
+
+		var envVars []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "pulumi"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			envVars = args.Env
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		_, err = infraProvider.selectStack(*mockContext.Context)
+		require.NoError(t, err)
+		for _, envVar := range envVars {
+			require.False(t, strings.HasPrefix(envVar, "PULUMI_BACKEND_URL="))
+		}
+	})
+}
+
+func TestPulumiPassphrase(t *testing.T) {
+	t.Run("Configured", func(t *testing.T) {
+		t.Setenv("PULUMI_CONFIG_PASSPHRASE_FILE", "passphrase.txt")
+		infraProvider := &PulumiProvider{console: mockinput.NewMockConsole()}
+
+		envVar, err := infraProvider.passphraseEnvVar(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, envVar)
+	})
+
+	t.Run("Prompted", func(t *testing.T) {
+		unsetPassphraseEnvVars(t)
+		console := mockinput.NewMockConsole()
+		console.WhenPrompt(func(options input.ConsoleOptions) bool {
+			return options.IsPassword && strings.Contains(options.Message, "passphrase")
+		}).Respond("passphrase")
+		infraProvider := &PulumiProvider{console: console}
+
+		envVar, err := infraProvider.passphraseEnvVar(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "PULUMI_CONFIG_PASSPHRASE=passphrase", envVar)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		unsetPassphraseEnvVars(t)
+		console := mockinput.NewMockConsole()
+		console.WhenPrompt(func(options input.ConsoleOptions) bool {
+			return true
+		}).Respond("")
+		infraProvider := &PulumiProvider{console: console}
+
+		envVar, err := infraProvider.passphraseEnvVar(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "PULUMI_CONFIG_PASSPHRASE=", envVar)
+		require.Contains(t, strings.Join(console.Output(), "\n"), "empty passphrase")
+	})
+}
+
+// unsetPassphraseEnvVars unsets the environment variables configuring the passphrase for the duration of the test
+func unsetPassphraseEnvVars(t *testing.T) {
+	for _, name := range []string{"PULUMI_CONFIG_PASSPHRASE", "PULUMI_CONFIG_PASSPHRASE_FILE"} {
+		t.Setenv(name, "")
+		require.NoError(t, os.Unsetenv(name))
+	}
+}
+
+func createPulumiProvider(t *testing.T, mockContext *mocks.MockContext, infraPath string) *PulumiProvider {
+	t.Setenv("PULUMI_CONFIG_PASSPHRASE", "passphrase")
+	projectDir := t.TempDir()
+	copyTestData(t, filepath.Join("testdata", infraPath), filepath.Join(projectDir, infraPath))
+
+	options := Options{
+		Module: "main",
+		Path:   infraPath,
+	}
+
+	env := environment.NewWithValues("test-env", map[string]string{
+		"AZURE_ENV_NAME":        "test-env",
+		"AZURE_LOCATION":        "westus2",
+		"AZURE_SUBSCRIPTION_ID": "00000000-0000-0000-0000-000000000000",
+	})
+
+	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
+	accountManager := &mockaccount.MockAccountManager{
+		Subscriptions: []account.Subscription{
+			{
+				Id:   "00000000-0000-0000-0000-000000000000",
+				Name: "test",
+			},
+		},
+		Locations: []account.Location{
+			{
+				Name:                "location",
+				DisplayName:         "Test Location",
+				RegionalDisplayName: "(US) Test Location",
+			},
+		},
+	}
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	provider := NewPulumiProvider(
+		&installedPulumiCli{PulumiCli: pulumiTools.NewPulumiCli(mockContext.CommandRunner)},
+		envManager,
+		env,
+		mockContext.Console,
+		&mockCurrentPrincipal{},
+		prompt.NewDefaultPrompter(env, mockContext.Console, accountManager, azCli, cloud.AzurePublic().PortalUrlBase),
+	)
+
+	err := provider.Initialize(*mockContext.Context, projectDir, options)
+	require.NoError(t, err)
+
+	return provider.(*PulumiProvider)
+}
+
+func copyTestData(t *testing.T, source string, target string) {
+	entries, err := os.ReadDir(source)
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(target, osutil.PermissionDirectory))
+	for _, entry := range entries {
+		contents, err := os.ReadFile(filepath.Join(source, entry.Name()))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(target, entry.Name()), contents, osutil.PermissionFile))
+	}
+}
+
+func prepareStackMocks(commandRunner *mockexec.MockCommandRunner) {
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "stack select")
+	}).Respond(exec.RunResult{
+		Stdout: "",
+		Stderr: "",
+	})
+}
+
+func prepareOutputMocks(commandRunner *mockexec.MockCommandRunner) {
+	output := `{"RG_NAME":"rg-test-env","INSTANCE_COUNT":2,"TAGS":{"azd-env-name":"test-env"},"UNSET":null}`
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "stack output")
+	}).Respond(exec.RunResult{
+		Stdout: output,
+		Stderr: "",
+	})
+}
+
+// installedPulumiCli skips the install check so the tests don't depend on pulumi being on the PATH.
+type installedPulumiCli struct {
+	pulumiTools.PulumiCli
+}
+
+func (c *installedPulumiCli) CheckInstalled(_ context.Context) error {
+	return nil
+}
+
+type mockCurrentPrincipal struct{}
+
+func (m *mockCurrentPrincipal) CurrentPrincipalId(_ context.Context) (string, error) {
+	return "11111111-1111-1111-1111-111111111111", nil
+}
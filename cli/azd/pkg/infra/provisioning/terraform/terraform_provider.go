@@ -2,6 +2,7 @@ package terraform
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -234,6 +235,288 @@ func (t *TerraformProvider) Preview(ctx context.Context) (*DeployPreviewResult,
 	}, nil
 }
 
+// Plan creates a terraform plan file and returns it as part of a provider neutral plan, together with the changes
+// reported by terraform for the plan.
+func (t *TerraformProvider) Plan(ctx context.Context) (*Plan, error) {
+	deployment, terraformDeploymentData, err := t.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	showOutput, err := t.showPlan(ctx, terraformDeploymentData.PlanFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := t.planFromDeployment(deployment, showOutput.sensitiveVariables())
+	if err != nil {
+		return nil, err
+	}
+
+	planFileBytes, err := os.ReadFile(terraformDeploymentData.PlanFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading plan file: %w", err)
+	}
+
+	providerData, err := json.Marshal(terraformPlanData{PlanFile: planFileBytes})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling plan file: %w", err)
+	}
+	plan.ProviderData = providerData
+
+	plan.Preview = previewPlan(showOutput)
+
+	return plan, nil
+}
+
+// ApplyPlan applies the terraform plan file saved in the plan after verifying the module and the parameters still
+// match the ones recorded in the plan.
+func (t *TerraformProvider) ApplyPlan(ctx context.Context, plan *Plan) (*DeployResult, error) {
+	var planData terraformPlanData
+	if err := json.Unmarshal(plan.ProviderData, &planData); err != nil || len(planData.PlanFile) == 0 {
+		return nil, fmt.Errorf("%w: the plan does not contain a terraform plan file", ErrPlanMismatch)
+	}
+
+	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
+	if err != nil {
+		return nil, fmt.Errorf("reading backend config: %w", err)
+	}
+
+	modulePath := t.modulePath()
+	initRes, err := t.init(ctx, isRemoteBackendConfig)
+	if err != nil {
+		return nil, fmt.Errorf("terraform init failed: %s , err: %w", initRes, err)
+	}
+
+	err = t.createInputParametersFile(ctx, t.parametersTemplateFilePath(), t.parametersFilePath())
+	if err != nil {
+		return nil, fmt.Errorf("creating parameters file: %w", err)
+	}
+
+	deployment, err := t.createDeployment(ctx, modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("create terraform template failed: %w", err)
+	}
+
+	// Only the hash of the parameters is verified, which doesn't depend on which variables are sensitive
+	current, err := t.planFromDeployment(deployment, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := plan.Verify(current); err != nil {
+		return nil, err
+	}
+
+	if err := os.WriteFile(t.planFilePath(), planData.PlanFile, osutil.PermissionFileOwnerOnly); err != nil {
+		return nil, fmt.Errorf("writing plan file: %w", err)
+	}
+
+	deploymentData := terraformDeploymentDetails{
+		ParameterFilePath: t.parametersFilePath(),
+		PlanFilePath:      t.planFilePath(),
+	}
+	if !isRemoteBackendConfig {
+		deploymentData.localStateFilePath = t.localStateFilePath()
+	}
+
+	applyArgs, err := t.createApplyArgs(isRemoteBackendConfig, deploymentData)
+	if err != nil {
+		return nil, err
+	}
+
+	runResult, err := t.cli.Apply(ctx, modulePath, applyArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("template Deploy failed: %s , err:%w", runResult, err)
+	}
+
+	outputs, err := t.createOutputParameters(ctx, modulePath, isRemoteBackendConfig)
+	if err != nil {
+		return nil, fmt.Errorf("create terraform template failed: %w", err)
+	}
+
+	deployment.Outputs = outputs
+	return &DeployResult{
+		Deployment: deployment,
+	}, nil
+}
+
+// planFromDeployment creates the provider neutral plan for the module and the resolved input variables.
+// The values of sensitive variables, and of variables with secret-looking names, are not included in the plan.
+func (t *TerraformProvider) planFromDeployment(deployment *Deployment, sensitiveVariables map[string]bool) (*Plan, error) {
+	templateHash, err := t.moduleHash()
+	if err != nil {
+		return nil, fmt.Errorf("hashing terraform module: %w", err)
+	}
+
+	values := map[string]any{}
+	planParameters := PlanParameters{}
+	for key, param := range deployment.Parameters {
+		values[key] = param.Value
+
+		if sensitiveVariables[key] || environment.IsSecretKey(key) {
+			planParameters[key] = PlanParameter{Secure: true}
+			continue
+		}
+
+		planParameters[key] = PlanParameter{Value: param.Value}
+	}
+
+	paramsHash, err := HashPlanValue(values)
+	if err != nil {
+		return nil, fmt.Errorf("hashing parameters: %w", err)
+	}
+
+	return &Plan{
+		TemplateHash:   templateHash,
+		ParametersHash: paramsHash,
+		Parameters:     planParameters,
+		Scope: PlanScope{
+			Kind:           "subscription",
+			SubscriptionId: t.env.GetSubscriptionId(),
+			Location:       t.env.GetLocation(),
+		},
+	}, nil
+}
+
+// moduleHash computes a hash of the contents of all the .tf files of the module.
+func (t *TerraformProvider) moduleHash() (string, error) {
+	modulePath := t.modulePath()
+	files, err := os.ReadDir(modulePath)
+	if err != nil {
+		return "", fmt.Errorf("reading .tf files contents: %w", err)
+	}
+
+	hash256 := sha256.New()
+	// os.ReadDir returns the entries sorted by file name, which keeps the hash stable.
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".tf" {
+			continue
+		}
+
+		fileContent, err := os.ReadFile(filepath.Join(modulePath, file.Name()))
+		if err != nil {
+			return "", fmt.Errorf("error reading .tf files: %w", err)
+		}
+
+		if _, err := hash256.Write([]byte(file.Name())); err != nil {
+			return "", err
+		}
+		if _, err := hash256.Write(fileContent); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%x", hash256.Sum(nil)), nil
+}
+
+// previewPlan returns the resource changes of the JSON representation of a terraform plan file.
+func previewPlan(showOutput *terraformPlanShowOutput) *DeploymentPreview {
+	changes := []*DeploymentPreviewChange{}
+	for _, resourceChange := range showOutput.ResourceChanges {
+		if resourceChange.Mode != terraformModeManaged {
+			continue
+		}
+
+		change := &DeploymentPreviewChange{
+			ChangeType:   mapTerraformActionsToChangeType(resourceChange.Change.Actions),
+			ResourceType: resourceChange.Type,
+			Name:         resourceChange.Name,
+			Before:       maskSensitiveValues(resourceChange.Change.Before, resourceChange.Change.BeforeSensitive),
+			After:        maskSensitiveValues(resourceChange.Change.After, resourceChange.Change.AfterSensitive),
+		}
+
+		if before, ok := resourceChange.Change.Before.(map[string]any); ok {
+			if id, ok := before["id"].(string); ok {
+				change.ResourceId = Resource{Id: id}
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	return &DeploymentPreview{
+		Status: "done",
+		Properties: &DeploymentPreviewProperties{
+			Changes: changes,
+		},
+	}
+}
+
+// The value displayed in place of the values of sensitive attributes
+const sensitiveValue = "(sensitive)"
+
+// maskSensitiveValues returns a copy of the value of a resource where the values of the sensitive attributes are masked.
+// sensitive mirrors the shape of the value, with true at the paths of the sensitive attributes, as the
+// `before_sensitive` and `after_sensitive` properties of a planned change.
+func maskSensitiveValues(value any, sensitive any) any {
+	if isSensitive, ok := sensitive.(bool); ok {
+		if isSensitive && value != nil {
+			return sensitiveValue
+		}
+
+		return value
+	}
+
+	switch typedValue := value.(type) {
+	case map[string]any:
+		sensitiveAttributes, _ := sensitive.(map[string]any)
+		masked := make(map[string]any, len(typedValue))
+		for key, attribute := range typedValue {
+			masked[key] = maskSensitiveValues(attribute, sensitiveAttributes[key])
+		}
+
+		return masked
+	case []any:
+		sensitiveItems, _ := sensitive.([]any)
+		masked := make([]any, len(typedValue))
+		for i, item := range typedValue {
+			var sensitiveItem any
+			if i < len(sensitiveItems) {
+				sensitiveItem = sensitiveItems[i]
+			}
+
+			masked[i] = maskSensitiveValues(item, sensitiveItem)
+		}
+
+		return masked
+	default:
+		return value
+	}
+}
+
+// showPlan returns the JSON representation of the plan file
+func (t *TerraformProvider) showPlan(ctx context.Context, planFilePath string) (*terraformPlanShowOutput, error) {
+	runResult, err := t.cli.Show(ctx, t.modulePath(), planFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("showing plan failed: %s, err:%w", runResult, err)
+	}
+
+	var showOutput terraformPlanShowOutput
+	if err := json.Unmarshal([]byte(runResult), &showOutput); err != nil {
+		return nil, err
+	}
+
+	return &showOutput, nil
+}
+
+// mapTerraformActionsToChangeType maps the actions of a terraform resource change to the canonical change type.
+// see https://developer.hashicorp.com/terraform/internals/json-format#change-representation
+func mapTerraformActionsToChangeType(actions []string) ChangeType {
+	switch strings.Join(actions, ",") {
+	case "create":
+		return ChangeTypeCreate
+	case "delete":
+		return ChangeTypeDelete
+	case "update", "delete,create", "create,delete":
+		return ChangeTypeModify
+	case "no-op", "read":
+		return ChangeTypeNoChange
+	}
+
+	return ChangeTypeUnsupported
+}
+
 // Destroys the specified deployment through terraform destroy
 func (t *TerraformProvider) Destroy(ctx context.Context, options DestroyOptions) (*DestroyResult, error) {
 	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
@@ -683,18 +966,28 @@ func (t *TerraformProvider) createInputParametersFile(
 	if err != nil {
 		return fmt.Errorf("reading parameter file template: %w", err)
 	}
+	var resolveErr error
 	replaced, err := envsubst.Eval(string(parametersBytes), func(name string) string {
 		if name == environment.PrincipalIdEnvVarName {
 			return principalId
 		}
 
-		return t.env.Getenv(name)
+		value, err := t.env.ResolvedGetenv(ctx, name)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
+
+		return value
 	})
 
 	if err != nil {
 		return fmt.Errorf("substituting parameter file: %w", err)
 	}
 
+	if resolveErr != nil {
+		return fmt.Errorf("resolving parameter file secrets: %w", resolveErr)
+	}
+
 	writeDir := filepath.Dir(inputFilePath)
 	if err := os.MkdirAll(writeDir, osutil.PermissionDirectory); err != nil {
 		return fmt.Errorf("creating directory structure: %w", err)
@@ -709,6 +1002,55 @@ func (t *TerraformProvider) createInputParametersFile(
 	return nil
 }
 
+// terraformPlanData is the terraform specific data saved in a plan.
+type terraformPlanData struct {
+	// The contents of the plan file written by `terraform plan`.
+	PlanFile []byte `json:"planFile"`
+}
+
+// terraformPlanShowOutput is a model type for the output of `terraform show` for a plan file.
+// see https://developer.hashicorp.com/terraform/internals/json-format#plan-representation for more information on the
+// shape of the JSON data
+type terraformPlanShowOutput struct {
+	FormatVersion   string                    `json:"format_version"`
+	ResourceChanges []terraformResourceChange `json:"resource_changes"`
+	Configuration   struct {
+		RootModule struct {
+			Variables map[string]struct {
+				Sensitive bool `json:"sensitive"`
+			} `json:"variables"`
+		} `json:"root_module"`
+	} `json:"configuration"`
+}
+
+// sensitiveVariables returns the names of the variables of the root module declared with `sensitive = true`
+func (o *terraformPlanShowOutput) sensitiveVariables() map[string]bool {
+	sensitive := map[string]bool{}
+	for name, variable := range o.Configuration.RootModule.Variables {
+		if variable.Sensitive {
+			sensitive[name] = true
+		}
+	}
+
+	return sensitive
+}
+
+// terraformResourceChange is a model type for a planned change to a single resource.
+type terraformResourceChange struct {
+	Address string `json:"address"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Change  struct {
+		Actions []string `json:"actions"`
+		Before  any      `json:"before"`
+		After   any      `json:"after"`
+		// The paths of the sensitive attributes of the resource, in the shape of its values
+		BeforeSensitive any `json:"before_sensitive"`
+		AfterSensitive  any `json:"after_sensitive"`
+	} `json:"change"`
+}
+
 // terraformShowOutput is a model type for the output of `terraform show` for a tfstate file.
 // see https://www.terraform.io/internals/json-format#state-representation for more information on the shape
 // of the JSON data
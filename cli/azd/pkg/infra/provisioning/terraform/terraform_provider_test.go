@@ -6,6 +6,7 @@ package terraform
 import (
 	"context"
 	_ "embed"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
@@ -59,6 +60,77 @@ func TestTerraformPlan(t *testing.T) {
 	require.NotEmpty(t, deploymentPlan.localStateFilePath)
 }
 
+func TestTerraformPlanSensitiveVariables(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	prepareGenericMocks(mockContext.CommandRunner)
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	deployment := &Deployment{
+		Parameters: map[string]InputParameter{
+			"location":       {Value: "westus2"},
+			"admin_password": {Value: "p@ssw0rd"},
+			"db_login":       {Value: "admin"},
+		},
+	}
+
+	plan, err := infraProvider.planFromDeployment(deployment, map[string]bool{"db_login": true})
+	require.NoError(t, err)
+	require.Equal(t, PlanParameters{
+		"location":       {Value: "westus2"},
+		"admin_password": {Secure: true},
+		"db_login":       {Secure: true},
+	}, plan.Parameters)
+
+	// The values of sensitive variables are still part of the hash of the parameters
+	deployment.Parameters["db_login"] = InputParameter{Value: "root"}
+	changed, err := infraProvider.planFromDeployment(deployment, map[string]bool{"db_login": true})
+	require.NoError(t, err)
+	require.NotEqual(t, plan.ParametersHash, changed.ParametersHash)
+}
+
+func TestTerraformPreviewPlanSensitiveValues(t *testing.T) {
+	showOutput := `{
+		"resource_changes": [{
+			"address": "azurerm_postgresql_flexible_server.db",
+			"mode": "managed",
+			"type": "azurerm_postgresql_flexible_server",
+			"name": "db",
+			"change": {
+				"actions": ["update"],
+				"before": {"id": "/subscriptions/SUB/db", "administrator_password": "old", "tags": {"env": "dev"}},
+				"after": {
+					"id": "/subscriptions/SUB/db",
+					"administrator_password": "new",
+					"tags": {"env": "dev"},
+					"connection_strings": ["Password=new", "Host=db"]
+				},
+				"before_sensitive": {"administrator_password": true, "tags": {}},
+				"after_sensitive": {"administrator_password": true, "tags": {}, "connection_strings": [true, false]}
+			}
+		}]
+	}`
+
+	var planShowOutput terraformPlanShowOutput
+	require.NoError(t, json.Unmarshal([]byte(showOutput), &planShowOutput))
+
+	preview := previewPlan(&planShowOutput)
+	require.Len(t, preview.Properties.Changes, 1)
+
+	change := preview.Properties.Changes[0]
+	require.Equal(t, "/subscriptions/SUB/db", change.ResourceId.Id)
+	require.Equal(t, map[string]any{
+		"id":                     "/subscriptions/SUB/db",
+		"administrator_password": "(sensitive)",
+		"tags":                   map[string]any{"env": "dev"},
+	}, change.Before)
+	require.Equal(t, map[string]any{
+		"id":                     "/subscriptions/SUB/db",
+		"administrator_password": "(sensitive)",
+		"tags":                   map[string]any{"env": "dev"},
+		"connection_strings":     []any{"(sensitive)", "Host=db"},
+	}, change.After)
+}
+
 func TestTerraformDestroy(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	prepareGenericMocks(mockContext.CommandRunner)
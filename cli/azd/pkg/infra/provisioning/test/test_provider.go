@@ -108,6 +108,47 @@ func (p *TestProvider) Preview(ctx context.Context) (*DeployPreviewResult, error
 	}, nil
 }
 
+// Plan creates a plan for the test deployment, using the environment location as the only parameter.
+func (p *TestProvider) Plan(ctx context.Context) (*Plan, error) {
+	parameters := PlanParameters{
+		"location": {Value: p.env.GetLocation()},
+	}
+
+	paramsHash, err := HashPlanValue(parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{
+		TemplateHash:   p.options.Module,
+		ParametersHash: paramsHash,
+		Parameters:     parameters,
+		Scope: PlanScope{
+			Kind:           "subscription",
+			SubscriptionId: p.env.GetSubscriptionId(),
+			Location:       p.env.GetLocation(),
+		},
+		Preview: &DeploymentPreview{
+			Status:     "Completed",
+			Properties: &DeploymentPreviewProperties{},
+		},
+	}, nil
+}
+
+// ApplyPlan deploys the test deployment when the plan matches the current plan.
+func (p *TestProvider) ApplyPlan(ctx context.Context, plan *Plan) (*DeployResult, error) {
+	current, err := p.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := plan.Verify(current); err != nil {
+		return nil, err
+	}
+
+	return p.Deploy(ctx)
+}
+
 func (p *TestProvider) Destroy(ctx context.Context, options DestroyOptions) (*DestroyResult, error) {
 	// TODO: progress, "Starting destroy"
 
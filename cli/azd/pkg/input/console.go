@@ -104,6 +104,14 @@ type Console interface {
 	ShowPreviewer(ctx context.Context, options *ShowPreviewerOptions) io.Writer
 	// Finalize the preview mode from console.
 	StopPreviewer(ctx context.Context, keepLogs bool)
+	// Shows a status line for each of the titles instead of a single spinner, to display the progress of operations
+	// running concurrently. Use UpdateStatusLine to update the lines and StopStatusLines to complete them.
+	ShowStatusLines(ctx context.Context, titles []string)
+	// Updates the message of the status line with the given title. The line is running while format is Step,
+	// otherwise the line is completed with the format.
+	UpdateStatusLine(ctx context.Context, title string, message string, format SpinnerUxType)
+	// Completes the status lines. Lines which haven't started are completed as skipped.
+	StopStatusLines(ctx context.Context)
 	// Determines if there is a current spinner running.
 	IsSpinnerRunning(ctx context.Context) bool
 	// Determines if the current spinner is an interactive spinner, where messages are updated periodically.
@@ -157,6 +165,9 @@ type AskerConsole struct {
 
 	previewer *progressLog
 
+	statusLinesMu sync.Mutex // secures statusLines, which are updated concurrently
+	statusLines   *statusLines
+
 	currentIndent *atomic.String
 	// consoleWidth is the width of the underlying console window. The value is updated as the window resized. Nil when
 	// isTerminal is false.
@@ -274,6 +285,12 @@ func (c *AskerConsole) MessageUxItem(ctx context.Context, item ux.UxItem) {
 }
 
 func (c *AskerConsole) println(ctx context.Context, msg string) {
+	if statusLines := c.currentStatusLines(); statusLines != nil {
+		// keep the status lines below any other output
+		statusLines.Println(msg)
+		return
+	}
+
 	if c.IsSpinnerInteractive() && c.spinner.Status() == yacspin.SpinnerRunning {
 		c.StopSpinner(ctx, "", Step)
 		// default non-format
@@ -294,6 +311,16 @@ func (c *AskerConsole) ShowPreviewer(ctx context.Context, options *ShowPreviewer
 	c.showProgressMu.Lock()
 	defer c.showProgressMu.Unlock()
 
+	if statusLines := c.currentStatusLines(); statusLines != nil {
+		// previewer is not compatible with status lines, the output is written above the lines instead.
+		prefix := ""
+		if options != nil {
+			prefix = options.Prefix
+		}
+
+		return &statusLinesWriter{lines: statusLines, prefix: c.currentIndent.Load() + prefix}
+	}
+
 	// Pause any active spinner
 	currentMsg := c.spinnerCurrentTitle
 	_ = c.spinner.Pause()
@@ -311,6 +338,11 @@ func (c *AskerConsole) ShowPreviewer(ctx context.Context, options *ShowPreviewer
 }
 
 func (c *AskerConsole) StopPreviewer(ctx context.Context, keepLogs bool) {
+	if c.previewer == nil {
+		// the previewer wasn't shown, since status lines were displayed
+		return
+	}
+
 	c.previewer.Stop(keepLogs)
 	c.previewer = nil
 	c.writer = c.defaultWriter
@@ -383,6 +415,12 @@ func (c *AskerConsole) ShowSpinner(ctx context.Context, title string, format Spi
 		return
 	}
 
+	if c.currentStatusLines() != nil {
+		// spinner is not compatible with status lines, which display the progress instead.
+		log.Printf("status lines are displayed, skipping spinner '%s'", title)
+		return
+	}
+
 	c.spinnerLineMu.Lock()
 	c.spinnerCurrentTitle = title
 
@@ -470,6 +508,59 @@ func (c *AskerConsole) StopSpinner(ctx context.Context, lastMessage string, form
 	c.spinnerLineMu.Unlock()
 }
 
+func (c *AskerConsole) ShowStatusLines(ctx context.Context, titles []string) {
+	if c.formatter != nil && c.formatter.Kind() == output.JsonFormat {
+		// Status lines are disabled when using json format.
+		return
+	}
+
+	c.showProgressMu.Lock()
+	defer c.showProgressMu.Unlock()
+
+	c.StopSpinner(ctx, "", Step)
+
+	widthFn := func() int {
+		if c.consoleWidth == nil {
+			return 0
+		}
+
+		return int(c.consoleWidth.Load())
+	}
+
+	statusLines := newStatusLines(c.writer, c.IsSpinnerInteractive(), c.getIndent(Step), titles, widthFn)
+	statusLines.Start()
+
+	c.statusLinesMu.Lock()
+	c.statusLines = statusLines
+	c.statusLinesMu.Unlock()
+}
+
+func (c *AskerConsole) UpdateStatusLine(ctx context.Context, title string, message string, format SpinnerUxType) {
+	if statusLines := c.currentStatusLines(); statusLines != nil {
+		statusLines.Update(title, message, format)
+	}
+}
+
+func (c *AskerConsole) StopStatusLines(ctx context.Context) {
+	statusLines := c.currentStatusLines()
+	if statusLines == nil {
+		return
+	}
+
+	statusLines.Stop()
+
+	c.statusLinesMu.Lock()
+	c.statusLines = nil
+	c.statusLinesMu.Unlock()
+}
+
+func (c *AskerConsole) currentStatusLines() *statusLines {
+	c.statusLinesMu.Lock()
+	defer c.statusLinesMu.Unlock()
+
+	return c.statusLines
+}
+
 func (c *AskerConsole) IsSpinnerRunning(ctx context.Context) bool {
 	return c.spinner.Status() != yacspin.SpinnerStopped
 }
@@ -478,21 +569,41 @@ func (c *AskerConsole) IsSpinnerInteractive() bool {
 	return c.spinnerTerminalMode&yacspin.ForceTTYMode > 0
 }
 
-var donePrefix string = output.WithSuccessFormat("(✓) Done:")
+var donePrefix string = output.WithSuccessFormat(stepPrefixText(StepDone))
 
 func (c *AskerConsole) getStopChar(format SpinnerUxType) string {
-	var stopChar string
+	return fmt.Sprintf("%s%s", c.getIndent(format), stepPrefix(format))
+}
+
+// stepPrefix returns the formatted symbol displayed before the message of a completed step
+func stepPrefix(format SpinnerUxType) string {
 	switch format {
 	case StepDone:
-		stopChar = donePrefix
+		return donePrefix
 	case StepFailed:
-		stopChar = output.WithErrorFormat("(x) Failed:")
+		return output.WithErrorFormat(stepPrefixText(format))
 	case StepWarning:
-		stopChar = output.WithWarningFormat("(!) Warning:")
+		return output.WithWarningFormat(stepPrefixText(format))
 	case StepSkipped:
-		stopChar = output.WithGrayFormat("(-) Skipped:")
+		return output.WithGrayFormat(stepPrefixText(format))
+	default:
+		return ""
+	}
+}
+
+func stepPrefixText(format SpinnerUxType) string {
+	switch format {
+	case StepDone:
+		return "(✓) Done:"
+	case StepFailed:
+		return "(x) Failed:"
+	case StepWarning:
+		return "(!) Warning:"
+	case StepSkipped:
+		return "(-) Skipped:"
+	default:
+		return ""
 	}
-	return fmt.Sprintf("%s%s", c.getIndent(format), stopChar)
 }
 
 func promptFromOptions(options ConsoleOptions) survey.Prompt {
@@ -0,0 +1,236 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/mattn/go-runewidth"
+)
+
+/* statusLines displays a line with the status of each item of a set of items progressing concurrently, like
+*
+*  (✓) Done: Deploying service api
+*  (>) Running: Deploying service web (Pushing container image)
+*  ( ) Waiting: Deploying service worker
+*
+* Notes:
+* - On terminals the lines are drawn once and updated in place. Any other output is written above the lines.
+* - Otherwise, a line is written when an item starts and when it completes, so logs remain readable.
+* - Lines are truncated with the symbol `...` at the end when they are wider than the screen.
+ */
+type statusLines struct {
+	writer io.Writer
+	// When true the lines are updated in place
+	interactive bool
+	indent      string
+	// Returns the width of the terminal, lines aren't truncated when it returns a number <= 0
+	widthFn TerminalWidthFn
+	titles  []string
+	items   map[string]*statusLineItem
+	// The number of lines drawn on the screen, which are moved over to update them
+	drawn int
+	// The mutex is used to coordinate updating the items and writing other output
+	mutex sync.Mutex
+}
+
+type statusLineItem struct {
+	message string
+	format  SpinnerUxType
+	started bool
+	stopped bool
+}
+
+var (
+	statusLineWaitingPrefix = output.WithGrayFormat("( ) Waiting:")
+	statusLineRunningPrefix = output.WithHighLightFormat("(>) Running:")
+)
+
+func newStatusLines(
+	writer io.Writer,
+	interactive bool,
+	indent string,
+	titles []string,
+	widthFn TerminalWidthFn,
+) *statusLines {
+	items := make(map[string]*statusLineItem, len(titles))
+	for _, title := range titles {
+		items[title] = &statusLineItem{}
+	}
+
+	return &statusLines{
+		writer:      writer,
+		interactive: interactive,
+		indent:      indent,
+		widthFn:     widthFn,
+		titles:      titles,
+		items:       items,
+	}
+}
+
+// Start draws the initial lines on terminals
+func (s *statusLines) Start() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.interactive {
+		s.draw()
+	}
+}
+
+// Update updates the message and the state of an item. Items are running while format is Step, and completed otherwise.
+// Updating an unknown or completed item is a no-op.
+func (s *statusLines) Update(title string, message string, format SpinnerUxType) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	item, has := s.items[title]
+	if !has || item.stopped {
+		return
+	}
+
+	started := item.started
+	item.started = true
+	item.message = message
+	item.format = format
+	item.stopped = format != Step
+
+	switch {
+	case s.interactive:
+		s.redraw()
+	case item.stopped:
+		fmt.Fprintln(s.writer, s.line(title))
+	case !started:
+		fmt.Fprintln(s.writer, s.indent+title)
+	}
+}
+
+// Stop completes the items which haven't started as skipped and draws the final lines
+func (s *statusLines) Stop() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, title := range s.titles {
+		item := s.items[title]
+		if item.started {
+			continue
+		}
+
+		item.started = true
+		item.stopped = true
+		item.format = StepSkipped
+
+		if !s.interactive {
+			fmt.Fprintln(s.writer, s.line(title))
+		}
+	}
+
+	if s.interactive {
+		s.redraw()
+	}
+}
+
+// Println writes the message above the lines
+func (s *statusLines) Println(message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !s.interactive {
+		fmt.Fprintln(s.writer, message)
+		return
+	}
+
+	s.clear()
+	fmt.Fprintln(s.writer, message)
+	s.draw()
+}
+
+// draw writes the lines at the current position of the cursor
+func (s *statusLines) draw() {
+	for _, title := range s.titles {
+		fmt.Fprintf(s.writer, "\r\033[K%s\n", s.line(title))
+	}
+
+	s.drawn = len(s.titles)
+}
+
+// redraw moves the cursor back over the lines to write them again
+func (s *statusLines) redraw() {
+	if s.drawn > 0 {
+		fmt.Fprintf(s.writer, "\033[%dA", s.drawn)
+	}
+
+	s.draw()
+}
+
+// clear removes the lines from the screen, leaving the cursor where the lines started
+func (s *statusLines) clear() {
+	if s.drawn > 0 {
+		fmt.Fprintf(s.writer, "\033[%dA\r\033[J", s.drawn)
+	}
+
+	s.drawn = 0
+}
+
+// line returns the line of an item, in the format of: <indent><prefix> <title> (<message>)
+func (s *statusLines) line(title string) string {
+	item := s.items[title]
+
+	var prefix string
+	var prefixLen int
+	switch {
+	case item.stopped:
+		prefix = stepPrefix(item.format)
+		prefixLen = runewidth.StringWidth(stepPrefixText(item.format))
+	case item.started:
+		prefix = statusLineRunningPrefix
+		prefixLen = runewidth.StringWidth("(>) Running:")
+	default:
+		prefix = statusLineWaitingPrefix
+		prefixLen = runewidth.StringWidth("( ) Waiting:")
+	}
+
+	text := title
+	if item.message != "" && !item.stopped {
+		text = fmt.Sprintf("%s (%s)", title, item.message)
+	}
+
+	// Adding one for the space between the prefix and the text. The text is truncated by its width on the screen, since
+	// characters may be encoded in several bytes and may be displayed over two columns.
+	if width := s.widthFn(); width > 0 {
+		available := width - runewidth.StringWidth(s.indent) - prefixLen - 1
+		if available > len(cPostfix) {
+			text = runewidth.Truncate(text, available, cPostfix)
+		}
+	}
+
+	return fmt.Sprintf("%s%s %s", s.indent, prefix, text)
+}
+
+// statusLinesWriter writes the output of a previewer shown while status lines are displayed, above the lines
+type statusLinesWriter struct {
+	lines  *statusLines
+	prefix string
+	// Holds the incomplete line until the rest of the line is written
+	buffer bytes.Buffer
+}
+
+func (w *statusLinesWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+
+	for {
+		line, err := w.buffer.ReadString('\n')
+		if err != nil {
+			w.buffer.WriteString(line)
+			return len(p), nil
+		}
+
+		w.lines.Println(w.prefix + strings.TrimRight(line, "\r\n"))
+	}
+}
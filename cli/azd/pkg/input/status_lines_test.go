@@ -0,0 +1,105 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package input
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_statusLines(t *testing.T) {
+	titles := []string{"Deploying service api", "Deploying service web", "Deploying service worker"}
+	noWidth := func() int { return 0 }
+
+	t.Run("NoTerminal", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		lines := newStatusLines(buffer, false, "  ", titles, noWidth)
+
+		lines.Start()
+		lines.Update(titles[0], "", Step)
+		lines.Update(titles[1], "", Step)
+		lines.Update(titles[0], "Pushing container image", Step)
+		lines.Println("  - Endpoint: https://api.contoso.com")
+		lines.Update(titles[0], "", StepDone)
+		lines.Update(titles[1], "", StepFailed)
+		lines.Stop()
+
+		require.Equal(t, []string{
+			"  Deploying service api",
+			"  Deploying service web",
+			"  - Endpoint: https://api.contoso.com",
+			"  (✓) Done: Deploying service api",
+			"  (x) Failed: Deploying service web",
+			"  (-) Skipped: Deploying service worker",
+		}, strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n"))
+	})
+
+	t.Run("Terminal", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		lines := newStatusLines(buffer, true, "  ", titles, noWidth)
+
+		lines.Start()
+		require.Equal(t,
+			"\r\033[K  ( ) Waiting: Deploying service api\n"+
+				"\r\033[K  ( ) Waiting: Deploying service web\n"+
+				"\r\033[K  ( ) Waiting: Deploying service worker\n",
+			buffer.String())
+
+		buffer.Reset()
+		lines.Update(titles[1], "Pushing container image", Step)
+		require.Equal(t,
+			"\033[3A"+
+				"\r\033[K  ( ) Waiting: Deploying service api\n"+
+				"\r\033[K  (>) Running: Deploying service web (Pushing container image)\n"+
+				"\r\033[K  ( ) Waiting: Deploying service worker\n",
+			buffer.String())
+
+		buffer.Reset()
+		lines.Println("message")
+		require.True(t, strings.HasPrefix(buffer.String(), "\033[3A\r\033[Jmessage\n"))
+
+		buffer.Reset()
+		lines.Update(titles[1], "", StepDone)
+		lines.Update(titles[1], "", StepFailed)
+		lines.Stop()
+		require.True(t, strings.HasSuffix(buffer.String(),
+			"\033[3A"+
+				"\r\033[K  (-) Skipped: Deploying service api\n"+
+				"\r\033[K  (✓) Done: Deploying service web\n"+
+				"\r\033[K  (-) Skipped: Deploying service worker\n"))
+	})
+
+	t.Run("Truncate", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		lines := newStatusLines(buffer, false, "  ", titles, func() int { return 30 })
+
+		lines.Update(titles[0], "", StepDone)
+		require.Equal(t, "  (✓) Done: Deploying servi...\n", buffer.String())
+
+		// Wide characters are displayed over two columns
+		buffer.Reset()
+		wideTitles := []string{"サービスをデプロイしています"}
+		lines = newStatusLines(buffer, false, "  ", wideTitles, func() int { return 30 })
+
+		lines.Update(wideTitles[0], "", StepDone)
+		require.Equal(t, "  (✓) Done: サービスをデプ...\n", buffer.String())
+	})
+
+	t.Run("Writer", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		lines := newStatusLines(buffer, false, "  ", titles, noWidth)
+		writer := &statusLinesWriter{lines: lines, prefix: "    "}
+
+		_, err := io.WriteString(writer, "first\r\nsec")
+		require.NoError(t, err)
+		_, err = io.WriteString(writer, "ond\n")
+		require.NoError(t, err)
+
+		require.Equal(t, "    first\n    second\n", buffer.String())
+	})
+}
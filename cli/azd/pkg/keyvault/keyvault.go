@@ -12,6 +12,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
 	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/convert"
 )
 
@@ -40,12 +41,32 @@ type KeyVaultService interface {
 		resourceGroupName string,
 		vaultName string,
 	) (*KeyVault, error)
+	// ListKeyVaults lists the key vaults of the subscription
+	ListKeyVaults(ctx context.Context, subscriptionId string) ([]*KeyVault, error)
 	GetKeyVaultSecret(
 		ctx context.Context,
 		subscriptionId string,
 		vaultName string,
 		secretName string,
 	) (*Secret, error)
+	// GetKeyVaultSecretVersion gets the given version of the secret, or its current version when version is empty
+	GetKeyVaultSecretVersion(
+		ctx context.Context,
+		subscriptionId string,
+		vaultName string,
+		secretName string,
+		version string,
+	) (*Secret, error)
+	// ListKeyVaultSecrets lists the names of the secrets of the key vault
+	ListKeyVaultSecrets(ctx context.Context, subscriptionId string, vaultName string) ([]string, error)
+	// CreateKeyVaultSecret sets the value of the secret, creating a new version of the secret when it already exists
+	CreateKeyVaultSecret(
+		ctx context.Context,
+		subscriptionId string,
+		vaultName string,
+		secretName string,
+		value string,
+	) error
 	PurgeKeyVault(ctx context.Context, subscriptionId string, vaultName string, location string) error
 }
 
@@ -53,6 +74,7 @@ type keyVaultService struct {
 	credentialProvider account.SubscriptionCredentialProvider
 	armClientOptions   *arm.ClientOptions
 	coreClientOptions  *azcore.ClientOptions
+	cloud              *cloud.Cloud
 }
 
 // NewKeyVaultService creates a new KeyVault service
@@ -60,11 +82,13 @@ func NewKeyVaultService(
 	credentialProvider account.SubscriptionCredentialProvider,
 	armClientOptions *arm.ClientOptions,
 	coreClientOptions *azcore.ClientOptions,
+	cloud *cloud.Cloud,
 ) KeyVaultService {
 	return &keyVaultService{
 		credentialProvider: credentialProvider,
 		armClientOptions:   armClientOptions,
 		coreClientOptions:  coreClientOptions,
+		cloud:              cloud,
 	}
 }
 
@@ -84,18 +108,44 @@ func (kvs *keyVaultService) GetKeyVault(
 		return nil, fmt.Errorf("getting key vault: %w", err)
 	}
 
-	return &KeyVault{
-		Id:       *vault.ID,
-		Name:     *vault.Name,
-		Location: *vault.Location,
-		Properties: struct {
-			EnableSoftDelete      bool "json:\"enableSoftDelete\""
-			EnablePurgeProtection bool "json:\"enablePurgeProtection\""
-		}{
-			EnableSoftDelete:      convert.ToValueWithDefault(vault.Properties.EnableSoftDelete, false),
-			EnablePurgeProtection: convert.ToValueWithDefault(vault.Properties.EnablePurgeProtection, false),
-		},
-	}, nil
+	return newKeyVault(&vault.Vault), nil
+}
+
+func (kvs *keyVaultService) ListKeyVaults(ctx context.Context, subscriptionId string) ([]*KeyVault, error) {
+	client, err := kvs.createKeyVaultClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	vaults := []*KeyVault{}
+	pager := client.NewListBySubscriptionPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing key vaults: %w", err)
+		}
+
+		for _, vault := range page.Value {
+			vaults = append(vaults, newKeyVault(vault))
+		}
+	}
+
+	return vaults, nil
+}
+
+func newKeyVault(vault *armkeyvault.Vault) *KeyVault {
+	keyVault := &KeyVault{
+		Id:       convert.ToValueWithDefault(vault.ID, ""),
+		Name:     convert.ToValueWithDefault(vault.Name, ""),
+		Location: convert.ToValueWithDefault(vault.Location, ""),
+	}
+
+	if vault.Properties != nil {
+		keyVault.Properties.EnableSoftDelete = convert.ToValueWithDefault(vault.Properties.EnableSoftDelete, false)
+		keyVault.Properties.EnablePurgeProtection = convert.ToValueWithDefault(vault.Properties.EnablePurgeProtection, false)
+	}
+
+	return keyVault
 }
 
 func (kvs *keyVaultService) GetKeyVaultSecret(
@@ -104,17 +154,22 @@ func (kvs *keyVaultService) GetKeyVaultSecret(
 	vaultName string,
 	secretName string,
 ) (*Secret, error) {
-	vaultUrl := vaultName
-	if !strings.Contains(strings.ToLower(vaultName), "https://") {
-		vaultUrl = fmt.Sprintf("https://%s.vault.azure.net", vaultName)
-	}
+	return kvs.GetKeyVaultSecretVersion(ctx, subscriptionId, vaultName, secretName, "")
+}
 
-	client, err := kvs.createSecretsDataClient(ctx, subscriptionId, vaultUrl)
+func (kvs *keyVaultService) GetKeyVaultSecretVersion(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+	version string,
+) (*Secret, error) {
+	client, err := kvs.createSecretsDataClient(ctx, subscriptionId, kvs.vaultUrl(vaultName))
 	if err != nil {
-		return nil, nil
+		return nil, err
 	}
 
-	response, err := client.GetSecret(ctx, secretName, "", nil)
+	response, err := client.GetSecret(ctx, secretName, version, nil)
 	if err != nil {
 		var httpErr *azcore.ResponseError
 		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
@@ -130,6 +185,54 @@ func (kvs *keyVaultService) GetKeyVaultSecret(
 	}, nil
 }
 
+func (kvs *keyVaultService) ListKeyVaultSecrets(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+) ([]string, error) {
+	client, err := kvs.createSecretsDataClient(ctx, subscriptionId, kvs.vaultUrl(vaultName))
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := []string{}
+	pager := client.NewListSecretsPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing key vault secrets: %w", err)
+		}
+
+		for _, secret := range page.Value {
+			if secret.ID != nil {
+				secrets = append(secrets, secret.ID.Name())
+			}
+		}
+	}
+
+	return secrets, nil
+}
+
+func (kvs *keyVaultService) CreateKeyVaultSecret(
+	ctx context.Context,
+	subscriptionId string,
+	vaultName string,
+	secretName string,
+	value string,
+) error {
+	client, err := kvs.createSecretsDataClient(ctx, subscriptionId, kvs.vaultUrl(vaultName))
+	if err != nil {
+		return err
+	}
+
+	_, err = client.SetSecret(ctx, secretName, azsecrets.SetSecretParameters{Value: &value}, nil)
+	if err != nil {
+		return fmt.Errorf("setting key vault secret: %w", err)
+	}
+
+	return nil
+}
+
 func (kvs *keyVaultService) PurgeKeyVault(
 	ctx context.Context, subscriptionId string, vaultName string, location string) error {
 	client, err := kvs.createKeyVaultClient(ctx, subscriptionId)
@@ -166,6 +269,15 @@ func (kvs *keyVaultService) createKeyVaultClient(
 	return client, nil
 }
 
+// vaultUrl returns the url of the key vault, which may be given by its name or its url
+func (kvs *keyVaultService) vaultUrl(vaultName string) string {
+	if strings.Contains(strings.ToLower(vaultName), "https://") {
+		return vaultName
+	}
+
+	return fmt.Sprintf("https://%s.%s", vaultName, kvs.cloud.KeyVaultEndpointSuffix)
+}
+
 // Creates a KeyVault client for data plan operations
 // Data plane client is able to fetch secret values. ARM control plane client never returns secret values.
 func (kvs *keyVaultService) createSecretsDataClient(
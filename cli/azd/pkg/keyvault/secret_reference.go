@@ -0,0 +1,109 @@
+package keyvault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SecretReferencePrefix is the scheme of the environment values referencing a key vault secret, such as
+// `akvs://<vault>/<secret>` or `akvs://<vault>/<secret>/<version>`
+const SecretReferencePrefix = "akvs://"
+
+// SecretReference references a secret of a key vault
+type SecretReference struct {
+	VaultName  string
+	SecretName string
+	// The version of the secret, the current version of the secret is used when empty
+	Version string
+}
+
+// String returns the reference as stored in the environment
+func (r *SecretReference) String() string {
+	reference := fmt.Sprintf("%s%s/%s", SecretReferencePrefix, r.VaultName, r.SecretName)
+	if r.Version != "" {
+		reference += "/" + r.Version
+	}
+
+	return reference
+}
+
+// IsSecretReference returns true when the value references a key vault secret
+func IsSecretReference(value string) bool {
+	return strings.HasPrefix(value, SecretReferencePrefix)
+}
+
+// SecretNameFromKey returns the default name of the secret storing the value of an environment key, as secret names only
+// allow alphanumeric characters and dashes
+func SecretNameFromKey(key string) string {
+	return strings.ToLower(strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+
+		return '-'
+	}, key))
+}
+
+// SecretNameFromEnvironmentKey returns the name of the secret storing the value of a key of the environment, which is
+// prefixed by the name of the environment so that environments sharing a key vault don't overwrite each other's secrets
+func SecretNameFromEnvironmentKey(envName string, key string) string {
+	return SecretNameFromKey(fmt.Sprintf("%s-%s", envName, key))
+}
+
+// ParseSecretReference parses a reference such as `akvs://<vault>/<secret>[/<version>]`
+func ParseSecretReference(reference string) (*SecretReference, error) {
+	if !IsSecretReference(reference) {
+		return nil, fmt.Errorf("invalid secret reference '%s', expected '%s<vault>/<secret>'", reference, SecretReferencePrefix)
+	}
+
+	parts := strings.Split(strings.TrimPrefix(reference, SecretReferencePrefix), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf(
+			"invalid secret reference '%s', expected '%s<vault>/<secret>[/<version>]'", reference, SecretReferencePrefix)
+	}
+
+	secretReference := &SecretReference{
+		VaultName:  parts[0],
+		SecretName: parts[1],
+	}
+
+	if len(parts) == 3 {
+		secretReference.Version = parts[2]
+	}
+
+	return secretReference, nil
+}
+
+// SecretResolver resolves the environment values referencing key vault secrets to the values of the secrets
+type SecretResolver struct {
+	keyVaultService KeyVaultService
+}
+
+func NewSecretResolver(keyVaultService KeyVaultService) *SecretResolver {
+	return &SecretResolver{
+		keyVaultService: keyVaultService,
+	}
+}
+
+// IsSecretReference returns true when the value references a key vault secret
+func (r *SecretResolver) IsSecretReference(value string) bool {
+	return IsSecretReference(value)
+}
+
+// ResolveSecret gets the value of the referenced secret, from a key vault of the subscription
+func (r *SecretResolver) ResolveSecret(ctx context.Context, subscriptionId string, reference string) (string, error) {
+	secretReference, err := ParseSecretReference(reference)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := r.keyVaultService.GetKeyVaultSecretVersion(
+		ctx, subscriptionId, secretReference.VaultName, secretReference.SecretName, secretReference.Version)
+	if err != nil {
+		return "", fmt.Errorf("getting secret '%s' of key vault '%s': %w",
+			secretReference.SecretName, secretReference.VaultName, err)
+	}
+
+	return secret.Value, nil
+}
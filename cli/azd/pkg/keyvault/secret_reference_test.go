@@ -0,0 +1,44 @@
+package keyvault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseSecretReference(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := map[string]*SecretReference{
+			"akvs://contoso/db-password":        {VaultName: "contoso", SecretName: "db-password"},
+			"akvs://contoso/db-password/abc123": {VaultName: "contoso", SecretName: "db-password", Version: "abc123"},
+		}
+
+		for reference, expected := range tests {
+			actual, err := ParseSecretReference(reference)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+			require.Equal(t, reference, actual.String())
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, reference := range []string{
+			"contoso/db-password",
+			"akvs://contoso",
+			"akvs://contoso/",
+			"akvs:///db-password",
+			"akvs://contoso/db-password/abc123/extra",
+		} {
+			_, err := ParseSecretReference(reference)
+			require.Error(t, err, reference)
+		}
+	})
+}
+
+func Test_SecretNameFromKey(t *testing.T) {
+	require.Equal(t, "db-password", SecretNameFromKey("DB_PASSWORD"))
+	require.Equal(t, "dev-eastus-db-password", SecretNameFromEnvironmentKey("dev.eastus", "DB_PASSWORD"))
+	require.NotEqual(t,
+		SecretNameFromEnvironmentKey("dev", "DB_PASSWORD"),
+		SecretNameFromEnvironmentKey("prod", "DB_PASSWORD"))
+}
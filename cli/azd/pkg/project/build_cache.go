@@ -0,0 +1,250 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// BuildCacheFileName is the name of the file, within the environment directory, persisting the build cache
+const BuildCacheFileName = "build-cache.json"
+
+// BuildCache persists the results of building, packaging and deploying the services of an environment, along with the
+// hash of the service they were produced for. The ServiceManager skips an operation, returning its previous result
+// marked as unchanged, when the service still has the same hash.
+type BuildCache struct {
+	path string
+
+	mutex   sync.Mutex
+	entries map[string]*buildCacheEntry
+}
+
+// buildCacheEntry is the result of an operation for a service
+type buildCacheEntry struct {
+	Hash string `json:"hash"`
+	// The resource the service was deployed to, deployments are only skipped when the target is unchanged
+	TargetResource string          `json:"targetResource,omitempty"`
+	Result         json.RawMessage `json:"result"`
+	// The docker details of the result, which the result itself can't restore since its details are untyped
+	DockerBuild   *dockerBuildResult   `json:"dockerBuild,omitempty"`
+	DockerPackage *dockerPackageResult `json:"dockerPackage,omitempty"`
+}
+
+// NewBuildCache creates a build cache persisted to the specified file
+func NewBuildCache(path string) *BuildCache {
+	return &BuildCache{
+		path: path,
+	}
+}
+
+// Invalidate removes the results of the operations of the service, so they run again
+func (c *BuildCache) Invalidate(serviceName string, operations ...ext.Event) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	changed := false
+	for _, operation := range operations {
+		key := buildCacheKey(serviceName, string(operation))
+		if _, has := c.entries[key]; has {
+			delete(c.entries, key)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return c.save()
+}
+
+// InvalidateAll removes the results of the operations of all the services, such as when their resources are deleted or
+// may have been recreated
+func (c *BuildCache) InvalidateAll(operations ...ext.Event) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	changed := false
+	for key := range c.entries {
+		for _, operation := range operations {
+			if strings.HasSuffix(key, ":"+string(operation)) {
+				delete(c.entries, key)
+				changed = true
+			}
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return c.save()
+}
+
+// get returns the result of the operation for the service when it was produced for the specified hash
+func (c *BuildCache) get(serviceName string, operation string, hash string) (*buildCacheEntry, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	entry, has := c.entries[buildCacheKey(serviceName, operation)]
+	if !has || entry.Hash != hash {
+		return nil, nil
+	}
+
+	return entry, nil
+}
+
+// set records the result of the operation for the service
+func (c *BuildCache) set(serviceName string, operation string, entry *buildCacheEntry) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if err := c.load(); err != nil {
+		return err
+	}
+
+	c.entries[buildCacheKey(serviceName, operation)] = entry
+	return c.save()
+}
+
+func (c *BuildCache) load() error {
+	if c.entries != nil {
+		return nil
+	}
+
+	entries := map[string]*buildCacheEntry{}
+
+	content, err := os.ReadFile(c.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading build cache: %w", err)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(content, &entries); err != nil {
+			return fmt.Errorf("parsing build cache %s: %w", c.path, err)
+		}
+	}
+
+	c.entries = entries
+	return nil
+}
+
+func (c *BuildCache) save() error {
+	content, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling build cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating build cache directory: %w", err)
+	}
+
+	if err := os.WriteFile(c.path, content, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing build cache: %w", err)
+	}
+
+	return nil
+}
+
+func buildCacheKey(serviceName string, operation string) string {
+	return fmt.Sprintf("%s:%s", serviceName, operation)
+}
+
+func newBuildCacheEntry(hash string, targetResource string, result any) (*buildCacheEntry, error) {
+	content, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &buildCacheEntry{
+		Hash:           hash,
+		TargetResource: targetResource,
+		Result:         content,
+	}
+
+	switch result := result.(type) {
+	case *ServiceBuildResult:
+		entry.DockerBuild, _ = result.Details.(*dockerBuildResult)
+	case *ServicePackageResult:
+		entry.DockerPackage, _ = result.Details.(*dockerPackageResult)
+	}
+
+	return entry, nil
+}
+
+// buildResult returns the cached build result when its output still exists
+func (e *buildCacheEntry) buildResult() (*ServiceBuildResult, bool) {
+	result := &ServiceBuildResult{}
+	if err := json.Unmarshal(e.Result, result); err != nil || !artifactExists(result.BuildOutputPath) {
+		return nil, false
+	}
+
+	result.Restore = nil
+	result.Details = nil
+	if e.DockerBuild != nil {
+		result.Details = e.DockerBuild
+	}
+
+	result.Unchanged = true
+	return result, true
+}
+
+// packageResult returns the cached package result when its package still exists
+func (e *buildCacheEntry) packageResult() (*ServicePackageResult, bool) {
+	result := &ServicePackageResult{}
+	if err := json.Unmarshal(e.Result, result); err != nil || !artifactExists(result.PackagePath) {
+		return nil, false
+	}
+
+	result.Build = nil
+	result.Details = nil
+	if e.DockerPackage != nil {
+		result.Details = e.DockerPackage
+	}
+
+	result.Unchanged = true
+	return result, true
+}
+
+// deployResult returns the cached deploy result
+func (e *buildCacheEntry) deployResult() (*ServiceDeployResult, bool) {
+	result := &ServiceDeployResult{}
+	if err := json.Unmarshal(e.Result, result); err != nil {
+		return nil, false
+	}
+
+	result.Package = nil
+	result.Details = nil
+	result.Unchanged = true
+	return result, true
+}
+
+// artifactExists returns false when the artifact is a file which no longer exists.
+// Other artifacts, such as container images, are expected to still exist.
+func artifactExists(path string) bool {
+	if path == "" || !filepath.IsAbs(path) {
+		return true
+	}
+
+	_, err := os.Stat(path)
+	return err == nil
+}
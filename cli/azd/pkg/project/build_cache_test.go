@@ -0,0 +1,109 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BuildCache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "dev", BuildCacheFileName)
+
+	packageResult := &ServicePackageResult{
+		PackagePath: "api:azd-deploy-1",
+		Details: &dockerPackageResult{
+			ImageHash:   "sha256:1234",
+			TargetImage: "api:azd-deploy-1",
+		},
+	}
+
+	entry, err := newBuildCacheEntry("hash1", "", packageResult)
+	require.NoError(t, err)
+	require.NoError(t, NewBuildCache(cachePath).set("api", string(ServiceEventPackage), entry))
+
+	t.Run("SameHash", func(t *testing.T) {
+		entry, err := NewBuildCache(cachePath).get("api", string(ServiceEventPackage), "hash1")
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+
+		cached, ok := entry.packageResult()
+		require.True(t, ok)
+		require.True(t, cached.Unchanged)
+		require.Equal(t, "api:azd-deploy-1", cached.PackagePath)
+		require.Equal(t, packageResult.Details, cached.Details)
+	})
+
+	t.Run("DifferentHash", func(t *testing.T) {
+		entry, err := NewBuildCache(cachePath).get("api", string(ServiceEventPackage), "hash2")
+		require.NoError(t, err)
+		require.Nil(t, entry)
+	})
+
+	t.Run("MissingPackageFile", func(t *testing.T) {
+		entry, err := newBuildCacheEntry("hash1", "", &ServicePackageResult{
+			PackagePath: filepath.Join(t.TempDir(), "missing.zip"),
+		})
+		require.NoError(t, err)
+
+		_, ok := entry.packageResult()
+		require.False(t, ok)
+	})
+
+	t.Run("Invalidate", func(t *testing.T) {
+		cache := NewBuildCache(cachePath)
+		require.NoError(t, cache.Invalidate("api", ServiceEventBuild, ServiceEventPackage))
+
+		entry, err := NewBuildCache(cachePath).get("api", string(ServiceEventPackage), "hash1")
+		require.NoError(t, err)
+		require.Nil(t, entry)
+	})
+}
+
+func Test_BuildCache_Deploy(t *testing.T) {
+	packagePath := filepath.Join(t.TempDir(), "api.zip")
+	require.NoError(t, os.WriteFile(packagePath, []byte("zip"), 0600))
+
+	entry, err := newBuildCacheEntry("hash1", "sub/rg/type/api", &ServiceDeployResult{
+		Package:          &ServicePackageResult{PackagePath: packagePath},
+		TargetResourceId: "/subscriptions/sub/resourceGroups/rg/providers/type/api",
+		Kind:             AppServiceTarget,
+		Endpoints:        []string{"https://api.azurewebsites.net/"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "sub/rg/type/api", entry.TargetResource)
+
+	cached, ok := entry.deployResult()
+	require.True(t, ok)
+	require.True(t, cached.Unchanged)
+	require.Nil(t, cached.Package)
+	require.Equal(t, []string{"https://api.azurewebsites.net/"}, cached.Endpoints)
+	require.Contains(t, cached.ToString(""), "No changes since the last deployment")
+}
+
+func Test_BuildCache_InvalidateAll(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "dev", BuildCacheFileName)
+
+	for _, serviceName := range []string{"api", "web"} {
+		for _, operation := range []ext.Event{ServiceEventPackage, ServiceEventDeploy} {
+			entry, err := newBuildCacheEntry("hash1", "", &ServiceDeployResult{})
+			require.NoError(t, err)
+			require.NoError(t, NewBuildCache(cachePath).set(serviceName, string(operation), entry))
+		}
+	}
+
+	require.NoError(t, NewBuildCache(cachePath).InvalidateAll(ServiceEventDeploy))
+
+	cache := NewBuildCache(cachePath)
+	for _, serviceName := range []string{"api", "web"} {
+		entry, err := cache.get(serviceName, string(ServiceEventDeploy), "hash1")
+		require.NoError(t, err)
+		require.Nil(t, entry)
+
+		entry, err = cache.get(serviceName, string(ServiceEventPackage), "hash1")
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+	}
+}
@@ -2,15 +2,23 @@ package project
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/rzip"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
@@ -24,6 +32,8 @@ type ContainerHelper struct {
 	docker                   docker.Docker
 	clock                    clock.Clock
 	cloud                    *cloud.Cloud
+	console                  input.Console
+	supplyChain              *ImageSupplyChain
 }
 
 func NewContainerHelper(
@@ -33,6 +43,8 @@ func NewContainerHelper(
 	containerRegistryService azcli.ContainerRegistryService,
 	docker docker.Docker,
 	cloud *cloud.Cloud,
+	console input.Console,
+	supplyChain *ImageSupplyChain,
 ) *ContainerHelper {
 	return &ContainerHelper{
 		env:                      env,
@@ -41,6 +53,8 @@ func NewContainerHelper(
 		docker:                   docker,
 		clock:                    clock,
 		cloud:                    cloud,
+		console:                  console,
+		supplyChain:              supplyChain,
 	}
 }
 
@@ -234,10 +248,46 @@ func (ch *ContainerHelper) Deploy(
 
 			// Default to the local image tag
 			remoteImage := targetImage
+			remoteImageDigest := ""
+			pushed := false
+
+			if ok && packageDetails != nil && packageDetails.RemoteImage != "" {
+				// The image was already pushed by a previous deployment, such as when rolling back
+				remoteImage = packageDetails.RemoteImage
+				if strings.Contains(remoteImage, "@") {
+					remoteImageDigest = remoteImage
+				}
+			} else if ok && packageDetails != nil && packageDetails.RemoteBuild {
+				// The image is built and pushed by the container registry, without a local Docker daemon
+				if registryName == "" {
+					task.SetError(errors.New("a container registry is required to build images remotely"))
+					return
+				}
+
+				remoteImage, remoteImageDigest, err = ch.remoteBuild(ctx, serviceConfig, targetImage, registryName, task)
+				if err != nil {
+					task.SetError(err)
+					return
+				}
+
+				pushed = true
+			} else if ok && packageDetails != nil && len(packageDetails.Platforms) > 1 {
+				// A multi-platform image only exists in the build cache, so it is built again and pushed as a manifest list
+				if registryName == "" {
+					task.SetError(errors.New("a container registry is required to deploy an image built for several platforms"))
+					return
+				}
 
-			// If we don't have a registry specified and the service does not reference a project path
-			// then we are referencing a public/pre-existing image and don't have anything to tag or push
-			if registryName == "" && serviceConfig.RelativePath == "" && sourceImage != "" {
+				remoteImage, remoteImageDigest, err = ch.pushMultiPlatformImage(ctx, serviceConfig, targetImage, task)
+				if err != nil {
+					task.SetError(err)
+					return
+				}
+
+				pushed = true
+			} else if registryName == "" && serviceConfig.RelativePath == "" && sourceImage != "" {
+				// If we don't have a registry specified and the service does not reference a project path
+				// then we are referencing a public/pre-existing image and don't have anything to tag or push
 				remoteImage = sourceImage
 			} else {
 				if targetImage == "" {
@@ -297,13 +347,32 @@ func (ch *ContainerHelper) Deploy(
 						task.SetError(errSuggestion)
 						return
 					}
+
+					remoteImageDigest = ch.remoteImageDigest(ctx, remoteImage)
+					pushed = true
+				}
+			}
+
+			if pushed && hasImageSupplyChain(serviceConfig) {
+				if err := ch.publishSupplyChain(ctx, serviceConfig, registryName, remoteImageDigest, task); err != nil {
+					task.SetError(err)
+					return
 				}
 			}
 
 			if writeImageToEnv {
 				// Save the name of the image we pushed into the environment with a well known key.
 				log.Printf("writing image name to environment")
-				ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", remoteImage)
+				// Images with an SBOM or a signature are deployed by digest, which is what they refer to
+				if remoteImageDigest != "" && hasImageSupplyChain(serviceConfig) {
+					ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", remoteImageDigest)
+				} else {
+					ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_NAME", remoteImage)
+				}
+
+				if remoteImageDigest != "" {
+					ch.env.SetServiceProperty(serviceConfig.Name, "IMAGE_DIGEST", remoteImageDigest)
+				}
 
 				if err := ch.envManager.Save(ctx, ch.env); err != nil {
 					task.SetError(fmt.Errorf("saving image name to environment: %w", err))
@@ -314,12 +383,268 @@ func (ch *ContainerHelper) Deploy(
 			task.SetResult(&ServiceDeployResult{
 				Package: packageOutput,
 				Details: &dockerDeployResult{
-					RemoteImageTag: remoteImage,
+					RemoteImageTag:    remoteImage,
+					RemoteImageDigest: remoteImageDigest,
 				},
 			})
 		})
 }
 
+// publishSupplyChain publishes the SBOM and the signature of the pushed image, which is referenced by its digest
+func (ch *ContainerHelper) publishSupplyChain(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	registryName string,
+	remoteImageDigest string,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) error {
+	if remoteImageDigest == "" {
+		return fmt.Errorf("the digest of the image of service '%s' is required to publish its sbom and signature, but "+
+			"it couldn't be determined once pushed", serviceConfig.Name)
+	}
+
+	credentials, err := ch.containerRegistryService.Credentials(ctx, ch.env.GetSubscriptionId(), registryName)
+	if err != nil {
+		return err
+	}
+
+	return ch.supplyChain.Publish(ctx, serviceConfig, remoteImageDigest, credentials, func(message string) {
+		task.SetProgress(NewServiceProgress(message))
+	})
+}
+
+// remoteBuild uploads the build context of the service to the container registry, which builds and pushes the image with
+// ACR Tasks. The logs of the build are displayed as it runs. The remote image and the remote image referenced by its digest
+// are returned.
+func (ch *ContainerHelper) remoteBuild(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetImage string,
+	loginServer string,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) (string, string, error) {
+	remoteImage, err := ch.RemoteImageTag(ctx, serviceConfig, targetImage)
+	if err != nil {
+		return "", "", fmt.Errorf("getting remote image tag: %w", err)
+	}
+
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	if err := dockerOptions.validateRemoteBuild(); err != nil {
+		return "", "", err
+	}
+
+	buildContext := dockerOptions.Context
+	if !filepath.IsAbs(buildContext) {
+		buildContext = filepath.Join(serviceConfig.Path(), buildContext)
+	}
+
+	dockerfile := dockerOptions.Path
+	if !filepath.IsAbs(dockerfile) {
+		dockerfile = filepath.Join(serviceConfig.Path(), dockerfile)
+	}
+
+	task.SetProgress(NewServiceProgress("Uploading build context"))
+	source, dockerfilePath, err := createBuildContextArchive(buildContext, dockerfile)
+	if err != nil {
+		return "", "", err
+	}
+	defer func() {
+		source.Close()
+		os.Remove(source.Name())
+	}()
+
+	log.Printf("building %s remotely in registry '%s'", remoteImage, loginServer)
+	task.SetProgress(NewServiceProgress("Building container image remotely"))
+	previewerWriter := ch.console.ShowPreviewer(ctx,
+		&input.ShowPreviewerOptions{
+			Prefix:       "  ",
+			MaxLineCount: 8,
+			Title:        "Remote Build Output",
+		})
+	result, err := ch.containerRegistryService.RemoteBuild(
+		ctx,
+		ch.env.GetSubscriptionId(),
+		loginServer,
+		&azcli.RemoteBuildRequest{
+			Source:         source,
+			DockerfilePath: dockerfilePath,
+			ImageNames:     []string{remoteImage},
+			Platform:       dockerOptions.platform(),
+			Target:         dockerOptions.Target,
+			BuildArgs:      dockerOptions.BuildArgs,
+		},
+		previewerWriter,
+	)
+	ch.console.StopPreviewer(ctx, false)
+	if err != nil {
+		return "", "", fmt.Errorf("building image remotely: %w", err)
+	}
+
+	remoteImageDigest := ""
+	if result.Digest != "" {
+		remoteImageDigest = fmt.Sprintf("%s@%s", imageRepository(remoteImage), result.Digest)
+	}
+
+	return remoteImage, remoteImageDigest, nil
+}
+
+// createBuildContextArchive writes the build context to a temporary gzipped tarball, excluding the files ignored by the
+// .dockerignore file of the context like docker does. The path of the Dockerfile within the context is returned along
+// with the tarball, which is positioned at its start.
+func createBuildContextArchive(buildContext string, dockerfile string) (*os.File, string, error) {
+	dockerfilePath, err := filepath.Rel(buildContext, dockerfile)
+	if err != nil || !isWithinDir(dockerfile, buildContext) {
+		return nil, "", fmt.Errorf(
+			"remote builds require the Dockerfile '%s' to be within the docker context '%s'", dockerfile, buildContext)
+	}
+
+	dockerfilePath = filepath.ToSlash(dockerfilePath)
+
+	rules := ignoreRules{}
+	if err := rules.load(filepath.Join(buildContext, ".dockerignore"), "", true); err != nil {
+		return nil, "", err
+	}
+
+	archive, err := os.CreateTemp("", "azd-build-context-*.tar.gz")
+	if err != nil {
+		return nil, "", fmt.Errorf("creating build context archive: %w", err)
+	}
+
+	err = rzip.CreateTarGzFromDirectory(buildContext, archive, func(relPath string, isDir bool) bool {
+		// The Dockerfile is always part of the build context, and the environments of azd never are
+		if relPath == dockerfilePath || (isDir && strings.HasPrefix(dockerfilePath, relPath+"/")) {
+			return false
+		}
+
+		if isDir && relPath == ".azure" {
+			return true
+		}
+
+		// The directories containing the Dockerfile are walked even when ignored, so their files are checked too
+		for dir := relPath; dir != "."; dir = path.Dir(dir) {
+			if rules.ignored(dir, isDir || dir != relPath) {
+				return true
+			}
+		}
+
+		return false
+	})
+	if err == nil {
+		_, err = archive.Seek(0, io.SeekStart)
+	}
+
+	if err != nil {
+		archive.Close()
+		os.Remove(archive.Name())
+		return nil, "", fmt.Errorf("creating build context archive: %w", err)
+	}
+
+	return archive, dockerfilePath, nil
+}
+
+// pushMultiPlatformImage builds the image of the service for all its platforms and pushes the resulting manifest list to
+// the registry, returning the remote image and the remote image referenced by its digest. The build reuses the layers of
+// the build cache populated when packaging the service.
+func (ch *ContainerHelper) pushMultiPlatformImage(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetImage string,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) (string, string, error) {
+	remoteImage, err := ch.RemoteImageTag(ctx, serviceConfig, targetImage)
+	if err != nil {
+		return "", "", fmt.Errorf("getting remote image tag: %w", err)
+	}
+
+	task.SetProgress(NewServiceProgress("Logging into container registry"))
+	if _, err := ch.Login(ctx, serviceConfig); err != nil {
+		return "", "", err
+	}
+
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	buildOptions, err := dockerOptions.buildOptions(ctx, ch.env)
+	if err != nil {
+		return "", "", err
+	}
+	buildOptions.Push = true
+
+	log.Printf("pushing %s for platforms %s to registry", remoteImage, dockerOptions.platform())
+	task.SetProgress(NewServiceProgress("Pushing multi-platform container image"))
+	digest, err := ch.docker.Build(
+		ctx,
+		serviceConfig.Path(),
+		dockerOptions.Path,
+		dockerOptions.platform(),
+		dockerOptions.Target,
+		dockerOptions.Context,
+		remoteImage,
+		dockerOptions.BuildArgs,
+		buildOptions,
+		nil,
+	)
+	if err != nil {
+		return "", "", fmt.Errorf("pushing multi-platform image: %w", err)
+	}
+
+	// The digest of the manifest list references the image for all its platforms
+	remoteImageDigest := ""
+	if digest != "" {
+		remoteImageDigest = fmt.Sprintf("%s@%s", imageRepository(remoteImage), digest)
+	}
+
+	return remoteImage, remoteImageDigest, nil
+}
+
+// remoteImageDigest returns the pushed image referenced by its digest, or an empty string when the digest isn't known
+func (ch *ContainerHelper) remoteImageDigest(ctx context.Context, remoteImage string) string {
+	out, err := ch.docker.Inspect(ctx, remoteImage, "{{json .RepoDigests}}")
+	if err != nil {
+		log.Printf("failed inspecting image '%s': %v", remoteImage, err)
+		return ""
+	}
+
+	var repoDigests []string
+	if err := json.Unmarshal([]byte(out), &repoDigests); err != nil {
+		log.Printf("failed parsing digests of image '%s': %v", remoteImage, err)
+		return ""
+	}
+
+	// The digests are listed by repository
+	repository := imageRepository(remoteImage)
+
+	for _, repoDigest := range repoDigests {
+		if strings.HasPrefix(repoDigest, repository+"@") {
+			return repoDigest
+		}
+	}
+
+	return ""
+}
+
 type dockerDeployResult struct {
 	RemoteImageTag string
+	// The pushed image referenced by its digest, when it is known
+	RemoteImageDigest string
+}
+
+// withoutLocalDocker removes Docker from the tools required by the service when its image is built remotely, so the
+// service can be deployed without a local Docker daemon
+func withoutLocalDocker(serviceConfig *ServiceConfig, requiredTools []tools.ExternalTool) []tools.ExternalTool {
+	if !serviceConfig.Docker.RemoteBuild {
+		return requiredTools
+	}
+
+	return slices.DeleteFunc(slices.Clone(requiredTools), func(tool tools.ExternalTool) bool {
+		_, isDocker := tool.(docker.Docker)
+		return isDocker
+	})
+}
+
+// imageRepository returns the image without its tag
+func imageRepository(image string) string {
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i]
+	}
+
+	return image
 }
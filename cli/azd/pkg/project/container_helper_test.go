@@ -1,9 +1,14 @@
 package project
 
 import (
+	"archive/tar"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -16,6 +21,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 	"github.com/benbjohnson/clock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -58,7 +64,7 @@ func Test_ContainerHelper_LocalImageTag(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			env := environment.NewWithValues("dev", map[string]string{})
-			containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic())
+			containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 			serviceConfig.Docker = tt.dockerConfig
 
 			tag, err := containerHelper.LocalImageTag(*mockContext.Context, serviceConfig)
@@ -107,7 +113,7 @@ func Test_ContainerHelper_RemoteImageTag(t *testing.T) {
 
 	mockContext := mocks.NewMockContext(context.Background())
 	env := environment.NewWithValues("dev", map[string]string{})
-	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic())
+	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -134,7 +140,7 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 			environment.ContainerRegistryEndpointEnvVarName: "contoso.azurecr.io",
 		})
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
 
@@ -146,7 +152,7 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		env := environment.NewWithValues("dev", map[string]string{})
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
@@ -160,7 +166,7 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 		env := environment.NewWithValues("dev", map[string]string{})
 		env.DotenvSet("MY_CUSTOM_REGISTRY", "custom.azurecr.io")
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		serviceConfig.Docker.Registry = osutil.NewExpandableString("${MY_CUSTOM_REGISTRY}")
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
@@ -173,7 +179,7 @@ func Test_ContainerHelper_Resolve_RegistryName(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		env := environment.NewWithValues("dev", map[string]string{})
 		envManager := &mockenv.MockEnvManager{}
-		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic())
+		containerHelper := NewContainerHelper(env, envManager, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 		registryName, err := containerHelper.RegistryName(*mockContext.Context, serviceConfig)
 
@@ -191,6 +197,7 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 		packagePath             string
 		dockerDetails           *dockerPackageResult
 		expectedRemoteImage     string
+		expectedDigest          string
 		expectDockerLoginCalled bool
 		expectDockerPullCalled  bool
 		expectDockerTagCalled   bool
@@ -211,6 +218,22 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 			expectDockerTagCalled:   true,
 			expectDockerPushCalled:  true,
 			expectedRemoteImage:     "contoso.azurecr.io/my-project/my-service:azd-deploy-0",
+			expectedDigest:          "contoso.azurecr.io/my-project/my-service@sha256:DIGEST",
+			expectError:             false,
+		},
+		{
+			name:     "Previously pushed image",
+			project:  "./src/api",
+			registry: osutil.NewExpandableString("contoso.azurecr.io"),
+			dockerDetails: &dockerPackageResult{
+				RemoteImage: "contoso.azurecr.io/my-project/my-service@sha256:DIGEST",
+			},
+			expectDockerLoginCalled: false,
+			expectDockerPullCalled:  false,
+			expectDockerTagCalled:   false,
+			expectDockerPushCalled:  false,
+			expectedRemoteImage:     "contoso.azurecr.io/my-project/my-service@sha256:DIGEST",
+			expectedDigest:          "contoso.azurecr.io/my-project/my-service@sha256:DIGEST",
 			expectError:             false,
 		},
 		{
@@ -343,6 +366,8 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 				mockContainerRegistryService,
 				dockerCli,
 				cloud.AzurePublic(),
+				mockinput.NewMockConsole(),
+				nil,
 			)
 			serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
 
@@ -369,6 +394,9 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 					dockerDeployResult, ok := deployResult.Details.(*dockerDeployResult)
 					require.True(t, ok)
 					require.Equal(t, tt.expectedRemoteImage, dockerDeployResult.RemoteImageTag)
+					if tt.expectedDigest != "" {
+						require.Equal(t, tt.expectedDigest, dockerDeployResult.RemoteImageDigest)
+					}
 				}
 			}
 
@@ -399,10 +427,98 @@ func Test_ContainerHelper_Deploy(t *testing.T) {
 	}
 }
 
+func Test_ContainerHelper_RemoteBuild(t *testing.T) {
+	mockContext := mocks.NewMockContext(context.Background())
+	env := environment.NewWithValues("dev", map[string]string{})
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", *mockContext.Context, env).Return(nil)
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = t.TempDir()
+	serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+	serviceConfig.Docker.RemoteBuild = true
+	serviceConfig.Docker.BuildArgs = []string{"VERSION=1.0"}
+
+	for name, content := range map[string]string{
+		"Dockerfile":      "FROM node:20",
+		".dockerignore":   "*.env\nDockerfile",
+		"app.js":          "console.log('api')",
+		"local.env":       "SECRET=value",
+		".azure/dev/.env": "AZURE_ENV_NAME=dev",
+	} {
+		filePath := filepath.Join(serviceConfig.Path(), filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(filePath), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(filePath, []byte(content), osutil.PermissionFile))
+	}
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	uploaded := []string{}
+	mockContainerRegistryService.On(
+		"RemoteBuild",
+		*mockContext.Context,
+		env.GetSubscriptionId(),
+		"contoso.azurecr.io",
+		mock.AnythingOfType("*azcli.RemoteBuildRequest"),
+		mock.Anything,
+	).Run(func(args mock.Arguments) {
+		request := args.Get(3).(*azcli.RemoteBuildRequest)
+
+		gzipReader, err := gzip.NewReader(request.Source)
+		require.NoError(t, err)
+
+		tarReader := tar.NewReader(gzipReader)
+		for {
+			header, err := tarReader.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			require.NoError(t, err)
+			uploaded = append(uploaded, header.Name)
+		}
+	}).Return(&azcli.RemoteBuildResult{RunId: "ca1", Digest: "sha256:remote"}, nil)
+
+	containerHelper := NewContainerHelper(
+		env,
+		envManager,
+		clock.NewMock(),
+		mockContainerRegistryService,
+		nil,
+		cloud.AzurePublic(),
+		mockinput.NewMockConsole(),
+		nil,
+	)
+
+	packageOutput := &ServicePackageResult{
+		Details: &dockerPackageResult{
+			TargetImage: "test-app/api-dev:azd-deploy-0",
+			RemoteBuild: true,
+		},
+	}
+
+	deployTask := containerHelper.Deploy(*mockContext.Context, serviceConfig, packageOutput, nil, true)
+	logProgress(deployTask)
+	deployResult, err := deployTask.Await()
+	require.NoError(t, err)
+
+	dockerDeployResult := deployResult.Details.(*dockerDeployResult)
+	require.Equal(t, "contoso.azurecr.io/test-app/api-dev:azd-deploy-0", dockerDeployResult.RemoteImageTag)
+	require.Equal(t, "contoso.azurecr.io/test-app/api-dev@sha256:remote", dockerDeployResult.RemoteImageDigest)
+
+	// The ignored files and the environments aren't uploaded, unlike the Dockerfile
+	require.ElementsMatch(t, []string{".dockerignore", "Dockerfile", "app.js"}, uploaded)
+
+	request := mockContainerRegistryService.Calls[0].Arguments.Get(3).(*azcli.RemoteBuildRequest)
+	require.Equal(t, "Dockerfile", request.DockerfilePath)
+	require.Equal(t, []string{"contoso.azurecr.io/test-app/api-dev:azd-deploy-0"}, request.ImageNames)
+	require.Equal(t, docker.DefaultPlatform, request.Platform)
+	require.Equal(t, []string{"VERSION=1.0"}, request.BuildArgs)
+}
+
 func Test_ContainerHelper_ConfiguredImage(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	env := environment.NewWithValues("dev", map[string]string{})
-	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic())
+	containerHelper := NewContainerHelper(env, nil, clock.NewMock(), nil, nil, cloud.AzurePublic(), nil, nil)
 
 	tests := []struct {
 		name                 string
@@ -574,6 +690,16 @@ func setupDockerMocks(mockContext *mocks.MockContext) map[string]exec.RunArgs {
 		return exec.NewRunResult(0, "", ""), nil
 	})
 
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker image inspect")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["docker-inspect"] = args
+
+		image := args.Args[len(args.Args)-1]
+		repository := image[:strings.LastIndex(image, ":")]
+		return exec.NewRunResult(0, fmt.Sprintf(`["%s@sha256:DIGEST"]`, repository), ""), nil
+	})
+
 	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
 		return strings.Contains(command, "docker pull")
 	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
@@ -621,3 +747,14 @@ func (m *mockContainerRegistryService) GetContainerRegistries(
 	args := m.Called(ctx, subscriptionId)
 	return args.Get(0).([]*armcontainerregistry.Registry), args.Error(1)
 }
+
+func (m *mockContainerRegistryService) RemoteBuild(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	request *azcli.RemoteBuildRequest,
+	logs io.Writer,
+) (*azcli.RemoteBuildResult, error) {
+	args := m.Called(ctx, subscriptionId, loginServer, request, logs)
+	return args.Get(0).(*azcli.RemoteBuildResult), args.Error(1)
+}
@@ -0,0 +1,275 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// DeploymentHistoryFileName is the name of the file, within the environment directory, recording the deployments
+const DeploymentHistoryFileName = "deployment-history.json"
+
+// The name of the folder, within the environment directory, retaining the deployed packages
+const deploymentPackagesDirName = "deployments"
+
+// The number of deployments recorded for each service
+const deploymentHistoryLimit = 5
+
+// ErrNoPreviousDeployment is returned when a service has no recorded deployment to roll back to
+var ErrNoPreviousDeployment = errors.New("no previous deployment was recorded")
+
+// DeploymentArtifact is what was deployed for a service, which can be deployed again without being built
+type DeploymentArtifact struct {
+	// The container image, referenced by its digest when it is known
+	Image string `json:"image,omitempty"`
+	// The container app revision which served the image
+	Revision string `json:"revision,omitempty"`
+	// The copy of the package retained by azd, such as a zip package
+	PackagePath string `json:"packagePath,omitempty"`
+}
+
+// newContainerDeploymentArtifact returns the artifact of an image deployed by the ContainerHelper
+func newContainerDeploymentArtifact(containerDeployResult *ServiceDeployResult, revision string) *DeploymentArtifact {
+	dockerDeployResult, ok := containerDeployResult.Details.(*dockerDeployResult)
+	if !ok || dockerDeployResult.RemoteImageTag == "" {
+		return nil
+	}
+
+	artifact := &DeploymentArtifact{
+		Image:    dockerDeployResult.RemoteImageDigest,
+		Revision: revision,
+	}
+
+	if artifact.Image == "" {
+		artifact.Image = dockerDeployResult.RemoteImageTag
+	}
+
+	return artifact
+}
+
+// DeploymentRecord is a deployment of a service
+type DeploymentRecord struct {
+	Timestamp        time.Time          `json:"timestamp"`
+	Kind             ServiceTargetKind  `json:"kind"`
+	TargetResourceId string             `json:"targetResourceId"`
+	Artifact         DeploymentArtifact `json:"artifact"`
+}
+
+// PackageResult returns the package which deploys the artifact of the record again. Retained packages are copied, since
+// service targets remove the packages once deployed.
+func (r *DeploymentRecord) PackageResult() (*ServicePackageResult, error) {
+	if r.Artifact.Image != "" {
+		return &ServicePackageResult{
+			PackagePath: r.Artifact.Image,
+			Details: &dockerPackageResult{
+				RemoteImage: r.Artifact.Image,
+			},
+		}, nil
+	}
+
+	source, err := os.Open(r.Artifact.PackagePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening retained package: %w", err)
+	}
+	defer source.Close()
+
+	target, err := os.CreateTemp("", "azd-rollback-*"+filepath.Ext(r.Artifact.PackagePath))
+	if err != nil {
+		return nil, fmt.Errorf("creating package: %w", err)
+	}
+	defer target.Close()
+
+	if _, err := io.Copy(target, source); err != nil {
+		return nil, fmt.Errorf("copying retained package: %w", err)
+	}
+
+	return &ServicePackageResult{
+		PackagePath: target.Name(),
+	}, nil
+}
+
+// DeploymentHistory records the last deployments of the services of an environment, retaining their packages, so a
+// service can be rolled back to its previous deployment.
+type DeploymentHistory struct {
+	path string
+
+	mutex   sync.Mutex
+	records map[string][]*DeploymentRecord
+}
+
+// NewDeploymentHistory creates a deployment history persisted to the specified file. The packages are retained next to
+// the file.
+func NewDeploymentHistory(path string) *DeploymentHistory {
+	return &DeploymentHistory{
+		path: path,
+	}
+}
+
+// RetainPackage copies the package of a service before it is deployed, since service targets remove the packages once
+// deployed. An empty path is returned when the package isn't a file, such as a container image or a folder.
+func (h *DeploymentHistory) RetainPackage(serviceName string, packagePath string) (string, error) {
+	info, err := os.Stat(packagePath)
+	if packagePath == "" || err != nil || !info.Mode().IsRegular() {
+		return "", nil
+	}
+
+	source, err := os.Open(packagePath)
+	if err != nil {
+		return "", fmt.Errorf("opening package: %w", err)
+	}
+	defer source.Close()
+
+	dir := filepath.Join(filepath.Dir(h.path), deploymentPackagesDirName, serviceName)
+	if err := os.MkdirAll(dir, osutil.PermissionDirectory); err != nil {
+		return "", fmt.Errorf("creating deployments directory: %w", err)
+	}
+
+	target, err := os.CreateTemp(dir, "package-*"+filepath.Ext(packagePath))
+	if err != nil {
+		return "", fmt.Errorf("creating retained package: %w", err)
+	}
+	defer target.Close()
+
+	if _, err := io.Copy(target, source); err != nil {
+		return "", fmt.Errorf("retaining package: %w", err)
+	}
+
+	return target.Name(), nil
+}
+
+// Record adds the deployment to the history of the service, along with the retained package. Deployments without an
+// artifact, or which were skipped, aren't recorded and their retained package is removed.
+func (h *DeploymentHistory) Record(
+	serviceName string,
+	deployResult *ServiceDeployResult,
+	retainedPackage string,
+) error {
+	record := &DeploymentRecord{
+		Timestamp:        time.Now(),
+		Kind:             deployResult.Kind,
+		TargetResourceId: deployResult.TargetResourceId,
+	}
+
+	if deployResult.Artifact != nil {
+		record.Artifact = *deployResult.Artifact
+	} else {
+		record.Artifact.PackagePath = retainedPackage
+	}
+
+	if record.Artifact.PackagePath != retainedPackage {
+		removeRetainedPackage(retainedPackage)
+	}
+
+	if deployResult.Unchanged || (record.Artifact.Image == "" && record.Artifact.PackagePath == "") {
+		removeRetainedPackage(record.Artifact.PackagePath)
+		return nil
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err := h.load(); err != nil {
+		return err
+	}
+
+	records := append(h.records[serviceName], record)
+	if len(records) > deploymentHistoryLimit {
+		for _, expired := range records[:len(records)-deploymentHistoryLimit] {
+			removeRetainedPackage(expired.Artifact.PackagePath)
+		}
+
+		records = records[len(records)-deploymentHistoryLimit:]
+	}
+
+	h.records[serviceName] = records
+	return h.save()
+}
+
+// Previous returns the deployment preceding the last deployment of the service, which the service is rolled back to
+func (h *DeploymentHistory) Previous(serviceName string) (*DeploymentRecord, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err := h.load(); err != nil {
+		return nil, err
+	}
+
+	records := h.records[serviceName]
+	if len(records) < 2 {
+		return nil, ErrNoPreviousDeployment
+	}
+
+	return records[len(records)-2], nil
+}
+
+// RemoveLast removes the last deployment of the service once it was rolled back, so the previous deployment becomes the
+// last deployment.
+func (h *DeploymentHistory) RemoveLast(serviceName string) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if err := h.load(); err != nil {
+		return err
+	}
+
+	records := h.records[serviceName]
+	if len(records) == 0 {
+		return nil
+	}
+
+	removeRetainedPackage(records[len(records)-1].Artifact.PackagePath)
+	h.records[serviceName] = records[:len(records)-1]
+	return h.save()
+}
+
+func (h *DeploymentHistory) load() error {
+	if h.records != nil {
+		return nil
+	}
+
+	records := map[string][]*DeploymentRecord{}
+
+	content, err := os.ReadFile(h.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading deployment history: %w", err)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(content, &records); err != nil {
+			return fmt.Errorf("parsing deployment history %s: %w", h.path, err)
+		}
+	}
+
+	h.records = records
+	return nil
+}
+
+func (h *DeploymentHistory) save() error {
+	content, err := json.MarshalIndent(h.records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling deployment history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating deployment history directory: %w", err)
+	}
+
+	if err := os.WriteFile(h.path, content, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing deployment history: %w", err)
+	}
+
+	return nil
+}
+
+func removeRetainedPackage(path string) {
+	if path != "" {
+		_ = os.Remove(path)
+	}
+}
@@ -0,0 +1,121 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DeploymentHistory(t *testing.T) {
+	t.Run("RecordAndRollBack", func(t *testing.T) {
+		dir := t.TempDir()
+		history := NewDeploymentHistory(filepath.Join(dir, DeploymentHistoryFileName))
+
+		for _, image := range []string{"app@sha256:v1", "app@sha256:v2"} {
+			err := history.Record("api", &ServiceDeployResult{
+				Kind:     ContainerAppTarget,
+				Artifact: &DeploymentArtifact{Image: image},
+			}, "")
+			require.NoError(t, err)
+		}
+
+		// Reload from disk
+		history = NewDeploymentHistory(filepath.Join(dir, DeploymentHistoryFileName))
+		previous, err := history.Previous("api")
+		require.NoError(t, err)
+		require.Equal(t, "app@sha256:v1", previous.Artifact.Image)
+
+		packageResult, err := previous.PackageResult()
+		require.NoError(t, err)
+		require.Equal(t, "app@sha256:v1", packageResult.PackagePath)
+		require.Equal(t, "app@sha256:v1", packageResult.Details.(*dockerPackageResult).RemoteImage)
+
+		require.NoError(t, history.RemoveLast("api"))
+		_, err = history.Previous("api")
+		require.ErrorIs(t, err, ErrNoPreviousDeployment)
+	})
+
+	t.Run("RetainPackage", func(t *testing.T) {
+		dir := t.TempDir()
+		history := NewDeploymentHistory(filepath.Join(dir, DeploymentHistoryFileName))
+
+		packagePath := filepath.Join(t.TempDir(), "api.zip")
+		require.NoError(t, os.WriteFile(packagePath, []byte("v1"), 0600))
+
+		retainedPackage, err := history.RetainPackage("api", packagePath)
+		require.NoError(t, err)
+		require.FileExists(t, retainedPackage)
+
+		// The service target removes the package once deployed
+		require.NoError(t, os.Remove(packagePath))
+
+		err = history.Record("api", &ServiceDeployResult{Kind: AppServiceTarget}, retainedPackage)
+		require.NoError(t, err)
+
+		require.NoError(t, os.WriteFile(packagePath, []byte("v2"), 0600))
+		nextPackage, err := history.RetainPackage("api", packagePath)
+		require.NoError(t, err)
+		err = history.Record("api", &ServiceDeployResult{Kind: AppServiceTarget}, nextPackage)
+		require.NoError(t, err)
+
+		previous, err := history.Previous("api")
+		require.NoError(t, err)
+
+		packageResult, err := previous.PackageResult()
+		require.NoError(t, err)
+		defer os.Remove(packageResult.PackagePath)
+
+		// The retained package is copied, since it is removed once deployed again
+		require.NotEqual(t, retainedPackage, packageResult.PackagePath)
+		content, err := os.ReadFile(packageResult.PackagePath)
+		require.NoError(t, err)
+		require.Equal(t, "v1", string(content))
+	})
+
+	t.Run("FolderPackageNotRetained", func(t *testing.T) {
+		history := NewDeploymentHistory(filepath.Join(t.TempDir(), DeploymentHistoryFileName))
+
+		retainedPackage, err := history.RetainPackage("web", t.TempDir())
+		require.NoError(t, err)
+		require.Empty(t, retainedPackage)
+	})
+
+	t.Run("UnchangedNotRecorded", func(t *testing.T) {
+		dir := t.TempDir()
+		history := NewDeploymentHistory(filepath.Join(dir, DeploymentHistoryFileName))
+
+		packagePath := filepath.Join(t.TempDir(), "api.zip")
+		require.NoError(t, os.WriteFile(packagePath, []byte("v1"), 0600))
+		retainedPackage, err := history.RetainPackage("api", packagePath)
+		require.NoError(t, err)
+
+		err = history.Record("api", &ServiceDeployResult{Kind: AppServiceTarget, Unchanged: true}, retainedPackage)
+		require.NoError(t, err)
+		require.NoFileExists(t, retainedPackage)
+		require.NoFileExists(t, filepath.Join(dir, DeploymentHistoryFileName))
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		dir := t.TempDir()
+		history := NewDeploymentHistory(filepath.Join(dir, DeploymentHistoryFileName))
+
+		retainedPackages := []string{}
+		for i := 0; i < deploymentHistoryLimit+1; i++ {
+			packagePath := filepath.Join(t.TempDir(), "api.zip")
+			require.NoError(t, os.WriteFile(packagePath, []byte("content"), 0600))
+
+			retainedPackage, err := history.RetainPackage("api", packagePath)
+			require.NoError(t, err)
+			retainedPackages = append(retainedPackages, retainedPackage)
+
+			err = history.Record("api", &ServiceDeployResult{Kind: AppServiceTarget}, retainedPackage)
+			require.NoError(t, err)
+		}
+
+		require.Len(t, history.records["api"], deploymentHistoryLimit)
+		require.NoFileExists(t, retainedPackages[0])
+		require.FileExists(t, retainedPackages[1])
+	})
+}
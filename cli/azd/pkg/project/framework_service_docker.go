@@ -33,19 +33,128 @@ import (
 )
 
 type DockerProjectOptions struct {
-	Path      string                  `yaml:"path,omitempty"      json:"path,omitempty"`
-	Context   string                  `yaml:"context,omitempty"   json:"context,omitempty"`
-	Platform  string                  `yaml:"platform,omitempty"  json:"platform,omitempty"`
+	Path     string `yaml:"path,omitempty"     json:"path,omitempty"`
+	Context  string `yaml:"context,omitempty"  json:"context,omitempty"`
+	Platform string `yaml:"platform,omitempty" json:"platform,omitempty"`
+	// The platforms of a multi-platform image, which take precedence over Platform. Images built for several platforms
+	// are pushed as a manifest list.
+	Platforms []string                `yaml:"platforms,omitempty" json:"platforms,omitempty"`
 	Target    string                  `yaml:"target,omitempty"    json:"target,omitempty"`
 	Registry  osutil.ExpandableString `yaml:"registry,omitempty"  json:"registry,omitempty"`
 	Image     osutil.ExpandableString `yaml:"image,omitempty"     json:"image,omitempty"`
 	Tag       osutil.ExpandableString `yaml:"tag,omitempty"       json:"tag,omitempty"`
 	BuildArgs []string                `yaml:"buildArgs,omitempty" json:"buildArgs,omitempty"`
+	// The BuildKit secrets of the build, mapping the id of each secret to the environment variable holding its value
+	Secrets map[string]string `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	// The SSH agent sockets or keys forwarded to the build, as `default` or `<id>=<path>`
+	Ssh []string `yaml:"ssh,omitempty" json:"ssh,omitempty"`
+	// The external cache sources of the build, such as `type=registry,ref=<image>`
+	CacheFrom []osutil.ExpandableString `yaml:"cacheFrom,omitempty" json:"cacheFrom,omitempty"`
+	// The cache export destinations of the build, such as `type=registry,ref=<image>,mode=max`
+	CacheTo []osutil.ExpandableString `yaml:"cacheTo,omitempty" json:"cacheTo,omitempty"`
+	// RemoteBuild builds the image with ACR Tasks in the container registry when deploying, instead of building it with
+	// the local Docker daemon
+	RemoteBuild bool `yaml:"remoteBuild,omitempty" json:"remoteBuild,omitempty"`
+	// The SBOM generated for the image once pushed, and attached to the image in the registry
+	Sbom *ImageSbomOptions `yaml:"sbom,omitempty" json:"sbom,omitempty"`
+	// The signing of the image once pushed
+	Sign *ImageSignOptions `yaml:"sign,omitempty" json:"sign,omitempty"`
+}
+
+// platform returns the platforms the image is built for, separated by commas
+func (o DockerProjectOptions) platform() string {
+	if len(o.Platforms) > 0 {
+		return strings.Join(o.Platforms, ",")
+	}
+
+	return o.Platform
+}
+
+// isMultiPlatform returns true when the image is built for several platforms, producing a manifest list
+func (o DockerProjectOptions) isMultiPlatform() bool {
+	return len(o.Platforms) > 1
+}
+
+// validateRemoteBuild returns an error when the image uses build features which aren't supported by remote builds
+func (o DockerProjectOptions) validateRemoteBuild() error {
+	unsupported := []string{}
+
+	if o.isMultiPlatform() {
+		unsupported = append(unsupported, "platforms")
+	}
+
+	if len(o.Secrets) > 0 {
+		unsupported = append(unsupported, "secrets")
+	}
+
+	if len(o.Ssh) > 0 {
+		unsupported = append(unsupported, "ssh")
+	}
+
+	if len(o.CacheFrom) > 0 || len(o.CacheTo) > 0 {
+		unsupported = append(unsupported, "cacheFrom", "cacheTo")
+	}
+
+	if len(unsupported) > 0 {
+		return fmt.Errorf("remote builds don't support the docker options: %s", strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// buildOptions returns the BuildKit options of the build, with the values of the secrets and of the cache references
+// read from the environment. The values of the build secrets referencing Key Vault secrets are resolved.
+func (o DockerProjectOptions) buildOptions(ctx context.Context, env *environment.Environment) (docker.BuildOptions, error) {
+	options := docker.BuildOptions{
+		Ssh: o.Ssh,
+	}
+
+	if len(o.Secrets) > 0 {
+		options.Secrets = map[string]string{}
+	}
+
+	for id, envName := range o.Secrets {
+		if _, has := env.LookupEnv(envName); !has {
+			return docker.BuildOptions{}, fmt.Errorf(
+				"the build secret '%s' reads the '%s' environment variable, which is not set", id, envName)
+		}
+
+		value, err := env.ResolvedGetenv(ctx, envName)
+		if err != nil {
+			return docker.BuildOptions{}, fmt.Errorf("resolving build secret '%s': %w", id, err)
+		}
+
+		options.Secrets[id] = value
+	}
+
+	for _, cacheFrom := range o.CacheFrom {
+		value, err := cacheFrom.Envsubst(env.Getenv)
+		if err != nil {
+			return docker.BuildOptions{}, fmt.Errorf("resolving cache source: %w", err)
+		}
+
+		options.CacheFrom = append(options.CacheFrom, value)
+	}
+
+	for _, cacheTo := range o.CacheTo {
+		value, err := cacheTo.Envsubst(env.Getenv)
+		if err != nil {
+			return docker.BuildOptions{}, fmt.Errorf("resolving cache destination: %w", err)
+		}
+
+		options.CacheTo = append(options.CacheTo, value)
+	}
+
+	return options, nil
 }
 
 type dockerBuildResult struct {
 	ImageId   string `json:"imageId"`
 	ImageName string `json:"imageName"`
+	// The platforms of a multi-platform image, which is only kept in the build cache until it is pushed
+	Platforms []string `json:"platforms,omitempty"`
+	// RemoteBuild is true when the image is built in the container registry when deploying
+	RemoteBuild bool `json:"remoteBuild,omitempty"`
 }
 
 func (dbr *dockerBuildResult) ToString(currentIndentation string) string {
@@ -68,6 +177,12 @@ type dockerPackageResult struct {
 	SourceImage string `json:"sourceImage"`
 	// The target image with tag that is used for publishing and deployment when targeting a container registry
 	TargetImage string `json:"targetImage"`
+	// The image already published to the container registry, which is deployed without being tagged and pushed again
+	RemoteImage string `json:"remoteImage,omitempty"`
+	// The platforms of a multi-platform image, which is built again from the build cache and pushed on deploy
+	Platforms []string `json:"platforms,omitempty"`
+	// RemoteBuild is true when the image is built in the container registry when deploying
+	RemoteBuild bool `json:"remoteBuild,omitempty"`
 }
 
 func (dpr *dockerPackageResult) ToString(currentIndentation string) string {
@@ -231,6 +346,28 @@ func (p *dockerProject) Build(
 				return
 			}
 
+			if dockerOptions.RemoteBuild {
+				// The image is built in the container registry when deploying
+				if errors.Is(err, os.ErrNotExist) {
+					task.SetError(fmt.Errorf("remote builds require a Dockerfile, '%s' was not found", path))
+					return
+				}
+
+				if err := dockerOptions.validateRemoteBuild(); err != nil {
+					task.SetError(err)
+					return
+				}
+
+				task.SetResult(&ServiceBuildResult{
+					Restore: restoreOutput,
+					Details: &dockerBuildResult{
+						ImageName:   imageName,
+						RemoteBuild: true,
+					},
+				})
+				return
+			}
+
 			if errors.Is(err, os.ErrNotExist) {
 				// Build the container from source
 				task.SetProgress(NewServiceProgress("Building Docker image from source"))
@@ -245,6 +382,12 @@ func (p *dockerProject) Build(
 				return
 			}
 
+			buildOptions, err := dockerOptions.buildOptions(ctx, p.env)
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
 			// Build the container
 			task.SetProgress(NewServiceProgress("Building Docker image"))
 			previewerWriter := p.console.ShowPreviewer(ctx,
@@ -257,11 +400,12 @@ func (p *dockerProject) Build(
 				ctx,
 				serviceConfig.Path(),
 				dockerOptions.Path,
-				dockerOptions.Platform,
+				dockerOptions.platform(),
 				dockerOptions.Target,
 				dockerOptions.Context,
 				imageName,
 				dockerOptions.BuildArgs,
+				buildOptions,
 				previewerWriter,
 			)
 			p.console.StopPreviewer(ctx, false)
@@ -270,14 +414,20 @@ func (p *dockerProject) Build(
 				return
 			}
 
+			buildResult := &dockerBuildResult{
+				ImageId:   imageId,
+				ImageName: imageName,
+			}
+
+			if dockerOptions.isMultiPlatform() {
+				buildResult.Platforms = dockerOptions.Platforms
+			}
+
 			log.Printf("built image %s for %s", imageId, serviceConfig.Name)
 			task.SetResult(&ServiceBuildResult{
 				Restore:         restoreOutput,
 				BuildOutputPath: imageId,
-				Details: &dockerBuildResult{
-					ImageId:   imageId,
-					ImageName: imageName,
-				},
+				Details:         buildResult,
 			})
 		},
 	)
@@ -294,6 +444,26 @@ func (p *dockerProject) Package(
 
 			if buildOutput != nil {
 				imageId = buildOutput.BuildOutputPath
+
+				// Multi-platform images and images built remotely aren't in the local image store, so they can't be tagged
+				buildDetails, ok := buildOutput.Details.(*dockerBuildResult)
+				if ok && (len(buildDetails.Platforms) > 1 || buildDetails.RemoteBuild) {
+					imageWithTag, err := p.containerHelper.LocalImageTag(ctx, serviceConfig)
+					if err != nil {
+						task.SetError(fmt.Errorf("generating local image tag: %w", err))
+						return
+					}
+
+					task.SetResult(&ServicePackageResult{
+						Build: buildOutput,
+						Details: &dockerPackageResult{
+							TargetImage: imageWithTag,
+							Platforms:   buildDetails.Platforms,
+							RemoteBuild: buildDetails.RemoteBuild,
+						},
+					})
+					return
+				}
 			}
 
 			packageDetails := &dockerPackageResult{
@@ -511,7 +681,7 @@ func getDockerOptionsWithDefaults(options DockerProjectOptions) DockerProjectOpt
 		options.Path = "./Dockerfile"
 	}
 
-	if options.Platform == "" {
+	if options.Platform == "" && len(options.Platforms) == 0 {
 		options.Platform = docker.DefaultPlatform
 	}
 
@@ -5,6 +5,7 @@ package project
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -108,7 +109,7 @@ services:
 	framework := NewDockerProject(
 		env,
 		docker,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, cloud.AzurePublic()),
+		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, cloud.AzurePublic(), nil, nil),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -216,7 +217,7 @@ services:
 	framework := NewDockerProject(
 		env,
 		docker,
-		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, cloud.AzurePublic()),
+		NewContainerHelper(env, envManager, clock.NewMock(), nil, docker, cloud.AzurePublic(), nil, nil),
 		mockinput.NewMockConsole(),
 		mockContext.AlphaFeaturesManager,
 		mockContext.CommandRunner)
@@ -418,7 +419,7 @@ func Test_DockerProject_Build(t *testing.T) {
 			dockerProject := NewDockerProject(
 				env,
 				dockerCli,
-				NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, cloud.AzurePublic()),
+				NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, cloud.AzurePublic(), nil, nil),
 				mockinput.NewMockConsole(),
 				mockContext.AlphaFeaturesManager,
 				mockContext.CommandRunner)
@@ -532,7 +533,7 @@ func Test_DockerProject_Package(t *testing.T) {
 			dockerProject := NewDockerProject(
 				env,
 				dockerCli,
-				NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, cloud.AzurePublic()),
+				NewContainerHelper(env, envManager, clock.NewMock(), nil, dockerCli, cloud.AzurePublic(), nil, nil),
 				mockinput.NewMockConsole(),
 				mockContext.AlphaFeaturesManager,
 				mockContext.CommandRunner)
@@ -575,3 +576,68 @@ func Test_DockerProject_Package(t *testing.T) {
 		})
 	}
 }
+
+func Test_DockerProjectOptions_BuildOptions(t *testing.T) {
+	options := DockerProjectOptions{
+		Platforms: []string{"linux/amd64", "linux/arm64"},
+		Secrets:   map[string]string{"feed": "FEED_TOKEN"},
+		Ssh:       []string{"default"},
+		CacheFrom: []osutil.ExpandableString{osutil.NewExpandableString("type=registry,ref=${REGISTRY}/app:cache")},
+		CacheTo:   []osutil.ExpandableString{osutil.NewExpandableString("type=registry,ref=${REGISTRY}/app:cache,mode=max")},
+	}
+
+	t.Run("Resolved", func(t *testing.T) {
+		env := environment.NewWithValues("test", map[string]string{
+			"FEED_TOKEN": "token",
+			"REGISTRY":   "contoso.azurecr.io",
+		})
+
+		buildOptions, err := options.buildOptions(context.Background(), env)
+		require.NoError(t, err)
+		require.Equal(t, docker.BuildOptions{
+			Secrets:   map[string]string{"feed": "token"},
+			Ssh:       []string{"default"},
+			CacheFrom: []string{"type=registry,ref=contoso.azurecr.io/app:cache"},
+			CacheTo:   []string{"type=registry,ref=contoso.azurecr.io/app:cache,mode=max"},
+		}, buildOptions)
+
+		require.Equal(t, "linux/amd64,linux/arm64", options.platform())
+		require.True(t, options.isMultiPlatform())
+		require.Empty(t, getDockerOptionsWithDefaults(options).Platform)
+	})
+
+	t.Run("SecretReference", func(t *testing.T) {
+		env := environment.NewWithValues("test", map[string]string{
+			environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
+			"FEED_TOKEN":                         "akvs://contoso-kv/feed-token",
+			"REGISTRY":                           "contoso.azurecr.io",
+		})
+		env.SetSecretResolver(&testSecretResolver{secrets: map[string]string{"akvs://contoso-kv/feed-token": "token"}})
+
+		buildOptions, err := options.buildOptions(context.Background(), env)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"feed": "token"}, buildOptions.Secrets)
+	})
+
+	t.Run("MissingSecret", func(t *testing.T) {
+		_, err := options.buildOptions(context.Background(), environment.NewWithValues("test", nil))
+		require.ErrorContains(t, err, "'FEED_TOKEN' environment variable")
+	})
+}
+
+// testSecretResolver resolves the references to secrets from a map of the values of the secrets
+type testSecretResolver struct {
+	secrets map[string]string
+}
+
+func (r *testSecretResolver) IsSecretReference(value string) bool {
+	return strings.HasPrefix(value, "akvs://")
+}
+
+func (r *testSecretResolver) ResolveSecret(_ context.Context, _ string, reference string) (string, error) {
+	if secret, has := r.secrets[reference]; has {
+		return secret, nil
+	}
+
+	return "", fmt.Errorf("secret '%s' not found", reference)
+}
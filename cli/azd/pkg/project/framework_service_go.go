@@ -0,0 +1,153 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/golang"
+	"github.com/otiai10/copy"
+)
+
+// The name of the executable produced when building a Go project
+const goBinaryName = "app"
+
+// Go programs are compiled for the linux/amd64 platform used by the Azure hosts, regardless of the local platform.
+var goBuildEnv = []string{
+	"GOOS=linux",
+	"GOARCH=amd64",
+	"CGO_ENABLED=0",
+}
+
+type goProject struct {
+	env *environment.Environment
+	cli golang.GoCli
+}
+
+// NewGoProject creates a new instance of a Go project
+func NewGoProject(cli golang.GoCli, env *environment.Environment) FrameworkService {
+	return &goProject{
+		env: env,
+		cli: cli,
+	}
+}
+
+func (gp *goProject) Requirements() FrameworkRequirements {
+	return FrameworkRequirements{
+		Package: FrameworkPackageRequirements{
+			RequireRestore: true,
+			RequireBuild:   true,
+		},
+	}
+}
+
+// Gets the required external tools for the project
+func (gp *goProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{gp.cli}
+}
+
+// Initializes the Go project
+func (gp *goProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return nil
+}
+
+// Restores the module dependencies of the project using `go mod download`
+func (gp *goProject) Restore(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			task.SetProgress(NewServiceProgress("Downloading Go modules"))
+			if err := gp.cli.ModDownload(ctx, serviceConfig.Path()); err != nil {
+				task.SetError(fmt.Errorf("downloading go modules for %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			task.SetResult(&ServiceRestoreResult{})
+		},
+	)
+}
+
+// Builds the main package of the project using `go build`. The executable is written to the output path when
+// specified, or to a temporary directory otherwise.
+func (gp *goProject) Build(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	restoreOutput *ServiceRestoreResult,
+) *async.TaskWithProgress[*ServiceBuildResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceBuildResult, ServiceProgress]) {
+			buildOutputDir := filepath.Join(serviceConfig.Path(), serviceConfig.OutputPath)
+			if serviceConfig.OutputPath == "" {
+				tempDir, err := os.MkdirTemp("", "azd-go")
+				if err != nil {
+					task.SetError(fmt.Errorf("creating build directory for %s: %w", serviceConfig.Name, err))
+					return
+				}
+				buildOutputDir = tempDir
+			}
+
+			task.SetProgress(NewServiceProgress("Building Go project"))
+			err := gp.cli.Build(ctx, serviceConfig.Path(), filepath.Join(buildOutputDir, goBinaryName), goBuildEnv)
+			if err != nil {
+				task.SetError(fmt.Errorf("building %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			task.SetResult(&ServiceBuildResult{
+				Restore:         restoreOutput,
+				BuildOutputPath: buildOutputDir,
+			})
+		},
+	)
+}
+
+func (gp *goProject) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	buildOutput *ServiceBuildResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			packageDest, err := os.MkdirTemp("", "azd")
+			if err != nil {
+				task.SetError(fmt.Errorf("creating package directory for %s: %w", serviceConfig.Name, err))
+				return
+			}
+
+			packageSource := buildOutput.BuildOutputPath
+			if packageSource == "" {
+				packageSource = filepath.Join(serviceConfig.Path(), serviceConfig.OutputPath)
+			}
+
+			if _, err := os.Stat(filepath.Join(packageSource, goBinaryName)); err != nil {
+				task.SetError(fmt.Errorf("reading go build output %s: %w", packageSource, err))
+				return
+			}
+
+			task.SetProgress(NewServiceProgress("Copying deployment package"))
+			if err := copy.Copy(packageSource, packageDest); err != nil {
+				task.SetError(fmt.Errorf("copying to staging directory failed: %w", err))
+				return
+			}
+
+			if err := validatePackageOutput(packageDest); err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServicePackageResult{
+				Build:       buildOutput,
+				PackagePath: packageDest,
+			})
+		},
+	)
+}
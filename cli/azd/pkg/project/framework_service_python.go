@@ -9,8 +9,10 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/internal/appdetect"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
@@ -18,15 +20,27 @@ import (
 )
 
 type pythonProject struct {
-	env *environment.Environment
-	cli *python.PythonCli
+	env       *environment.Environment
+	cli       *python.PythonCli
+	poetryCli *python.PoetryCli
+	uvCli     *python.UvCli
+
+	// The CLIs of the tools managing the dependencies of the initialized projects, such as Poetry or uv
+	toolClis []tools.ExternalTool
 }
 
 // NewPythonProject creates a new instance of the Python project
-func NewPythonProject(cli *python.PythonCli, env *environment.Environment) FrameworkService {
+func NewPythonProject(
+	cli *python.PythonCli,
+	poetryCli *python.PoetryCli,
+	uvCli *python.UvCli,
+	env *environment.Environment,
+) FrameworkService {
 	return &pythonProject{
-		env: env,
-		cli: cli,
+		env:       env,
+		cli:       cli,
+		poetryCli: poetryCli,
+		uvCli:     uvCli,
 	}
 }
 
@@ -42,26 +56,73 @@ func (pp *pythonProject) Requirements() FrameworkRequirements {
 
 // Gets the required external tools for the project
 func (pp *pythonProject) RequiredExternalTools(context.Context) []tools.ExternalTool {
-	return []tools.ExternalTool{pp.cli}
+	return append([]tools.ExternalTool{pp.cli}, pp.toolClis...)
 }
 
-// Initializes the Python project
+// Initializes the Python project, detecting the tool managing its dependencies so that the tool is required
 func (pp *pythonProject) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	tool, err := appdetect.PyProjectTool(serviceConfig.Path())
+	if err != nil {
+		return fmt.Errorf("detecting python tool for project '%s': %w", serviceConfig.Path(), err)
+	}
+
+	var toolCli tools.ExternalTool
+	switch tool {
+	case appdetect.PyToolPoetry:
+		toolCli = pp.poetryCli
+	case appdetect.PyToolUv:
+		toolCli = pp.uvCli
+	default:
+		return nil
+	}
+
+	if !slices.Contains(pp.toolClis, toolCli) {
+		pp.toolClis = append(pp.toolClis, toolCli)
+	}
+
 	return nil
 }
 
-// Restores the project dependencies using PIP requirements.txt
+// Restores the project dependencies using the tool declared by the project: Poetry or uv for projects using them,
+// or PIP with requirements.txt or pyproject.toml otherwise.
 func (pp *pythonProject) Restore(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 ) *async.TaskWithProgress[*ServiceRestoreResult, ServiceProgress] {
 	return async.RunTaskWithProgress(
 		func(task *async.TaskContextWithProgress[*ServiceRestoreResult, ServiceProgress]) {
+			tool, err := appdetect.PyProjectTool(serviceConfig.Path())
+			if err != nil {
+				task.SetError(fmt.Errorf("detecting python tool for project '%s': %w", serviceConfig.Path(), err))
+				return
+			}
+
+			switch tool {
+			case appdetect.PyToolPoetry:
+				task.SetProgress(NewServiceProgress("Installing Python dependencies with Poetry"))
+				if err := pp.poetryCli.Install(ctx, serviceConfig.Path()); err != nil {
+					task.SetError(err)
+					return
+				}
+
+				task.SetResult(&ServiceRestoreResult{})
+				return
+			case appdetect.PyToolUv:
+				task.SetProgress(NewServiceProgress("Installing Python dependencies with uv"))
+				if err := pp.uvCli.Sync(ctx, serviceConfig.Path()); err != nil {
+					task.SetError(err)
+					return
+				}
+
+				task.SetResult(&ServiceRestoreResult{})
+				return
+			}
+
 			task.SetProgress(NewServiceProgress("Checking for Python virtual environment"))
 			vEnvName := pp.getVenvName(serviceConfig)
 			vEnvPath := path.Join(serviceConfig.Path(), vEnvName)
 
-			_, err := os.Stat(vEnvPath)
+			_, err = os.Stat(vEnvPath)
 			if err != nil {
 				if os.IsNotExist(err) {
 					task.SetProgress(NewServiceProgress("Creating Python virtual environment"))
@@ -83,7 +144,11 @@ func (pp *pythonProject) Restore(
 			}
 
 			task.SetProgress(NewServiceProgress("Installing Python PIP dependencies"))
-			err = pp.cli.InstallRequirements(ctx, serviceConfig.Path(), vEnvName, "requirements.txt")
+			if pp.usesPyProject(serviceConfig) {
+				err = pp.cli.InstallProject(ctx, serviceConfig.Path(), vEnvName)
+			} else {
+				err = pp.cli.InstallRequirements(ctx, serviceConfig.Path(), vEnvName, "requirements.txt")
+			}
 			if err != nil {
 				task.SetError(
 					fmt.Errorf("requirements for project '%s' could not be installed: %w", serviceConfig.Path(), err),
@@ -155,6 +220,15 @@ func (pp *pythonProject) Package(
 				return
 			}
 
+			// The remote build of the hosts installs the dependencies listed in requirements.txt, which projects managed
+			// by Poetry or uv usually don't have.
+			if _, err := os.Stat(filepath.Join(packageDest, "requirements.txt")); os.IsNotExist(err) {
+				if err := pp.exportRequirements(ctx, task, serviceConfig, packageDest); err != nil {
+					task.SetError(fmt.Errorf("packaging for %s: %w", serviceConfig.Name, err))
+					return
+				}
+			}
+
 			if err := validatePackageOutput(packageDest); err != nil {
 				task.SetError(err)
 				return
@@ -168,6 +242,43 @@ func (pp *pythonProject) Package(
 	)
 }
 
+// exportRequirements writes the dependencies of a project managed by Poetry or uv to the requirements.txt file of the
+// package. Projects managed by PIP are left as is.
+func (pp *pythonProject) exportRequirements(
+	ctx context.Context,
+	task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress],
+	serviceConfig *ServiceConfig,
+	packageDest string,
+) error {
+	tool, err := appdetect.PyProjectTool(serviceConfig.Path())
+	if err != nil {
+		return fmt.Errorf("detecting python tool: %w", err)
+	}
+
+	requirementsFile := filepath.Join(packageDest, "requirements.txt")
+	switch tool {
+	case appdetect.PyToolPoetry:
+		task.SetProgress(NewServiceProgress("Exporting Python dependencies with Poetry"))
+		return pp.poetryCli.ExportRequirements(ctx, serviceConfig.Path(), requirementsFile)
+	case appdetect.PyToolUv:
+		task.SetProgress(NewServiceProgress("Exporting Python dependencies with uv"))
+		return pp.uvCli.ExportRequirements(ctx, serviceConfig.Path(), requirementsFile)
+	}
+
+	return nil
+}
+
+// usesPyProject returns true when the dependencies of the project are declared in pyproject.toml instead of
+// requirements.txt.
+func (pp *pythonProject) usesPyProject(serviceConfig *ServiceConfig) bool {
+	if _, err := os.Stat(filepath.Join(serviceConfig.Path(), "requirements.txt")); err == nil {
+		return false
+	}
+
+	_, err := os.Stat(filepath.Join(serviceConfig.Path(), "pyproject.toml"))
+	return err == nil
+}
+
 const cVenvConfigFileName = "pyvenv.cfg"
 
 func isPythonVirtualEnv(path string) bool {
@@ -15,6 +15,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/python"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/ostest"
@@ -58,7 +59,12 @@ func Test_PythonProject_Restore(t *testing.T) {
 	pythonCli := python.NewPythonCli(mockContext.CommandRunner)
 	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePython)
 
-	pythonProject := NewPythonProject(pythonCli, env)
+	pythonProject := NewPythonProject(
+		pythonCli,
+		python.NewPoetryCli(mockContext.CommandRunner),
+		python.NewUvCli(mockContext.CommandRunner),
+		env,
+	)
 	restoreTask := pythonProject.Restore(*mockContext.Context, serviceConfig)
 	logProgress(restoreTask)
 
@@ -92,7 +98,12 @@ func Test_PythonProject_Build(t *testing.T) {
 	pythonCli := python.NewPythonCli(mockContext.CommandRunner)
 	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePython)
 
-	pythonProject := NewPythonProject(pythonCli, env)
+	pythonProject := NewPythonProject(
+		pythonCli,
+		python.NewPoetryCli(mockContext.CommandRunner),
+		python.NewUvCli(mockContext.CommandRunner),
+		env,
+	)
 	buildTask := pythonProject.Build(*mockContext.Context, serviceConfig, nil)
 	logProgress(buildTask)
 
@@ -114,7 +125,12 @@ func Test_PythonProject_Package(t *testing.T) {
 	err = os.WriteFile(filepath.Join(serviceConfig.Path(), "requirements.txt"), nil, osutil.PermissionFile)
 	require.NoError(t, err)
 
-	pythonProject := NewPythonProject(pythonCli, env)
+	pythonProject := NewPythonProject(
+		pythonCli,
+		python.NewPoetryCli(mockContext.CommandRunner),
+		python.NewUvCli(mockContext.CommandRunner),
+		env,
+	)
 	packageTask := pythonProject.Package(
 		*mockContext.Context,
 		serviceConfig,
@@ -133,6 +149,152 @@ func Test_PythonProject_Package(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func Test_PythonProject_Restore_DeclaredTool(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		command string
+		args    []string
+	}{
+		{
+			"Poetry",
+			map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"api\"\n"},
+			"poetry",
+			[]string{"install", "--no-root", "--no-interaction"},
+		},
+		{
+			"Uv",
+			map[string]string{"pyproject.toml": "[project]\nname = \"api\"\n", "uv.lock": "version = 1\n"},
+			"uv",
+			[]string{"sync"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			ostest.Chdir(t, tempDir)
+
+			var runArgs exec.RunArgs
+
+			mockContext := mocks.NewMockContext(context.Background())
+			mockContext.CommandRunner.
+				When(func(args exec.RunArgs, command string) bool {
+					return args.Cmd == tt.command
+				}).
+				RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+					runArgs = args
+					return exec.NewRunResult(0, "", ""), nil
+				})
+
+			env := environment.New("test")
+			serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePython)
+			err := os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory)
+			require.NoError(t, err)
+			for name, contents := range tt.files {
+				err = os.WriteFile(filepath.Join(serviceConfig.Path(), name), []byte(contents), osutil.PermissionFile)
+				require.NoError(t, err)
+			}
+
+			pythonProject := NewPythonProject(
+				python.NewPythonCli(mockContext.CommandRunner),
+				python.NewPoetryCli(mockContext.CommandRunner),
+				python.NewUvCli(mockContext.CommandRunner),
+				env,
+			)
+			restoreTask := pythonProject.Restore(*mockContext.Context, serviceConfig)
+			logProgress(restoreTask)
+
+			result, err := restoreTask.Await()
+			require.NoError(t, err)
+			require.NotNil(t, result)
+			require.Equal(t, tt.command, runArgs.Cmd)
+			require.Equal(t, serviceConfig.Path(), runArgs.Cwd)
+			require.Equal(t, tt.args, runArgs.Args)
+		})
+	}
+}
+
+func Test_PythonProject_RequiredExternalTools(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	pythonCli := python.NewPythonCli(mockContext.CommandRunner)
+	poetryCli := python.NewPoetryCli(mockContext.CommandRunner)
+	uvCli := python.NewUvCli(mockContext.CommandRunner)
+	pythonProject := NewPythonProject(pythonCli, poetryCli, uvCli, environment.New("test"))
+
+	services := map[string]map[string]string{
+		"./src/pip":    {"requirements.txt": "flask\n"},
+		"./src/poetry": {"pyproject.toml": "[tool.poetry]\nname = \"api\"\n"},
+	}
+	for servicePath, files := range services {
+		serviceConfig := createTestServiceConfig(servicePath, AppServiceTarget, ServiceLanguagePython)
+		err := os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory)
+		require.NoError(t, err)
+		for name, contents := range files {
+			err = os.WriteFile(filepath.Join(serviceConfig.Path(), name), []byte(contents), osutil.PermissionFile)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, pythonProject.Initialize(*mockContext.Context, serviceConfig))
+	}
+
+	// The tool managing the dependencies of the projects is checked along with python
+	require.Equal(t,
+		[]tools.ExternalTool{pythonCli, poetryCli},
+		pythonProject.RequiredExternalTools(*mockContext.Context))
+}
+
+func Test_PythonProject_Package_ExportRequirements(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	var exportArgs exec.RunArgs
+
+	mockContext := mocks.NewMockContext(context.Background())
+	mockContext.CommandRunner.
+		When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "uv export")
+		}).
+		RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			exportArgs = args
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+	env := environment.New("test")
+	serviceConfig := createTestServiceConfig("./src/api", AppServiceTarget, ServiceLanguagePython)
+	err := os.MkdirAll(serviceConfig.Path(), osutil.PermissionDirectory)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(serviceConfig.Path(), "pyproject.toml"), nil, osutil.PermissionFile)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(serviceConfig.Path(), "uv.lock"), nil, osutil.PermissionFile)
+	require.NoError(t, err)
+
+	pythonProject := NewPythonProject(
+		python.NewPythonCli(mockContext.CommandRunner),
+		python.NewPoetryCli(mockContext.CommandRunner),
+		python.NewUvCli(mockContext.CommandRunner),
+		env,
+	)
+	packageTask := pythonProject.Package(
+		*mockContext.Context,
+		serviceConfig,
+		&ServiceBuildResult{
+			BuildOutputPath: serviceConfig.Path(),
+		},
+	)
+	logProgress(packageTask)
+
+	result, err := packageTask.Await()
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	require.Equal(t, "uv", exportArgs.Cmd)
+	require.Contains(t, exportArgs.Args, "--no-dev")
+	require.Contains(t, exportArgs.Args, filepath.Join(result.PackagePath, "requirements.txt"))
+}
+
 func pythonExe() string {
 	if runtime.GOOS == "windows" {
 		return "py" // https://peps.python.org/pep-0397
@@ -0,0 +1,145 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/notation"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/oras"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/syft"
+)
+
+// The artifact types of the SBOMs attached to images, by format
+var sbomArtifactTypes = map[string]string{
+	syft.FormatSpdxJson:      "application/spdx+json",
+	syft.FormatCycloneDxJson: "application/vnd.cyclonedx+json",
+}
+
+// ImageSbomOptions configures the software bill of materials (SBOM) generated for the image of a service once pushed
+type ImageSbomOptions struct {
+	// The format of the SBOM, either spdx-json (the default) or cyclonedx-json
+	Format string `yaml:"format,omitempty" json:"format,omitempty"`
+}
+
+// ImageSignOptions configures the signing of the image of a service once pushed
+type ImageSignOptions struct {
+	// The name of the notation signing key, such as a key stored in Azure Key Vault added with `notation key add`
+	Key osutil.ExpandableString `yaml:"key" json:"key"`
+}
+
+// hasImageSupplyChain returns true when the image of the service gets an SBOM or a signature once pushed. Such images are
+// deployed by digest, which is what the SBOM and the signature refer to.
+func hasImageSupplyChain(serviceConfig *ServiceConfig) bool {
+	return serviceConfig.Docker.Sbom != nil || serviceConfig.Docker.Sign != nil
+}
+
+// ImageSupplyChain publishes the supply-chain metadata of the images pushed by azd: the SBOM of the image, attached to the
+// image as an OCI artifact, and the signature of the image.
+type ImageSupplyChain struct {
+	env      *environment.Environment
+	syft     syft.SyftCli
+	oras     oras.OrasCli
+	notation notation.NotationCli
+}
+
+func NewImageSupplyChain(
+	env *environment.Environment,
+	syft syft.SyftCli,
+	oras oras.OrasCli,
+	notation notation.NotationCli,
+) *ImageSupplyChain {
+	return &ImageSupplyChain{
+		env:      env,
+		syft:     syft,
+		oras:     oras,
+		notation: notation,
+	}
+}
+
+// Publish generates the SBOM of the pushed image and attaches it to the image, then signs the image, as configured for
+// the service. The image must be referenced by its digest.
+func (s *ImageSupplyChain) Publish(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	image string,
+	credentials *azcli.DockerCredentials,
+	progress func(message string),
+) error {
+	sbomOptions := serviceConfig.Docker.Sbom
+	signOptions := serviceConfig.Docker.Sign
+
+	requiredTools := []tools.ExternalTool{}
+	if sbomOptions != nil {
+		requiredTools = append(requiredTools, s.syft, s.oras)
+	}
+
+	if signOptions != nil {
+		requiredTools = append(requiredTools, s.notation)
+	}
+
+	if err := tools.EnsureInstalled(ctx, requiredTools...); err != nil {
+		return err
+	}
+
+	if sbomOptions != nil {
+		format := sbomOptions.Format
+		if format == "" {
+			format = syft.FormatSpdxJson
+		}
+
+		artifactType, has := sbomArtifactTypes[format]
+		if !has {
+			return fmt.Errorf(
+				"unsupported sbom format '%s', supported formats are %s and %s",
+				format, syft.FormatSpdxJson, syft.FormatCycloneDxJson)
+		}
+
+		sbomDir, err := os.MkdirTemp("", "azd-sbom")
+		if err != nil {
+			return fmt.Errorf("creating sbom directory: %w", err)
+		}
+		defer os.RemoveAll(sbomDir)
+
+		sbomPath := filepath.Join(sbomDir, fmt.Sprintf("%s.%s.json", serviceConfig.Name, format))
+
+		log.Printf("generating %s sbom of image %s", format, image)
+		progress("Generating SBOM")
+		err = s.syft.GenerateSbom(
+			ctx, image, credentials.LoginServer, credentials.Username, credentials.Password, format, sbomPath)
+		if err != nil {
+			return err
+		}
+
+		progress("Attaching SBOM to container image")
+		err = s.oras.Attach(ctx, image, credentials.Username, credentials.Password, artifactType, sbomPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	if signOptions != nil {
+		key, err := signOptions.Key.Envsubst(s.env.Getenv)
+		if err != nil {
+			return fmt.Errorf("resolving signing key: %w", err)
+		}
+
+		if key == "" {
+			return fmt.Errorf("the signing key of service '%s' is empty", serviceConfig.Name)
+		}
+
+		log.Printf("signing image %s with key '%s'", image, key)
+		progress("Signing container image")
+		if err := s.notation.Sign(ctx, image, credentials.Username, credentials.Password, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
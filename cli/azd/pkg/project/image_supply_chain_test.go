@@ -0,0 +1,154 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSupplyChainTool records the invocations of the supply-chain tools, which are always installed
+type fakeSupplyChainTool struct {
+	calls []string
+}
+
+func (f *fakeSupplyChainTool) CheckInstalled(ctx context.Context) error { return nil }
+func (f *fakeSupplyChainTool) InstallUrl() string                       { return "" }
+func (f *fakeSupplyChainTool) Name() string                             { return "fake" }
+
+func (f *fakeSupplyChainTool) GenerateSbom(
+	ctx context.Context,
+	image string,
+	loginServer string,
+	username string,
+	password string,
+	format string,
+	outputPath string,
+) error {
+	f.calls = append(f.calls, "sbom "+format+" "+image+" "+filepath.Base(outputPath))
+	return os.WriteFile(outputPath, []byte("{}"), osutil.PermissionFile)
+}
+
+func (f *fakeSupplyChainTool) Attach(
+	ctx context.Context,
+	image string,
+	username string,
+	password string,
+	artifactType string,
+	filePath string,
+) error {
+	f.calls = append(f.calls, "attach "+artifactType+" "+image+" "+filepath.Base(filePath))
+	return nil
+}
+
+func (f *fakeSupplyChainTool) Sign(ctx context.Context, image string, username string, password string, key string) error {
+	f.calls = append(f.calls, "sign "+key+" "+image+" "+password)
+	return nil
+}
+
+func Test_ContainerHelper_Deploy_SupplyChain(t *testing.T) {
+	setup := func(t *testing.T, env *environment.Environment) (*ServiceConfig, *ContainerHelper, *fakeSupplyChainTool) {
+		envManager := &mockenv.MockEnvManager{}
+		envManager.On("Save", mock.Anything, env).Return(nil)
+
+		serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+		serviceConfig.Project.Path = t.TempDir()
+		serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+		serviceConfig.Docker.RemoteBuild = true
+
+		dockerfile := filepath.Join(serviceConfig.Path(), "Dockerfile")
+		require.NoError(t, os.MkdirAll(filepath.Dir(dockerfile), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(dockerfile, []byte("FROM node:20"), osutil.PermissionFile))
+
+		mockContainerRegistryService := &mockContainerRegistryService{}
+		mockContainerRegistryService.
+			On("RemoteBuild", mock.Anything, mock.Anything, "contoso.azurecr.io", mock.Anything, mock.Anything).
+			Return(&azcli.RemoteBuildResult{RunId: "ca1", Digest: "sha256:signed"}, nil)
+		mockContainerRegistryService.On("Credentials", mock.Anything, mock.Anything, "contoso.azurecr.io").
+			Return(&azcli.DockerCredentials{
+				Username:    "00000000-0000-0000-0000-000000000000",
+				Password:    "REFRESH_TOKEN",
+				LoginServer: "contoso.azurecr.io",
+			}, nil)
+
+		tool := &fakeSupplyChainTool{}
+		containerHelper := NewContainerHelper(
+			env,
+			envManager,
+			clock.NewMock(),
+			mockContainerRegistryService,
+			nil,
+			cloud.AzurePublic(),
+			mockinput.NewMockConsole(),
+			NewImageSupplyChain(env, tool, tool, tool),
+		)
+
+		return serviceConfig, containerHelper, tool
+	}
+
+	packageOutput := &ServicePackageResult{
+		Details: &dockerPackageResult{
+			TargetImage: "test-app/api-dev:azd-deploy-0",
+			RemoteBuild: true,
+		},
+	}
+
+	t.Run("SbomAndSignature", func(t *testing.T) {
+		env := environment.NewWithValues("dev", map[string]string{"SIGNING_KEY": "release"})
+		serviceConfig, containerHelper, tool := setup(t, env)
+		serviceConfig.Docker.Sbom = &ImageSbomOptions{}
+		serviceConfig.Docker.Sign = &ImageSignOptions{Key: osutil.NewExpandableString("${SIGNING_KEY}")}
+
+		deployTask := containerHelper.Deploy(context.Background(), serviceConfig, packageOutput, nil, true)
+		logProgress(deployTask)
+		_, err := deployTask.Await()
+		require.NoError(t, err)
+
+		image := "contoso.azurecr.io/test-app/api-dev@sha256:signed"
+		require.Equal(t, []string{
+			"sbom spdx-json " + image + " api.spdx-json.json",
+			"attach application/spdx+json " + image + " api.spdx-json.json",
+			"sign release " + image + " REFRESH_TOKEN",
+		}, tool.calls)
+
+		// The image is deployed by digest
+		require.Equal(t, image, env.GetServiceProperty("api", "IMAGE_NAME"))
+		require.Equal(t, image, env.GetServiceProperty("api", "IMAGE_DIGEST"))
+	})
+
+	t.Run("NoSupplyChain", func(t *testing.T) {
+		env := environment.NewWithValues("dev", map[string]string{})
+		serviceConfig, containerHelper, tool := setup(t, env)
+
+		deployTask := containerHelper.Deploy(context.Background(), serviceConfig, packageOutput, nil, true)
+		logProgress(deployTask)
+		_, err := deployTask.Await()
+		require.NoError(t, err)
+
+		require.Empty(t, tool.calls)
+		require.Equal(t, "contoso.azurecr.io/test-app/api-dev:azd-deploy-0", env.GetServiceProperty("api", "IMAGE_NAME"))
+		require.Equal(t,
+			"contoso.azurecr.io/test-app/api-dev@sha256:signed", env.GetServiceProperty("api", "IMAGE_DIGEST"))
+	})
+
+	t.Run("UnsupportedSbomFormat", func(t *testing.T) {
+		env := environment.NewWithValues("dev", map[string]string{})
+		serviceConfig, containerHelper, _ := setup(t, env)
+		serviceConfig.Docker.Sbom = &ImageSbomOptions{Format: "syft-table"}
+
+		deployTask := containerHelper.Deploy(context.Background(), serviceConfig, packageOutput, nil, true)
+		logProgress(deployTask)
+		_, err := deployTask.Await()
+		require.ErrorContains(t, err, "unsupported sbom format 'syft-table'")
+	})
+}
@@ -109,6 +109,18 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 		if svc.Host == ContainerAppTarget && svc.Language == ServiceLanguageNone && svc.Image == "" {
 			return nil, fmt.Errorf("parsing service %s: must specify language or image", svc.Name)
 		}
+
+		if err := validateDeploymentOptions(svc); err != nil {
+			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
+		}
+
+		if err := validateAppServiceOptions(svc); err != nil {
+			return nil, fmt.Errorf("parsing service %s: %w", svc.Name, err)
+		}
+	}
+
+	if err := validateServiceDependencies(projectConfig.Services); err != nil {
+		return nil, err
 	}
 
 	return &projectConfig, nil
@@ -34,6 +34,10 @@ type ServiceConfig struct {
 	K8s AksOptions `yaml:"k8s,omitempty"`
 	// The optional Azure Spring Apps options
 	Spring SpringOptions `yaml:"spring,omitempty"`
+	// The optional Azure App Service options
+	AppService AppServiceOptions `yaml:"appservice,omitempty"`
+	// The optional deployment strategy options, for container apps
+	Deployment DeploymentOptions `yaml:"deployment,omitempty"`
 	// The infrastructure provisioning configuration
 	Infra provisioning.Options `yaml:"infra,omitempty"`
 	// Hook configuration for service
@@ -43,6 +47,8 @@ type ServiceConfig struct {
 	DotNetContainerApp *DotNetContainerAppOptions `yaml:"-,omitempty"`
 	// Custom configuration for the service target
 	Config map[string]any `yaml:"config,omitempty"`
+	// The services which must be packaged and deployed before this service
+	DependsOn []string `yaml:"dependsOn,omitempty"`
 
 	*ext.EventDispatcher[ServiceLifecycleEventArgs] `yaml:"-"`
 }
@@ -0,0 +1,225 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DependencyFailedError is returned for a service that didn't run since one of the services it depends on failed
+type DependencyFailedError struct {
+	Service    string
+	Dependency string
+}
+
+func (e *DependencyFailedError) Error() string {
+	return fmt.Sprintf("service '%s' was skipped since its dependency '%s' failed", e.Service, e.Dependency)
+}
+
+// ServiceRunFn is the function run for each service of a ServiceGraph
+type ServiceRunFn func(ctx context.Context, serviceConfig *ServiceConfig) error
+
+// ServiceGraph orders services according to the services they depend on, configured with `dependsOn`
+type ServiceGraph struct {
+	services []*ServiceConfig
+	// The dependencies of each service which are part of the graph
+	dependencies map[string][]string
+}
+
+// NewServiceGraph creates a graph of the specified services.
+// Dependencies on services which aren't part of the graph are ignored, such as dependencies on services which aren't
+// targeted by a command. An error is returned when the services have a circular dependency.
+func NewServiceGraph(services []*ServiceConfig) (*ServiceGraph, error) {
+	graph := &ServiceGraph{
+		services:     services,
+		dependencies: map[string][]string{},
+	}
+
+	names := map[string]struct{}{}
+	for _, svc := range services {
+		names[svc.Name] = struct{}{}
+	}
+
+	for _, svc := range services {
+		for _, dependency := range svc.DependsOn {
+			if _, has := names[dependency]; has {
+				graph.dependencies[svc.Name] = append(graph.dependencies[svc.Name], dependency)
+			}
+		}
+	}
+
+	if cycle := graph.cycle(); cycle != nil {
+		return nil, fmt.Errorf("services have a circular dependency: %s", strings.Join(cycle, " -> "))
+	}
+
+	return graph, nil
+}
+
+// cycle returns the services forming a circular dependency, or nil when there is none
+func (g *ServiceGraph) cycle() []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	state := map[string]int{}
+	path := []string{}
+
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		switch state[name] {
+		case visiting:
+			start := slices.Index(path, name)
+			return append(slices.Clone(path[start:]), name)
+		case visited:
+			return nil
+		}
+
+		state[name] = visiting
+		path = append(path, name)
+
+		for _, dependency := range g.dependencies[name] {
+			if cycle := visit(dependency); cycle != nil {
+				return cycle
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for _, svc := range g.services {
+		if cycle := visit(svc.Name); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
+
+// Run runs the function for each service once the services it depends on have completed, running up to parallelism
+// services concurrently. Services are started in the order of the graph when they are ready at the same time.
+//
+// When the function fails for a service, the services depending on it are skipped with a DependencyFailedError,
+// while the other services keep running. The returned error joins the errors of all the failed and skipped services.
+func (g *ServiceGraph) Run(ctx context.Context, parallelism int, runFn ServiceRunFn) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	type result struct {
+		name string
+		err  error
+	}
+
+	pending := slices.Clone(g.services)
+	completed := map[string]bool{}
+	failed := map[string]bool{}
+	results := make(chan result)
+	running := 0
+	errs := []error{}
+
+	for len(pending) > 0 || running > 0 {
+		for i := 0; i < len(pending) && running < parallelism && ctx.Err() == nil; {
+			svc := pending[i]
+
+			if dependency, has := g.failedDependency(svc.Name, failed); has {
+				pending = slices.Delete(pending, i, i+1)
+				failed[svc.Name] = true
+				errs = append(errs, &DependencyFailedError{Service: svc.Name, Dependency: dependency})
+				// Skipping a service may skip services earlier in the pending list
+				i = 0
+				continue
+			}
+
+			if !g.ready(svc.Name, completed) {
+				i++
+				continue
+			}
+
+			pending = slices.Delete(pending, i, i+1)
+			running++
+
+			go func(svc *ServiceConfig) {
+				results <- result{name: svc.Name, err: runFn(ctx, svc)}
+			}(svc)
+		}
+
+		if running == 0 {
+			// Nothing can start, which happens when the context is canceled
+			if err := ctx.Err(); err != nil {
+				errs = append(errs, err)
+			}
+
+			break
+		}
+
+		result := <-results
+		running--
+		completed[result.name] = true
+
+		if result.err != nil {
+			failed[result.name] = true
+			errs = append(errs, result.err)
+		}
+	}
+
+	if len(errs) == 1 {
+		// Keep the error as is, so it can be handled like the error of a single service
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
+}
+
+// ready returns true when all the dependencies of the service have completed
+func (g *ServiceGraph) ready(name string, completed map[string]bool) bool {
+	for _, dependency := range g.dependencies[name] {
+		if !completed[dependency] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// failedDependency returns a dependency of the service which failed or was skipped
+func (g *ServiceGraph) failedDependency(name string, failed map[string]bool) (string, bool) {
+	for _, dependency := range g.dependencies[name] {
+		if failed[dependency] {
+			return dependency, true
+		}
+	}
+
+	return "", false
+}
+
+// validateServiceDependencies ensures the services only depend on services of the project and have no circular dependency
+func validateServiceDependencies(services map[string]*ServiceConfig) error {
+	serviceList := make([]*ServiceConfig, 0, len(services))
+	for _, svc := range services {
+		for _, dependency := range svc.DependsOn {
+			if dependency == svc.Name {
+				return fmt.Errorf("parsing service %s: a service can't depend on itself", svc.Name)
+			}
+
+			if _, has := services[dependency]; !has {
+				return fmt.Errorf("parsing service %s: dependency '%s' isn't a service of the project", svc.Name, dependency)
+			}
+		}
+
+		serviceList = append(serviceList, svc)
+	}
+
+	// Sort the services so the reported cycle is stable
+	slices.SortFunc(serviceList, func(a, b *ServiceConfig) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	_, err := NewServiceGraph(serviceList)
+	return err
+}
@@ -0,0 +1,208 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceGraph_Run(t *testing.T) {
+	t.Run("Order", func(t *testing.T) {
+		graph, err := NewServiceGraph(testGraphServices())
+		require.NoError(t, err)
+
+		order := []string{}
+		err = graph.Run(context.Background(), 1, func(ctx context.Context, svc *ServiceConfig) error {
+			order = append(order, svc.Name)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"api", "db", "worker", "web"}, order)
+	})
+
+	t.Run("Parallelism", func(t *testing.T) {
+		services := []*ServiceConfig{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}}
+		graph, err := NewServiceGraph(services)
+		require.NoError(t, err)
+
+		var running, maxRunning atomic.Int32
+		started := make(chan struct{}, len(services))
+		release := make(chan struct{})
+
+		go func() {
+			// Releases the services once the first two have started
+			<-started
+			<-started
+			close(release)
+		}()
+
+		err = graph.Run(context.Background(), 2, func(ctx context.Context, svc *ServiceConfig) error {
+			current := running.Add(1)
+			for {
+				observed := maxRunning.Load()
+				if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+					break
+				}
+			}
+
+			started <- struct{}{}
+			<-release
+			running.Add(-1)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(2), maxRunning.Load())
+	})
+
+	t.Run("DependenciesCompleteFirst", func(t *testing.T) {
+		graph, err := NewServiceGraph(testGraphServices())
+		require.NoError(t, err)
+
+		mutex := sync.Mutex{}
+		completed := map[string]bool{}
+		err = graph.Run(context.Background(), 4, func(ctx context.Context, svc *ServiceConfig) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			for _, dependency := range svc.DependsOn {
+				require.True(t, completed[dependency], "%s ran before %s", svc.Name, dependency)
+			}
+
+			completed[svc.Name] = true
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, completed, 4)
+	})
+
+	t.Run("FailureSkipsDependents", func(t *testing.T) {
+		graph, err := NewServiceGraph(testGraphServices())
+		require.NoError(t, err)
+
+		mutex := sync.Mutex{}
+		ran := []string{}
+		err = graph.Run(context.Background(), 4, func(ctx context.Context, svc *ServiceConfig) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			ran = append(ran, svc.Name)
+			if svc.Name == "db" {
+				return errors.New("db failed")
+			}
+
+			return nil
+		})
+		require.ErrorContains(t, err, "db failed")
+		require.ElementsMatch(t, []string{"api", "db"}, ran)
+
+		var dependencyErr *DependencyFailedError
+		require.ErrorAs(t, err, &dependencyErr)
+		require.Contains(t, err.Error(), "service 'worker' was skipped since its dependency 'db' failed")
+		require.Contains(t, err.Error(), "service 'web' was skipped since its dependency 'worker' failed")
+	})
+
+	t.Run("IgnoresServicesOutsideGraph", func(t *testing.T) {
+		graph, err := NewServiceGraph([]*ServiceConfig{{Name: "web", DependsOn: []string{"api"}}})
+		require.NoError(t, err)
+
+		ran := []string{}
+		err = graph.Run(context.Background(), 1, func(ctx context.Context, svc *ServiceConfig) error {
+			ran = append(ran, svc.Name)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"web"}, ran)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		graph, err := NewServiceGraph(testGraphServices())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ran := []string{}
+		err = graph.Run(ctx, 1, func(ctx context.Context, svc *ServiceConfig) error {
+			ran = append(ran, svc.Name)
+			cancel()
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, []string{"api"}, ran)
+	})
+}
+
+func Test_NewServiceGraph_Cycle(t *testing.T) {
+	_, err := NewServiceGraph([]*ServiceConfig{
+		{Name: "api", DependsOn: []string{"web"}},
+		{Name: "db"},
+		{Name: "web", DependsOn: []string{"worker"}},
+		{Name: "worker", DependsOn: []string{"db", "api"}},
+	})
+	require.EqualError(t, err, "services have a circular dependency: api -> web -> worker -> api")
+}
+
+func Test_Parse_DependsOn(t *testing.T) {
+	const projectTemplate = `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    host: appservice
+  web:
+    project: src/web
+    language: js
+    host: appservice
+    dependsOn:
+`
+
+	t.Run("Valid", func(t *testing.T) {
+		projectConfig, err := Parse(context.Background(), projectTemplate+"      - api\n")
+		require.NoError(t, err)
+		require.Equal(t, []string{"api"}, projectConfig.Services["web"].DependsOn)
+	})
+
+	t.Run("UnknownService", func(t *testing.T) {
+		_, err := Parse(context.Background(), projectTemplate+"      - db\n")
+		require.ErrorContains(t, err, "dependency 'db' isn't a service of the project")
+	})
+
+	t.Run("Self", func(t *testing.T) {
+		_, err := Parse(context.Background(), projectTemplate+"      - web\n")
+		require.ErrorContains(t, err, "a service can't depend on itself")
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		projectYaml := `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    host: appservice
+    dependsOn:
+      - web
+  web:
+    project: src/web
+    language: js
+    host: appservice
+    dependsOn:
+      - api
+`
+		_, err := Parse(context.Background(), projectYaml)
+		require.EqualError(t, err, "services have a circular dependency: api -> web -> api")
+	})
+}
+
+// testGraphServices returns services where web depends on worker and api, and worker depends on db
+func testGraphServices() []*ServiceConfig {
+	return []*ServiceConfig{
+		{Name: "api"},
+		{Name: "db"},
+		{Name: "web", DependsOn: []string{"worker", "api"}},
+		{Name: "worker", DependsOn: []string{"db"}},
+	}
+}
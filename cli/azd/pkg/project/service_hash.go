@@ -0,0 +1,408 @@
+package project
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"gopkg.in/yaml.v3"
+)
+
+// Folders which are never part of the content of a service
+var serviceHashSkippedDirs = map[string]struct{}{
+	".git":   {},
+	".azure": {},
+}
+
+var envReferenceRegex = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)`)
+
+// serviceHash computes a hash of everything which affects the build, package and deployment of a service:
+// the files of the service (excluding the files ignored by .gitignore and .dockerignore files), the configuration of the
+// service, including its docker build args, and the values of the environment variables used by the configuration.
+func serviceHash(serviceConfig *ServiceConfig, env *environment.Environment) (string, error) {
+	hash := sha256.New()
+
+	config, err := yaml.Marshal(serviceConfig)
+	if err != nil {
+		return "", fmt.Errorf("marshaling service configuration: %w", err)
+	}
+
+	fmt.Fprintf(hash, "config\x00%s\x00", config)
+
+	// Expandable strings aren't marshaled, their values are hashed instead
+	expandables := []osutil.ExpandableString{
+		serviceConfig.ResourceGroupName,
+		serviceConfig.ResourceName,
+		serviceConfig.Docker.Registry,
+		serviceConfig.Docker.Image,
+		serviceConfig.Docker.Tag,
+	}
+	expandables = append(expandables, serviceConfig.Docker.CacheFrom...)
+	expandables = append(expandables, serviceConfig.Docker.CacheTo...)
+	if serviceConfig.Docker.Sign != nil {
+		expandables = append(expandables, serviceConfig.Docker.Sign.Key)
+	}
+
+	helmValuesFiles := []string{}
+	if serviceConfig.K8s.Helm != nil {
+		for _, release := range serviceConfig.K8s.Helm.Releases {
+			keys := make([]string, 0, len(release.Set))
+			for key := range release.Set {
+				keys = append(keys, key)
+			}
+			slices.Sort(keys)
+
+			for _, key := range keys {
+				expandables = append(expandables, release.Set[key])
+			}
+
+			if release.Values != "" {
+				helmValuesFiles = append(helmValuesFiles, release.Values)
+			}
+			helmValuesFiles = append(helmValuesFiles, release.ValuesFiles...)
+		}
+	}
+
+	for _, expandable := range expandables {
+		value, err := expandable.Envsubst(env.Getenv)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(hash, "value\x00%s\x00", value)
+	}
+
+	envNames := []string{}
+	for _, match := range envReferenceRegex.FindAllStringSubmatch(string(config), -1) {
+		envNames = append(envNames, match[1])
+	}
+
+	// The helm values files are rendered with the values of the environment variables they reference
+	for _, valuesFile := range helmValuesFiles {
+		if !filepath.IsAbs(valuesFile) {
+			valuesFile = filepath.Join(serviceConfig.Path(), valuesFile)
+		}
+
+		content, err := os.ReadFile(valuesFile)
+		if errors.Is(err, os.ErrNotExist) {
+			// The deployment reports the missing values file
+			continue
+		} else if err != nil {
+			return "", fmt.Errorf("reading helm values file: %w", err)
+		}
+
+		for _, match := range envReferenceRegex.FindAllStringSubmatch(string(content), -1) {
+			envNames = append(envNames, match[1])
+		}
+	}
+
+	// Build args without a value are read from the environment by docker
+	for _, arg := range serviceConfig.Docker.BuildArgs {
+		if !strings.Contains(arg, "=") {
+			envNames = append(envNames, arg)
+		}
+	}
+
+	slices.Sort(envNames)
+	for _, name := range slices.Compact(envNames) {
+		fmt.Fprintf(hash, "env\x00%s=%s\x00", name, env.Getenv(name))
+	}
+
+	roots, files := serviceHashPaths(serviceConfig)
+	for _, root := range roots {
+		if err := hashDirectory(hash, serviceConfig.Project.Path, root); err != nil {
+			return "", err
+		}
+	}
+
+	for _, file := range files {
+		if err := hashFile(hash, file, file); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// serviceHashPaths returns the directories and the files which make up the content of the service.
+// The docker context and the Dockerfile are included when they are outside of the service directory.
+func serviceHashPaths(serviceConfig *ServiceConfig) (roots []string, files []string) {
+	roots = []string{serviceConfig.Path()}
+
+	if serviceConfig.Docker.Context != "" {
+		context := serviceConfig.Docker.Context
+		if !filepath.IsAbs(context) {
+			context = filepath.Join(serviceConfig.Path(), context)
+		}
+
+		switch {
+		case isWithinDir(context, roots[0]):
+		case isWithinDir(roots[0], context):
+			roots[0] = context
+		default:
+			roots = append(roots, context)
+		}
+	}
+
+	if serviceConfig.Docker.Path != "" {
+		dockerfile := serviceConfig.Docker.Path
+		if !filepath.IsAbs(dockerfile) {
+			dockerfile = filepath.Join(serviceConfig.Path(), dockerfile)
+		}
+
+		if !slices.ContainsFunc(roots, func(root string) bool { return isWithinDir(dockerfile, root) }) {
+			files = append(files, dockerfile)
+		}
+	}
+
+	return roots, files
+}
+
+// isWithinDir returns true when the path is the directory or one of its descendants
+func isWithinDir(path string, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// hashDirectory writes the files of the directory which aren't ignored to the hash.
+// The .gitignore files of the directory and of its parents up to the project directory, and the .dockerignore file of
+// the directory are respected.
+func hashDirectory(hash hash.Hash, projectDir string, root string) error {
+	// Paths are relative to the project directory, so the .gitignore files of the parents of root apply
+	top := root
+	if isWithinDir(root, projectDir) {
+		top = projectDir
+	}
+
+	rules := ignoreRules{}
+	rootRel, err := filepath.Rel(top, root)
+	if err != nil {
+		return err
+	}
+
+	rootRel = filepath.ToSlash(rootRel)
+	if rootRel == "." {
+		rootRel = ""
+	}
+
+	// Load the .gitignore files of the parents of root, from the top
+	parent := ""
+	for _, segment := range strings.Split(rootRel, "/") {
+		if segment == "" {
+			break
+		}
+
+		if err := rules.load(filepath.Join(top, filepath.FromSlash(parent), ".gitignore"), parent, false); err != nil {
+			return err
+		}
+
+		parent = path.Join(parent, segment)
+	}
+
+	if err := rules.load(filepath.Join(root, ".dockerignore"), rootRel, true); err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(root, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(top, filePath)
+		if err != nil {
+			return err
+		}
+
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			rel = ""
+		}
+
+		if entry.IsDir() {
+			if filePath != root {
+				if _, skipped := serviceHashSkippedDirs[entry.Name()]; skipped || rules.ignored(rel, true) {
+					return filepath.SkipDir
+				}
+			}
+
+			return rules.load(filepath.Join(filePath, ".gitignore"), rel, false)
+		}
+
+		if rules.ignored(rel, false) {
+			return nil
+		}
+
+		return hashFile(hash, filePath, rel)
+	})
+}
+
+// hashFile writes the name and the content of the file to the hash
+func hashFile(hash hash.Hash, filePath string, name string) error {
+	info, err := os.Lstat(filePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(hash, "file\x00%s\x00", name)
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(filePath)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(hash, "link\x00%s\x00", target)
+		return nil
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fileHash := sha256.New()
+	if _, err := io.Copy(fileHash, file); err != nil {
+		return fmt.Errorf("reading %s: %w", filePath, err)
+	}
+
+	fmt.Fprintf(hash, "%x\x00", fileHash.Sum(nil))
+	return nil
+}
+
+// ignoreRule is a pattern of a .gitignore or .dockerignore file
+type ignoreRule struct {
+	// The slash separated directory containing the ignore file, relative to the top of the walk
+	base    string
+	pattern []string
+	negate  bool
+	dirOnly bool
+	// Anchored patterns match the path relative to base, other patterns match the name of the file at any depth
+	anchored bool
+}
+
+type ignoreRules []ignoreRule
+
+// load parses the ignore file when it exists. The patterns of .dockerignore files are always relative to base.
+func (r *ignoreRules) load(filePath string, base string, dockerignore bool) error {
+	content, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading %s: %w", filePath, err)
+	}
+
+	*r = append(*r, parseIgnoreRules(string(content), base, dockerignore)...)
+	return nil
+}
+
+func parseIgnoreRules(content string, base string, dockerignore bool) []ignoreRule {
+	rules := []ignoreRule{}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+
+		rule.anchored = dockerignore || strings.Contains(line, "/")
+
+		line = path.Clean(strings.TrimPrefix(line, "/"))
+		if line == "." || line == "" {
+			continue
+		}
+
+		rule.pattern = strings.Split(line, "/")
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// ignored returns true when the slash separated path is ignored. The last matching rule wins, like in git.
+func (r ignoreRules) ignored(relPath string, isDir bool) bool {
+	ignored := false
+
+	for _, rule := range r {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		name := relPath
+		if rule.base != "" {
+			if !strings.HasPrefix(relPath, rule.base+"/") {
+				continue
+			}
+
+			name = strings.TrimPrefix(relPath, rule.base+"/")
+		}
+
+		var matched bool
+		if rule.anchored {
+			matched = matchPathSegments(rule.pattern, strings.Split(name, "/"))
+		} else {
+			matched = matchPathSegments(rule.pattern, []string{path.Base(name)})
+		}
+
+		if matched {
+			ignored = !rule.negate
+		}
+	}
+
+	return ignored
+}
+
+// matchPathSegments matches the segments of a path against the segments of a pattern, where ** matches any number of
+// segments
+func matchPathSegments(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchPathSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if matched, err := path.Match(pattern[0], name[0]); err != nil || !matched {
+			return false
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
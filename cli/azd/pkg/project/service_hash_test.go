@@ -0,0 +1,144 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/helm"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ServiceHash(t *testing.T) {
+	projectDir := t.TempDir()
+	writeFile := func(name string, content string) {
+		path := filepath.Join(projectDir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+	}
+
+	writeFile(".gitignore", "node_modules/\n*.log\n")
+	writeFile("src/api/.dockerignore", "tmp\n")
+	writeFile("src/api/index.js", "console.log('hello')")
+	writeFile("src/api/.azure/cache.json", "{}")
+
+	serviceConfig := &ServiceConfig{
+		Project:      &ProjectConfig{Path: projectDir},
+		Name:         "api",
+		RelativePath: "src/api",
+		Host:         ContainerAppTarget,
+		ResourceName: osutil.NewExpandableString("${API_NAME}"),
+	}
+	env := environment.NewWithValues("dev", map[string]string{"API_NAME": "api-dev"})
+
+	hash, err := serviceHash(serviceConfig, env)
+	require.NoError(t, err)
+
+	t.Run("IgnoredFiles", func(t *testing.T) {
+		writeFile("src/api/node_modules/lib/index.js", "module.exports = {}")
+		writeFile("src/api/debug.log", "log")
+		writeFile("src/api/tmp", "tmp")
+		writeFile("src/api/.azure/cache.json", "{\"changed\": true}")
+		writeFile("src/web/index.js", "console.log('web')")
+
+		unchanged, err := serviceHash(serviceConfig, env)
+		require.NoError(t, err)
+		require.Equal(t, hash, unchanged)
+	})
+
+	t.Run("EnvValue", func(t *testing.T) {
+		otherEnv := environment.NewWithValues("dev", map[string]string{"API_NAME": "api-test"})
+
+		changed, err := serviceHash(serviceConfig, otherEnv)
+		require.NoError(t, err)
+		require.NotEqual(t, hash, changed)
+	})
+
+	t.Run("BuildArgs", func(t *testing.T) {
+		otherConfig := *serviceConfig
+		otherConfig.Docker.BuildArgs = []string{"VERSION=2"}
+
+		changed, err := serviceHash(&otherConfig, env)
+		require.NoError(t, err)
+		require.NotEqual(t, hash, changed)
+	})
+
+	t.Run("HelmValues", func(t *testing.T) {
+		writeFile("src/api/values.yaml", "image: ${IMAGE_NAME}\n")
+
+		helmConfig := *serviceConfig
+		helmConfig.K8s.Helm = &helm.Config{
+			Releases: []*helm.Release{
+				{
+					Name:   "api",
+					Chart:  "./chart",
+					Values: "values.yaml",
+					Set:    map[string]osutil.ExpandableString{"replicas": osutil.NewExpandableString("${REPLICAS}")},
+				},
+			},
+		}
+
+		helmEnv := func(imageName string, replicas string) *environment.Environment {
+			return environment.NewWithValues("dev", map[string]string{
+				"API_NAME":   "api-dev",
+				"IMAGE_NAME": imageName,
+				"REPLICAS":   replicas,
+			})
+		}
+
+		helmHash, err := serviceHash(&helmConfig, helmEnv("api:v1", "1"))
+		require.NoError(t, err)
+
+		// The variables referenced by the values file and by the values set on the command line are hashed
+		changed, err := serviceHash(&helmConfig, helmEnv("api:v2", "1"))
+		require.NoError(t, err)
+		require.NotEqual(t, helmHash, changed)
+
+		changed, err = serviceHash(&helmConfig, helmEnv("api:v1", "2"))
+		require.NoError(t, err)
+		require.NotEqual(t, helmHash, changed)
+	})
+
+	t.Run("ChangedFile", func(t *testing.T) {
+		writeFile("src/api/index.js", "console.log('changed')")
+
+		changed, err := serviceHash(serviceConfig, env)
+		require.NoError(t, err)
+		require.NotEqual(t, hash, changed)
+	})
+}
+
+func Test_IgnoreRules(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		base         string
+		dockerignore bool
+		path         string
+		isDir        bool
+		expected     bool
+	}{
+		{name: "Name", content: "*.log", path: "src/debug.log", expected: true},
+		{name: "NameNoMatch", content: "*.log", path: "src/debug.txt", expected: false},
+		{name: "DirOnly", content: "bin/", path: "src/bin", isDir: true, expected: true},
+		{name: "DirOnlyFile", content: "bin/", path: "src/bin", expected: false},
+		{name: "Anchored", content: "/dist", path: "dist", isDir: true, expected: true},
+		{name: "AnchoredNested", content: "/dist", path: "src/dist", isDir: true, expected: false},
+		{name: "DoubleStar", content: "**/obj", path: "src/api/obj", isDir: true, expected: true},
+		{name: "Negated", content: "*.env\n!sample.env", path: "sample.env", expected: false},
+		{name: "Base", content: "*.js", base: "src", path: "web/index.js", expected: false},
+		{name: "BaseMatch", content: "*.js", base: "src", path: "src/index.js", expected: true},
+		{name: "Dockerignore", content: "tmp", base: "src", dockerignore: true, path: "src/api/tmp", expected: false},
+		{name: "DockerignoreRoot", content: "tmp", base: "src", dockerignore: true, path: "src/tmp", expected: true},
+		{name: "Comment", content: "# *.js", path: "index.js", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := ignoreRules(parseIgnoreRules(tt.content, tt.base, tt.dockerignore))
+			require.Equal(t, tt.expected, rules.ignored(tt.path, tt.isDir))
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
@@ -98,17 +99,29 @@ type ServiceManager interface {
 	GetServiceTarget(ctx context.Context, serviceConfig *ServiceConfig) (ServiceTarget, error)
 }
 
-// ServiceOperationCache is an alias to map used for internal caching of service operation results
+// ServiceOperationCache is used for internal caching of service operation results
 // The ServiceManager is a scoped component since it depends on the current environment
 // The ServiceOperationCache is used as a singleton cache for all service manager instances
-type ServiceOperationCache map[string]any
+type ServiceOperationCache struct {
+	// mu guards the results since services can be packaged and deployed concurrently
+	mu      sync.Mutex
+	results map[string]any
+}
+
+// NewServiceOperationCache creates a new empty cache of service operation results
+func NewServiceOperationCache() *ServiceOperationCache {
+	return &ServiceOperationCache{
+		results: map[string]any{},
+	}
+}
 
 type serviceManager struct {
 	env                 *environment.Environment
 	resourceManager     ResourceManager
 	serviceLocator      ioc.ServiceLocator
-	operationCache      ServiceOperationCache
+	operationCache      *ServiceOperationCache
 	alphaFeatureManager *alpha.FeatureManager
+	buildCache          *BuildCache
 	initialized         map[*ServiceConfig]map[any]bool
 }
 
@@ -117,8 +130,9 @@ func NewServiceManager(
 	env *environment.Environment,
 	resourceManager ResourceManager,
 	serviceLocator ioc.ServiceLocator,
-	operationCache ServiceOperationCache,
+	operationCache *ServiceOperationCache,
 	alphaFeatureManager *alpha.FeatureManager,
+	buildCache *BuildCache,
 ) ServiceManager {
 	return &serviceManager{
 		env:                 env,
@@ -126,6 +140,7 @@ func NewServiceManager(
 		serviceLocator:      serviceLocator,
 		operationCache:      operationCache,
 		alphaFeatureManager: alphaFeatureManager,
+		buildCache:          buildCache,
 		initialized:         map[*ServiceConfig]map[any]bool{},
 	}
 }
@@ -146,7 +161,7 @@ func (sm *serviceManager) GetRequiredTools(ctx context.Context, serviceConfig *S
 	requiredTools = append(requiredTools, frameworkService.RequiredExternalTools(ctx)...)
 	requiredTools = append(requiredTools, serviceTarget.RequiredExternalTools(ctx)...)
 
-	return tools.Unique(requiredTools), nil
+	return tools.Unique(withoutLocalDocker(serviceConfig, requiredTools)), nil
 }
 
 // Initializes the service configuration and dependent framework & service target
@@ -233,6 +248,15 @@ func (sm *serviceManager) Build(
 			return
 		}
 
+		hash := sm.serviceHash(serviceConfig)
+		if entry := sm.buildCacheEntry(serviceConfig, ServiceEventBuild, hash); entry != nil {
+			if buildResult, ok := entry.buildResult(); ok {
+				task.SetResult(buildResult)
+				sm.setOperationResult(serviceConfig, string(ServiceEventBuild), buildResult)
+				return
+			}
+		}
+
 		if restoreOutput == nil {
 			cachedResult, ok := sm.getOperationResult(serviceConfig, string(ServiceEventRestore))
 			if ok && cachedResult != nil {
@@ -263,6 +287,7 @@ func (sm *serviceManager) Build(
 
 		task.SetResult(buildResult)
 		sm.setOperationResult(serviceConfig, string(ServiceEventBuild), buildResult)
+		sm.setBuildCacheEntry(serviceConfig, ServiceEventBuild, hash, "", buildResult)
 	})
 }
 
@@ -286,6 +311,21 @@ func (sm *serviceManager) Package(
 			return
 		}
 
+		hash := sm.serviceHash(serviceConfig)
+		if entry := sm.buildCacheEntry(serviceConfig, ServiceEventPackage, hash); entry != nil {
+			if packageResult, ok := entry.packageResult(); ok {
+				if err := movePackage(packageResult, options.OutputPath); err != nil {
+					task.SetError(err)
+					return
+				}
+
+				task.SetResult(packageResult)
+				sm.setOperationResult(serviceConfig, string(ServiceEventPackage), packageResult)
+				sm.setBuildCacheEntry(serviceConfig, ServiceEventPackage, hash, "", packageResult)
+				return
+			}
+		}
+
 		if buildOutput == nil {
 			cachedResult, ok := sm.getOperationResult(serviceConfig, string(ServiceEventBuild))
 			if ok && cachedResult != nil {
@@ -383,46 +423,13 @@ func (sm *serviceManager) Package(
 			return
 		}
 
-		// Package path can be a file path or a container image name
-		// We only move to desired output path for file based packages
-		_, err = os.Stat(packageResult.PackagePath)
-		hasPackageFile := err == nil
-
-		if hasPackageFile && options.OutputPath != "" {
-			var destFilePath string
-			var destDirectory string
-
-			isFilePath := filepath.Ext(options.OutputPath) != ""
-			if isFilePath {
-				destFilePath = options.OutputPath
-				destDirectory = filepath.Dir(options.OutputPath)
-			} else {
-				destFilePath = filepath.Join(options.OutputPath, filepath.Base(packageResult.PackagePath))
-				destDirectory = options.OutputPath
-			}
-
-			_, err := os.Stat(destDirectory)
-			if errors.Is(err, os.ErrNotExist) {
-				// Create the desired output directory if it does not already exist
-				if err := os.MkdirAll(destDirectory, osutil.PermissionDirectory); err != nil {
-					task.SetError(fmt.Errorf("failed creating output directory '%s': %w", destDirectory, err))
-					return
-				}
-			}
-
-			// Move the package file to the desired path
-			// We can't use os.Rename here since that does not work across disks
-			if err := moveFile(packageResult.PackagePath, destFilePath); err != nil {
-				task.SetError(
-					fmt.Errorf("failed moving package file '%s' to '%s': %w", packageResult.PackagePath, destFilePath, err),
-				)
-				return
-			}
-
-			packageResult.PackagePath = destFilePath
+		if err := movePackage(packageResult, options.OutputPath); err != nil {
+			task.SetError(err)
+			return
 		}
 
 		task.SetResult(packageResult)
+		sm.setBuildCacheEntry(serviceConfig, ServiceEventPackage, hash, "", packageResult)
 	})
 }
 
@@ -441,11 +448,9 @@ func (sm *serviceManager) Deploy(
 			return
 		}
 
-		if packageResult == nil {
-			cachedResult, ok := sm.getOperationResult(serviceConfig, string(ServiceEventPackage))
-			if ok && cachedResult != nil {
-				packageResult = cachedResult.(*ServicePackageResult)
-			}
+		sourcePackage, _ := sm.getOperationResult(serviceConfig, string(ServiceEventPackage))
+		if packageResult == nil && sourcePackage != nil {
+			packageResult = sourcePackage.(*ServicePackageResult)
 		}
 
 		serviceTarget, err := sm.GetServiceTarget(ctx, serviceConfig)
@@ -506,6 +511,29 @@ func (sm *serviceManager) Deploy(
 			}
 		}
 
+		// Only deployments of the package of the service are cached, a package from elsewhere, such as --from-package,
+		// isn't related to the hash of the service
+		hash := ""
+		if packageResult != nil && sourcePackage == packageResult {
+			hash = sm.serviceHash(serviceConfig)
+		}
+
+		targetResourceKey := strings.Join([]string{
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceType(),
+			targetResource.ResourceName(),
+		}, "/")
+
+		entry := sm.buildCacheEntry(serviceConfig, ServiceEventDeploy, hash)
+		if entry != nil && entry.TargetResource == targetResourceKey {
+			if deployResult, ok := entry.deployResult(); ok {
+				task.SetResult(deployResult)
+				sm.setOperationResult(serviceConfig, string(ServiceEventDeploy), deployResult)
+				return
+			}
+		}
+
 		deployResult, err := runCommand(
 			ctx,
 			task,
@@ -530,6 +558,7 @@ func (sm *serviceManager) Deploy(
 
 		task.SetResult(deployResult)
 		sm.setOperationResult(serviceConfig, string(ServiceEventDeploy), deployResult)
+		sm.setBuildCacheEntry(serviceConfig, ServiceEventDeploy, hash, targetResourceKey, deployResult)
 	})
 }
 
@@ -549,6 +578,11 @@ func (sm *serviceManager) GetServiceTarget(ctx context.Context, serviceConfig *S
 		}
 	}
 
+	// All the plugins share the same service target, which launches the plugin of the service
+	if serviceConfig.Host.IsPlugin() {
+		host = string(PluginTarget)
+	}
+
 	if err := sm.serviceLocator.ResolveNamed(host, &target); err != nil {
 		return nil, fmt.Errorf(
 			"failed to resolve service host '%s' for service '%s', %w",
@@ -623,7 +657,11 @@ func OverriddenEndpoints(ctx context.Context, serviceConfig *ServiceConfig, env
 // Attempts to retrieve the result of a previous operation from the cache
 func (sm *serviceManager) getOperationResult(serviceConfig *ServiceConfig, operationName string) (any, bool) {
 	key := fmt.Sprintf("%s:%s:%s", sm.env.Name(), serviceConfig.Name, operationName)
-	value, ok := sm.operationCache[key]
+
+	sm.operationCache.mu.Lock()
+	defer sm.operationCache.mu.Unlock()
+
+	value, ok := sm.operationCache.results[key]
 
 	return value, ok
 }
@@ -631,7 +669,68 @@ func (sm *serviceManager) getOperationResult(serviceConfig *ServiceConfig, opera
 // Sets the result of an operation in the cache
 func (sm *serviceManager) setOperationResult(serviceConfig *ServiceConfig, operationName string, result any) {
 	key := fmt.Sprintf("%s:%s:%s", sm.env.Name(), serviceConfig.Name, operationName)
-	sm.operationCache[key] = result
+
+	sm.operationCache.mu.Lock()
+	defer sm.operationCache.mu.Unlock()
+
+	sm.operationCache.results[key] = result
+}
+
+// serviceHash returns the hash of the service for the build cache, or an empty string when the results of the service
+// aren't cached. Services without source, such as services using an existing image, and .NET Aspire services aren't
+// cached since their results depend on more than their files.
+func (sm *serviceManager) serviceHash(serviceConfig *ServiceConfig) string {
+	if sm.buildCache == nil ||
+		serviceConfig.RelativePath == "" ||
+		serviceConfig.Image != "" ||
+		serviceConfig.Host == DotNetContainerAppTarget {
+		return ""
+	}
+
+	hash, err := serviceHash(serviceConfig, sm.env)
+	if err != nil {
+		log.Printf("failed computing hash of service '%s', skipping build cache: %v", serviceConfig.Name, err)
+		return ""
+	}
+
+	return hash
+}
+
+// Attempts to retrieve the result of an operation of a previous run from the build cache
+func (sm *serviceManager) buildCacheEntry(serviceConfig *ServiceConfig, operation ext.Event, hash string) *buildCacheEntry {
+	if hash == "" {
+		return nil
+	}
+
+	entry, err := sm.buildCache.get(serviceConfig.Name, string(operation), hash)
+	if err != nil {
+		log.Printf("failed reading build cache, skipping: %v", err)
+		return nil
+	}
+
+	return entry
+}
+
+// Records the result of an operation in the build cache
+func (sm *serviceManager) setBuildCacheEntry(
+	serviceConfig *ServiceConfig,
+	operation ext.Event,
+	hash string,
+	targetResource string,
+	result any,
+) {
+	if hash == "" {
+		return
+	}
+
+	entry, err := newBuildCacheEntry(hash, targetResource, result)
+	if err == nil {
+		err = sm.buildCache.set(serviceConfig.Name, string(operation), entry)
+	}
+
+	if err != nil {
+		log.Printf("failed writing build cache for service '%s': %v", serviceConfig.Name, err)
+	}
 }
 
 // isComponentInitialized Checks if a component has been initialized for a service configuration
@@ -690,6 +789,53 @@ func syncProgress[T comparable, P comparable](task *async.TaskContextWithProgres
 	}
 }
 
+// movePackage moves the package file to the output path. Packages which aren't files, such as container images, aren't
+// moved.
+func movePackage(packageResult *ServicePackageResult, outputPath string) error {
+	// Package path can be a file path or a container image name
+	// We only move to desired output path for file based packages
+	_, err := os.Stat(packageResult.PackagePath)
+	hasPackageFile := err == nil
+
+	if !hasPackageFile || outputPath == "" {
+		return nil
+	}
+
+	var destFilePath string
+	var destDirectory string
+
+	isFilePath := filepath.Ext(outputPath) != ""
+	if isFilePath {
+		destFilePath = outputPath
+		destDirectory = filepath.Dir(outputPath)
+	} else {
+		destFilePath = filepath.Join(outputPath, filepath.Base(packageResult.PackagePath))
+		destDirectory = outputPath
+	}
+
+	// An unchanged package may already be at the desired path
+	if destFilePath == packageResult.PackagePath {
+		return nil
+	}
+
+	_, err = os.Stat(destDirectory)
+	if errors.Is(err, os.ErrNotExist) {
+		// Create the desired output directory if it does not already exist
+		if err := os.MkdirAll(destDirectory, osutil.PermissionDirectory); err != nil {
+			return fmt.Errorf("failed creating output directory '%s': %w", destDirectory, err)
+		}
+	}
+
+	// Move the package file to the desired path
+	// We can't use os.Rename here since that does not work across disks
+	if err := moveFile(packageResult.PackagePath, destFilePath); err != nil {
+		return fmt.Errorf("failed moving package file '%s' to '%s': %w", packageResult.PackagePath, destFilePath, err)
+	}
+
+	packageResult.PackagePath = destFilePath
+	return nil
+}
+
 // Copies a file from the source path to the destination path
 // Deletes the source file after the copy is complete
 func moveFile(sourcePath string, destinationPath string) error {
@@ -39,7 +39,7 @@ const (
 func createServiceManager(
 	mockContext *mocks.MockContext,
 	env *environment.Environment,
-	operationCache ServiceOperationCache,
+	operationCache *ServiceOperationCache,
 ) ServiceManager {
 	azCli := mockazcli.NewAzCliFromMockContext(mockContext)
 	depOpService := mockazcli.NewDeploymentOperationsServiceFromMockContext(mockContext)
@@ -52,14 +52,14 @@ func createServiceManager(
 			},
 		}))
 
-	return NewServiceManager(env, resourceManager, mockContext.Container, operationCache, alphaManager)
+	return NewServiceManager(env, resourceManager, mockContext.Container, operationCache, alphaManager, nil)
 }
 
 func Test_ServiceManager_GetRequiredTools(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
 	env := environment.New("test")
-	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 	tools, err := sm.GetRequiredTools(*mockContext.Context, serviceConfig)
 	require.NoError(t, err)
@@ -71,7 +71,7 @@ func Test_ServiceManager_Initialize(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
 	env := environment.New("test")
-	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 	err := sm.Initialize(*mockContext.Context, serviceConfig)
@@ -82,7 +82,7 @@ func Test_ServiceManager_Restore(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
 	env := environment.New("test")
-	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 	raisedPreRestoreEvent := false
@@ -116,7 +116,7 @@ func Test_ServiceManager_Build(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
 	env := environment.New("test")
-	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 	raisedPreBuildEvent := false
@@ -150,7 +150,7 @@ func Test_ServiceManager_Package(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
 	env := environment.New("test")
-	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 	raisedPrePackageEvent := false
@@ -189,7 +189,7 @@ func Test_ServiceManager_Deploy(t *testing.T) {
 	env := environment.NewWithValues("test", map[string]string{
 		environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
 	})
-	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 	raisedPreDeployEvent := false
@@ -224,7 +224,7 @@ func Test_ServiceManager_GetFrameworkService(t *testing.T) {
 		mockContext := mocks.NewMockContext(context.Background())
 		setupMocksForServiceManager(mockContext)
 		env := environment.New("test")
-		sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+		sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 		serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 		framework, err := sm.GetFrameworkService(*mockContext.Context, serviceConfig)
@@ -239,7 +239,7 @@ func Test_ServiceManager_GetFrameworkService(t *testing.T) {
 
 		setupMocksForServiceManager(mockContext)
 		env := environment.New("test")
-		sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+		sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 		serviceConfig := createTestServiceConfig("", ServiceTargetFake, ServiceLanguageNone)
 		serviceConfig.Image = "nginx"
 
@@ -255,7 +255,7 @@ func Test_ServiceManager_GetFrameworkService(t *testing.T) {
 
 		setupMocksForServiceManager(mockContext)
 		env := environment.New("test")
-		sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+		sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 		serviceConfig := createTestServiceConfig("", ServiceTargetFake, ServiceLanguageNone)
 
 		_, err := sm.GetFrameworkService(*mockContext.Context, serviceConfig)
@@ -267,7 +267,7 @@ func Test_ServiceManager_GetServiceTarget(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
 	env := environment.New("test")
-	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 	serviceTarget, err := sm.GetServiceTarget(*mockContext.Context, serviceConfig)
@@ -280,7 +280,7 @@ func Test_ServiceManager_CacheResults(t *testing.T) {
 	mockContext := mocks.NewMockContext(context.Background())
 	setupMocksForServiceManager(mockContext)
 	env := environment.New("test")
-	sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+	sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 	buildCalled := convert.RefOf(false)
@@ -308,7 +308,7 @@ func Test_ServiceManager_CacheResults_Across_Instances(t *testing.T) {
 	setupMocksForServiceManager(mockContext)
 	env := environment.New("test")
 
-	operationCache := ServiceOperationCache{}
+	operationCache := NewServiceOperationCache()
 
 	sm1 := createServiceManager(mockContext, env, operationCache)
 	serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
@@ -381,7 +381,7 @@ func Test_ServiceManager_Events_With_Errors(t *testing.T) {
 			env := environment.NewWithValues("test", map[string]string{
 				environment.SubscriptionIdEnvVarName: "SUBSCRIPTION_ID",
 			})
-			sm := createServiceManager(mockContext, env, ServiceOperationCache{})
+			sm := createServiceManager(mockContext, env, NewServiceOperationCache())
 			serviceConfig := createTestServiceConfig("./src/api", ServiceTargetFake, ServiceLanguageFake)
 
 			eventTypes := []string{"pre", "post"}
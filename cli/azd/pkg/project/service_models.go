@@ -44,16 +44,24 @@ type ServiceBuildResult struct {
 	Restore         *ServiceRestoreResult `json:"restore"`
 	BuildOutputPath string                `json:"buildOutputPath"`
 	Details         interface{}           `json:"details"`
+	// True when the service didn't change since it was last built, and the build was skipped
+	Unchanged bool `json:"unchanged,omitempty"`
 }
 
 // Supports rendering messages for UX items
 func (sbr *ServiceBuildResult) ToString(currentIndentation string) string {
+	var unchanged string
+	if sbr.Unchanged {
+		unchanged = fmt.Sprintf("%s- No changes since the last build\n", currentIndentation)
+	}
+
 	uxItem, ok := sbr.Details.(ux.UxItem)
 	if ok {
-		return uxItem.ToString(currentIndentation)
+		return unchanged + uxItem.ToString(currentIndentation)
 	}
 
-	return fmt.Sprintf("%s- Build Output: %s", currentIndentation, output.WithLinkFormat(sbr.BuildOutputPath))
+	return fmt.Sprintf(
+		"%s%s- Build Output: %s", unchanged, currentIndentation, output.WithLinkFormat(sbr.BuildOutputPath))
 }
 
 func (sbr *ServiceBuildResult) MarshalJSON() ([]byte, error) {
@@ -69,20 +77,28 @@ type ServicePackageResult struct {
 	Build       *ServiceBuildResult `json:"build"`
 	PackagePath string              `json:"packagePath"`
 	Details     interface{}         `json:"details"`
+	// True when the service didn't change since it was last packaged, and packaging was skipped
+	Unchanged bool `json:"unchanged,omitempty"`
 }
 
 // Supports rendering messages for UX items
 func (spr *ServicePackageResult) ToString(currentIndentation string) string {
+	var unchanged string
+	if spr.Unchanged {
+		unchanged = fmt.Sprintf("%s- No changes since the last package\n", currentIndentation)
+	}
+
 	uxItem, ok := spr.Details.(ux.UxItem)
 	if ok {
-		return uxItem.ToString(currentIndentation)
+		return unchanged + uxItem.ToString(currentIndentation)
 	}
 
 	if spr.PackagePath != "" {
-		return fmt.Sprintf("%s- Package Output: %s", currentIndentation, output.WithLinkFormat(spr.PackagePath))
+		return fmt.Sprintf(
+			"%s%s- Package Output: %s", unchanged, currentIndentation, output.WithLinkFormat(spr.PackagePath))
 	}
 
-	return ""
+	return unchanged
 }
 
 func (spr *ServicePackageResult) MarshalJSON() ([]byte, error) {
@@ -97,6 +113,10 @@ type ServiceDeployResult struct {
 	Kind             ServiceTargetKind `json:"kind"`
 	Endpoints        []string          `json:"endpoints"`
 	Details          interface{}       `json:"details"`
+	// True when the service didn't change since it was last deployed, and the deployment was skipped
+	Unchanged bool `json:"unchanged,omitempty"`
+	// The container image which was deployed, along with the revision serving it, recorded for rollbacks
+	Artifact *DeploymentArtifact `json:"artifact,omitempty"`
 }
 
 // Supports rendering messages for UX items
@@ -108,6 +128,10 @@ func (spr *ServiceDeployResult) ToString(currentIndentation string) string {
 
 	builder := strings.Builder{}
 
+	if spr.Unchanged {
+		builder.WriteString(fmt.Sprintf("%s- No changes since the last deployment\n", currentIndentation))
+	}
+
 	if len(spr.Endpoints) == 0 {
 		builder.WriteString(fmt.Sprintf("%s- No endpoints were found\n", currentIndentation))
 	} else {
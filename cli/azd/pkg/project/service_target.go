@@ -27,6 +27,8 @@ const (
 	AksTarget                ServiceTargetKind = "aks"
 	DotNetContainerAppTarget ServiceTargetKind = "containerapp-dotnet"
 	AiEndpointTarget         ServiceTargetKind = "ai.endpoint"
+	// PluginTarget is the service target of the hosts implemented by plugins, as in `host: plugin:<name>`
+	PluginTarget ServiceTargetKind = "plugin"
 )
 
 // RequiresContainer returns true if the service target runs a container image.
@@ -40,7 +42,26 @@ func (stk ServiceTargetKind) RequiresContainer() bool {
 	return false
 }
 
+// IsPlugin returns true if the service target is implemented by a plugin.
+func (stk ServiceTargetKind) IsPlugin() bool {
+	return strings.HasPrefix(string(stk), PluginTargetPrefix)
+}
+
+// PluginName returns the name of the plugin implementing the service target.
+func (stk ServiceTargetKind) PluginName() string {
+	return strings.TrimPrefix(string(stk), PluginTargetPrefix)
+}
+
 func parseServiceHost(kind ServiceTargetKind) (ServiceTargetKind, error) {
+	if kind.IsPlugin() {
+		if !pluginNameRegex.MatchString(kind.PluginName()) {
+			return ServiceTargetKind(""), fmt.Errorf(
+				"invalid host '%s': the plugin name must contain only lowercase letters, digits, '-', '_' and '.'", kind)
+		}
+
+		return kind, nil
+	}
+
 	switch kind {
 
 	// NOTE: We do not support DotNetContainerAppTarget as a listed service host type in azure.yaml, hence
@@ -92,6 +113,34 @@ type ServiceTarget interface {
 	) ([]string, error)
 }
 
+// RolloutTarget is implemented by the service targets which can leave a deployment rolled out to only part of the
+// traffic, as configured by the deployment strategy of the service.
+type RolloutTarget interface {
+	// Promote sends all the traffic to the revision being rolled out, and retires the previous revision
+	Promote(ctx context.Context, serviceConfig *ServiceConfig, targetResource *environment.TargetResource) error
+
+	// Abort sends all the traffic back to the previous revision, and retires the revision being rolled out
+	Abort(ctx context.Context, serviceConfig *ServiceConfig, targetResource *environment.TargetResource) error
+}
+
+// SlotTarget is implemented by the service targets which can deploy to a deployment slot, instead of the production slot
+type SlotTarget interface {
+	// SwapSlot swaps the specified deployment slot with the production slot
+	SwapSlot(
+		ctx context.Context,
+		serviceConfig *ServiceConfig,
+		targetResource *environment.TargetResource,
+		slotName string,
+	) error
+}
+
+// PreviewTarget is implemented by the service targets which can show the changes a deployment would make
+type PreviewTarget interface {
+	// Preview returns the changes deploying the service would make to the target resource, without deploying it.
+	// An empty preview means the deployment wouldn't change anything.
+	Preview(ctx context.Context, serviceConfig *ServiceConfig, targetResource *environment.TargetResource) (string, error)
+}
+
 // NewServiceDeployResult is a helper function to create a new ServiceDeployResult
 func NewServiceDeployResult(
 	relatedResourceId string,
@@ -137,7 +186,8 @@ func resourceTypeMismatchError(
 // As an example, ContainerAppTarget is able to provision the container app as part of deployment,
 // and thus returns true.
 func (st ServiceTargetKind) SupportsDelayedProvisioning() bool {
-	return st == AksTarget
+	// Plugins can deploy to resources which aren't Azure resources tagged with the name of the service
+	return st == AksTarget || st.IsPlugin()
 }
 
 func checkResourceType(resource *environment.TargetResource, expectedResourceType infra.AzureResourceType) error {
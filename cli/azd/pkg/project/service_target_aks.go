@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -203,6 +204,8 @@ func (t *aksTarget) Deploy(
 				return
 			}
 
+			var artifact *DeploymentArtifact
+
 			// Only deploy the container image if a package output has been defined
 			// Empty package details is a valid scenario for any AKS deployment that does not build any containers
 			// Ex) Helm charts, or other manifests that reference external images
@@ -211,15 +214,20 @@ func (t *aksTarget) Deploy(
 				containerDeployTask := t.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true)
 				syncProgress(task, containerDeployTask.Progress())
 
-				_, err := containerDeployTask.Await()
+				containerDeployResult, err := containerDeployTask.Await()
 				if err != nil {
 					task.SetError(err)
 					return
 				}
+
+				artifact = newContainerDeploymentArtifact(containerDeployResult, "")
 			}
 
 			// Sync environment
-			t.kubectl.SetEnv(t.env.Dotenv())
+			if err := t.syncKubectlEnv(ctx); err != nil {
+				task.SetError(err)
+				return
+			}
 
 			// Deploy k8s resources in the following order:
 			// 1. Helm
@@ -295,6 +303,7 @@ func (t *aksTarget) Deploy(
 				Kind:      AksTarget,
 				Details:   deployment,
 				Endpoints: endpoints,
+				Artifact:  artifact,
 			})
 		})
 }
@@ -457,13 +466,41 @@ func (t *aksTarget) deployHelmCharts(
 			return false, err
 		}
 
-		task.SetProgress(NewServiceProgress(fmt.Sprintf("Installing helm release: %s", release.Name)))
-		if err := t.helmCli.Upgrade(ctx, release); err != nil {
+		if err := t.deployHelmRelease(ctx, serviceConfig, release, task); err != nil {
 			return false, err
 		}
+	}
+
+	return true, nil
+}
 
+// deployHelmRelease upgrades the helm release and waits for it to be deployed. When the upgrade fails, the release is
+// rolled back to the revision deployed before the upgrade.
+func (t *aksTarget) deployHelmRelease(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	release *helm.Release,
+	task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress],
+) error {
+	options, cleanup, err := t.renderHelmValues(ctx, serviceConfig, release)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	// The release doesn't exist on the first deployment, so there is no revision to roll back to
+	previousRevision := 0
+	if status, err := t.helmCli.Status(ctx, release); err != nil {
+		log.Printf("helm release '%s' has no previous revision: %v", release.Name, err)
+	} else if status.Info.Status == helm.StatusKindDeployed {
+		previousRevision = int(status.Version)
+	}
+
+	task.SetProgress(NewServiceProgress(fmt.Sprintf("Installing helm release: %s", release.Name)))
+	err = t.helmCli.Upgrade(ctx, release, options)
+	if err == nil {
 		task.SetProgress(NewServiceProgress(fmt.Sprintf("Checking helm release status: %s", release.Name)))
-		err := retry.Do(
+		err = retry.Do(
 			ctx,
 			retry.WithMaxDuration(10*time.Minute, retry.NewConstant(5*time.Second)),
 			func(ctx context.Context) error {
@@ -472,6 +509,10 @@ func (t *aksTarget) deployHelmCharts(
 					return err
 				}
 
+				if status.Info.Status == helm.StatusKindFailed {
+					return fmt.Errorf("helm release '%s' failed", release.Name)
+				}
+
 				if status.Info.Status != helm.StatusKindDeployed {
 					fmt.Printf("Status: %s\n", status.Info.Status)
 					return retry.RetryableError(
@@ -482,13 +523,188 @@ func (t *aksTarget) deployHelmCharts(
 				return nil
 			},
 		)
+	}
+
+	if err != nil && previousRevision > 0 {
+		task.SetProgress(NewServiceProgress(fmt.Sprintf("Rolling back helm release: %s", release.Name)))
+		if rollbackErr := t.helmCli.Rollback(ctx, release, previousRevision); rollbackErr != nil {
+			return fmt.Errorf("%w, and the rollback of the release failed: %w", err, rollbackErr)
+		}
+
+		return fmt.Errorf("helm release '%s' was rolled back to revision %d: %w", release.Name, previousRevision, err)
+	}
+
+	return err
+}
+
+// renderHelmValues renders the values files of the release, which support environment variable substitution, to a
+// temporary directory, and resolves the values set on the command line. The returned function removes the rendered
+// values files. The values referencing Key Vault secrets are substituted with the values of the secrets.
+func (t *aksTarget) renderHelmValues(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	release *helm.Release,
+) (*helm.UpgradeOptions, func(), error) {
+	options := &helm.UpgradeOptions{}
+	cleanup := func() {}
+
+	var resolveErr error
+	getenv := func(name string) string {
+		value, err := t.env.ResolvedGetenv(ctx, name)
+		if err != nil && resolveErr == nil {
+			resolveErr = err
+		}
 
+		return value
+	}
+
+	valuesFiles := release.ValuesFiles
+	if release.Values != "" {
+		valuesFiles = append([]string{release.Values}, release.ValuesFiles...)
+	}
+
+	if len(valuesFiles) > 0 {
+		renderDir, err := os.MkdirTemp("", "azd-helm")
 		if err != nil {
-			return false, err
+			return nil, nil, fmt.Errorf("creating helm values directory: %w", err)
+		}
+
+		cleanup = func() {
+			_ = os.RemoveAll(renderDir)
+		}
+
+		for i, valuesFile := range valuesFiles {
+			valuesPath := valuesFile
+			if !filepath.IsAbs(valuesPath) {
+				valuesPath = filepath.Join(serviceConfig.Path(), valuesPath)
+			}
+
+			content, err := os.ReadFile(valuesPath)
+			if err != nil {
+				cleanup()
+				return nil, nil, fmt.Errorf("reading helm values file: %w", err)
+			}
+
+			rendered, err := osutil.NewExpandableString(string(content)).Envsubst(getenv)
+			if err != nil {
+				cleanup()
+				return nil, nil, fmt.Errorf("rendering helm values file '%s': %w", valuesFile, err)
+			}
+
+			if resolveErr != nil {
+				cleanup()
+				return nil, nil, fmt.Errorf("rendering helm values file '%s': %w", valuesFile, resolveErr)
+			}
+
+			// The rendered files are prefixed by their position, since values files may share the same name. They are
+			// only readable by the user, since the values may include secrets.
+			renderedPath := filepath.Join(renderDir, fmt.Sprintf("%d-%s", i, filepath.Base(valuesPath)))
+			if err := os.WriteFile(renderedPath, []byte(rendered), osutil.PermissionFileOwnerOnly); err != nil {
+				cleanup()
+				return nil, nil, fmt.Errorf("writing helm values file: %w", err)
+			}
+
+			options.ValuesFiles = append(options.ValuesFiles, renderedPath)
 		}
 	}
 
-	return true, nil
+	keys := make([]string, 0, len(release.Set))
+	for key := range release.Set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		// Values resolved from the environment may be secrets, such as connection strings
+		resolvedFromEnv := false
+		value, err := release.Set[key].Envsubst(func(name string) string {
+			resolvedFromEnv = true
+			return getenv(name)
+		})
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("resolving helm value '%s': %w", key, err)
+		}
+
+		if resolveErr != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("resolving helm value '%s': %w", key, resolveErr)
+		}
+
+		options.Set = append(options.Set, fmt.Sprintf("%s=%s", key, value))
+		if resolvedFromEnv && value != "" {
+			options.SensitiveValues = append(options.SensitiveValues, value)
+		}
+	}
+
+	return options, cleanup, nil
+}
+
+// Preview returns the changes deploying the helm releases of the service would make to the cluster, without deploying
+// them. The k8s manifests applied with kubectl or kustomize aren't previewed.
+func (t *aksTarget) Preview(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) (string, error) {
+	if serviceConfig.K8s.Helm == nil {
+		return "", nil
+	}
+
+	if !t.featureManager.IsEnabled(featureHelm) {
+		return "", fmt.Errorf("Helm support is not enabled. Run '%s' to enable it.", alpha.GetEnableCommand(featureHelm))
+	}
+
+	if err := t.syncKubectlEnv(ctx); err != nil {
+		return "", err
+	}
+
+	if _, err := t.ensureClusterContext(ctx, serviceConfig, targetResource, t.getK8sNamespace(serviceConfig)); err != nil {
+		return "", err
+	}
+
+	for _, repo := range serviceConfig.K8s.Helm.Repositories {
+		if err := t.helmCli.AddRepo(ctx, repo); err != nil {
+			return "", err
+		}
+
+		if err := t.helmCli.UpdateRepo(ctx, repo.Name); err != nil {
+			return "", err
+		}
+	}
+
+	diffs := []string{}
+	for _, release := range serviceConfig.K8s.Helm.Releases {
+		if release.Namespace == "" {
+			release.Namespace = t.getK8sNamespace(serviceConfig)
+		}
+
+		diff, err := t.diffHelmRelease(ctx, serviceConfig, release)
+		if err != nil {
+			return "", err
+		}
+
+		if diff != "" {
+			diffs = append(diffs, diff)
+		}
+	}
+
+	return strings.Join(diffs, "\n"), nil
+}
+
+// diffHelmRelease returns the changes upgrading the helm release with its rendered values would make
+func (t *aksTarget) diffHelmRelease(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	release *helm.Release,
+) (string, error) {
+	options, cleanup, err := t.renderHelmValues(ctx, serviceConfig, release)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	return t.helmCli.Diff(ctx, release, options)
 }
 
 // Gets the service endpoints for the AKS service target
@@ -838,8 +1054,23 @@ func (t *aksTarget) getK8sNamespace(serviceConfig *ServiceConfig) string {
 	return namespace
 }
 
+// syncKubectlEnv sets the env vars of kubectl to the values of the environment, with the values referencing secrets
+// replaced by the values of the secrets
+func (t *aksTarget) syncKubectlEnv(ctx context.Context) error {
+	envValues, err := t.env.ResolvedDotenv(ctx)
+	if err != nil {
+		return err
+	}
+
+	t.kubectl.SetEnv(envValues)
+	return nil
+}
+
 func (t *aksTarget) setK8sContext(ctx context.Context, serviceConfig *ServiceConfig, eventName ext.Event) error {
-	t.kubectl.SetEnv(t.env.Dotenv())
+	if err := t.syncKubectlEnv(ctx); err != nil {
+		return err
+	}
+
 	hasCustomKubeConfig := false
 
 	// If a KUBECONFIG env var is set, use it.
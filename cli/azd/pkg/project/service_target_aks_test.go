@@ -3,11 +3,13 @@ package project
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -33,6 +35,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockazsdk"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 	"github.com/azure/azure-dev/cli/azd/test/ostest"
 	"github.com/benbjohnson/clock"
 	"github.com/stretchr/testify/mock"
@@ -312,6 +315,214 @@ func Test_Deploy_Helm(t *testing.T) {
 	require.Contains(t, strings.Join(helmStatus.Args, " "), "status argocd")
 }
 
+func Test_Deploy_Helm_Values(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockResults, err := setupMocksForHelm(mockContext)
+	require.NoError(t, err)
+
+	// The rendered values files are removed once deployed, so they are read when upgrading
+	renderedValues := []string{}
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm upgrade")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["helm-upgrade"] = args
+		for i, arg := range args.Args {
+			if arg == "--values" {
+				content, err := os.ReadFile(args.Args[i+1])
+				require.NoError(t, err)
+				renderedValues = append(renderedValues, string(content))
+
+				if runtime.GOOS != "windows" {
+					info, err := os.Stat(args.Args[i+1])
+					require.NoError(t, err)
+					require.Equal(t, osutil.PermissionFileOwnerOnly, info.Mode().Perm())
+				}
+			}
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := *createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.RelativePath = ""
+	serviceConfig.K8s.Helm = &helm.Config{
+		Releases: []*helm.Release{
+			{
+				Name:        "api",
+				Chart:       "./chart",
+				Values:      "values.yaml",
+				ValuesFiles: []string{"values.dev.yaml", "overrides/values.yaml"},
+				Set: map[string]osutil.ExpandableString{
+					"image.repository":  osutil.NewExpandableString("${AZURE_CONTAINER_REGISTRY_ENDPOINT}/api"),
+					"db.password":       osutil.NewExpandableString("${DB_PASSWORD}"),
+					"ingress.hosts":     osutil.NewExpandableString("{a.contoso.com,b.contoso.com}"),
+					"autoscaling.limit": osutil.NewExpandableString("3"),
+				},
+			},
+		},
+	}
+
+	files := map[string]string{
+		"values.yaml":           "registry: ${AZURE_CONTAINER_REGISTRY_ENDPOINT}\n",
+		"values.dev.yaml":       "replicas: 1\n",
+		"overrides/values.yaml": "location: ${AZURE_LOCATION}\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(serviceConfig.Path(), filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+	}
+
+	// The values referencing Key Vault secrets are resolved
+	env := createEnv()
+	env.DotenvSet("DB_PASSWORD", "akvs://contoso-kv/db-password")
+	env.SetSecretResolver(&testSecretResolver{secrets: map[string]string{"akvs://contoso-kv/db-password": "p@ss"}})
+	userConfig := config.NewConfig(nil)
+	_ = userConfig.Set("alpha.aks.helm", "on")
+
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, userConfig)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, &serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(infra.AzureResourceTypeManagedCluster))
+	deployTask := serviceTarget.Deploy(*mockContext.Context, &serviceConfig, &ServicePackageResult{}, scope)
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+	require.NoError(t, err)
+
+	// The values files are rendered in order
+	require.Equal(t, []string{
+		"registry: REGISTRY.azurecr.io\n",
+		"replicas: 1\n",
+		"location: LOCATION\n",
+	}, renderedValues)
+
+	helmUpgrade := strings.Join(mockResults["helm-upgrade"].Args, " ")
+	require.Contains(t, helmUpgrade,
+		"--set autoscaling.limit=3 "+
+			"--set db.password=p@ss "+
+			"--set image.repository=REGISTRY.azurecr.io/api "+
+			"--set ingress.hosts={a.contoso.com,b.contoso.com}")
+
+	// Only the values resolved from the environment are redacted from the logs
+	require.Equal(t, []string{"p@ss", "REGISTRY.azurecr.io/api"}, mockResults["helm-upgrade"].SensitiveData)
+}
+
+func Test_Deploy_Helm_Rollback(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockResults, err := setupMocksForHelm(mockContext)
+	require.NoError(t, err)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm status")
+	}).Respond(exec.NewRunResult(0, `{"version": 3, "info": {"status": "deployed"}}`, ""))
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm upgrade")
+	}).SetError(errors.New("timed out waiting for the condition"))
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm rollback")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["helm-rollback"] = args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	serviceConfig := *createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.RelativePath = ""
+	serviceConfig.K8s.Helm = &helm.Config{
+		Releases: []*helm.Release{
+			{
+				Name:  "api",
+				Chart: "./chart",
+			},
+		},
+	}
+
+	env := createEnv()
+	userConfig := config.NewConfig(nil)
+	_ = userConfig.Set("alpha.aks.helm", "on")
+
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, userConfig)
+	err = simulateInitliaze(*mockContext.Context, serviceTarget, &serviceConfig)
+	require.NoError(t, err)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(infra.AzureResourceTypeManagedCluster))
+	deployTask := serviceTarget.Deploy(*mockContext.Context, &serviceConfig, &ServicePackageResult{}, scope)
+	logProgress(deployTask)
+	_, err = deployTask.Await()
+	require.ErrorContains(t, err, "helm release 'api' was rolled back to revision 3")
+	require.ErrorContains(t, err, "timed out waiting for the condition")
+
+	helmRollback, helmRollbackCalled := mockResults["helm-rollback"]
+	require.True(t, helmRollbackCalled)
+	require.Equal(t, []string{"rollback", "api", "3", "--wait", "--namespace", "Test-App"}, helmRollback.Args)
+}
+
+func Test_Preview_Helm(t *testing.T) {
+	tempDir := t.TempDir()
+	ostest.Chdir(t, tempDir)
+
+	mockContext := mocks.NewMockContext(context.Background())
+	err := setupMocksForAksTarget(mockContext)
+	require.NoError(t, err)
+
+	mockResults, err := setupMocksForHelm(mockContext)
+	require.NoError(t, err)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "helm diff upgrade")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		mockResults["helm-diff"] = args
+		return exec.NewRunResult(0, "Test-App, api, Deployment (apps) has changed:", ""), nil
+	})
+
+	serviceConfig := *createTestServiceConfig(tempDir, AksTarget, ServiceLanguageTypeScript)
+	serviceConfig.RelativePath = ""
+	serviceConfig.K8s.Helm = &helm.Config{
+		Releases: []*helm.Release{
+			{
+				Name:  "api",
+				Chart: "./chart",
+				Set:   map[string]osutil.ExpandableString{"replicas": osutil.NewExpandableString("2")},
+			},
+		},
+	}
+
+	env := createEnv()
+	userConfig := config.NewConfig(nil)
+	_ = userConfig.Set("alpha.aks.helm", "on")
+
+	serviceTarget := createAksServiceTarget(mockContext, &serviceConfig, env, userConfig)
+	previewTarget, ok := serviceTarget.(PreviewTarget)
+	require.True(t, ok)
+
+	scope := environment.NewTargetResource("SUB_ID", "RG_ID", "", string(infra.AzureResourceTypeManagedCluster))
+	preview, err := previewTarget.Preview(*mockContext.Context, &serviceConfig, scope)
+	require.NoError(t, err)
+	require.Equal(t, "Test-App, api, Deployment (apps) has changed:", preview)
+
+	require.Equal(t, []string{
+		"diff", "upgrade", "api", "./chart", "--allow-unreleased", "--set", "replicas=2", "--namespace", "Test-App",
+	}, mockResults["helm-diff"].Args)
+
+	// Nothing is deployed
+	_, helmUpgradeCalled := mockResults["helm-upgrade"]
+	require.False(t, helmUpgradeCalled)
+}
+
 func Test_Deploy_Kustomize(t *testing.T) {
 	tempDir := t.TempDir()
 	ostest.Chdir(t, tempDir)
@@ -765,6 +976,13 @@ func setupMocksForDocker(mockContext *mocks.MockContext) {
 	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
 		return exec.NewRunResult(0, "", ""), nil
 	})
+
+	// Inspect the digest of the pushed image
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "docker image inspect")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		return exec.NewRunResult(0, "[]", ""), nil
+	})
 }
 
 func createK8sResourceList[T any](resource T) *kubectl.List[T] {
@@ -838,6 +1056,8 @@ func createAksServiceTarget(
 		containerRegistryService,
 		dockerCli,
 		cloud.AzurePublic(),
+		mockinput.NewMockConsole(),
+		nil,
 	)
 
 	if userConfig == nil {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
@@ -17,6 +18,42 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/azcli"
 )
 
+// AppServiceOptions are the options of the services deployed to Azure App Service
+type AppServiceOptions struct {
+	// The deployment slot the service is deployed to, instead of the production slot
+	Slot string `yaml:"slot,omitempty"`
+}
+
+// The name of a deployment slot
+var appServiceSlotRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-]*$`)
+
+// ValidateAppServiceSlot validates the name of a deployment slot
+func ValidateAppServiceSlot(slotName string) error {
+	if !appServiceSlotRegex.MatchString(slotName) {
+		return fmt.Errorf(
+			"invalid deployment slot '%s': must contain only letters, digits and hyphens, and start with a letter or "+
+				"a digit", slotName)
+	}
+
+	if strings.EqualFold(slotName, "production") {
+		return fmt.Errorf("invalid deployment slot '%s': the production slot can't be used as a deployment slot", slotName)
+	}
+
+	return nil
+}
+
+func validateAppServiceOptions(serviceConfig *ServiceConfig) error {
+	if serviceConfig.AppService.Slot == "" {
+		return nil
+	}
+
+	if serviceConfig.Host != AppServiceTarget {
+		return fmt.Errorf("the 'appservice' options are only supported for the '%s' host", AppServiceTarget)
+	}
+
+	return ValidateAppServiceSlot(serviceConfig.AppService.Slot)
+}
+
 type appServiceTarget struct {
 	env *environment.Environment
 	cli azcli.AzCli
@@ -70,7 +107,8 @@ func (st *appServiceTarget) Package(
 	)
 }
 
-// Deploys the prepared zip archive using Zip deploy to the Azure App Service resource
+// Deploys the prepared zip archive using Zip deploy to the Azure App Service resource, or to its deployment slot when one is
+// configured
 func (st *appServiceTarget) Deploy(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
@@ -93,14 +131,29 @@ func (st *appServiceTarget) Deploy(
 			defer os.Remove(packageOutput.PackagePath)
 			defer zipFile.Close()
 
-			task.SetProgress(NewServiceProgress("Uploading deployment package"))
-			res, err := st.cli.DeployAppServiceZip(
-				ctx,
-				targetResource.SubscriptionId(),
-				targetResource.ResourceGroupName(),
-				targetResource.ResourceName(),
-				zipFile,
-			)
+			slotName := serviceConfig.AppService.Slot
+
+			var res *string
+			if slotName == "" {
+				task.SetProgress(NewServiceProgress("Uploading deployment package"))
+				res, err = st.cli.DeployAppServiceZip(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					zipFile,
+				)
+			} else {
+				task.SetProgress(NewServiceProgress(fmt.Sprintf("Uploading deployment package to slot %s", slotName)))
+				res, err = st.cli.DeployAppServiceSlotZip(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					slotName,
+					zipFile,
+				)
+			}
 			if err != nil {
 				task.SetError(fmt.Errorf("deploying service %s: %w", serviceConfig.Name, err))
 				return
@@ -113,12 +166,17 @@ func (st *appServiceTarget) Deploy(
 				return
 			}
 
+			resourceId := azure.WebsiteRID(
+				targetResource.SubscriptionId(),
+				targetResource.ResourceGroupName(),
+				targetResource.ResourceName(),
+			)
+			if slotName != "" {
+				resourceId = fmt.Sprintf("%s/slots/%s", resourceId, slotName)
+			}
+
 			sdr := NewServiceDeployResult(
-				azure.WebsiteRID(
-					targetResource.SubscriptionId(),
-					targetResource.ResourceGroupName(),
-					targetResource.ResourceName(),
-				),
+				resourceId,
 				AppServiceTarget,
 				*res,
 				endpoints,
@@ -130,18 +188,30 @@ func (st *appServiceTarget) Deploy(
 	)
 }
 
-// Gets the exposed endpoints for the App Service
+// Gets the exposed endpoints for the App Service, which are the endpoints of its deployment slot when one is configured
 func (st *appServiceTarget) Endpoints(
 	ctx context.Context,
 	serviceConfig *ServiceConfig,
 	targetResource *environment.TargetResource,
 ) ([]string, error) {
-	appServiceProperties, err := st.cli.GetAppServiceProperties(
-		ctx,
-		targetResource.SubscriptionId(),
-		targetResource.ResourceGroupName(),
-		targetResource.ResourceName(),
-	)
+	var appServiceProperties *azcli.AzCliAppServiceProperties
+	var err error
+	if serviceConfig.AppService.Slot == "" {
+		appServiceProperties, err = st.cli.GetAppServiceProperties(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		)
+	} else {
+		appServiceProperties, err = st.cli.GetAppServiceSlotProperties(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+			serviceConfig.AppService.Slot,
+		)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("fetching service properties: %w", err)
 	}
@@ -154,6 +224,34 @@ func (st *appServiceTarget) Endpoints(
 	return endpoints, nil
 }
 
+// SwapSlot swaps the specified deployment slot of the App Service with its production slot
+func (st *appServiceTarget) SwapSlot(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	slotName string,
+) error {
+	if err := st.validateTargetResource(targetResource); err != nil {
+		return fmt.Errorf("validating target resource: %w", err)
+	}
+
+	if err := ValidateAppServiceSlot(slotName); err != nil {
+		return err
+	}
+
+	if err := st.cli.SwapAppServiceSlot(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		slotName,
+	); err != nil {
+		return fmt.Errorf("swapping slot of service %s: %w", serviceConfig.Name, err)
+	}
+
+	return nil
+}
+
 func (st *appServiceTarget) validateTargetResource(
 	targetResource *environment.TargetResource,
 ) error {
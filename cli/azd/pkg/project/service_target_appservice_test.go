@@ -53,3 +53,29 @@ func TestNewAppServiceTargetTypeValidation(t *testing.T) {
 		})
 	}
 }
+
+func Test_ValidateAppServiceOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    ServiceTargetKind
+		slot    string
+		wantErr bool
+	}{
+		{name: "NoSlot", host: ContainerAppTarget},
+		{name: "Slot", host: AppServiceTarget, slot: "staging"},
+		{name: "UnsupportedHost", host: AzureFunctionTarget, slot: "staging", wantErr: true},
+		{name: "InvalidSlot", host: AppServiceTarget, slot: "-staging", wantErr: true},
+		{name: "ProductionSlot", host: AppServiceTarget, slot: "Production", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAppServiceOptions(&ServiceConfig{Host: tt.host, AppService: AppServiceOptions{Slot: tt.slot}})
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -89,7 +89,7 @@ func (at *containerAppTarget) Deploy(
 			containerDeployTask := at.containerHelper.Deploy(ctx, serviceConfig, packageOutput, targetResource, true)
 			syncProgress(task, containerDeployTask.Progress())
 
-			_, err := containerDeployTask.Await()
+			containerDeployResult, err := containerDeployTask.Await()
 			if err != nil {
 				task.SetError(err)
 				return
@@ -100,17 +100,43 @@ func (at *containerAppTarget) Deploy(
 			progressLog := func(msg string) {
 				task.SetProgress(NewServiceProgress(msg))
 			}
-			err = at.containerAppService.AddRevision(
-				ctx,
-				targetResource.SubscriptionId(),
-				targetResource.ResourceGroupName(),
-				targetResource.ResourceName(),
-				imageName,
-				progressLog,
-			)
-			if err != nil {
-				task.SetError(fmt.Errorf("updating container app service: %w", err))
-				return
+
+			var revisionName string
+			var rolloutResult *containerAppRolloutResult
+			if serviceConfig.Deployment.Strategy == DeploymentStrategyNone {
+				revisionName, err = at.containerAppService.AddRevision(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					imageName,
+					progressLog,
+				)
+				if err != nil {
+					task.SetError(fmt.Errorf("updating container app service: %w", err))
+					return
+				}
+			} else {
+				// The new revision doesn't receive any traffic until it is rolled out by the deployment strategy
+				traffic, err := at.containerAppService.AddCandidateRevision(
+					ctx,
+					targetResource.SubscriptionId(),
+					targetResource.ResourceGroupName(),
+					targetResource.ResourceName(),
+					imageName,
+					progressLog,
+				)
+				if err != nil {
+					task.SetError(fmt.Errorf("updating container app service: %w", err))
+					return
+				}
+
+				revisionName = traffic.CandidateRevision
+				rolloutResult, err = at.rollout(ctx, serviceConfig, targetResource, traffic, progressLog)
+				if err != nil {
+					task.SetError(fmt.Errorf("rolling out revision %s: %w", traffic.CandidateRevision, err))
+					return
+				}
 			}
 
 			task.SetProgress(NewServiceProgress("Fetching endpoints for container app service"))
@@ -120,7 +146,7 @@ func (at *containerAppTarget) Deploy(
 				return
 			}
 
-			task.SetResult(&ServiceDeployResult{
+			deployResult := &ServiceDeployResult{
 				Package: packageOutput,
 				TargetResourceId: azure.ContainerAppRID(
 					targetResource.SubscriptionId(),
@@ -129,7 +155,16 @@ func (at *containerAppTarget) Deploy(
 				),
 				Kind:      ContainerAppTarget,
 				Endpoints: endpoints,
-			})
+				Artifact:  newContainerDeploymentArtifact(containerDeployResult, revisionName),
+			}
+
+			// A rollout left in progress is reported along with the endpoints
+			if rolloutResult != nil {
+				rolloutResult.Endpoints = endpoints
+				deployResult.Details = rolloutResult
+			}
+
+			task.SetResult(deployResult)
 		},
 	)
 }
@@ -0,0 +1,330 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// DeploymentStrategyKind is the strategy used to move the traffic of a service to a new deployment
+type DeploymentStrategyKind string
+
+const (
+	// The new revision receives all the traffic once it is ready
+	DeploymentStrategyNone DeploymentStrategyKind = ""
+	// The traffic is moved to the new revision in steps
+	DeploymentStrategyCanary DeploymentStrategyKind = "canary"
+	// The new revision doesn't receive any traffic, but is reachable through a preview URL until it is promoted
+	DeploymentStrategyBlueGreen DeploymentStrategyKind = "bluegreen"
+)
+
+// The default label of the revision being rolled out with the bluegreen strategy
+const defaultBlueGreenLabel = "green"
+
+var revisionLabelRegex = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// The deployment strategy options
+type DeploymentOptions struct {
+	// The strategy used to move the traffic to the new revision, ex) canary, bluegreen
+	Strategy DeploymentStrategyKind `yaml:"strategy,omitempty"`
+	// The options of the canary strategy
+	Canary *CanaryOptions `yaml:"canary,omitempty"`
+	// The options of the bluegreen strategy
+	BlueGreen *BlueGreenOptions `yaml:"blueGreen,omitempty"`
+}
+
+// The canary strategy options
+type CanaryOptions struct {
+	// The steps of the rollout, in order. The rollout is left in progress unless the last step sends all the traffic to
+	// the new revision.
+	Steps []CanaryStep `yaml:"steps"`
+}
+
+// A step of a canary rollout
+type CanaryStep struct {
+	// The percentage of the traffic sent to the new revision
+	Weight int `yaml:"weight"`
+	// How long to wait before the next step, ex) 5m, 1h30m
+	Interval string `yaml:"interval,omitempty"`
+}
+
+// The bluegreen strategy options
+type BlueGreenOptions struct {
+	// The label of the new revision, which makes the revision reachable through its own URL. Defaults to 'green'
+	Label string `yaml:"label,omitempty"`
+}
+
+// label returns the label of the new revision
+func (o *BlueGreenOptions) label() string {
+	if o == nil || o.Label == "" {
+		return defaultBlueGreenLabel
+	}
+
+	return o.Label
+}
+
+func validateDeploymentOptions(serviceConfig *ServiceConfig) error {
+	options := serviceConfig.Deployment
+
+	switch options.Strategy {
+	case DeploymentStrategyNone:
+		return nil
+	case DeploymentStrategyCanary, DeploymentStrategyBlueGreen:
+	default:
+		return fmt.Errorf("unsupported deployment strategy '%s'", options.Strategy)
+	}
+
+	if serviceConfig.Host != ContainerAppTarget {
+		return fmt.Errorf(
+			"the '%s' deployment strategy is only supported for the '%s' host", options.Strategy, ContainerAppTarget)
+	}
+
+	if options.Strategy == DeploymentStrategyBlueGreen {
+		if label := options.BlueGreen.label(); !revisionLabelRegex.MatchString(label) {
+			return fmt.Errorf(
+				"invalid label '%s': must start with a lowercase letter and contain only lowercase letters, digits and "+
+					"hyphens", label)
+		}
+
+		return nil
+	}
+
+	if options.Canary == nil || len(options.Canary.Steps) == 0 {
+		return errors.New("the 'canary' deployment strategy requires at least one step")
+	}
+
+	previousWeight := 0
+	for i, step := range options.Canary.Steps {
+		if step.Weight < 1 || step.Weight > 100 {
+			return fmt.Errorf("canary step %d: the weight must be between 1 and 100", i+1)
+		}
+
+		if step.Weight <= previousWeight {
+			return fmt.Errorf("canary step %d: the weight must be greater than the weight of the previous step", i+1)
+		}
+
+		if step.Interval != "" {
+			if _, err := time.ParseDuration(step.Interval); err != nil {
+				return fmt.Errorf("canary step %d: invalid interval '%s': %w", i+1, step.Interval, err)
+			}
+		}
+
+		previousWeight = step.Weight
+	}
+
+	return nil
+}
+
+// containerAppRolloutResult is the details of a deployment which left a rollout in progress
+type containerAppRolloutResult struct {
+	Strategy          DeploymentStrategyKind `json:"strategy"`
+	ServiceName       string                 `json:"-"`
+	Endpoints         []string               `json:"-"`
+	StableRevision    string                 `json:"stableRevision"`
+	CandidateRevision string                 `json:"candidateRevision"`
+	CandidateWeight   int32                  `json:"candidateWeight"`
+	PreviewEndpoint   string                 `json:"previewEndpoint,omitempty"`
+}
+
+func (r *containerAppRolloutResult) ToString(currentIndentation string) string {
+	builder := strings.Builder{}
+
+	for _, endpoint := range r.Endpoints {
+		builder.WriteString(fmt.Sprintf("%s- Endpoint: %s\n", currentIndentation, output.WithLinkFormat(endpoint)))
+	}
+
+	if r.PreviewEndpoint != "" {
+		builder.WriteString(
+			fmt.Sprintf("%s- Preview endpoint: %s\n", currentIndentation, output.WithLinkFormat(r.PreviewEndpoint)))
+	}
+
+	builder.WriteString(fmt.Sprintf(
+		"%s- Revision %s receives %d%% of the traffic, revision %s receives the rest\n",
+		currentIndentation,
+		r.CandidateRevision,
+		r.CandidateWeight,
+		r.StableRevision,
+	))
+	builder.WriteString(fmt.Sprintf(
+		"%s- Run %s to complete the rollout, or %s to revert it\n",
+		currentIndentation,
+		output.WithHighLightFormat("azd deploy %s --promote", r.ServiceName),
+		output.WithHighLightFormat("azd deploy %s --abort", r.ServiceName),
+	))
+
+	return builder.String()
+}
+
+func (r *containerAppRolloutResult) MarshalJSON() ([]byte, error) {
+	// Avoid infinite recursion, since the type implements json.Marshaler
+	type rolloutResult containerAppRolloutResult
+	return json.Marshal((*rolloutResult)(r))
+}
+
+// rollout moves the traffic to the candidate revision following the deployment strategy of the service, and returns the
+// details of the rollout when it is left in progress
+func (at *containerAppTarget) rollout(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+	traffic *containerapps.RevisionTraffic,
+	progressLog func(string),
+) (*containerAppRolloutResult, error) {
+	result := &containerAppRolloutResult{
+		Strategy:          serviceConfig.Deployment.Strategy,
+		ServiceName:       serviceConfig.Name,
+		StableRevision:    traffic.StableRevision,
+		CandidateRevision: traffic.CandidateRevision,
+	}
+
+	switch serviceConfig.Deployment.Strategy {
+	case DeploymentStrategyBlueGreen:
+		traffic.CandidateLabel = serviceConfig.Deployment.BlueGreen.label()
+		progressLog(fmt.Sprintf("Labeling revision %s as '%s'", traffic.CandidateRevision, traffic.CandidateLabel))
+		if err := at.setRevisionTraffic(ctx, targetResource, traffic); err != nil {
+			return nil, err
+		}
+
+		ingressConfig, err := at.containerAppService.GetIngressConfiguration(
+			ctx,
+			targetResource.SubscriptionId(),
+			targetResource.ResourceGroupName(),
+			targetResource.ResourceName(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("fetching service properties: %w", err)
+		}
+
+		if len(ingressConfig.HostNames) > 0 {
+			result.PreviewEndpoint = fmt.Sprintf(
+				"https://%s/", containerapps.LabelHostName(ingressConfig.HostNames[0], traffic.CandidateLabel))
+		}
+	case DeploymentStrategyCanary:
+		steps := serviceConfig.Deployment.Canary.Steps
+		for i, step := range steps {
+			traffic.CandidateWeight = int32(step.Weight)
+			progressLog(fmt.Sprintf(
+				"Sending %d%% of the traffic to revision %s (step %d/%d)",
+				step.Weight, traffic.CandidateRevision, i+1, len(steps)))
+			if err := at.setRevisionTraffic(ctx, targetResource, traffic); err != nil {
+				return nil, err
+			}
+
+			if i == len(steps)-1 || step.Interval == "" {
+				continue
+			}
+
+			// The interval is validated when the project is loaded
+			interval, _ := time.ParseDuration(step.Interval)
+			progressLog(fmt.Sprintf("Waiting %s before the next step", interval))
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+
+		if traffic.CandidateWeight == 100 {
+			progressLog(fmt.Sprintf("Deactivating revision %s", traffic.StableRevision))
+			if err := at.deactivateRevision(ctx, targetResource, traffic.StableRevision); err != nil {
+				return nil, err
+			}
+
+			return nil, nil
+		}
+	}
+
+	result.CandidateWeight = traffic.CandidateWeight
+	return result, nil
+}
+
+// Promote sends all the traffic to the revision being rolled out, and deactivates the stable revision
+func (at *containerAppTarget) Promote(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	traffic, err := at.getRevisionTraffic(ctx, targetResource)
+	if err != nil {
+		return err
+	}
+
+	traffic.CandidateWeight = 100
+	traffic.CandidateLabel = ""
+	if err := at.setRevisionTraffic(ctx, targetResource, traffic); err != nil {
+		return err
+	}
+
+	return at.deactivateRevision(ctx, targetResource, traffic.StableRevision)
+}
+
+// Abort sends all the traffic back to the stable revision, and deactivates the revision being rolled out
+func (at *containerAppTarget) Abort(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) error {
+	traffic, err := at.getRevisionTraffic(ctx, targetResource)
+	if err != nil {
+		return err
+	}
+
+	traffic.CandidateWeight = 0
+	traffic.CandidateLabel = ""
+	if err := at.setRevisionTraffic(ctx, targetResource, traffic); err != nil {
+		return err
+	}
+
+	return at.deactivateRevision(ctx, targetResource, traffic.CandidateRevision)
+}
+
+func (at *containerAppTarget) getRevisionTraffic(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+) (*containerapps.RevisionTraffic, error) {
+	return at.containerAppService.GetRevisionTraffic(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+	)
+}
+
+func (at *containerAppTarget) setRevisionTraffic(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	traffic *containerapps.RevisionTraffic,
+) error {
+	return at.containerAppService.SetRevisionTraffic(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		traffic,
+	)
+}
+
+func (at *containerAppTarget) deactivateRevision(
+	ctx context.Context,
+	targetResource *environment.TargetResource,
+	revisionName string,
+) error {
+	return at.containerAppService.DeactivateRevision(
+		ctx,
+		targetResource.SubscriptionId(),
+		targetResource.ResourceGroupName(),
+		targetResource.ResourceName(),
+		revisionName,
+	)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseDeploymentStrategy(t *testing.T) {
+	const testProj = `
+name: test-proj
+services:
+  api:
+    project: src/api
+    language: js
+    host: containerapp
+    deployment:
+      strategy: canary
+      canary:
+        steps:
+          - weight: 10
+            interval: 5m
+          - weight: 100
+  web:
+    project: src/web
+    language: js
+    host: containerapp
+    deployment:
+      strategy: bluegreen
+`
+
+	projectConfig, err := Parse(context.Background(), testProj)
+	require.NoError(t, err)
+
+	api := projectConfig.Services["api"]
+	require.Equal(t, DeploymentStrategyCanary, api.Deployment.Strategy)
+	require.Equal(t, []CanaryStep{{Weight: 10, Interval: "5m"}, {Weight: 100}}, api.Deployment.Canary.Steps)
+
+	web := projectConfig.Services["web"]
+	require.Equal(t, DeploymentStrategyBlueGreen, web.Deployment.Strategy)
+	require.Equal(t, "green", web.Deployment.BlueGreen.label())
+}
+
+func Test_ValidateDeploymentOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    ServiceTargetKind
+		options DeploymentOptions
+		wantErr bool
+	}{
+		{
+			name: "NoStrategy",
+			host: AppServiceTarget,
+		},
+		{
+			name:    "UnknownStrategy",
+			host:    ContainerAppTarget,
+			options: DeploymentOptions{Strategy: "rolling"},
+			wantErr: true,
+		},
+		{
+			name:    "UnsupportedHost",
+			host:    AppServiceTarget,
+			options: DeploymentOptions{Strategy: DeploymentStrategyBlueGreen},
+			wantErr: true,
+		},
+		{
+			name: "BlueGreenLabel",
+			host: ContainerAppTarget,
+			options: DeploymentOptions{
+				Strategy:  DeploymentStrategyBlueGreen,
+				BlueGreen: &BlueGreenOptions{Label: "preview-1"},
+			},
+		},
+		{
+			name: "BlueGreenInvalidLabel",
+			host: ContainerAppTarget,
+			options: DeploymentOptions{
+				Strategy:  DeploymentStrategyBlueGreen,
+				BlueGreen: &BlueGreenOptions{Label: "Preview"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "CanaryWithoutSteps",
+			host:    ContainerAppTarget,
+			options: DeploymentOptions{Strategy: DeploymentStrategyCanary},
+			wantErr: true,
+		},
+		{
+			name: "CanaryWeightOutOfRange",
+			host: ContainerAppTarget,
+			options: DeploymentOptions{
+				Strategy: DeploymentStrategyCanary,
+				Canary:   &CanaryOptions{Steps: []CanaryStep{{Weight: 101}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "CanaryWeightsNotIncreasing",
+			host: ContainerAppTarget,
+			options: DeploymentOptions{
+				Strategy: DeploymentStrategyCanary,
+				Canary:   &CanaryOptions{Steps: []CanaryStep{{Weight: 50}, {Weight: 50}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "CanaryInvalidInterval",
+			host: ContainerAppTarget,
+			options: DeploymentOptions{
+				Strategy: DeploymentStrategyCanary,
+				Canary:   &CanaryOptions{Steps: []CanaryStep{{Weight: 10, Interval: "5 minutes"}, {Weight: 100}}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDeploymentOptions(&ServiceConfig{Host: tt.host, Deployment: tt.options})
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
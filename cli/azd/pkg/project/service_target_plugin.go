@@ -0,0 +1,351 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/async"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"go.lsp.dev/jsonrpc2"
+)
+
+// PluginTargetPrefix prefixes the name of the plugin implementing the host of a service, as in `host: plugin:<name>`
+const PluginTargetPrefix = "plugin:"
+
+// pluginExecutablePrefix prefixes the name of the executable implementing a plugin, which is found in the PATH
+const pluginExecutablePrefix = "azd-target-"
+
+// The name of a plugin
+var pluginNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-_.]*$`)
+
+// The methods a plugin implements, and the notifications it sends.
+//
+// A plugin is launched for every method, which is invoked with JSON-RPC 2.0 over the standard input and output of the
+// plugin, with each message preceded by a Content-Length header. The plugin exits once its standard input is closed.
+// While handling a method, the plugin can send `progress` notifications which are displayed by azd.
+const (
+	pluginMethodInitialize     = "initialize"
+	pluginMethodPackage        = "package"
+	pluginMethodDeploy         = "deploy"
+	pluginMethodEndpoints      = "endpoints"
+	pluginNotificationProgress = "progress"
+)
+
+// pluginService is the service sent to the plugin
+type pluginService struct {
+	Name       string         `json:"name"`
+	Host       string         `json:"host"`
+	Language   string         `json:"language,omitempty"`
+	Path       string         `json:"path"`
+	OutputPath string         `json:"outputPath,omitempty"`
+	Image      string         `json:"image,omitempty"`
+	Config     map[string]any `json:"config,omitempty"`
+}
+
+// pluginTargetResource is the resource the service is deployed to. The resource name and type are empty when the plugin
+// doesn't deploy to an Azure resource tagged with the name of the service.
+type pluginTargetResource struct {
+	SubscriptionId    string `json:"subscriptionId"`
+	ResourceGroupName string `json:"resourceGroupName"`
+	ResourceName      string `json:"resourceName,omitempty"`
+	ResourceType      string `json:"resourceType,omitempty"`
+}
+
+type pluginInitializeParams struct {
+	Service pluginService `json:"service"`
+}
+
+type pluginPackageParams struct {
+	Service     pluginService `json:"service"`
+	PackagePath string        `json:"packagePath"`
+}
+
+type pluginPackageResult struct {
+	PackagePath string `json:"packagePath"`
+	Details     any    `json:"details,omitempty"`
+}
+
+type pluginDeployParams struct {
+	Service        pluginService        `json:"service"`
+	PackagePath    string               `json:"packagePath"`
+	TargetResource pluginTargetResource `json:"targetResource"`
+}
+
+type pluginDeployResult struct {
+	TargetResourceId string   `json:"targetResourceId"`
+	Endpoints        []string `json:"endpoints"`
+	Details          any      `json:"details,omitempty"`
+}
+
+type pluginEndpointsParams struct {
+	Service        pluginService        `json:"service"`
+	TargetResource pluginTargetResource `json:"targetResource"`
+}
+
+type pluginEndpointsResult struct {
+	Endpoints []string `json:"endpoints"`
+}
+
+type pluginProgressParams struct {
+	Message string `json:"message"`
+}
+
+type pluginTarget struct {
+	env *environment.Environment
+
+	// command returns the command launching the plugin with the specified name
+	command func(ctx context.Context, pluginName string) (*exec.Cmd, error)
+}
+
+// NewPluginTarget creates a new instance of the service target for the hosts implemented by plugins. The plugin is
+// resolved from the host of the service.
+func NewPluginTarget(env *environment.Environment) ServiceTarget {
+	return &pluginTarget{
+		env:     env,
+		command: pluginCommand,
+	}
+}
+
+// pluginCommand returns the command launching the executable of the plugin, found in the PATH
+func pluginCommand(ctx context.Context, pluginName string) (*exec.Cmd, error) {
+	executable := pluginExecutablePrefix + pluginName
+
+	path, err := exec.LookPath(executable)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"plugin '%s' was not found, the '%s' executable must be in the PATH: %w", pluginName, executable, err)
+	}
+
+	return exec.CommandContext(ctx, path), nil
+}
+
+// Gets the required external tools
+func (t *pluginTarget) RequiredExternalTools(context.Context) []tools.ExternalTool {
+	return []tools.ExternalTool{}
+}
+
+// Initializes the plugin for the service
+func (t *pluginTarget) Initialize(ctx context.Context, serviceConfig *ServiceConfig) error {
+	return t.call(ctx, serviceConfig, pluginMethodInitialize, pluginInitializeParams{
+		Service: newPluginService(serviceConfig),
+	}, nil, nil)
+}
+
+// Prepares the artifacts of the service for deployment with the plugin
+func (t *pluginTarget) Package(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+) *async.TaskWithProgress[*ServicePackageResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServicePackageResult, ServiceProgress]) {
+			var result pluginPackageResult
+			err := t.call(ctx, serviceConfig, pluginMethodPackage, pluginPackageParams{
+				Service:     newPluginService(serviceConfig),
+				PackagePath: packageOutput.PackagePath,
+			}, &result, func(message string) {
+				task.SetProgress(NewServiceProgress(message))
+			})
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			// The package is left as is when the plugin doesn't prepare its own package
+			if result.PackagePath == "" {
+				result.PackagePath = packageOutput.PackagePath
+			}
+
+			task.SetResult(&ServicePackageResult{
+				Build:       packageOutput.Build,
+				PackagePath: result.PackagePath,
+				Details:     result.Details,
+			})
+		},
+	)
+}
+
+// Deploys the package of the service with the plugin
+func (t *pluginTarget) Deploy(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	packageOutput *ServicePackageResult,
+	targetResource *environment.TargetResource,
+) *async.TaskWithProgress[*ServiceDeployResult, ServiceProgress] {
+	return async.RunTaskWithProgress(
+		func(task *async.TaskContextWithProgress[*ServiceDeployResult, ServiceProgress]) {
+			var result pluginDeployResult
+			err := t.call(ctx, serviceConfig, pluginMethodDeploy, pluginDeployParams{
+				Service:        newPluginService(serviceConfig),
+				PackagePath:    packageOutput.PackagePath,
+				TargetResource: newPluginTargetResource(targetResource),
+			}, &result, func(message string) {
+				task.SetProgress(NewServiceProgress(message))
+			})
+			if err != nil {
+				task.SetError(err)
+				return
+			}
+
+			task.SetResult(&ServiceDeployResult{
+				Package:          packageOutput,
+				TargetResourceId: result.TargetResourceId,
+				Kind:             serviceConfig.Host,
+				Endpoints:        result.Endpoints,
+				Details:          result.Details,
+			})
+		},
+	)
+}
+
+// Gets the endpoints of the service from the plugin
+func (t *pluginTarget) Endpoints(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	targetResource *environment.TargetResource,
+) ([]string, error) {
+	var result pluginEndpointsResult
+	err := t.call(ctx, serviceConfig, pluginMethodEndpoints, pluginEndpointsParams{
+		Service:        newPluginService(serviceConfig),
+		TargetResource: newPluginTargetResource(targetResource),
+	}, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Endpoints, nil
+}
+
+// call launches the plugin of the service, invokes the method and stops the plugin once it replied. The progress
+// notifications sent by the plugin while handling the method are reported to progress.
+func (t *pluginTarget) call(
+	ctx context.Context,
+	serviceConfig *ServiceConfig,
+	method string,
+	params any,
+	result any,
+	progress func(message string),
+) error {
+	pluginName := serviceConfig.Host.PluginName()
+
+	cmd, err := t.command(ctx, pluginName)
+	if err != nil {
+		return err
+	}
+
+	envVars, err := t.env.Environ(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The plugin runs from the service directory, with the values of the environment
+	cmd.Dir = serviceConfig.Path()
+	cmd.Env = append(cmd.Environ(), envVars...)
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return fmt.Errorf("creating plugin input: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("creating plugin output: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting plugin '%s': %w", pluginName, err)
+	}
+
+	conn := jsonrpc2.NewConn(jsonrpc2.NewStream(&pluginStdio{ReadCloser: stdout, WriteCloser: stdin}))
+	conn.Go(ctx, func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+		if req.Method() != pluginNotificationProgress {
+			return jsonrpc2.MethodNotFoundHandler(ctx, reply, req)
+		}
+
+		var progressParams pluginProgressParams
+		if err := json.Unmarshal(req.Params(), &progressParams); err == nil && progress != nil {
+			progress(progressParams.Message)
+		}
+
+		return reply(ctx, nil, nil)
+	})
+
+	// The call is abandoned when the plugin exits without replying
+	callCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-conn.Done():
+			cancel()
+		case <-callCtx.Done():
+		}
+	}()
+
+	_, callErr := conn.Call(callCtx, method, params, result)
+
+	// Closing the input of the plugin lets it exit
+	_ = conn.Close()
+	waitErr := cmd.Wait()
+	<-conn.Done()
+
+	if callErr == nil {
+		return nil
+	}
+
+	if errors.Is(callErr, context.Canceled) && ctx.Err() == nil {
+		callErr = fmt.Errorf("the plugin exited without replying: %w", waitErr)
+	}
+
+	if output := strings.TrimSpace(stderr.String()); output != "" {
+		return fmt.Errorf("plugin '%s' failed to %s service '%s': %w\n%s",
+			pluginName, method, serviceConfig.Name, callErr, output)
+	}
+
+	return fmt.Errorf("plugin '%s' failed to %s service '%s': %w", pluginName, method, serviceConfig.Name, callErr)
+}
+
+// pluginStdio is the connection to the plugin through its standard input and output
+type pluginStdio struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+// Close closes the input of the plugin, and its output
+func (s *pluginStdio) Close() error {
+	return errors.Join(s.WriteCloser.Close(), s.ReadCloser.Close())
+}
+
+func newPluginService(serviceConfig *ServiceConfig) pluginService {
+	return pluginService{
+		Name:       serviceConfig.Name,
+		Host:       string(serviceConfig.Host),
+		Language:   string(serviceConfig.Language),
+		Path:       serviceConfig.Path(),
+		OutputPath: serviceConfig.OutputPath,
+		Image:      serviceConfig.Image,
+		Config:     serviceConfig.Config,
+	}
+}
+
+func newPluginTargetResource(targetResource *environment.TargetResource) pluginTargetResource {
+	return pluginTargetResource{
+		SubscriptionId:    targetResource.SubscriptionId(),
+		ResourceGroupName: targetResource.ResourceGroupName(),
+		ResourceName:      targetResource.ResourceName(),
+		ResourceType:      targetResource.ResourceType(),
+	}
+}
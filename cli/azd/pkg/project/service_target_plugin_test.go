@@ -0,0 +1,170 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+	"go.lsp.dev/jsonrpc2"
+)
+
+// fakePluginEnvVarName is set when the test binary is launched as the fake plugin
+const fakePluginEnvVarName = "AZD_TEST_FAKE_PLUGIN"
+
+// Test_FakePlugin isn't a test, it runs the fake plugin when the test binary is launched by newFakePluginTarget
+func Test_FakePlugin(t *testing.T) {
+	if os.Getenv(fakePluginEnvVarName) != "1" {
+		return
+	}
+
+	conn := jsonrpc2.NewConn(jsonrpc2.NewStream(&pluginStdio{ReadCloser: os.Stdin, WriteCloser: os.Stdout}))
+	conn.Go(context.Background(), func(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+		var params pluginDeployParams
+		if err := json.Unmarshal(req.Params(), &params); err != nil {
+			return reply(ctx, nil, err)
+		}
+
+		if params.Service.Config["fail"] == true {
+			fmt.Fprintln(os.Stderr, "fake plugin log")
+			return reply(ctx, nil, fmt.Errorf("%s failed", req.Method()))
+		}
+
+		switch req.Method() {
+		case pluginMethodInitialize:
+			return reply(ctx, nil, nil)
+		case pluginMethodPackage:
+			_ = conn.Notify(ctx, pluginNotificationProgress, pluginProgressParams{Message: "Packaging"})
+			return reply(ctx, pluginPackageResult{PackagePath: params.PackagePath + ".plugin"}, nil)
+		case pluginMethodDeploy:
+			_ = conn.Notify(ctx, pluginNotificationProgress, pluginProgressParams{Message: "Deploying " + params.PackagePath})
+			return reply(ctx, pluginDeployResult{
+				TargetResourceId: "fleet/" + params.Service.Name,
+				Endpoints:        []string{"http://" + params.Service.Name + ".fleet"},
+				Details:          map[string]any{"instances": 3},
+			}, nil)
+		case pluginMethodEndpoints:
+			return reply(ctx, pluginEndpointsResult{
+				Endpoints: []string{"http://" + params.Service.Name + ".fleet"},
+			}, nil)
+		case "exit":
+			os.Exit(1)
+		}
+
+		return jsonrpc2.MethodNotFoundHandler(ctx, reply, req)
+	})
+
+	<-conn.Done()
+	os.Exit(0)
+}
+
+// newFakePluginTarget returns a plugin target launching the test binary as the plugin
+func newFakePluginTarget(t *testing.T) *pluginTarget {
+	return &pluginTarget{
+		env: environment.NewWithValues("test", map[string]string{}),
+		command: func(ctx context.Context, pluginName string) (*exec.Cmd, error) {
+			require.Equal(t, "fleet", pluginName)
+
+			cmd := exec.CommandContext(ctx, os.Args[0], "-test.run=^Test_FakePlugin$")
+			cmd.Env = append(cmd.Environ(), fakePluginEnvVarName+"=1")
+			return cmd, nil
+		},
+	}
+}
+
+func Test_PluginTarget(t *testing.T) {
+	serviceConfig := &ServiceConfig{
+		Name: "api",
+		Host: ServiceTargetKind(PluginTargetPrefix + "fleet"),
+		Project: &ProjectConfig{
+			Path: t.TempDir(),
+		},
+		RelativePath: ".",
+	}
+	targetResource := environment.NewTargetResource("SUB_ID", "RG_ID", "", "")
+
+	t.Run("Deploy", func(t *testing.T) {
+		target := newFakePluginTarget(t)
+		ctx := context.Background()
+
+		require.NoError(t, target.Initialize(ctx, serviceConfig))
+
+		packageTask := target.Package(ctx, serviceConfig, &ServicePackageResult{PackagePath: "api.zip"})
+		packageProgress := collectProgress(packageTask.Progress())
+		packageResult, err := packageTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, "api.zip.plugin", packageResult.PackagePath)
+		require.Equal(t, []string{"Packaging"}, <-packageProgress)
+
+		deployTask := target.Deploy(ctx, serviceConfig, packageResult, targetResource)
+		deployProgress := collectProgress(deployTask.Progress())
+		deployResult, err := deployTask.Await()
+		require.NoError(t, err)
+		require.Equal(t, []string{"Deploying api.zip.plugin"}, <-deployProgress)
+		require.Equal(t, "fleet/api", deployResult.TargetResourceId)
+		require.Equal(t, serviceConfig.Host, deployResult.Kind)
+		require.Equal(t, []string{"http://api.fleet"}, deployResult.Endpoints)
+		require.Equal(t, map[string]any{"instances": float64(3)}, deployResult.Details)
+
+		endpoints, err := target.Endpoints(ctx, serviceConfig, targetResource)
+		require.NoError(t, err)
+		require.Equal(t, []string{"http://api.fleet"}, endpoints)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		failingServiceConfig := *serviceConfig
+		failingServiceConfig.Config = map[string]any{"fail": true}
+
+		err := newFakePluginTarget(t).Initialize(context.Background(), &failingServiceConfig)
+		require.ErrorContains(t, err, "initialize failed")
+		require.ErrorContains(t, err, "fake plugin log")
+
+		var rpcErr *jsonrpc2.Error
+		require.True(t, errors.As(err, &rpcErr))
+	})
+
+	t.Run("PluginExited", func(t *testing.T) {
+		target := newFakePluginTarget(t)
+
+		err := target.call(context.Background(), serviceConfig, "exit", pluginInitializeParams{}, nil, nil)
+		require.ErrorContains(t, err, "exited without replying")
+	})
+}
+
+func Test_ParsePluginHost(t *testing.T) {
+	host, err := parseServiceHost("plugin:vm-fleet")
+	require.NoError(t, err)
+	require.True(t, host.IsPlugin())
+	require.Equal(t, "vm-fleet", host.PluginName())
+	require.True(t, host.SupportsDelayedProvisioning())
+
+	_, err = parseServiceHost("plugin:")
+	require.Error(t, err)
+
+	_, err = parseServiceHost("plugin:VM Fleet")
+	require.Error(t, err)
+
+	require.False(t, AppServiceTarget.IsPlugin())
+}
+
+// collectProgress returns the messages of the progress once it completes
+func collectProgress(progress <-chan ServiceProgress) <-chan []string {
+	messages := make(chan []string, 1)
+	go func() {
+		collected := []string{}
+		for value := range progress {
+			collected = append(collected, value.Message)
+		}
+		messages <- collected
+	}()
+
+	return messages
+}
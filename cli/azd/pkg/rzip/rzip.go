@@ -4,7 +4,9 @@
 package rzip
 
 import (
+	"archive/tar"
 	"archive/zip"
+	"compress/gzip"
 	"io"
 	"io/fs"
 	"os"
@@ -56,3 +58,80 @@ func CreateFromDirectory(source string, buf *os.File) error {
 
 	return w.Close()
 }
+
+// CreateTarGzFromDirectory writes a gzipped tarball of the files of the directory to buf, such as the build context of a
+// container image. Files and directories, identified by their slash separated path relative to source, are excluded
+// when skip returns true.
+func CreateTarGzFromDirectory(source string, buf io.Writer, skip func(relPath string, isDir bool) bool) error {
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	err := filepath.WalkDir(source, func(path string, info fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+		if skip != nil && skip(rel, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		fileInfo, err := info.Info()
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if fileInfo.Mode()&fs.ModeSymlink != 0 {
+			link, err = os.Readlink(path)
+			if err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(fileInfo, link)
+		if err != nil {
+			return err
+		}
+
+		header.Name = rel
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !fileInfo.Mode().IsRegular() {
+			return nil
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		_, err = io.Copy(tw, in)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
@@ -0,0 +1,274 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package filesystem implements a remote state backend storing blobs as files in a directory, such as a network share
+// mounted on the machines of a team.
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// Config contains the configuration of the file system remote state backend
+type Config struct {
+	// The root directory of the remote state. Relative paths are relative to the project directory.
+	Path string `json:"path"`
+	// The directory under Path the blobs of the project are stored in. Defaults to the project name.
+	Folder string `json:"folder"`
+}
+
+// Root returns the directory the blobs are stored in.
+func (c *Config) Root() string {
+	return filepath.Join(c.Path, c.Folder)
+}
+
+// NewBlobClient creates a new BlobClient storing blobs as files under the configured directory.
+func NewBlobClient(config *Config) storage.BlobClient {
+	return &blobClient{
+		config: config,
+	}
+}
+
+type blobClient struct {
+	config *Config
+}
+
+// Items returns a list of the blobs stored under the configured directory.
+func (bc *blobClient) Items(ctx context.Context) ([]*storage.Blob, error) {
+	root := bc.config.Root()
+	blobs := []*storage.Blob{}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		// in-progress uploads
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".tmp-") {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		etag, err := fileETag(path)
+		if err != nil {
+			return err
+		}
+
+		blobs = append(blobs, &storage.Blob{
+			Name:         d.Name(),
+			Path:         filepath.ToSlash(rel),
+			CreationTime: info.ModTime(),
+			LastModified: info.ModTime(),
+			ETag:         etag,
+		})
+
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("'%s': %w", root, storage.ErrContainerNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to list files in '%s', %w", root, err)
+	}
+
+	return blobs, nil
+}
+
+// Download opens the file of a blob.
+func (bc *blobClient) Download(ctx context.Context, blobPath string) (io.ReadCloser, error) {
+	body, _, err := bc.DownloadWithETag(ctx, blobPath)
+	return body, err
+}
+
+// DownloadWithETag reads the file of a blob, along with its ETag.
+func (bc *blobClient) DownloadWithETag(ctx context.Context, blobPath string) (io.ReadCloser, string, error) {
+	path, err := bc.filePath(blobPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The content is read at once, so the ETag matches the content even when the file is replaced concurrently
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download blob '%s', %w", blobPath, describeError(err))
+	}
+
+	return io.NopCloser(bytes.NewReader(content)), contentETag(content), nil
+}
+
+// Upload writes the file of a blob.
+func (bc *blobClient) Upload(ctx context.Context, blobPath string, reader io.Reader) error {
+	_, err := bc.UploadIf(ctx, blobPath, reader, storage.Condition{})
+	return err
+}
+
+// UploadIf writes the file of a blob when the condition is met, returning the ETag of the written file.
+// The file is replaced atomically, so readers never observe a partially written blob.
+//
+// Conditions are evaluated right before the file is replaced, so they protect against lost updates between processes
+// that read and write the blob minutes apart, but not against writes racing within the same instant.
+func (bc *blobClient) UploadIf(
+	ctx context.Context,
+	blobPath string,
+	reader io.Reader,
+	condition storage.Condition,
+) (string, error) {
+	path, err := bc.filePath(blobPath)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory); err != nil {
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(path), ".tmp-"+filepath.Base(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tempFile, hash), reader); err != nil {
+		tempFile.Close()
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	}
+
+	if err := tempFile.Close(); err != nil {
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	}
+
+	if err := checkCondition(path, condition); err != nil {
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	}
+
+	if condition.IfNotExists {
+		// Linking fails when the file exists, which prevents two writers from both creating the blob
+		if err := os.Link(tempFile.Name(), path); errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, storage.ErrPreconditionFailed)
+		} else if err == nil {
+			return hashETag(hash.Sum(nil)), nil
+		}
+
+		// Some file systems don't support hard links, fallback to renaming the file
+	}
+
+	if err := osutil.Rename(ctx, tempFile.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	}
+
+	return hashETag(hash.Sum(nil)), nil
+}
+
+// Delete deletes the file of a blob, and its directory when it becomes empty.
+func (bc *blobClient) Delete(ctx context.Context, blobPath string) error {
+	return bc.DeleteIf(ctx, blobPath, storage.Condition{})
+}
+
+// DeleteIf deletes the file of a blob when the condition is met, and its directory when it becomes empty.
+func (bc *blobClient) DeleteIf(ctx context.Context, blobPath string, condition storage.Condition) error {
+	path, err := bc.filePath(blobPath)
+	if err != nil {
+		return err
+	}
+
+	if err := checkCondition(path, condition); err != nil {
+		return fmt.Errorf("failed to delete blob '%s', %w", blobPath, err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete blob '%s', %w", blobPath, describeError(err))
+	}
+
+	// best effort, the directory may still contain other blobs
+	if dir := filepath.Dir(path); dir != bc.config.Root() {
+		_ = os.Remove(dir)
+	}
+
+	return nil
+}
+
+// filePath returns the path of the file of a blob, ensuring it is located under the configured directory.
+func (bc *blobClient) filePath(blobPath string) (string, error) {
+	root := bc.config.Root()
+	path := filepath.Join(root, filepath.FromSlash(blobPath))
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", fmt.Errorf("invalid blob path '%s'", blobPath)
+	}
+
+	return path, nil
+}
+
+// checkCondition returns storage.ErrPreconditionFailed when the file doesn't meet the condition.
+func checkCondition(path string, condition storage.Condition) error {
+	if condition.IfMatch == "" && !condition.IfNotExists {
+		return nil
+	}
+
+	etag, err := fileETag(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if condition.IfMatch != "" {
+			return storage.ErrPreconditionFailed
+		}
+
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if condition.IfNotExists || etag != condition.IfMatch {
+		return storage.ErrPreconditionFailed
+	}
+
+	return nil
+}
+
+// fileETag returns the ETag of a file, computed from its content since the modification time of files on network
+// shares may not be precise enough.
+func fileETag(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return contentETag(content), nil
+}
+
+func contentETag(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hashETag(hash[:])
+}
+
+func hashETag(hash []byte) string {
+	return fmt.Sprintf("\"%x\"", hash)
+}
+
+func describeError(err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %w", storage.ErrBlobNotFound, err)
+	}
+
+	return err
+}
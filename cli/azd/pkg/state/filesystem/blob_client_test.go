@@ -0,0 +1,87 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package filesystem
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BlobClient(t *testing.T) {
+	config := &Config{
+		Path:   t.TempDir(),
+		Folder: "my-project",
+	}
+	client := NewBlobClient(config)
+	ctx := context.Background()
+
+	_, err := client.Items(ctx)
+	require.ErrorIs(t, err, storage.ErrContainerNotFound)
+
+	err = client.Upload(ctx, "dev/.env", strings.NewReader("KEY=value\n"))
+	require.NoError(t, err)
+	err = client.Upload(ctx, "dev/config.json", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	blobs, err := client.Items(ctx)
+	require.NoError(t, err)
+	require.Len(t, blobs, 2)
+	require.Equal(t, ".env", blobs[0].Name)
+	require.Equal(t, "dev/.env", blobs[0].Path)
+	require.Equal(t, "config.json", blobs[1].Name)
+	require.Equal(t, "dev/config.json", blobs[1].Path)
+
+	reader, err := client.Download(ctx, "dev/.env")
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "KEY=value\n", string(content))
+
+	err = client.Delete(ctx, "dev/.env")
+	require.NoError(t, err)
+	err = client.Delete(ctx, "dev/config.json")
+	require.NoError(t, err)
+
+	_, err = client.Download(ctx, "dev/.env")
+	require.ErrorIs(t, err, storage.ErrBlobNotFound)
+	require.NoDirExists(t, filepath.Join(config.Root(), "dev"))
+
+	t.Run("Conditions", func(t *testing.T) {
+		etag, err := client.UploadIf(ctx, "test/.lock", strings.NewReader("1"), storage.Condition{IfNotExists: true})
+		require.NoError(t, err)
+		require.NotEmpty(t, etag)
+
+		_, err = client.UploadIf(ctx, "test/.lock", strings.NewReader("2"), storage.Condition{IfNotExists: true})
+		require.ErrorIs(t, err, storage.ErrPreconditionFailed)
+
+		reader, downloadedETag, err := client.DownloadWithETag(ctx, "test/.lock")
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.Equal(t, etag, downloadedETag)
+
+		newETag, err := client.UploadIf(ctx, "test/.lock", strings.NewReader("3"), storage.Condition{IfMatch: etag})
+		require.NoError(t, err)
+
+		_, err = client.UploadIf(ctx, "test/.lock", strings.NewReader("4"), storage.Condition{IfMatch: etag})
+		require.ErrorIs(t, err, storage.ErrPreconditionFailed)
+
+		err = client.DeleteIf(ctx, "test/.lock", storage.Condition{IfMatch: etag})
+		require.ErrorIs(t, err, storage.ErrPreconditionFailed)
+
+		err = client.DeleteIf(ctx, "test/.lock", storage.Condition{IfMatch: newETag})
+		require.NoError(t, err)
+	})
+
+	t.Run("InvalidPath", func(t *testing.T) {
+		err := client.Upload(ctx, "../other-project/dev/.env", strings.NewReader(""))
+		require.Error(t, err)
+	})
+}
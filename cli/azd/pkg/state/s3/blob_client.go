@@ -0,0 +1,325 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package s3 implements a remote state backend storing blobs as objects in a bucket of an S3 compatible object storage,
+// such as Amazon S3 or MinIO.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
+	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
+)
+
+const defaultRegion = "us-east-1"
+
+// Config contains the configuration of the S3 remote state backend.
+// The credentials are read from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+type Config struct {
+	// The endpoint of the service, e.g. http://localhost:9000. Defaults to the Amazon S3 endpoint of the region.
+	Endpoint string `json:"endpoint"`
+	// The region of the bucket. Defaults to us-east-1.
+	Region string `json:"region"`
+	// The name of the bucket
+	Bucket string `json:"bucket"`
+	// The prefix of the keys of the objects of the project. Defaults to the project name.
+	Prefix string `json:"prefix"`
+	// When true, the bucket is addressed as a sub domain of the endpoint instead of the first segment of the path.
+	VirtualHostedStyle bool `json:"virtualHostedStyle"`
+}
+
+// ResponseError is returned when the service responds with an error
+type ResponseError struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("%s (%d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+func (e *ResponseError) Unwrap() error {
+	switch {
+	case e.Code == "NoSuchBucket":
+		return storage.ErrContainerNotFound
+	case e.Code == "NoSuchKey" || e.StatusCode == http.StatusNotFound:
+		return storage.ErrBlobNotFound
+	case e.StatusCode == http.StatusPreconditionFailed || e.Code == "ConditionalRequestConflict":
+		return storage.ErrPreconditionFailed
+	}
+
+	return nil
+}
+
+// NewBlobClient creates a new BlobClient storing blobs as objects in the configured bucket.
+func NewBlobClient(config *Config, httpClient httputil.HttpClient) storage.BlobClient {
+	return &blobClient{
+		config:     config,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+type blobClient struct {
+	config     *Config
+	httpClient httputil.HttpClient
+	now        func() time.Time
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key          string    `xml:"Key"`
+		LastModified time.Time `xml:"LastModified"`
+		ETag         string    `xml:"ETag"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// Items returns a list of the blobs stored under the configured prefix.
+func (bc *blobClient) Items(ctx context.Context) ([]*storage.Blob, error) {
+	blobs := []*storage.Blob{}
+	keyPrefix := bc.key("")
+	continuationToken := ""
+
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", keyPrefix)
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		res, err := bc.send(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects, %w", err)
+		}
+
+		var page listBucketResult
+		err = xml.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects, %w", err)
+		}
+
+		for _, object := range page.Contents {
+			blobPath := strings.TrimPrefix(object.Key, keyPrefix)
+			blobs = append(blobs, &storage.Blob{
+				Name:         path.Base(blobPath),
+				Path:         blobPath,
+				CreationTime: object.LastModified,
+				LastModified: object.LastModified,
+				ETag:         object.ETag,
+			})
+		}
+
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		continuationToken = page.NextContinuationToken
+	}
+
+	return blobs, nil
+}
+
+// Download downloads the object of a blob.
+func (bc *blobClient) Download(ctx context.Context, blobPath string) (io.ReadCloser, error) {
+	body, _, err := bc.DownloadWithETag(ctx, blobPath)
+	return body, err
+}
+
+// DownloadWithETag downloads the object of a blob, along with its ETag.
+func (bc *blobClient) DownloadWithETag(ctx context.Context, blobPath string) (io.ReadCloser, string, error) {
+	res, err := bc.send(ctx, http.MethodGet, bc.key(blobPath), nil, nil, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download blob '%s', %w", blobPath, err)
+	}
+
+	return res.Body, res.Header.Get("ETag"), nil
+}
+
+// Upload uploads the object of a blob.
+func (bc *blobClient) Upload(ctx context.Context, blobPath string, reader io.Reader) error {
+	_, err := bc.UploadIf(ctx, blobPath, reader, storage.Condition{})
+	return err
+}
+
+// UploadIf uploads the object of a blob when the condition is met, returning the ETag of the uploaded object.
+func (bc *blobClient) UploadIf(
+	ctx context.Context,
+	blobPath string,
+	reader io.Reader,
+	condition storage.Condition,
+) (string, error) {
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	}
+
+	res, err := bc.send(ctx, http.MethodPut, bc.key(blobPath), nil, conditionHeaders(condition), body)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload blob '%s', %w", blobPath, err)
+	}
+	defer res.Body.Close()
+
+	return res.Header.Get("ETag"), nil
+}
+
+// Delete deletes the object of a blob.
+func (bc *blobClient) Delete(ctx context.Context, blobPath string) error {
+	return bc.DeleteIf(ctx, blobPath, storage.Condition{})
+}
+
+// DeleteIf deletes the object of a blob when the condition is met.
+func (bc *blobClient) DeleteIf(ctx context.Context, blobPath string, condition storage.Condition) error {
+	res, err := bc.send(ctx, http.MethodDelete, bc.key(blobPath), nil, conditionHeaders(condition), nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete blob '%s', %w", blobPath, err)
+	}
+
+	return res.Body.Close()
+}
+
+// conditionHeaders returns the conditional request headers of a condition
+func conditionHeaders(condition storage.Condition) http.Header {
+	headers := http.Header{}
+	if condition.IfMatch != "" {
+		headers.Set("If-Match", condition.IfMatch)
+	}
+
+	if condition.IfNotExists {
+		headers.Set("If-None-Match", "*")
+	}
+
+	return headers
+}
+
+// key returns the key of the object of a blob
+func (bc *blobClient) key(blobPath string) string {
+	if bc.config.Prefix == "" {
+		return blobPath
+	}
+
+	return strings.TrimSuffix(bc.config.Prefix, "/") + "/" + blobPath
+}
+
+// send sends a signed request for the object with the specified key, or for the bucket when the key is empty.
+// An error is returned for responses with an unsuccessful status code.
+func (bc *blobClient) send(
+	ctx context.Context,
+	method string,
+	key string,
+	query url.Values,
+	headers http.Header,
+	body []byte,
+) (*http.Response, error) {
+	requestUrl, err := bc.url(key)
+	if err != nil {
+		return nil, err
+	}
+	requestUrl.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, requestUrl.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range headers {
+		req.Header[name] = values
+	}
+
+	credentials, err := credentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	payloadHash := emptyPayloadHash
+	if len(body) > 0 {
+		payloadHash = hashHex(body)
+	}
+	signRequest(req, payloadHash, credentials, bc.region(), bc.now())
+
+	res, err := bc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res, nil
+	}
+
+	defer res.Body.Close()
+	responseErr := &ResponseError{
+		StatusCode: res.StatusCode,
+		Code:       http.StatusText(res.StatusCode),
+	}
+
+	// HEAD responses and some errors don't have a body
+	if errBody, err := io.ReadAll(res.Body); err == nil && len(errBody) > 0 {
+		_ = xml.Unmarshal(errBody, responseErr)
+	}
+
+	return nil, responseErr
+}
+
+func (bc *blobClient) region() string {
+	if bc.config.Region == "" {
+		return defaultRegion
+	}
+
+	return bc.config.Region
+}
+
+// url returns the URL of the object with the specified key, or of the bucket when the key is empty.
+func (bc *blobClient) url(key string) (*url.URL, error) {
+	if bc.config.Bucket == "" {
+		return nil, errors.New("the S3 bucket name is not configured")
+	}
+
+	endpoint := bc.config.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", bc.region())
+	}
+
+	endpointUrl, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint '%s': %w", endpoint, err)
+	}
+
+	if bc.config.VirtualHostedStyle {
+		endpointUrl.Host = bc.config.Bucket + "." + endpointUrl.Host
+		endpointUrl.Path = "/" + key
+	} else {
+		endpointUrl.Path = "/" + bc.config.Bucket + "/" + key
+	}
+
+	return endpointUrl, nil
+}
+
+func credentialsFromEnv() (Credentials, error) {
+	credentials := Credentials{
+		AccessKeyId:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+
+	if credentials.AccessKeyId == "" || credentials.SecretAccessKey == "" {
+		return Credentials{}, errors.New(
+			"S3 credentials are not configured. Set the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables")
+	}
+
+	return credentials, nil
+}
@@ -0,0 +1,207 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package s3
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BlobClient(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "test-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test-secret")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	server := newFakeS3Server(t, "state")
+	defer server.Close()
+
+	client := NewBlobClient(&Config{
+		Endpoint: server.URL,
+		Bucket:   "state",
+		Prefix:   "my-project",
+	}, server.Client())
+	ctx := context.Background()
+
+	blobs, err := client.Items(ctx)
+	require.NoError(t, err)
+	require.Empty(t, blobs)
+
+	err = client.Upload(ctx, "dev/.env", strings.NewReader("KEY=value\n"))
+	require.NoError(t, err)
+	err = client.Upload(ctx, "dev/config.json", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	blobs, err = client.Items(ctx)
+	require.NoError(t, err)
+	require.Len(t, blobs, 2)
+	require.Equal(t, ".env", blobs[0].Name)
+	require.Equal(t, "dev/.env", blobs[0].Path)
+	require.Equal(t, "config.json", blobs[1].Name)
+	require.Equal(t, "dev/config.json", blobs[1].Path)
+
+	reader, err := client.Download(ctx, "dev/.env")
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	require.Equal(t, "KEY=value\n", string(content))
+
+	err = client.Delete(ctx, "dev/.env")
+	require.NoError(t, err)
+
+	_, err = client.Download(ctx, "dev/.env")
+	require.ErrorIs(t, err, storage.ErrBlobNotFound)
+
+	t.Run("Conditions", func(t *testing.T) {
+		etag, err := client.UploadIf(ctx, "test/.lock", strings.NewReader("1"), storage.Condition{IfNotExists: true})
+		require.NoError(t, err)
+		require.NotEmpty(t, etag)
+
+		_, err = client.UploadIf(ctx, "test/.lock", strings.NewReader("2"), storage.Condition{IfNotExists: true})
+		require.ErrorIs(t, err, storage.ErrPreconditionFailed)
+
+		reader, downloadedETag, err := client.DownloadWithETag(ctx, "test/.lock")
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.Equal(t, etag, downloadedETag)
+
+		newETag, err := client.UploadIf(ctx, "test/.lock", strings.NewReader("3"), storage.Condition{IfMatch: etag})
+		require.NoError(t, err)
+
+		_, err = client.UploadIf(ctx, "test/.lock", strings.NewReader("4"), storage.Condition{IfMatch: etag})
+		require.ErrorIs(t, err, storage.ErrPreconditionFailed)
+
+		err = client.DeleteIf(ctx, "test/.lock", storage.Condition{IfMatch: etag})
+		require.ErrorIs(t, err, storage.ErrPreconditionFailed)
+
+		err = client.DeleteIf(ctx, "test/.lock", storage.Condition{IfMatch: newETag})
+		require.NoError(t, err)
+	})
+
+	t.Run("BucketNotFound", func(t *testing.T) {
+		client := NewBlobClient(&Config{
+			Endpoint: server.URL,
+			Bucket:   "missing",
+		}, server.Client())
+
+		_, err := client.Items(ctx)
+		require.ErrorIs(t, err, storage.ErrContainerNotFound)
+	})
+
+	t.Run("MissingCredentials", func(t *testing.T) {
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+		_, err := client.Items(ctx)
+		require.Error(t, err)
+	})
+}
+
+// newFakeS3Server starts an in-memory stand-in for an S3 compatible service, such as MinIO, serving a single bucket
+// with path-style requests.
+func newFakeS3Server(t *testing.T, bucket string) *httptest.Server {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+
+	writeError := func(w http.ResponseWriter, status int, code string) {
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=test-key/") ||
+			r.Header.Get("X-Amz-Date") == "" ||
+			r.Header.Get("X-Amz-Content-Sha256") == "" {
+			writeError(w, http.StatusForbidden, "AccessDenied")
+			return
+		}
+
+		requestBucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+		if requestBucket != bucket {
+			writeError(w, http.StatusNotFound, "NoSuchBucket")
+			return
+		}
+
+		etag := func(object []byte) string {
+			return fmt.Sprintf("%q", hashHex(object)[:32])
+		}
+
+		if object, has := objects[key]; key != "" && r.Method != http.MethodGet {
+			ifMatch := r.Header.Get("If-Match")
+			if (ifMatch != "" && (!has || ifMatch != etag(object))) ||
+				(r.Header.Get("If-None-Match") == "*" && has) {
+				writeError(w, http.StatusPreconditionFailed, "PreconditionFailed")
+				return
+			}
+		}
+
+		switch {
+		case r.Method == http.MethodGet && key == "":
+			prefix := r.URL.Query().Get("prefix")
+			keys := []string{}
+			for key := range objects {
+				if strings.HasPrefix(key, prefix) {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+
+			type content struct {
+				Key          string
+				LastModified time.Time
+				ETag         string
+			}
+			result := struct {
+				XMLName  xml.Name `xml:"ListBucketResult"`
+				Contents []content
+			}{}
+			for _, key := range keys {
+				result.Contents = append(result.Contents, content{
+					Key:          key,
+					LastModified: time.Now().UTC(),
+					ETag:         etag(objects[key]),
+				})
+			}
+
+			require.NoError(t, xml.NewEncoder(w).Encode(result))
+		case r.Method == http.MethodGet:
+			object, has := objects[key]
+			if !has {
+				writeError(w, http.StatusNotFound, "NoSuchKey")
+				return
+			}
+
+			w.Header().Set("ETag", etag(object))
+			_, _ = w.Write(object)
+		case r.Method == http.MethodPut:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Equal(t, hashHex(body), r.Header.Get("X-Amz-Content-Sha256"))
+
+			objects[key] = bytes.Clone(body)
+			w.Header().Set("ETag", etag(body))
+		case r.Method == http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+		}
+	}))
+}
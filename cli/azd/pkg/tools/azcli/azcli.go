@@ -55,6 +55,21 @@ type AzCli interface {
 		appName string,
 		deployZipFile io.Reader,
 	) (*string, error)
+	DeployAppServiceSlotZip(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		slotName string,
+		deployZipFile io.Reader,
+	) (*string, error)
+	SwapAppServiceSlot(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroup string,
+		appName string,
+		slotName string,
+	) error
 	DeployFunctionAppUsingZipFile(
 		ctx context.Context,
 		subscriptionID string,
@@ -98,6 +113,13 @@ type AzCli interface {
 		resourceGroupName string,
 		applicationName string,
 	) (*AzCliAppServiceProperties, error)
+	GetAppServiceSlotProperties(
+		ctx context.Context,
+		subscriptionId string,
+		resourceGroupName string,
+		applicationName string,
+		slotName string,
+	) (*AzCliAppServiceProperties, error)
 	GetStaticWebAppProperties(
 		ctx context.Context,
 		subscriptionID string,
@@ -0,0 +1,76 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azcli
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/appservice/armappservice/v2"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GetAppServiceSlotProperties(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		ran := false
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.Contains(request.URL.Path, "/providers/Microsoft.Web/sites/WEB_APP_NAME/slots/staging")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			ran = true
+
+			response := armappservice.WebAppsClientGetSlotResponse{
+				Site: armappservice.Site{
+					Location: convert.RefOf("eastus2"),
+					Name:     convert.RefOf("WEB_APP_NAME/staging"),
+					Properties: &armappservice.SiteProperties{
+						DefaultHostName: convert.RefOf("WEB_APP_NAME-staging.azurewebsites.net"),
+					},
+				},
+			}
+
+			return mocks.CreateHttpResponseWithBody(request, http.StatusOK, response)
+		})
+
+		props, err := azCli.GetAppServiceSlotProperties(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"WEB_APP_NAME",
+			"staging",
+		)
+		require.NoError(t, err)
+		require.Equal(t, []string{"WEB_APP_NAME-staging.azurewebsites.net"}, props.HostNames)
+		require.True(t, ran)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		azCli := newAzCliFromMockContext(mockContext)
+
+		mockContext.HttpClient.When(func(request *http.Request) bool {
+			return request.Method == http.MethodGet &&
+				strings.Contains(request.URL.Path, "/providers/Microsoft.Web/sites/WEB_APP_NAME/slots/staging")
+		}).RespondFn(func(request *http.Request) (*http.Response, error) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		})
+
+		props, err := azCli.GetAppServiceSlotProperties(
+			*mockContext.Context,
+			"SUBSCRIPTION_ID",
+			"RESOURCE_GROUP_ID",
+			"WEB_APP_NAME",
+			"staging",
+		)
+		require.Nil(t, props)
+		require.Error(t, err)
+	})
+}
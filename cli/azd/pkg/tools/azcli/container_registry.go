@@ -46,6 +46,14 @@ type ContainerRegistryService interface {
 	Credentials(ctx context.Context, subscriptionId string, loginServer string) (*DockerCredentials, error)
 	// Gets a list of container registries for the specified subscription
 	GetContainerRegistries(ctx context.Context, subscriptionId string) ([]*armcontainerregistry.Registry, error)
+	// Builds and pushes an image with ACR Tasks in the specified container registry, writing the logs of the build to logs
+	RemoteBuild(
+		ctx context.Context,
+		subscriptionId string,
+		loginServer string,
+		request *RemoteBuildRequest,
+		logs io.Writer,
+	) (*RemoteBuildResult, error)
 }
 
 type containerRegistryService struct {
@@ -0,0 +1,343 @@
+package azcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+)
+
+// The interval at which the status and the logs of a remote build are polled
+var remoteBuildPollInterval = 2 * time.Second
+
+// The statuses of a run which hasn't completed yet
+var remoteBuildPendingStatuses = []armcontainerregistry.RunStatus{
+	armcontainerregistry.RunStatusQueued,
+	armcontainerregistry.RunStatusStarted,
+	armcontainerregistry.RunStatusRunning,
+}
+
+// RemoteBuildRequest is an image built by the container registry with ACR Tasks
+type RemoteBuildRequest struct {
+	// The build context, as a gzipped tarball
+	Source io.ReadSeeker
+	// The path of the Dockerfile within the build context
+	DockerfilePath string
+	// The images pushed once built, with or without the login server of the registry
+	ImageNames []string
+	// The platform of the image, such as `linux/amd64`
+	Platform string
+	// The target stage of the Dockerfile
+	Target string
+	// The build args, as `<name>=<value>`
+	BuildArgs []string
+}
+
+// RemoteBuildResult is an image built and pushed by the container registry
+type RemoteBuildResult struct {
+	RunId string
+	// The digest of the pushed image
+	Digest string
+}
+
+// RemoteBuild uploads the build context to the container registry and builds the image with ACR Tasks, which pushes the
+// image once built. The logs of the build are written to logs as the build runs.
+func (crs *containerRegistryService) RemoteBuild(
+	ctx context.Context,
+	subscriptionId string,
+	loginServer string,
+	request *RemoteBuildRequest,
+	logs io.Writer,
+) (*RemoteBuildResult, error) {
+	registryName := strings.Split(loginServer, ".")[0]
+
+	_, resourceGroup, err := crs.findContainerRegistryByName(ctx, subscriptionId, registryName)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := crs.createRegistriesClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceLocation, err := crs.uploadBuildSource(ctx, client, resourceGroup, registryName, request.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	buildRequest, err := newDockerBuildRequest(loginServer, sourceLocation, request)
+	if err != nil {
+		return nil, err
+	}
+
+	poller, err := client.BeginScheduleRun(ctx, resourceGroup, registryName, buildRequest, nil)
+	if err != nil {
+		return nil, fmt.Errorf("scheduling remote build: %w", err)
+	}
+
+	scheduled, err := poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("scheduling remote build: %w", err)
+	}
+
+	if scheduled.Properties == nil || scheduled.Properties.RunID == nil {
+		return nil, errors.New("scheduling remote build: the registry didn't return the run")
+	}
+
+	runId := *scheduled.Properties.RunID
+	log.Printf("remote build %s scheduled in registry '%s'", runId, registryName)
+
+	run, err := crs.waitForRun(ctx, subscriptionId, resourceGroup, registryName, runId, logs)
+	if err != nil {
+		return nil, err
+	}
+
+	status := armcontainerregistry.RunStatus("")
+	if run.Properties.Status != nil {
+		status = *run.Properties.Status
+	}
+
+	if status != armcontainerregistry.RunStatusSucceeded {
+		return nil, fmt.Errorf("remote build %s in registry '%s' completed with status '%s'", runId, registryName, status)
+	}
+
+	result := &RemoteBuildResult{
+		RunId: runId,
+	}
+
+	for _, image := range run.Properties.OutputImages {
+		if image.Digest != nil {
+			result.Digest = *image.Digest
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// uploadBuildSource uploads the build context to the storage of the registry, returning its location
+func (crs *containerRegistryService) uploadBuildSource(
+	ctx context.Context,
+	client *armcontainerregistry.RegistriesClient,
+	resourceGroup string,
+	registryName string,
+	source io.ReadSeeker,
+) (string, error) {
+	upload, err := client.GetBuildSourceUploadURL(ctx, resourceGroup, registryName, nil)
+	if err != nil {
+		return "", fmt.Errorf("getting build source upload url: %w", err)
+	}
+
+	if upload.UploadURL == nil || upload.RelativePath == nil {
+		return "", errors.New("getting build source upload url: the registry didn't return the upload url")
+	}
+
+	pipeline := azruntime.NewPipeline("azd-acr", internal.Version, azruntime.PipelineOptions{}, crs.coreClientOptions)
+
+	req, err := azruntime.NewRequest(ctx, http.MethodPut, *upload.UploadURL)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Raw().Header.Set("x-ms-blob-type", "BlockBlob")
+	if err := req.SetBody(streaming.NopCloser(source), "application/octet-stream"); err != nil {
+		return "", fmt.Errorf("setting build source: %w", err)
+	}
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("uploading build source: %w", err)
+	}
+	defer response.Body.Close()
+
+	if !azruntime.HasStatusCode(response, http.StatusCreated, http.StatusOK) {
+		return "", fmt.Errorf("uploading build source: %w", azruntime.NewResponseError(response))
+	}
+
+	return *upload.RelativePath, nil
+}
+
+// waitForRun polls the run until it completes, writing the logs of the run to logs as they are appended
+func (crs *containerRegistryService) waitForRun(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	registryName string,
+	runId string,
+	logs io.Writer,
+) (*armcontainerregistry.Run, error) {
+	credential, err := crs.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	runsClient, err := armcontainerregistry.NewRunsClient(subscriptionId, credential, crs.armClientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("creating runs client: %w", err)
+	}
+
+	logLink, err := runsClient.GetLogSasURL(ctx, resourceGroup, registryName, runId, nil)
+	if err != nil {
+		return nil, fmt.Errorf("getting logs of remote build %s: %w", runId, err)
+	}
+
+	var logOffset int64
+	for {
+		run, err := runsClient.Get(ctx, resourceGroup, registryName, runId, nil)
+		if err != nil {
+			return nil, fmt.Errorf("getting status of remote build %s: %w", runId, err)
+		}
+
+		// The logs are read once more after the run completes, so they are complete
+		if logLink.LogLink != nil {
+			read, err := crs.readBuildLogs(ctx, *logLink.LogLink, logOffset, logs)
+			if err != nil {
+				log.Printf("failed reading logs of remote build %s: %v", runId, err)
+			}
+
+			logOffset += read
+		}
+
+		if run.Properties == nil || run.Properties.Status == nil ||
+			!slices.Contains(remoteBuildPendingStatuses, *run.Properties.Status) {
+			if run.Properties == nil {
+				run.Properties = &armcontainerregistry.RunProperties{}
+			}
+
+			return &run.Run, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(remoteBuildPollInterval):
+		}
+	}
+}
+
+// readBuildLogs writes the logs of the run appended after offset to logs, returning the number of bytes written
+func (crs *containerRegistryService) readBuildLogs(
+	ctx context.Context,
+	logLink string,
+	offset int64,
+	logs io.Writer,
+) (int64, error) {
+	pipeline := azruntime.NewPipeline("azd-acr", internal.Version, azruntime.PipelineOptions{}, crs.coreClientOptions)
+
+	req, err := azruntime.NewRequest(ctx, http.MethodGet, logLink)
+	if err != nil {
+		return 0, err
+	}
+
+	req.Raw().Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+
+	response, err := pipeline.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusRequestedRangeNotSatisfiable, http.StatusNotFound:
+		// No logs were appended yet
+		return 0, nil
+	case http.StatusOK, http.StatusPartialContent:
+		return io.Copy(logs, response.Body)
+	default:
+		return 0, azruntime.NewResponseError(response)
+	}
+}
+
+// newDockerBuildRequest returns the run building the image from the uploaded build context
+func newDockerBuildRequest(
+	loginServer string,
+	sourceLocation string,
+	request *RemoteBuildRequest,
+) (*armcontainerregistry.DockerBuildRequest, error) {
+	platform, err := newPlatformProperties(request.Platform)
+	if err != nil {
+		return nil, err
+	}
+
+	buildRequest := &armcontainerregistry.DockerBuildRequest{
+		Type:           convert.RefOf(armcontainerregistry.RunRequestTypeDockerBuildRequest),
+		SourceLocation: convert.RefOf(sourceLocation),
+		DockerFilePath: convert.RefOf(request.DockerfilePath),
+		Platform:       platform,
+		IsPushEnabled:  convert.RefOf(true),
+		Arguments:      []*armcontainerregistry.Argument{},
+	}
+
+	if request.Target != "" {
+		buildRequest.Target = convert.RefOf(request.Target)
+	}
+
+	// The images are named relative to the registry
+	for _, imageName := range request.ImageNames {
+		buildRequest.ImageNames = append(buildRequest.ImageNames,
+			convert.RefOf(strings.TrimPrefix(imageName, loginServer+"/")))
+	}
+
+	for _, arg := range request.BuildArgs {
+		name, value, hasValue := strings.Cut(arg, "=")
+		if !hasValue {
+			// As with docker, the value of an argument without a value is read from the environment, and the argument
+			// is skipped when the environment variable isn't set
+			envValue, has := os.LookupEnv(name)
+			if !has {
+				log.Printf("skipping build argument '%s', which has no value and isn't set in the environment", name)
+				continue
+			}
+
+			value = envValue
+		}
+
+		buildRequest.Arguments = append(buildRequest.Arguments, &armcontainerregistry.Argument{
+			Name:     convert.RefOf(name),
+			Value:    convert.RefOf(value),
+			IsSecret: convert.RefOf(false),
+		})
+	}
+
+	return buildRequest, nil
+}
+
+// newPlatformProperties parses a platform such as `linux/arm64/v8`
+func newPlatformProperties(platform string) (*armcontainerregistry.PlatformProperties, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid platform '%s', expected '<os>/<architecture>[/<variant>]'", platform)
+	}
+
+	properties := &armcontainerregistry.PlatformProperties{
+		Architecture: convert.RefOf(armcontainerregistry.Architecture(parts[1])),
+	}
+
+	switch strings.ToLower(parts[0]) {
+	case "linux":
+		properties.OS = convert.RefOf(armcontainerregistry.OSLinux)
+	case "windows":
+		properties.OS = convert.RefOf(armcontainerregistry.OSWindows)
+	default:
+		return nil, fmt.Errorf("invalid platform '%s', remote builds support the linux and windows operating systems", platform)
+	}
+
+	if len(parts) == 3 {
+		properties.Variant = convert.RefOf(armcontainerregistry.Variant(parts[2]))
+	}
+
+	return properties, nil
+}
@@ -0,0 +1,253 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package azcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerregistry/armcontainerregistry"
+	"github.com/azure/azure-dev/cli/azd/pkg/convert"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	remoteBuildRegistryPath = "/resourceGroups/RESOURCE_GROUP/providers/Microsoft.ContainerRegistry/registries/contoso"
+	remoteBuildUploadUrl    = "https://contoso.blob.core.windows.net/source/context.tar.gz"
+	remoteBuildLogUrl       = "https://contoso.blob.core.windows.net/logs/ca1.log"
+)
+
+// fakeRemoteBuild fakes the registry APIs used by remote builds. The run reports the statuses in order each time it is
+// polled, and the logs of the run grow by one line each time the run is polled.
+type fakeRemoteBuild struct {
+	statuses []armcontainerregistry.RunStatus
+	logLines []string
+
+	polls        int
+	uploaded     []byte
+	buildRequest map[string]any
+}
+
+func (f *fakeRemoteBuild) register(mockContext *mocks.MockContext) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet &&
+			strings.HasSuffix(request.URL.Path, "/providers/Microsoft.ContainerRegistry/registries")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.RegistryListResult{
+			Value: []*armcontainerregistry.Registry{
+				{
+					ID:   convert.RefOf("/subscriptions/SUBSCRIPTION_ID" + remoteBuildRegistryPath),
+					Name: convert.RefOf("contoso"),
+				},
+			},
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			strings.HasSuffix(request.URL.Path, remoteBuildRegistryPath+"/listBuildSourceUploadUrl")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.SourceUploadDefinition{
+			UploadURL:    convert.RefOf(remoteBuildUploadUrl + "?sig=sas"),
+			RelativePath: convert.RefOf("source/context.tar.gz"),
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPut && strings.HasPrefix(request.URL.String(), remoteBuildUploadUrl)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		var err error
+		f.uploaded, err = io.ReadAll(request.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateEmptyHttpResponse(request, http.StatusCreated)
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost && strings.HasSuffix(request.URL.Path, remoteBuildRegistryPath+"/scheduleRun")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(request.Body).Decode(&f.buildRequest); err != nil {
+			return nil, err
+		}
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, f.run(armcontainerregistry.RunStatusQueued))
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodPost &&
+			strings.HasSuffix(request.URL.Path, remoteBuildRegistryPath+"/runs/ca1/listLogSasUrl")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, armcontainerregistry.RunGetLogResult{
+			LogLink: convert.RefOf(remoteBuildLogUrl + "?sig=sas"),
+		})
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasSuffix(request.URL.Path, remoteBuildRegistryPath+"/runs/ca1")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		status := f.statuses[min(f.polls, len(f.statuses)-1)]
+		f.polls++
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, f.run(status))
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.Method == http.MethodGet && strings.HasPrefix(request.URL.String(), remoteBuildLogUrl)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		content := strings.Join(f.logLines[:min(f.polls, len(f.logLines))], "")
+
+		var offset int
+		_, err := fmt.Sscanf(request.Header.Get("Range"), "bytes=%d-", &offset)
+		if err != nil {
+			return nil, err
+		}
+
+		if offset >= len(content) {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusRequestedRangeNotSatisfiable)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusPartialContent,
+			Header:     http.Header{},
+			Request:    request,
+			Body:       io.NopCloser(strings.NewReader(content[offset:])),
+		}, nil
+	})
+}
+
+func (f *fakeRemoteBuild) run(status armcontainerregistry.RunStatus) armcontainerregistry.Run {
+	run := armcontainerregistry.Run{
+		Properties: &armcontainerregistry.RunProperties{
+			RunID:  convert.RefOf("ca1"),
+			Status: convert.RefOf(status),
+		},
+	}
+
+	if status == armcontainerregistry.RunStatusSucceeded {
+		run.Properties.OutputImages = []*armcontainerregistry.ImageDescriptor{
+			{
+				Registry:   convert.RefOf("contoso.azurecr.io"),
+				Repository: convert.RefOf("app/api"),
+				Tag:        convert.RefOf("v1"),
+				Digest:     convert.RefOf("sha256:remote"),
+			},
+		}
+	}
+
+	return run
+}
+
+func newContainerRegistryServiceFromMockContext(mockContext *mocks.MockContext) ContainerRegistryService {
+	return NewContainerRegistryService(
+		mockaccount.SubscriptionCredentialProviderFunc(func(_ context.Context, _ string) (azcore.TokenCredential, error) {
+			return mockContext.Credentials, nil
+		}),
+		nil,
+		mockContext.ArmClientOptions,
+		mockContext.CoreClientOptions,
+	)
+}
+
+func Test_ContainerRegistryService_RemoteBuild(t *testing.T) {
+	pollInterval := remoteBuildPollInterval
+	remoteBuildPollInterval = 0
+	t.Cleanup(func() { remoteBuildPollInterval = pollInterval })
+
+	request := &RemoteBuildRequest{
+		DockerfilePath: "src/Dockerfile",
+		ImageNames:     []string{"contoso.azurecr.io/app/api:v1"},
+		Platform:       "linux/arm64/v8",
+		Target:         "runtime",
+		BuildArgs:      []string{"VERSION=1.0"},
+	}
+
+	t.Run("Succeeded", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		fake := &fakeRemoteBuild{
+			statuses: []armcontainerregistry.RunStatus{
+				armcontainerregistry.RunStatusRunning,
+				armcontainerregistry.RunStatusRunning,
+				armcontainerregistry.RunStatusSucceeded,
+			},
+			logLines: []string{"Step 1/2\n", "Step 2/2\n", "Pushed\n"},
+		}
+		fake.register(mockContext)
+
+		request.Source = bytes.NewReader([]byte("build context"))
+		logs := &bytes.Buffer{}
+
+		result, err := newContainerRegistryServiceFromMockContext(mockContext).RemoteBuild(
+			*mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io", request, logs)
+		require.NoError(t, err)
+		require.Equal(t, &RemoteBuildResult{RunId: "ca1", Digest: "sha256:remote"}, result)
+
+		require.Equal(t, "build context", string(fake.uploaded))
+		require.Equal(t, "Step 1/2\nStep 2/2\nPushed\n", logs.String())
+
+		require.Equal(t, "DockerBuildRequest", fake.buildRequest["type"])
+		require.Equal(t, "source/context.tar.gz", fake.buildRequest["sourceLocation"])
+		require.Equal(t, "src/Dockerfile", fake.buildRequest["dockerFilePath"])
+		require.Equal(t, []any{"app/api:v1"}, fake.buildRequest["imageNames"])
+		require.Equal(t, "runtime", fake.buildRequest["target"])
+		require.Equal(t, true, fake.buildRequest["isPushEnabled"])
+		require.Equal(t, map[string]any{"os": "Linux", "architecture": "arm64", "variant": "v8"},
+			fake.buildRequest["platform"])
+		require.Equal(t, []any{map[string]any{"name": "VERSION", "value": "1.0", "isSecret": false}},
+			fake.buildRequest["arguments"])
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		fake := &fakeRemoteBuild{
+			statuses: []armcontainerregistry.RunStatus{armcontainerregistry.RunStatusFailed},
+			logLines: []string{"error: failed to solve\n"},
+		}
+		fake.register(mockContext)
+
+		request.Source = bytes.NewReader([]byte("build context"))
+		logs := &bytes.Buffer{}
+
+		result, err := newContainerRegistryServiceFromMockContext(mockContext).RemoteBuild(
+			*mockContext.Context, "SUBSCRIPTION_ID", "contoso.azurecr.io", request, logs)
+		require.Nil(t, result)
+		require.ErrorContains(t, err, "completed with status 'Failed'")
+		require.Equal(t, "error: failed to solve\n", logs.String())
+	})
+
+	t.Run("BuildArgsFromEnvironment", func(t *testing.T) {
+		t.Setenv("AZD_TEST_BUILD_VERSION", "2.0")
+
+		buildRequest, err := newDockerBuildRequest("contoso.azurecr.io", "source/context.tar.gz", &RemoteBuildRequest{
+			DockerfilePath: "Dockerfile",
+			Platform:       "linux/amd64",
+			BuildArgs:      []string{"AZD_TEST_BUILD_VERSION", "AZD_TEST_BUILD_UNSET", "EMPTY="},
+		})
+		require.NoError(t, err)
+
+		// The arguments without a value are read from the environment, and skipped when they aren't set
+		require.Equal(t, []*armcontainerregistry.Argument{
+			{Name: convert.RefOf("AZD_TEST_BUILD_VERSION"), Value: convert.RefOf("2.0"), IsSecret: convert.RefOf(false)},
+			{Name: convert.RefOf("EMPTY"), Value: convert.RefOf(""), IsSecret: convert.RefOf(false)},
+		}, buildRequest.Arguments)
+	})
+
+	t.Run("InvalidPlatform", func(t *testing.T) {
+		_, err := newPlatformProperties("arm64")
+		require.Error(t, err)
+
+		_, err = newPlatformProperties("darwin/arm64")
+		require.Error(t, err)
+	})
+}
@@ -49,6 +49,46 @@ func (cli *azCli) appService(
 	return &webApp, nil
 }
 
+// GetAppServiceSlotProperties gets the properties of a deployment slot of the web app
+func (cli *azCli) GetAppServiceSlotProperties(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) (*AzCliAppServiceProperties, error) {
+	slot, err := cli.appServiceSlot(ctx, subscriptionId, resourceGroup, appName, slotName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AzCliAppServiceProperties{
+		HostNames: []string{*slot.Properties.DefaultHostName},
+	}, nil
+}
+
+// appServiceSlot gets a deployment slot of the web app. A slot is a site of its own, so it is returned as the response
+// of the web app.
+func (cli *azCli) appServiceSlot(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) (*armappservice.WebAppsClientGetResponse, error) {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := client.GetSlot(ctx, resourceGroup, appName, slotName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving webapp slot '%s' properties: %w", slotName, err)
+	}
+
+	return &armappservice.WebAppsClientGetResponse{Site: slot.Site}, nil
+}
+
 func isLinuxWebApp(response *armappservice.WebAppsClientGetResponse) bool {
 	if response.Properties != nil && response.Properties.SiteConfig != nil &&
 		response.Properties.SiteConfig.LinuxFxVersion != nil {
@@ -171,6 +211,67 @@ func (cli *azCli) DeployAppServiceZip(
 	return convert.RefOf(response.StatusText), nil
 }
 
+// DeployAppServiceSlotZip deploys the zip to a deployment slot of the web app. The deployment status API only tracks the
+// production slot, so the deployment completes once the package is deployed to the slot.
+func (cli *azCli) DeployAppServiceSlotZip(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+	deployZipFile io.Reader,
+) (*string, error) {
+	slot, err := cli.appServiceSlot(ctx, subscriptionId, resourceGroup, appName, slotName)
+	if err != nil {
+		return nil, err
+	}
+
+	hostName, err := appServiceRepositoryHost(slot, fmt.Sprintf("%s/%s", appName, slotName))
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := cli.createZipDeployClient(ctx, subscriptionId, hostName)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Deploy(ctx, deployZipFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert.RefOf(response.StatusText), nil
+}
+
+// SwapAppServiceSlot swaps a deployment slot of the web app with its production slot
+func (cli *azCli) SwapAppServiceSlot(
+	ctx context.Context,
+	subscriptionId string,
+	resourceGroup string,
+	appName string,
+	slotName string,
+) error {
+	client, err := cli.createWebAppsClient(ctx, subscriptionId)
+	if err != nil {
+		return err
+	}
+
+	poller, err := client.BeginSwapSlotWithProduction(ctx, resourceGroup, appName, armappservice.CsmSlotEntity{
+		TargetSlot:   convert.RefOf(slotName),
+		PreserveVnet: convert.RefOf(true),
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("swapping slot '%s' with production: %w", slotName, err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("swapping slot '%s' with production: %w", slotName, err)
+	}
+
+	return nil
+}
+
 func (cli *azCli) createWebAppsClient(ctx context.Context, subscriptionId string) (*armappservice.WebAppsClient, error) {
 	credential, err := cli.credentialProvider.CredentialForSubscription(ctx, subscriptionId)
 	if err != nil {
@@ -2,12 +2,14 @@ package docker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -18,6 +20,27 @@ import (
 
 const DefaultPlatform string = "linux/amd64"
 
+// BuildOptions are the BuildKit features used by a build
+type BuildOptions struct {
+	// The secrets exposed to the build, by id. The values are passed to docker through environment variables, so they
+	// are neither part of the command line nor of the image.
+	Secrets map[string]string
+	// The SSH agent sockets or keys forwarded to the build, as `default` or `<id>=<path>`
+	Ssh []string
+	// The external cache sources, as `type=registry,ref=<image>`
+	CacheFrom []string
+	// The cache export destinations, as `type=registry,ref=<image>,mode=max`
+	CacheTo []string
+	// Push pushes the image to the registry instead of loading it in the local image store, which is required to
+	// build an image for several platforms.
+	Push bool
+}
+
+// usesBuildx returns true when the build requires the buildx builder, instead of the default builder
+func (o BuildOptions) usesBuildx(platform string) bool {
+	return strings.Contains(platform, ",") || len(o.CacheTo) > 0 || o.Push
+}
+
 type Docker interface {
 	tools.ExternalTool
 	Login(ctx context.Context, loginServer string, username string, password string) error
@@ -30,6 +53,7 @@ type Docker interface {
 		buildContext string,
 		name string,
 		buildArgs []string,
+		options BuildOptions,
 		buildProgress io.Writer,
 	) (string, error)
 	Tag(ctx context.Context, cwd string, imageName string, tag string) error
@@ -67,6 +91,10 @@ func (d *docker) Login(ctx context.Context, loginServer string, username string,
 // Runs a Docker build for a given Dockerfile, writing the output of docker build to [stdOut] when it is
 // not nil. If the platform is not specified (empty) it defaults to amd64. If the build is successful,
 // the function returns the image id of the built image.
+//
+// Several platforms, separated by commas, produce a manifest list with docker buildx. Such images can't be loaded in the
+// local image store, so they are either pushed, with [BuildOptions.Push], or only kept in the build cache, in which
+// case an empty image id is returned. Pushed images return the digest of the manifest list instead of the image id.
 func (d *docker) Build(
 	ctx context.Context,
 	cwd string,
@@ -76,12 +104,15 @@ func (d *docker) Build(
 	buildContext string,
 	tagName string,
 	buildArgs []string,
+	options BuildOptions,
 	buildProgress io.Writer,
 ) (string, error) {
 	if strings.TrimSpace(platform) == "" {
 		platform = DefaultPlatform
 	}
 
+	multiPlatform := strings.Contains(platform, ",")
+
 	tmpFolder, err := os.MkdirTemp(os.TempDir(), "azd-docker-build")
 	defer func() {
 		// fail to remove tmp files is not so bad as the OS will delete it
@@ -94,11 +125,15 @@ func (d *docker) Build(
 	}
 	imgIdFile := filepath.Join(tmpFolder, "imgId")
 
-	args := []string{
-		"build",
+	args := []string{"build"}
+	if options.usesBuildx(platform) {
+		args = []string{"buildx", "build"}
+	}
+
+	args = append(args,
 		"-f", dockerFilePath,
 		"--platform", platform,
-	}
+	)
 
 	if target != "" {
 		args = append(args, "--target", target)
@@ -111,13 +146,49 @@ func (d *docker) Build(
 	for _, arg := range buildArgs {
 		args = append(args, "--build-arg", arg)
 	}
+
+	env := []string{}
+	secretValues := make([]string, 0, len(options.Secrets))
+	secretIds := make([]string, 0, len(options.Secrets))
+	for id := range options.Secrets {
+		secretIds = append(secretIds, id)
+	}
+	slices.Sort(secretIds)
+
+	for i, id := range secretIds {
+		envName := fmt.Sprintf("AZD_BUILD_SECRET_%d", i)
+		env = append(env, fmt.Sprintf("%s=%s", envName, options.Secrets[id]))
+		secretValues = append(secretValues, options.Secrets[id])
+		args = append(args, "--secret", fmt.Sprintf("id=%s,env=%s", id, envName))
+	}
+
+	for _, ssh := range options.Ssh {
+		args = append(args, "--ssh", ssh)
+	}
+
+	for _, cacheFrom := range options.CacheFrom {
+		args = append(args, "--cache-from", cacheFrom)
+	}
+
+	for _, cacheTo := range options.CacheTo {
+		args = append(args, "--cache-to", cacheTo)
+	}
+
+	if options.Push {
+		args = append(args, "--push")
+	} else if options.usesBuildx(platform) && !multiPlatform {
+		// buildx doesn't load the image in the local image store by default
+		args = append(args, "--load")
+	}
+
 	args = append(args, buildContext)
 
 	// create a file with the docker img id
 	args = append(args, "--iidfile", imgIdFile)
 
 	// Build and produce output
-	runArgs := exec.NewRunArgs("docker", args...).WithCwd(cwd)
+	// The values of the secrets are redacted from the logs of the command
+	runArgs := exec.NewRunArgsWithSensitiveData("docker", args, secretValues).WithCwd(cwd).WithEnv(env)
 
 	if buildProgress != nil {
 		// setting stderr and stdout both, as it's been noticed
@@ -131,7 +202,10 @@ func (d *docker) Build(
 	}
 
 	imgId, err := os.ReadFile(imgIdFile)
-	if err != nil {
+	if errors.Is(err, os.ErrNotExist) && multiPlatform && !options.Push {
+		// The manifest list only exists in the build cache
+		return "", nil
+	} else if err != nil {
 		return "", fmt.Errorf("building image: %w", err)
 	}
 	return strings.TrimSpace(string(imgId)), nil
@@ -67,6 +67,7 @@ func Test_DockerBuild(t *testing.T) {
 			dockerContext,
 			imageName,
 			buildArgs,
+			BuildOptions{},
 			nil,
 		)
 
@@ -124,6 +125,7 @@ func Test_DockerBuild(t *testing.T) {
 			dockerContext,
 			imageName,
 			buildArgs,
+			BuildOptions{},
 			nil,
 		)
 
@@ -180,7 +182,8 @@ func Test_DockerBuildEmptyPlatform(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil)
+	result, err := docker.Build(
+		context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, BuildOptions{}, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
@@ -228,7 +231,8 @@ func Test_DockerBuildArgsEmpty(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil)
+	result, err := docker.Build(
+		context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, BuildOptions{}, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
@@ -278,13 +282,157 @@ func Test_DockerBuildArgsMultiple(t *testing.T) {
 		}, nil
 	})
 
-	result, err := docker.Build(context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, nil)
+	result, err := docker.Build(
+		context.Background(), cwd, dockerFile, "", "", dockerContext, imageName, buildArgs, BuildOptions{}, nil)
 
 	require.Equal(t, true, ran)
 	require.Nil(t, err)
 	require.Equal(t, mockedDockerImgId, result)
 }
 
+func Test_DockerBuildx(t *testing.T) {
+	cwd := "."
+	dockerFile := "./Dockerfile"
+	dockerContext := "../"
+	imageName := "IMAGE_NAME"
+
+	t.Run("BuildKitFeatures", func(t *testing.T) {
+		ran := false
+
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewDocker(mockContext.CommandRunner)
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker buildx build")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+
+			argsNoFile, value := args.Args[:len(args.Args)-2], args.Args[len(args.Args)-1]
+
+			require.Equal(t, []string{
+				"buildx", "build",
+				"-f", dockerFile,
+				"--platform", DefaultPlatform,
+				"-t", imageName,
+				"--secret", "id=feed,env=AZD_BUILD_SECRET_0",
+				"--secret", "id=npmrc,env=AZD_BUILD_SECRET_1",
+				"--ssh", "default",
+				"--cache-from", "type=registry,ref=contoso.azurecr.io/app:cache",
+				"--cache-to", "type=registry,ref=contoso.azurecr.io/app:cache,mode=max",
+				"--load",
+				dockerContext,
+			}, argsNoFile)
+
+			// The values of the secrets are only passed through the environment
+			require.Equal(t, []string{"AZD_BUILD_SECRET_0=feed-token", "AZD_BUILD_SECRET_1=npm-token"}, args.Env)
+			require.Equal(t, []string{"feed-token", "npm-token"}, args.SensitiveData)
+
+			err := os.WriteFile(value, []byte(mockedDockerImgId), 0600)
+			require.NoError(t, err)
+
+			return exec.RunResult{}, nil
+		})
+
+		result, err := docker.Build(
+			context.Background(),
+			cwd,
+			dockerFile,
+			DefaultPlatform,
+			"",
+			dockerContext,
+			imageName,
+			nil,
+			BuildOptions{
+				Secrets:   map[string]string{"npmrc": "npm-token", "feed": "feed-token"},
+				Ssh:       []string{"default"},
+				CacheFrom: []string{"type=registry,ref=contoso.azurecr.io/app:cache"},
+				CacheTo:   []string{"type=registry,ref=contoso.azurecr.io/app:cache,mode=max"},
+			},
+			nil,
+		)
+
+		require.True(t, ran)
+		require.NoError(t, err)
+		require.Equal(t, mockedDockerImgId, result)
+	})
+
+	t.Run("MultiPlatform", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewDocker(mockContext.CommandRunner)
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker buildx build")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			argsNoFile := args.Args[:len(args.Args)-2]
+
+			// The manifest list isn't loaded in the local image store, so no image id file is written
+			require.Equal(t, []string{
+				"buildx", "build",
+				"-f", dockerFile,
+				"--platform", "linux/amd64,linux/arm64",
+				"-t", imageName,
+				dockerContext,
+			}, argsNoFile)
+
+			return exec.RunResult{}, nil
+		})
+
+		result, err := docker.Build(
+			context.Background(),
+			cwd,
+			dockerFile,
+			"linux/amd64,linux/arm64",
+			"",
+			dockerContext,
+			imageName,
+			nil,
+			BuildOptions{},
+			nil,
+		)
+
+		require.NoError(t, err)
+		require.Empty(t, result)
+	})
+
+	t.Run("MultiPlatformPush", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+		docker := NewDocker(mockContext.CommandRunner)
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "docker buildx build")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			argsNoFile, value := args.Args[:len(args.Args)-2], args.Args[len(args.Args)-1]
+
+			require.Equal(t, []string{
+				"buildx", "build",
+				"-f", dockerFile,
+				"--platform", "linux/amd64,linux/arm64",
+				"-t", imageName,
+				"--push",
+				dockerContext,
+			}, argsNoFile)
+
+			err := os.WriteFile(value, []byte("sha256:manifest-list"), 0600)
+			require.NoError(t, err)
+
+			return exec.RunResult{}, nil
+		})
+
+		result, err := docker.Build(
+			context.Background(),
+			cwd,
+			dockerFile,
+			"linux/amd64,linux/arm64",
+			"",
+			dockerContext,
+			imageName,
+			nil,
+			BuildOptions{Push: true},
+			nil,
+		)
+
+		require.NoError(t, err)
+		require.Equal(t, "sha256:manifest-list", result)
+	})
+}
+
 func Test_DockerTag(t *testing.T) {
 	cwd := "."
 	imageName := "image-name"
@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// Creates a new runner of executables, such as binaries or scripts with a shebang, which run with the arguments of the
+// execution options
+func NewExecutableScript(commandRunner exec.CommandRunner, cwd string, envVars []string) Script {
+	return &executableScript{
+		commandRunner: commandRunner,
+		cwd:           cwd,
+		envVars:       envVars,
+	}
+}
+
+type executableScript struct {
+	commandRunner exec.CommandRunner
+	cwd           string
+	envVars       []string
+}
+
+// Executes the specified executable
+// When interactive is true will attach to stdin, stdout & stderr
+func (es *executableScript) Execute(ctx context.Context, path string, options ExecOptions) (exec.RunResult, error) {
+	runArgs := exec.NewRunArgs(path, options.Args...).
+		WithCwd(es.cwd).
+		WithEnv(es.envVars)
+
+	if options.Interactive != nil {
+		runArgs = runArgs.WithInteractive(*options.Interactive)
+	}
+
+	if options.StdOut != nil {
+		runArgs = runArgs.WithStdOut(options.StdOut)
+	}
+
+	return es.commandRunner.Run(ctx, runArgs)
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package golang
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+type GoCli interface {
+	tools.ExternalTool
+	// Downloads the modules required by the module in the project directory
+	ModDownload(ctx context.Context, projectPath string) error
+	// Compiles the main package of the project into the output file. env is appended to the environment of the
+	// go command, e.g. to set GOOS and GOARCH when cross compiling.
+	Build(ctx context.Context, projectPath string, output string, env []string) error
+}
+
+type goCli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewGoCli(commandRunner exec.CommandRunner) GoCli {
+	return &goCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *goCli) Name() string {
+	return "Go"
+}
+
+func (cli *goCli) InstallUrl() string {
+	return "https://go.dev/doc/install"
+}
+
+func (cli *goCli) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 1,
+			Minor: 20,
+			Patch: 0},
+		UpdateCommand: "Visit https://go.dev/dl/ to upgrade",
+	}
+}
+
+func (cli *goCli) CheckInstalled(ctx context.Context) error {
+	err := tools.ToolInPath("go")
+	if err != nil {
+		return err
+	}
+
+	// The output looks like: go version go1.22.1 linux/amd64
+	goRes, err := tools.ExecuteCommand(ctx, cli.commandRunner, "go", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	log.Printf("go version: %s", goRes)
+
+	goSemver, err := tools.ExtractVersion(goRes)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+	updateDetail := cli.versionInfo()
+	if goSemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: updateDetail}
+	}
+	return nil
+}
+
+func (cli *goCli) ModDownload(ctx context.Context, projectPath string) error {
+	runArgs := exec.
+		NewRunArgs("go", "mod", "download").
+		WithCwd(projectPath)
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running go mod download: %s (%w)", res.Stderr, err)
+	}
+
+	return nil
+}
+
+func (cli *goCli) Build(ctx context.Context, projectPath string, output string, env []string) error {
+	runArgs := exec.
+		NewRunArgs("go", "build", "-o", output, ".").
+		WithCwd(projectPath).
+		WithEnv(env)
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed running go build: %s (%w)", res.Stderr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package notation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The environment variable notation reads the password of the registry from, when not set on the command line
+const notationPasswordEnvVarName = "NOTATION_PASSWORD"
+
+// NotationCli signs container images with notation
+type NotationCli interface {
+	tools.ExternalTool
+	// Signs an image in a container registry, which must be referenced by its digest, with a signing key configured with
+	// `notation key add`, such as a key stored in Azure Key Vault. The signature is stored in the registry, which is
+	// authenticated with the username and the password.
+	Sign(ctx context.Context, image string, username string, password string, key string) error
+}
+
+type notationCli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewNotationCli(commandRunner exec.CommandRunner) NotationCli {
+	return &notationCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *notationCli) Name() string {
+	return "Notation CLI"
+}
+
+func (cli *notationCli) InstallUrl() string {
+	return "https://notaryproject.dev/docs/user-guides/installation/cli/"
+}
+
+func (cli *notationCli) CheckInstalled(ctx context.Context) error {
+	return tools.ToolInPath("notation")
+}
+
+func (cli *notationCli) Sign(ctx context.Context, image string, username string, password string, key string) error {
+	// notation sign doesn't read the password from stdin, it is passed by environment rather than on the command line
+	runArgs := exec.NewRunArgsWithSensitiveData("notation", []string{
+		"sign", image,
+		"--key", key,
+		"--username", username,
+	}, []string{password}).WithEnv([]string{notationPasswordEnvVarName + "=" + password})
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("signing image %s: %s (%w)", image, res.Stderr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package notation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NotationSign(t *testing.T) {
+	image := "contoso.azurecr.io/app/api@sha256:abc"
+
+	t.Run("NoErrors", func(t *testing.T) {
+		ran := false
+		mockContext := mocks.NewMockContext(context.Background())
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "notation sign")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+
+			require.Equal(t, []string{
+				"sign", image,
+				"--key", "release",
+				"--username", "user",
+			}, args.Args)
+			require.Equal(t, []string{"NOTATION_PASSWORD=secret"}, args.Env)
+			require.Equal(t, []string{"secret"}, args.SensitiveData)
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		err := NewNotationCli(mockContext.CommandRunner).Sign(*mockContext.Context, image, "user", "secret", "release")
+		require.NoError(t, err)
+		require.True(t, ran)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "notation sign")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(1, "", "key 'release' not found"), errors.New("exit code: 1")
+		})
+
+		err := NewNotationCli(mockContext.CommandRunner).Sign(*mockContext.Context, image, "user", "secret", "release")
+		require.ErrorContains(t, err, "key 'release' not found")
+	})
+}
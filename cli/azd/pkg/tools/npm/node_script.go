@@ -0,0 +1,102 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package npm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// Creates a new NodeScript command runner. JavaScript scripts run with node, TypeScript scripts run with tsx, which must
+// be installed in the project or on the PATH.
+func NewNodeScript(commandRunner exec.CommandRunner, cwd string, envVars []string) tools.Script {
+	return &nodeScript{
+		commandRunner: commandRunner,
+		cwd:           cwd,
+		envVars:       envVars,
+	}
+}
+
+type nodeScript struct {
+	commandRunner exec.CommandRunner
+	cwd           string
+	envVars       []string
+}
+
+// Executes the specified JavaScript or TypeScript script
+// When interactive is true will attach to stdin, stdout & stderr
+func (ns *nodeScript) Execute(ctx context.Context, path string, options tools.ExecOptions) (exec.RunResult, error) {
+	var runArgs exec.RunArgs
+
+	switch filepath.Ext(path) {
+	case ".ts", ".mts":
+		tsx, err := ns.tsxPath(path)
+		if err != nil {
+			return exec.RunResult{}, err
+		}
+
+		runArgs = exec.NewRunArgs(tsx, append([]string{path}, options.Args...)...)
+	default:
+		runArgs = exec.NewRunArgs("node", append([]string{path}, options.Args...)...)
+	}
+
+	runArgs = runArgs.
+		WithCwd(ns.cwd).
+		WithEnv(ns.envVars)
+
+	if options.Interactive != nil {
+		runArgs = runArgs.WithInteractive(*options.Interactive)
+	}
+
+	if options.StdOut != nil {
+		runArgs = runArgs.WithStdOut(options.StdOut)
+	}
+
+	return ns.commandRunner.Run(ctx, runArgs)
+}
+
+// tsxPath returns the path of tsx, installed in the node_modules of the script or of one of its parent directories, or
+// on the PATH. tsx is never downloaded on demand.
+func (ns *nodeScript) tsxPath(path string) (string, error) {
+	tsxName := "tsx"
+	if runtime.GOOS == "windows" {
+		tsxName = "tsx.cmd"
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(ns.cwd, path)
+	}
+
+	for dir := filepath.Dir(path); ; dir = filepath.Dir(dir) {
+		tsxPath := filepath.Join(dir, "node_modules", ".bin", tsxName)
+		if _, err := os.Stat(tsxPath); err == nil {
+			return tsxPath, nil
+		}
+
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+
+	if err := tools.ToolInPath("tsx"); err == nil {
+		return "tsx", nil
+	} else if !errors.Is(err, osexec.ErrNotFound) {
+		return "", err
+	}
+
+	return "", &internal.ErrorWithSuggestion{
+		Err: fmt.Errorf("running TypeScript script '%s' requires tsx, which isn't installed", path),
+		Suggestion: "Install tsx in your project with 'npm install --save-dev tsx', " +
+			"or globally with 'npm install --global tsx'.",
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package oras
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// OrasCli manages OCI artifacts in container registries with oras
+type OrasCli interface {
+	tools.ExternalTool
+	// Attaches the file to an image in a container registry, as an OCI artifact of the specified type referring to the
+	// image. The registry is authenticated with the username and the password.
+	Attach(
+		ctx context.Context,
+		image string,
+		username string,
+		password string,
+		artifactType string,
+		filePath string,
+	) error
+}
+
+type orasCli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewOrasCli(commandRunner exec.CommandRunner) OrasCli {
+	return &orasCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *orasCli) Name() string {
+	return "ORAS CLI"
+}
+
+func (cli *orasCli) InstallUrl() string {
+	return "https://oras.land/docs/installation"
+}
+
+func (cli *orasCli) CheckInstalled(ctx context.Context) error {
+	return tools.ToolInPath("oras")
+}
+
+func (cli *orasCli) Attach(
+	ctx context.Context,
+	image string,
+	username string,
+	password string,
+	artifactType string,
+	filePath string,
+) error {
+	// The file is attached by its name, relative to the working directory
+	runArgs := exec.NewRunArgs(
+		"oras", "attach", image,
+		"--artifact-type", artifactType,
+		"--username", username,
+		"--password-stdin",
+		filepath.Base(filePath),
+	).
+		WithCwd(filepath.Dir(filePath)).
+		WithStdIn(strings.NewReader(password))
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("attaching %s to image %s: %s (%w)", artifactType, image, res.Stderr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package oras
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_OrasAttach(t *testing.T) {
+	ran := false
+	mockContext := mocks.NewMockContext(context.Background())
+	sbomPath := filepath.Join(t.TempDir(), "api.spdx.json")
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "oras attach")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran = true
+
+		// The file is attached by its name, so the annotations of the artifact don't include the local path
+		require.Equal(t, filepath.Dir(sbomPath), args.Cwd)
+		require.Equal(t, []string{
+			"attach", "contoso.azurecr.io/app/api@sha256:abc",
+			"--artifact-type", "application/spdx+json",
+			"--username", "user",
+			"--password-stdin",
+			"api.spdx.json",
+		}, args.Args)
+
+		// The password is passed on stdin rather than on the command line
+		password, err := io.ReadAll(args.StdIn)
+		require.NoError(t, err)
+		require.Equal(t, "secret", string(password))
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	err := NewOrasCli(mockContext.CommandRunner).Attach(
+		*mockContext.Context, "contoso.azurecr.io/app/api@sha256:abc", "user", "secret", "application/spdx+json", sbomPath)
+	require.NoError(t, err)
+	require.True(t, ran)
+}
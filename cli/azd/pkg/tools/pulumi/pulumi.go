@@ -0,0 +1,251 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pulumi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+type PulumiCli interface {
+	tools.ExternalTool
+	// Set environment variables to be used in all pulumi commands
+	SetEnv(envVars []string)
+	// Selects the specified stack, creating it when it does not exist yet
+	SelectStack(ctx context.Context, modulePath string, stackName string) (string, error)
+	// Sets a single configuration value on the stack. Secret values are encrypted by the secrets provider of the stack.
+	SetConfig(
+		ctx context.Context,
+		modulePath string,
+		stackName string,
+		configFilePath string,
+		key string,
+		value string,
+		isJson bool,
+		secret bool,
+	) (string, error)
+	// Displays the set of changes that would be applied to the stack
+	Preview(ctx context.Context, modulePath string, stackName string, additionalArgs ...string) (string, error)
+	// Creates or updates all resources in the stack
+	Up(ctx context.Context, modulePath string, stackName string, additionalArgs ...string) (string, error)
+	// Retrieves the output values from the most recent stack update as JSON
+	StackOutput(ctx context.Context, modulePath string, stackName string) (string, error)
+	// Retrieves the checkpoint of the stack, including all managed resources, as JSON
+	StackExport(ctx context.Context, modulePath string, stackName string) (string, error)
+	// Destroys all resources in the stack
+	Destroy(ctx context.Context, modulePath string, stackName string, additionalArgs ...string) (string, error)
+}
+
+type pulumiCli struct {
+	commandRunner exec.CommandRunner
+	env           []string
+}
+
+func NewPulumiCli(commandRunner exec.CommandRunner) PulumiCli {
+	return &pulumiCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *pulumiCli) Name() string {
+	return "Pulumi CLI"
+}
+
+func (cli *pulumiCli) InstallUrl() string {
+	return "https://www.pulumi.com/docs/install/"
+}
+
+func (cli *pulumiCli) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 3,
+			Minor: 60,
+			Patch: 0},
+		UpdateCommand: "Download newer version from https://www.pulumi.com/docs/install/",
+	}
+}
+
+func (cli *pulumiCli) CheckInstalled(ctx context.Context) error {
+	err := tools.ToolInPath("pulumi")
+	if err != nil {
+		return err
+	}
+
+	versionOutput, err := tools.ExecuteCommand(ctx, cli.commandRunner, "pulumi", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	log.Printf("pulumi version: %s", versionOutput)
+
+	pulumiSemver, err := tools.ExtractVersion(versionOutput)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+	updateDetail := cli.versionInfo()
+	if pulumiSemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: updateDetail}
+	}
+	return nil
+}
+
+// Set environment variables to be used in all pulumi commands
+func (cli *pulumiCli) SetEnv(env []string) {
+	cli.env = env
+}
+
+func (cli *pulumiCli) runCommand(ctx context.Context, runArgs exec.RunArgs) (exec.RunResult, error) {
+	return cli.commandRunner.Run(ctx, runArgs.WithEnv(cli.env))
+}
+
+func (cli *pulumiCli) SelectStack(ctx context.Context, modulePath string, stackName string) (string, error) {
+	runArgs := exec.NewRunArgs(
+		"pulumi", "stack", "select", stackName, "--create", "--non-interactive", "--cwd", modulePath,
+	)
+
+	cmdRes, err := cli.runCommand(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi stack select: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) SetConfig(
+	ctx context.Context,
+	modulePath string,
+	stackName string,
+	configFilePath string,
+	key string,
+	value string,
+	isJson bool,
+	secret bool,
+) (string, error) {
+	args := []string{
+		"config", "set", key,
+		"--stack", stackName,
+		"--cwd", modulePath,
+		"--config-file", configFilePath,
+		"--non-interactive",
+	}
+
+	if isJson {
+		args = append(args, "--json")
+	}
+
+	if secret {
+		args = append(args, "--secret")
+	}
+
+	// Config values are resolved from the azd environment and may contain secrets, they are read by pulumi from stdin
+	// rather than passed on the command line
+	cmdRes, err := cli.runCommand(ctx, exec.NewRunArgs("pulumi", args...).WithStdIn(strings.NewReader(value)))
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi config set: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) Preview(
+	ctx context.Context,
+	modulePath string,
+	stackName string,
+	additionalArgs ...string,
+) (string, error) {
+	args := []string{"preview", "--stack", stackName, "--cwd", modulePath, "--diff"}
+	args = append(args, additionalArgs...)
+
+	cmdRes, err := cli.runCommand(ctx, exec.NewRunArgs("pulumi", args...).WithInteractive(true))
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi preview: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) Up(
+	ctx context.Context,
+	modulePath string,
+	stackName string,
+	additionalArgs ...string,
+) (string, error) {
+	args := []string{"up", "--stack", stackName, "--cwd", modulePath, "--yes"}
+	args = append(args, additionalArgs...)
+
+	cmdRes, err := cli.runCommand(ctx, exec.NewRunArgs("pulumi", args...).WithInteractive(true))
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi up: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) StackOutput(ctx context.Context, modulePath string, stackName string) (string, error) {
+	runArgs := exec.NewRunArgs(
+		"pulumi", "stack", "output", "--json", "--show-secrets", "--stack", stackName, "--cwd", modulePath,
+	)
+
+	cmdRes, err := cli.runCommand(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi stack output: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) StackExport(ctx context.Context, modulePath string, stackName string) (string, error) {
+	runArgs := exec.NewRunArgs("pulumi", "stack", "export", "--stack", stackName, "--cwd", modulePath)
+
+	cmdRes, err := cli.runCommand(ctx, runArgs)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi stack export: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *pulumiCli) Destroy(
+	ctx context.Context,
+	modulePath string,
+	stackName string,
+	additionalArgs ...string,
+) (string, error) {
+	args := []string{"destroy", "--stack", stackName, "--cwd", modulePath}
+	args = append(args, additionalArgs...)
+
+	cmdRes, err := cli.runCommand(ctx, exec.NewRunArgs("pulumi", args...).WithInteractive(true))
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi destroy: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
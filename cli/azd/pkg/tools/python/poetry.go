@@ -0,0 +1,100 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package python
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+// PoetryCli runs Poetry, the dependency manager of Python projects declaring a [tool.poetry] table in pyproject.toml.
+type PoetryCli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewPoetryCli(commandRunner exec.CommandRunner) *PoetryCli {
+	return &PoetryCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *PoetryCli) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 1,
+			Minor: 2,
+			Patch: 0},
+		UpdateCommand: "Run poetry self update to upgrade",
+	}
+}
+
+func (cli *PoetryCli) CheckInstalled(ctx context.Context) error {
+	err := tools.ToolInPath("poetry")
+	if err != nil {
+		return err
+	}
+
+	// The output looks like: Poetry (version 1.8.2)
+	poetryRes, err := tools.ExecuteCommand(ctx, cli.commandRunner, "poetry", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	log.Printf("poetry version: %s", poetryRes)
+
+	poetrySemver, err := tools.ExtractVersion(poetryRes)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+	updateDetail := cli.versionInfo()
+	if poetrySemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: updateDetail}
+	}
+	return nil
+}
+
+func (cli *PoetryCli) InstallUrl() string {
+	return "https://python-poetry.org/docs/#installation"
+}
+
+func (cli *PoetryCli) Name() string {
+	return "Poetry"
+}
+
+// Install installs the dependencies of the project in the virtual environment managed by Poetry.
+// The project itself is not installed.
+func (cli *PoetryCli) Install(ctx context.Context, projectPath string) error {
+	runArgs := exec.
+		NewRunArgs("poetry", "install", "--no-root", "--no-interaction").
+		WithCwd(projectPath)
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to install dependencies for project '%s': %s (%w)", projectPath, res.Stderr, err)
+	}
+
+	return nil
+}
+
+// ExportRequirements writes the locked runtime dependencies of the project to outputFile, in the requirements.txt
+// format understood by pip.
+func (cli *PoetryCli) ExportRequirements(ctx context.Context, projectPath string, outputFile string) error {
+	runArgs := exec.
+		NewRunArgs(
+			"poetry", "export", "--format", "requirements.txt", "--without-hashes", "--output", outputFile,
+		).
+		WithCwd(projectPath)
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to export requirements for project '%s': %s (%w)", projectPath, res.Stderr, err)
+	}
+
+	return nil
+}
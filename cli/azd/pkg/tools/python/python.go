@@ -78,6 +78,17 @@ func (cli *PythonCli) InstallRequirements(ctx context.Context, workingDir, envir
 	return nil
 }
 
+// InstallProject installs the project in the working directory, along with the dependencies declared in its
+// pyproject.toml file.
+func (cli *PythonCli) InstallProject(ctx context.Context, workingDir, environment string) error {
+	_, err := cli.Run(ctx, workingDir, environment, "-m", "pip", "install", ".")
+	if err != nil {
+		return fmt.Errorf("failed to install project '%s': %w", workingDir, err)
+	}
+
+	return nil
+}
+
 func (cli *PythonCli) CreateVirtualEnv(ctx context.Context, workingDir, name string) error {
 	pyString, err := checkPath()
 	if err != nil {
@@ -0,0 +1,49 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package python
+
+import (
+	"context"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// Creates a new PythonScript command runner, running scripts with the Python interpreter found on the PATH
+func NewPythonScript(commandRunner exec.CommandRunner, cwd string, envVars []string) tools.Script {
+	return &pythonScript{
+		commandRunner: commandRunner,
+		cwd:           cwd,
+		envVars:       envVars,
+	}
+}
+
+type pythonScript struct {
+	commandRunner exec.CommandRunner
+	cwd           string
+	envVars       []string
+}
+
+// Executes the specified python script
+// When interactive is true will attach to stdin, stdout & stderr
+func (ps *pythonScript) Execute(ctx context.Context, path string, options tools.ExecOptions) (exec.RunResult, error) {
+	pyString, err := checkPath()
+	if err != nil {
+		return exec.RunResult{}, err
+	}
+
+	runArgs := exec.NewRunArgs(pyString, append([]string{path}, options.Args...)...).
+		WithCwd(ps.cwd).
+		WithEnv(ps.envVars)
+
+	if options.Interactive != nil {
+		runArgs = runArgs.WithInteractive(*options.Interactive)
+	}
+
+	if options.StdOut != nil {
+		runArgs = runArgs.WithStdOut(options.StdOut)
+	}
+
+	return ps.commandRunner.Run(ctx, runArgs)
+}
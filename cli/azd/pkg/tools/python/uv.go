@@ -0,0 +1,106 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package python
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+// UvCli runs uv, the package and project manager of Python projects declaring a [tool.uv] table in pyproject.toml or
+// locked with uv.lock.
+type UvCli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewUvCli(commandRunner exec.CommandRunner) *UvCli {
+	return &UvCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *UvCli) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 0,
+			Minor: 4,
+			Patch: 0},
+		UpdateCommand: "Run uv self update to upgrade",
+	}
+}
+
+func (cli *UvCli) CheckInstalled(ctx context.Context) error {
+	err := tools.ToolInPath("uv")
+	if err != nil {
+		return err
+	}
+
+	// The output looks like: uv 0.4.18 (7b55e9790 2024-10-01)
+	uvRes, err := tools.ExecuteCommand(ctx, cli.commandRunner, "uv", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	log.Printf("uv version: %s", uvRes)
+
+	uvSemver, err := tools.ExtractVersion(uvRes)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+	updateDetail := cli.versionInfo()
+	if uvSemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: updateDetail}
+	}
+	return nil
+}
+
+func (cli *UvCli) InstallUrl() string {
+	return "https://docs.astral.sh/uv/getting-started/installation/"
+}
+
+func (cli *UvCli) Name() string {
+	return "uv"
+}
+
+// Sync installs the locked dependencies of the project in the .venv virtual environment of the project,
+// creating the virtual environment when it doesn't exist.
+func (cli *UvCli) Sync(ctx context.Context, projectPath string) error {
+	runArgs := exec.
+		NewRunArgs("uv", "sync").
+		WithCwd(projectPath)
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to sync dependencies for project '%s': %s (%w)", projectPath, res.Stderr, err)
+	}
+
+	return nil
+}
+
+// ExportRequirements writes the locked runtime dependencies of the project to outputFile, in the requirements.txt
+// format understood by pip. The project itself is not part of the exported requirements.
+func (cli *UvCli) ExportRequirements(ctx context.Context, projectPath string, outputFile string) error {
+	runArgs := exec.
+		NewRunArgs(
+			"uv", "export",
+			"--format", "requirements-txt",
+			"--no-hashes",
+			"--no-dev",
+			"--no-emit-project",
+			"--output-file", outputFile,
+		).
+		WithCwd(projectPath)
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("failed to export requirements for project '%s': %s (%w)", projectPath, res.Stderr, err)
+	}
+
+	return nil
+}
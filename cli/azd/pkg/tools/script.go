@@ -11,6 +11,8 @@ import (
 type ExecOptions struct {
 	Interactive *bool
 	StdOut      io.Writer
+	// The arguments passed to the script, supported by python, node and executable scripts
+	Args []string
 }
 
 // Utility to easily execute a bash script across platforms
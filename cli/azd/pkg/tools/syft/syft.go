@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package syft
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+)
+
+// The SBOM formats supported by azd, which can be attached to an image
+const (
+	FormatSpdxJson      = "spdx-json"
+	FormatCycloneDxJson = "cyclonedx-json"
+)
+
+// SyftCli generates software bills of materials (SBOMs) of container images with syft
+type SyftCli interface {
+	tools.ExternalTool
+	// Generates the SBOM of an image in a container registry, in the specified format, to the output file. The registry is
+	// authenticated with the username and the password.
+	GenerateSbom(
+		ctx context.Context,
+		image string,
+		loginServer string,
+		username string,
+		password string,
+		format string,
+		outputPath string,
+	) error
+}
+
+type syftCli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewSyftCli(commandRunner exec.CommandRunner) SyftCli {
+	return &syftCli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *syftCli) Name() string {
+	return "syft"
+}
+
+func (cli *syftCli) InstallUrl() string {
+	return "https://github.com/anchore/syft#installation"
+}
+
+func (cli *syftCli) CheckInstalled(ctx context.Context) error {
+	return tools.ToolInPath("syft")
+}
+
+func (cli *syftCli) GenerateSbom(
+	ctx context.Context,
+	image string,
+	loginServer string,
+	username string,
+	password string,
+	format string,
+	outputPath string,
+) error {
+	// The image is read from the registry, so a local Docker daemon isn't required. The password is redacted from the
+	// logs of the command.
+	runArgs := exec.NewRunArgsWithSensitiveData(
+		"syft",
+		[]string{"scan", fmt.Sprintf("registry:%s", image), "--output", fmt.Sprintf("%s=%s", format, outputPath)},
+		[]string{password},
+	).WithEnv([]string{
+		fmt.Sprintf("SYFT_REGISTRY_AUTH_AUTHORITY=%s", loginServer),
+		fmt.Sprintf("SYFT_REGISTRY_AUTH_USERNAME=%s", username),
+		fmt.Sprintf("SYFT_REGISTRY_AUTH_PASSWORD=%s", password),
+	})
+
+	res, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf("generating sbom of image %s: %s (%w)", image, res.Stderr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package syft
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SyftGenerateSbom(t *testing.T) {
+	image := "contoso.azurecr.io/app/api@sha256:abc"
+
+	t.Run("NoErrors", func(t *testing.T) {
+		ran := false
+		mockContext := mocks.NewMockContext(context.Background())
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "syft scan")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = true
+
+			require.Equal(t, []string{
+				"scan", "registry:" + image,
+				"--output", "spdx-json=/tmp/api.spdx.json",
+			}, args.Args)
+
+			// The registry credentials aren't passed as arguments
+			require.Equal(t, []string{
+				"SYFT_REGISTRY_AUTH_AUTHORITY=contoso.azurecr.io",
+				"SYFT_REGISTRY_AUTH_USERNAME=user",
+				"SYFT_REGISTRY_AUTH_PASSWORD=secret",
+			}, args.Env)
+			require.Equal(t, []string{"secret"}, args.SensitiveData)
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		err := NewSyftCli(mockContext.CommandRunner).GenerateSbom(
+			*mockContext.Context, image, "contoso.azurecr.io", "user", "secret", FormatSpdxJson, "/tmp/api.spdx.json")
+		require.NoError(t, err)
+		require.True(t, ran)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(context.Background())
+
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "syft scan")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			return exec.NewRunResult(1, "", "unauthorized"), errors.New("exit code: 1")
+		})
+
+		err := NewSyftCli(mockContext.CommandRunner).GenerateSbom(
+			*mockContext.Context, image, "contoso.azurecr.io", "user", "secret", FormatSpdxJson, "/tmp/api.spdx.json")
+		require.ErrorContains(t, err, "unauthorized")
+	})
+}
@@ -0,0 +1,243 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// EvaluateCondition evaluates the condition of a step or a hook against the specified environment values.
+//
+// Conditions support environment value references like ${NAME}, quoted or bare literals, the '==' and '!=' comparisons,
+// the '!', '&&' and '||' operators and parentheses. (Example: ${DEPLOY_API} == 'true' && !${SKIP_DEPLOY})
+// A value is true unless it is empty, 'false' or '0'. An empty condition is always true.
+func EvaluateCondition(condition string, values map[string]string) (bool, error) {
+	if strings.TrimSpace(condition) == "" {
+		return true, nil
+	}
+
+	tokens, err := tokenizeCondition(condition)
+	if err != nil {
+		return false, fmt.Errorf("invalid condition '%s': %w", condition, err)
+	}
+
+	parser := &conditionParser{tokens: tokens, values: values}
+	result, err := parser.parseOr()
+	if err == nil && parser.pos < len(parser.tokens) {
+		err = fmt.Errorf("unexpected '%s'", parser.tokens[parser.pos].text)
+	}
+	if err != nil {
+		return false, fmt.Errorf("invalid condition '%s': %w", condition, err)
+	}
+
+	return isTruthy(result), nil
+}
+
+type conditionTokenKind int
+
+const (
+	conditionTokenValue conditionTokenKind = iota
+	conditionTokenReference
+	conditionTokenOperator
+)
+
+type conditionToken struct {
+	kind conditionTokenKind
+	text string
+}
+
+var conditionOperators = []string{"==", "!=", "&&", "||", "!", "(", ")"}
+
+func tokenizeCondition(condition string) ([]conditionToken, error) {
+	tokens := []conditionToken{}
+	remaining := condition
+
+	for {
+		remaining = strings.TrimLeftFunc(remaining, unicode.IsSpace)
+		if remaining == "" {
+			return tokens, nil
+		}
+
+		if operator, has := conditionOperator(remaining); has {
+			tokens = append(tokens, conditionToken{kind: conditionTokenOperator, text: operator})
+			remaining = remaining[len(operator):]
+			continue
+		}
+
+		switch remaining[0] {
+		case '$':
+			end := strings.IndexByte(remaining, '}')
+			if !strings.HasPrefix(remaining, "${") || end < 0 {
+				return nil, fmt.Errorf("environment value references must be in the form ${NAME}")
+			}
+
+			name := strings.TrimSpace(remaining[2:end])
+			if name == "" {
+				return nil, fmt.Errorf("empty environment value reference")
+			}
+
+			tokens = append(tokens, conditionToken{kind: conditionTokenReference, text: name})
+			remaining = remaining[end+1:]
+		case '\'', '"':
+			end := strings.IndexByte(remaining[1:], remaining[0])
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string %s", remaining)
+			}
+
+			tokens = append(tokens, conditionToken{kind: conditionTokenValue, text: remaining[1 : end+1]})
+			remaining = remaining[end+2:]
+		default:
+			end := strings.IndexFunc(remaining, func(r rune) bool {
+				return unicode.IsSpace(r) || strings.ContainsRune("=!&|()'\"$", r)
+			})
+			if end < 0 {
+				end = len(remaining)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("unexpected '%c'", remaining[0])
+			}
+
+			tokens = append(tokens, conditionToken{kind: conditionTokenValue, text: remaining[:end]})
+			remaining = remaining[end:]
+		}
+	}
+}
+
+func conditionOperator(text string) (string, bool) {
+	for _, operator := range conditionOperators {
+		if strings.HasPrefix(text, operator) {
+			return operator, true
+		}
+	}
+
+	return "", false
+}
+
+// conditionParser is a recursive descent parser evaluating conditions while parsing them
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+	values map[string]string
+}
+
+func (p *conditionParser) parseOr() (string, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return "", err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return "", err
+		}
+
+		left = formatBool(isTruthy(left) || isTruthy(right))
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (string, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return "", err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return "", err
+		}
+
+		left = formatBool(isTruthy(left) && isTruthy(right))
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (string, error) {
+	if p.accept("!") {
+		value, err := p.parseUnary()
+		if err != nil {
+			return "", err
+		}
+
+		return formatBool(!isTruthy(value)), nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *conditionParser) parseComparison() (string, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return "", err
+	}
+
+	for _, operator := range []string{"==", "!="} {
+		if p.accept(operator) {
+			right, err := p.parsePrimary()
+			if err != nil {
+				return "", err
+			}
+
+			return formatBool((left == right) == (operator == "==")), nil
+		}
+	}
+
+	return left, nil
+}
+
+func (p *conditionParser) parsePrimary() (string, error) {
+	if p.pos >= len(p.tokens) {
+		return "", fmt.Errorf("unexpected end of condition")
+	}
+
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch {
+	case token.kind == conditionTokenReference:
+		return p.values[token.text], nil
+	case token.kind == conditionTokenValue:
+		return token.text, nil
+	case token.text == "(":
+		value, err := p.parseOr()
+		if err != nil {
+			return "", err
+		}
+
+		if !p.accept(")") {
+			return "", fmt.Errorf("missing ')'")
+		}
+
+		return value, nil
+	default:
+		return "", fmt.Errorf("unexpected '%s'", token.text)
+	}
+}
+
+// accept consumes the next token when it is the specified operator
+func (p *conditionParser) accept(operator string) bool {
+	if p.pos < len(p.tokens) &&
+		p.tokens[p.pos].kind == conditionTokenOperator &&
+		p.tokens[p.pos].text == operator {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func isTruthy(value string) bool {
+	return value != "" && value != "0" && !strings.EqualFold(value, "false")
+}
+
+func formatBool(value bool) string {
+	if value {
+		return "true"
+	}
+
+	return "false"
+}
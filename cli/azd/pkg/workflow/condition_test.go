@@ -0,0 +1,64 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EvaluateCondition(t *testing.T) {
+	values := map[string]string{
+		"DEPLOY_API": "true",
+		"SKIP_WEB":   "false",
+		"REGION":     "eastus2",
+		"COUNT":      "0",
+	}
+
+	tests := []struct {
+		name      string
+		condition string
+		expected  bool
+	}{
+		{name: "Empty", condition: "", expected: true},
+		{name: "Reference", condition: "${DEPLOY_API}", expected: true},
+		{name: "FalseReference", condition: "${SKIP_WEB}", expected: false},
+		{name: "ZeroReference", condition: "${COUNT}", expected: false},
+		{name: "MissingReference", condition: "${MISSING}", expected: false},
+		{name: "Equal", condition: "${REGION} == 'eastus2'", expected: true},
+		{name: "EqualDoubleQuotes", condition: `${REGION} == "westus"`, expected: false},
+		{name: "NotEqual", condition: "${REGION} != westus", expected: true},
+		{name: "Not", condition: "!${SKIP_WEB}", expected: true},
+		{name: "And", condition: "${DEPLOY_API} && ${SKIP_WEB}", expected: false},
+		{name: "Or", condition: "${SKIP_WEB} || ${DEPLOY_API}", expected: true},
+		{name: "Precedence", condition: "${DEPLOY_API} || ${SKIP_WEB} && ${MISSING}", expected: true},
+		{name: "Parentheses", condition: "(${DEPLOY_API} || ${SKIP_WEB}) && ${MISSING}", expected: false},
+		{name: "NotParentheses", condition: "!(${REGION} == 'eastus2')", expected: false},
+		{name: "EmptyString", condition: "${MISSING} == ''", expected: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := EvaluateCondition(test.condition, values)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, actual)
+		})
+	}
+
+	invalidConditions := []string{
+		"${DEPLOY_API",
+		"$DEPLOY_API",
+		"${}",
+		"'eastus2",
+		"(${DEPLOY_API}",
+		"${DEPLOY_API} ==",
+		"${DEPLOY_API} eastus2",
+		"&& ${DEPLOY_API}",
+	}
+
+	for _, condition := range invalidConditions {
+		t.Run(condition, func(t *testing.T) {
+			_, err := EvaluateCondition(condition, values)
+			require.Error(t, err)
+		})
+	}
+}
@@ -97,6 +97,41 @@ func Test_WorkflowMap_UnmarshallYAML(t *testing.T) {
 		assertWorkflow(t, upWorkflow)
 	})
 
+	t.Run("script, conditional and parallel steps", func(t *testing.T) {
+		var workflowMap WorkflowMap
+		yamlString := heredoc.Doc(`
+			up:
+			  - azd: provision
+			  - name: seed
+			    run: ./scripts/seed.sh
+			    shell: sh
+			    if: ${SEED_DATA} == 'true'
+			    continueOnError: true
+			  - parallel:
+			      - azd: deploy api
+			      - azd: deploy web
+		`)
+
+		err := yaml.Unmarshal([]byte(yamlString), &workflowMap)
+		require.NoError(t, err)
+
+		upWorkflow, ok := workflowMap["up"]
+		require.True(t, ok)
+		require.Len(t, upWorkflow.Steps, 3)
+
+		seedStep := upWorkflow.Steps[1]
+		require.Equal(t, "seed", seedStep.Name)
+		require.Equal(t, "./scripts/seed.sh", seedStep.Run)
+		require.Equal(t, "sh", seedStep.Shell)
+		require.Equal(t, "${SEED_DATA} == 'true'", seedStep.If)
+		require.True(t, seedStep.ContinueOnError)
+
+		parallelStep := upWorkflow.Steps[2]
+		require.Len(t, parallelStep.Parallel, 2)
+		require.Equal(t, []string{"deploy", "api"}, parallelStep.Parallel[0].AzdCommand.Args)
+		require.Equal(t, []string{"deploy", "web"}, parallelStep.Parallel[1].AzdCommand.Args)
+	})
+
 	t.Run("invalid workflow", func(t *testing.T) {
 		var workflowMap WorkflowMap
 		yamlString := heredoc.Doc(`
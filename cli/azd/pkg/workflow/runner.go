@@ -1,11 +1,17 @@
 package workflow
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"strings"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 )
 
 // AzdCommandRunner abstracts the execution of an azd command given an set of arguments and context.
@@ -14,31 +20,209 @@ type AzdCommandRunner interface {
 	ExecuteContext(ctx context.Context) error
 }
 
+// ScriptRunner abstracts the execution of the shell scripts of steps
+// and the environment values the conditions of steps are evaluated against.
+type ScriptRunner interface {
+	// Values returns the latest values of the environment
+	Values(ctx context.Context) (map[string]string, error)
+	// RunScript runs the script of the step.
+	// When stdout is nil the output of the script is displayed by the console.
+	RunScript(ctx context.Context, step *Step, stdout io.Writer) error
+}
+
 // Runner is responsible for executing a workflow
 type Runner struct {
-	azdRunner AzdCommandRunner
-	console   input.Console
+	azdRunner    AzdCommandRunner
+	scriptRunner ScriptRunner
+	console      input.Console
+
+	// azd commands share the root command, steps running in parallel must execute them one at a time
+	azdMutex sync.Mutex
+	// Serializes the output of the steps running in parallel
+	outputMutex sync.Mutex
 }
 
 // NewRunner creates a new instance of the Runner.
-func NewRunner(azdRunner AzdCommandRunner, console input.Console) *Runner {
+func NewRunner(azdRunner AzdCommandRunner, scriptRunner ScriptRunner, console input.Console) *Runner {
 	return &Runner{
-		azdRunner: azdRunner,
-		console:   console,
+		azdRunner:    azdRunner,
+		scriptRunner: scriptRunner,
+		console:      console,
 	}
 }
 
 // Run executes the specified workflow against the root cobra command
 func (r *Runner) Run(ctx context.Context, workflow *Workflow) error {
 	for _, step := range workflow.Steps {
-		if len(step.AzdCommand.Args) > 0 {
-			r.azdRunner.SetArgs(step.AzdCommand.Args)
+		if err := r.runStep(ctx, step, nil, nil); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// runStep runs a step when its condition is true.
+// The output of scripts is written to stdout when set, which is the case for steps running in parallel.
+// The condition is evaluated against values when set, otherwise against the latest values of the environment.
+func (r *Runner) runStep(ctx context.Context, step *Step, stdout io.Writer, values map[string]string) error {
+	if err := step.validate(); err != nil {
+		return err
+	}
+
+	run, err := r.evaluateCondition(ctx, step, values)
+	if err != nil {
+		return err
+	}
+
+	if !run {
+		log.Printf("skipping workflow step '%s', condition '%s' is false", step.DisplayName(), step.If)
+		return nil
+	}
 
-		if err := r.azdRunner.ExecuteContext(ctx); err != nil {
-			return fmt.Errorf("error executing step command '%s': %w", strings.Join(step.AzdCommand.Args, " "), err)
+	switch {
+	case len(step.Parallel) > 0:
+		err = r.runParallel(ctx, step.Parallel, values)
+	case step.Run != "":
+		if err = r.scriptRunner.RunScript(ctx, step, stdout); err != nil {
+			err = fmt.Errorf("error executing step script '%s': %w", step.DisplayName(), err)
 		}
+	default:
+		err = r.runAzdCommand(ctx, step)
+	}
+
+	if err != nil && step.ContinueOnError {
+		r.console.Message(ctx, output.WithWarningFormat("WARNING: %s", err.Error()))
+		r.console.Message(
+			ctx,
+			output.WithWarningFormat("Execution will continue since continueOnError has been set to true."),
+		)
+		log.Println(err.Error())
+
+		return nil
+	}
+
+	return err
+}
+
+func (r *Runner) evaluateCondition(ctx context.Context, step *Step, values map[string]string) (bool, error) {
+	if strings.TrimSpace(step.If) == "" {
+		return true, nil
+	}
+
+	if values == nil {
+		var err error
+		values, err = r.scriptRunner.Values(ctx)
+		if err != nil {
+			return false, fmt.Errorf(
+				"getting environment values for the condition of step '%s': %w", step.DisplayName(), err)
+		}
+	}
+
+	return EvaluateCondition(step.If, values)
+}
+
+func (r *Runner) runAzdCommand(ctx context.Context, step *Step) error {
+	r.azdMutex.Lock()
+	defer r.azdMutex.Unlock()
+
+	if len(step.AzdCommand.Args) > 0 {
+		r.azdRunner.SetArgs(step.AzdCommand.Args)
+	}
+
+	if err := r.azdRunner.ExecuteContext(ctx); err != nil {
+		return fmt.Errorf("error executing step command '%s': %w", strings.Join(step.AzdCommand.Args, " "), err)
 	}
 
 	return nil
 }
+
+// runParallel runs the steps concurrently and waits for all of them to complete.
+// The output of the scripts is prefixed with the name of their step, since their output is interleaved.
+// The conditions of the steps are evaluated against the values of the environment read before the steps start, since
+// reading the environment reloads it while the other steps may be changing it.
+func (r *Runner) runParallel(ctx context.Context, steps []*Step, values map[string]string) error {
+	if values == nil && hasConditions(steps) {
+		var err error
+		values, err = r.scriptRunner.Values(ctx)
+		if err != nil {
+			return fmt.Errorf("getting environment values for the conditions of parallel steps: %w", err)
+		}
+	}
+
+	errs := make([]error, len(steps))
+	wg := sync.WaitGroup{}
+
+	for i, step := range steps {
+		wg.Add(1)
+		go func(i int, step *Step) {
+			defer wg.Done()
+
+			writer := &prefixWriter{
+				prefix: fmt.Sprintf("[%s] ", step.DisplayName()),
+				writer: r.console.Handles().Stdout,
+				mutex:  &r.outputMutex,
+			}
+			defer writer.Flush()
+
+			errs[i] = r.runStep(ctx, step, writer, values)
+		}(i, step)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// hasConditions returns true when any of the steps, or of their parallel steps, has a condition
+func hasConditions(steps []*Step) bool {
+	for _, step := range steps {
+		if strings.TrimSpace(step.If) != "" || hasConditions(step.Parallel) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prefixWriter writes complete lines prefixed with the name of a step, sharing a mutex with the writers of the other
+// steps so lines of concurrent steps aren't mixed.
+type prefixWriter struct {
+	prefix string
+	writer io.Writer
+	mutex  *sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buffer.Write(p)
+
+	for {
+		line, err := w.buffer.ReadBytes('\n')
+		if err != nil {
+			// Keep the incomplete line until the rest of the line is written
+			w.buffer.Write(line)
+			return len(p), nil
+		}
+
+		if err := w.writeLine(line); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush writes the remaining incomplete line
+func (w *prefixWriter) Flush() {
+	if w.buffer.Len() > 0 {
+		_ = w.writeLine(append(w.buffer.Bytes(), '\n'))
+		w.buffer.Reset()
+	}
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	_, err := fmt.Fprintf(w.writer, "%s%s", w.prefix, line)
+	return err
+}
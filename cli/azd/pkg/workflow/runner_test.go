@@ -0,0 +1,221 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Runner_Run(t *testing.T) {
+	t.Run("Commands", func(t *testing.T) {
+		azdRunner, scriptRunner := newTestRunners()
+		runner := NewRunner(azdRunner, scriptRunner, mockinput.NewMockConsole())
+
+		err := runner.Run(context.Background(), testWorkflow)
+		require.NoError(t, err)
+		require.Equal(t, []string{"package --all", "provision", "deploy --all"}, azdRunner.executed)
+	})
+
+	t.Run("Scripts", func(t *testing.T) {
+		azdRunner, scriptRunner := newTestRunners()
+		runner := NewRunner(azdRunner, scriptRunner, mockinput.NewMockConsole())
+
+		err := runner.Run(context.Background(), &Workflow{
+			Steps: []*Step{
+				{Run: "./scripts/seed.sh"},
+				NewAzdCommandStep("deploy", "--all"),
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"./scripts/seed.sh"}, scriptRunner.ran)
+		require.Equal(t, []string{"deploy --all"}, azdRunner.executed)
+	})
+
+	t.Run("Conditions", func(t *testing.T) {
+		azdRunner, scriptRunner := newTestRunners()
+		scriptRunner.values = map[string]string{"DEPLOY_API": "true"}
+		runner := NewRunner(azdRunner, scriptRunner, mockinput.NewMockConsole())
+
+		err := runner.Run(context.Background(), &Workflow{
+			Steps: []*Step{
+				{AzdCommand: Command{Args: []string{"deploy", "api"}}, If: "${DEPLOY_API} == 'true'"},
+				{AzdCommand: Command{Args: []string{"deploy", "web"}}, If: "${DEPLOY_WEB}"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"deploy api"}, azdRunner.executed)
+	})
+
+	t.Run("InvalidCondition", func(t *testing.T) {
+		azdRunner, scriptRunner := newTestRunners()
+		runner := NewRunner(azdRunner, scriptRunner, mockinput.NewMockConsole())
+
+		err := runner.Run(context.Background(), &Workflow{
+			Steps: []*Step{
+				{AzdCommand: Command{Args: []string{"provision"}}, If: "(${A}"},
+			},
+		})
+		require.Error(t, err)
+		require.Empty(t, azdRunner.executed)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		azdRunner, scriptRunner := newTestRunners()
+		scriptRunner.failing = "./scripts/seed.sh"
+		runner := NewRunner(azdRunner, scriptRunner, mockinput.NewMockConsole())
+
+		err := runner.Run(context.Background(), &Workflow{
+			Steps: []*Step{
+				{Run: "./scripts/seed.sh"},
+				NewAzdCommandStep("deploy", "--all"),
+			},
+		})
+		require.Error(t, err)
+		require.Empty(t, azdRunner.executed)
+	})
+
+	t.Run("ContinueOnError", func(t *testing.T) {
+		azdRunner, scriptRunner := newTestRunners()
+		scriptRunner.failing = "./scripts/seed.sh"
+		runner := NewRunner(azdRunner, scriptRunner, mockinput.NewMockConsole())
+
+		err := runner.Run(context.Background(), &Workflow{
+			Steps: []*Step{
+				{Run: "./scripts/seed.sh", ContinueOnError: true},
+				NewAzdCommandStep("deploy", "--all"),
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"deploy --all"}, azdRunner.executed)
+	})
+
+	t.Run("Parallel", func(t *testing.T) {
+		azdRunner, scriptRunner := newTestRunners()
+		scriptRunner.failing = "./scripts/lint.sh"
+		runner := NewRunner(azdRunner, scriptRunner, mockinput.NewMockConsole())
+
+		err := runner.Run(context.Background(), &Workflow{
+			Steps: []*Step{
+				{
+					Parallel: []*Step{
+						{Run: "./scripts/test.sh"},
+						{Run: "./scripts/lint.sh"},
+						NewAzdCommandStep("package", "--all"),
+					},
+				},
+				NewAzdCommandStep("deploy", "--all"),
+			},
+		})
+		require.ErrorContains(t, err, "./scripts/lint.sh")
+		require.ElementsMatch(t, []string{"./scripts/test.sh", "./scripts/lint.sh"}, scriptRunner.ran)
+		require.Equal(t, []string{"package --all"}, azdRunner.executed)
+		require.True(t, scriptRunner.prefixed)
+	})
+
+	t.Run("ParallelConditions", func(t *testing.T) {
+		azdRunner, scriptRunner := newTestRunners()
+		scriptRunner.values = map[string]string{"RUN_TESTS": "true"}
+		runner := NewRunner(azdRunner, scriptRunner, mockinput.NewMockConsole())
+
+		err := runner.Run(context.Background(), &Workflow{
+			Steps: []*Step{
+				{
+					Parallel: []*Step{
+						{Run: "./scripts/test.sh", If: "${RUN_TESTS}"},
+						{Run: "./scripts/lint.sh", If: "${RUN_LINT}"},
+						{Run: "./scripts/audit.sh", If: "!${RUN_LINT}"},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"./scripts/test.sh", "./scripts/audit.sh"}, scriptRunner.ran)
+		// The values are read once, before the parallel steps start
+		require.Equal(t, 1, scriptRunner.valuesReads)
+	})
+
+	t.Run("InvalidStep", func(t *testing.T) {
+		azdRunner, scriptRunner := newTestRunners()
+		runner := NewRunner(azdRunner, scriptRunner, mockinput.NewMockConsole())
+
+		err := runner.Run(context.Background(), &Workflow{
+			Steps: []*Step{
+				{AzdCommand: Command{Args: []string{"provision"}}, Run: "./scripts/seed.sh"},
+			},
+		})
+		require.Error(t, err)
+		require.Empty(t, azdRunner.executed)
+		require.Empty(t, scriptRunner.ran)
+	})
+}
+
+func Test_PrefixWriter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	writer := &prefixWriter{prefix: "[api] ", writer: buffer, mutex: &sync.Mutex{}}
+
+	_, err := io.WriteString(writer, "building\npack")
+	require.NoError(t, err)
+	_, err = io.WriteString(writer, "aging\ndone")
+	require.NoError(t, err)
+	writer.Flush()
+
+	require.Equal(t, "[api] building\n[api] packaging\n[api] done\n", buffer.String())
+}
+
+type testAzdRunner struct {
+	args     []string
+	executed []string
+}
+
+func (r *testAzdRunner) SetArgs(args []string) {
+	r.args = args
+}
+
+func (r *testAzdRunner) ExecuteContext(ctx context.Context) error {
+	r.executed = append(r.executed, strings.Join(r.args, " "))
+	return nil
+}
+
+type testScriptRunner struct {
+	mutex       sync.Mutex
+	values      map[string]string
+	valuesReads int
+	failing     string
+	ran         []string
+	prefixed    bool
+}
+
+func (r *testScriptRunner) Values(ctx context.Context) (map[string]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.valuesReads++
+	return r.values, nil
+}
+
+func (r *testScriptRunner) RunScript(ctx context.Context, step *Step, stdout io.Writer) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.ran = append(r.ran, step.Run)
+	if writer, ok := stdout.(*prefixWriter); ok && writer.prefix == "["+step.Run+"] " {
+		r.prefixed = true
+	}
+
+	if step.Run == r.failing {
+		return errors.New("exit code 1")
+	}
+
+	return nil
+}
+
+func newTestRunners() (*testAzdRunner, *testScriptRunner) {
+	return &testAzdRunner{}, &testScriptRunner{values: map[string]string{}}
+}
@@ -85,9 +85,62 @@ func (w *Workflow) unmarshalSteps(rawSteps any) ([]*Step, error) {
 }
 
 // Step stores a single step to execute within a workflow
-// This struct can be expanded over time to support other types of steps/commands
+// A step either executes an azd command, runs a shell script or runs a group of steps in parallel
 type Step struct {
+	// The optional name of the step, displayed in the output of the workflow
+	Name       string  `yaml:"name,omitempty"`
 	AzdCommand Command `yaml:"azd,omitempty"`
+	// The inline script to execute or path to existing file, run like hooks
+	Run string `yaml:"run,omitempty"`
+	// The type of shell running the script (sh, pwsh, python, node or exec)
+	Shell string `yaml:"shell,omitempty"`
+	// When set to true will bind the stdin, stdout & stderr of the script to the running console
+	Interactive bool `yaml:"interactive,omitempty"`
+	// The condition evaluated against the environment values, the step is skipped when false
+	// (Example: ${DEPLOY_API} == 'true')
+	If string `yaml:"if,omitempty"`
+	// When set to true a failure of the step is reported as a warning and the workflow continues
+	ContinueOnError bool `yaml:"continueOnError,omitempty"`
+	// The steps to run in parallel
+	Parallel []*Step `yaml:"parallel,omitempty"`
+}
+
+// DisplayName returns the name of the step, or a description of what the step executes when the step isn't named
+func (s *Step) DisplayName() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case s.Run != "":
+		return strings.TrimSpace(strings.SplitN(strings.TrimSpace(s.Run), "\n", 2)[0])
+	case len(s.Parallel) > 0:
+		return "parallel"
+	default:
+		return strings.TrimSpace("azd " + strings.Join(s.AzdCommand.Args, " "))
+	}
+}
+
+// validate ensures the step only configures a single kind of step
+func (s *Step) validate() error {
+	kinds := 0
+	if len(s.AzdCommand.Args) > 0 {
+		kinds++
+	}
+	if s.Run != "" {
+		kinds++
+	}
+	if len(s.Parallel) > 0 {
+		kinds++
+	}
+
+	if kinds > 1 {
+		return fmt.Errorf("step '%s' must only specify one of 'azd', 'run' or 'parallel'", s.DisplayName())
+	}
+
+	if s.Shell != "" && s.Run == "" {
+		return fmt.Errorf("step '%s' specifies a shell without a script to run", s.DisplayName())
+	}
+
+	return nil
 }
 
 // NewAzdCommandStep creates a new step that executes an azd command with the specified name and args
@@ -55,3 +55,52 @@ func (m *MockEnvManager) Delete(ctx context.Context, name string) error {
 	args := m.Called(name)
 	return args.Error(0)
 }
+
+func (m *MockEnvManager) Lock(ctx context.Context, name string, operation string) (*environment.Lease, error) {
+	args := m.Called(ctx, name, operation)
+	return args.Get(0).(*environment.Lease), args.Error(1)
+}
+
+func (m *MockEnvManager) LockStatus(ctx context.Context, name string) (*environment.LockInfo, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).(*environment.LockInfo), args.Error(1)
+}
+
+func (m *MockEnvManager) BreakLock(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+func (m *MockEnvManager) History(ctx context.Context, name string) ([]*environment.Snapshot, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]*environment.Snapshot), args.Error(1)
+}
+
+func (m *MockEnvManager) Restore(
+	ctx context.Context,
+	env *environment.Environment,
+	version int,
+) (*environment.Snapshot, error) {
+	args := m.Called(ctx, env, version)
+	return args.Get(0).(*environment.Snapshot), args.Error(1)
+}
+
+func (m *MockEnvManager) ExportBundle(
+	ctx context.Context,
+	name string,
+	from environment.DataStoreKind,
+) (*environment.Bundle, error) {
+	args := m.Called(ctx, name, from)
+	return args.Get(0).(*environment.Bundle), args.Error(1)
+}
+
+func (m *MockEnvManager) ImportBundle(
+	ctx context.Context,
+	bundle *environment.Bundle,
+	name string,
+	to environment.DataStoreKind,
+	overwrite bool,
+) (*environment.Environment, error) {
+	args := m.Called(ctx, bundle, name, to, overwrite)
+	return args.Get(0).(*environment.Environment), args.Error(1)
+}
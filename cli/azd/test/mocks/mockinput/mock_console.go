@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
@@ -34,6 +35,8 @@ type MockConsole struct {
 	expressions []*MockConsoleExpression
 	log         []string
 	spinnerOps  []SpinnerOp
+	// status lines are updated concurrently
+	statusLinesMu sync.Mutex
 }
 
 func NewMockConsole() *MockConsole {
@@ -109,6 +112,27 @@ func (c *MockConsole) ShowPreviewer(ctx context.Context, options *input.ShowPrev
 
 func (c *MockConsole) StopPreviewer(ctx context.Context, keepLogs bool) {}
 
+func (c *MockConsole) ShowStatusLines(ctx context.Context, titles []string) {}
+
+// Records the status lines as spinner operations, so they can be verified like spinners
+func (c *MockConsole) UpdateStatusLine(ctx context.Context, title string, message string, format input.SpinnerUxType) {
+	c.statusLinesMu.Lock()
+	defer c.statusLinesMu.Unlock()
+
+	op := SpinnerOpShow
+	if format != input.Step {
+		op = SpinnerOpStop
+	}
+
+	c.spinnerOps = append(c.spinnerOps, SpinnerOp{
+		Op:      op,
+		Message: title,
+		Format:  format,
+	})
+}
+
+func (c *MockConsole) StopStatusLines(ctx context.Context) {}
+
 func (c *MockConsole) IsSpinnerRunning(ctx context.Context) bool {
 	if len(c.spinnerOps) > 0 && c.spinnerOps[len(c.spinnerOps)-1].Op == SpinnerOpShow {
 		return true